@@ -14,6 +14,7 @@ import (
 	"github.com/openshift/origin/pkg/cmd/openshift-tests/dev"
 	"github.com/openshift/origin/pkg/cmd/openshift-tests/disruption"
 	"github.com/openshift/origin/pkg/cmd/openshift-tests/images"
+	merge_results "github.com/openshift/origin/pkg/cmd/openshift-tests/merge-results"
 	"github.com/openshift/origin/pkg/cmd/openshift-tests/monitor"
 	run_monitor "github.com/openshift/origin/pkg/cmd/openshift-tests/monitor/run"
 	"github.com/openshift/origin/pkg/cmd/openshift-tests/monitor/timeline"
@@ -77,15 +78,17 @@ func main() {
 
 	root.AddCommand(
 		run.NewRunCommand(ioStreams),
+		merge_results.NewMergeResultsCommand(ioStreams),
 		run_upgrade.NewRunUpgradeCommand(ioStreams),
 		images.NewImagesCommand(),
 		run_test.NewRunTestCommand(ioStreams),
-		dev.NewDevCommand(),
+		dev.NewDevCommand(ioStreams),
 		run_monitor.NewRunMonitorCommand(ioStreams),
 		monitor.NewMonitorCommand(ioStreams),
 		disruption.NewDisruptionCommand(ioStreams),
 		risk_analysis.NewTestFailureRiskAnalysisCommand(),
 		run_resourcewatch.NewRunResourceWatchCommand(),
+		run_resourcewatch.NewResourceWatchCommand(ioStreams),
 		timeline.NewTimelineCommand(ioStreams),
 		run_disruption.NewRunInClusterDisruptionMonitorCommand(ioStreams),
 		collectdiskcertificates.NewRunCollectDiskCertificatesCommand(ioStreams),
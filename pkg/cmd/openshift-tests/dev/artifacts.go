@@ -0,0 +1,141 @@
+package dev
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+)
+
+const (
+	intervalsFilePrefix   = "e2e-events"
+	clusterDataFilePrefix = "cluster-data"
+)
+
+// artifactRun is a single openshift-tests invocation found in the artifacts of a CI job.  Upgrade jobs have one
+// for the upgrade and one for the conformance run that follows it.
+type artifactRun struct {
+	// IntervalsFile is the e2e-events_<timestamp>.json file of the run, tracked resources are next to it.
+	IntervalsFile string
+	// ClusterDataFile is the cluster-data_<timestamp>.json file of the run, empty if none was found.
+	ClusterDataFile string
+}
+
+// findArtifactRuns walks artifactsDir for intervals files and pairs each one with the cluster data written by the
+// same run.  When the run did not write cluster data, the data of another run in the same job is used since the
+// job variants do not change between them.  Anything under skipDir is ignored.
+func findArtifactRuns(artifactsDir, skipDir string) ([]artifactRun, error) {
+	intervalsFiles := []string{}
+	clusterDataFiles := []string{}
+	err := filepath.WalkDir(artifactsDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if len(skipDir) > 0 && path == skipDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		base := d.Name()
+		switch {
+		case strings.HasPrefix(base, intervalsFilePrefix) && strings.HasSuffix(base, ".json"):
+			intervalsFiles = append(intervalsFiles, path)
+		case strings.HasPrefix(base, clusterDataFilePrefix) && strings.HasSuffix(base, ".json"):
+			clusterDataFiles = append(clusterDataFiles, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read artifacts from %s: %w", artifactsDir, err)
+	}
+	// the timestamp in the filename orders the runs, an upgrade before the conformance run that follows it.
+	byTimestamp := func(filenames []string) func(i, j int) bool {
+		return func(i, j int) bool {
+			if filepath.Base(filenames[i]) != filepath.Base(filenames[j]) {
+				return filepath.Base(filenames[i]) < filepath.Base(filenames[j])
+			}
+			return filenames[i] < filenames[j]
+		}
+	}
+	sort.Slice(intervalsFiles, byTimestamp(intervalsFiles))
+	sort.Slice(clusterDataFiles, byTimestamp(clusterDataFiles))
+
+	runs := []artifactRun{}
+	for _, intervalsFile := range intervalsFiles {
+		run := artifactRun{IntervalsFile: intervalsFile}
+
+		// e2e-events_20230214-203340.json is written alongside cluster-data_20230214-203340.json
+		timeSuffix := strings.TrimPrefix(filepath.Base(intervalsFile), intervalsFilePrefix)
+		sameRun := filepath.Join(filepath.Dir(intervalsFile), clusterDataFilePrefix+timeSuffix)
+		if _, err := os.Stat(sameRun); err == nil {
+			run.ClusterDataFile = sameRun
+		} else if len(clusterDataFiles) > 0 {
+			run.ClusterDataFile = clusterDataFiles[0]
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// jobTypeOverrides are job variants specified on the command line, they take precedence over the cluster data.
+type jobTypeOverrides struct {
+	Release      string
+	FromRelease  string
+	Platform     string
+	Architecture string
+	Network      string
+	Topology     string
+}
+
+// clusterDataFor reads the cluster data of the run and applies the overrides.  Every variant except FromRelease,
+// which is empty for jobs that did not upgrade, must be known to match historical data.
+func clusterDataFor(run artifactRun, overrides jobTypeOverrides) (*platformidentification.ClusterData, error) {
+	clusterData := &platformidentification.ClusterData{}
+	if len(run.ClusterDataFile) > 0 {
+		var err error
+		clusterData, err = platformidentification.ReadClusterDataFromFile(run.ClusterDataFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, override := range []struct {
+		value  string
+		target *string
+	}{
+		{value: overrides.Release, target: &clusterData.Release},
+		{value: overrides.FromRelease, target: &clusterData.FromRelease},
+		{value: overrides.Platform, target: &clusterData.Platform},
+		{value: overrides.Architecture, target: &clusterData.Architecture},
+		{value: overrides.Network, target: &clusterData.Network},
+		{value: overrides.Topology, target: &clusterData.Topology},
+	} {
+		if len(override.value) > 0 {
+			*override.target = override.value
+		}
+	}
+
+	missing := []string{}
+	for flag, value := range map[string]string{
+		"--release":  clusterData.Release,
+		"--platform": clusterData.Platform,
+		"--arch":     clusterData.Architecture,
+		"--network":  clusterData.Network,
+		"--topology": clusterData.Topology,
+	} {
+		if len(value) == 0 {
+			missing = append(missing, flag)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("unable to determine the job variants of %s from cluster data, specify %s",
+			run.IntervalsFile, strings.Join(missing, ", "))
+	}
+	return clusterData, nil
+}
@@ -0,0 +1,58 @@
+package dev
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeArtifact(t *testing.T, filename, content string) string {
+	require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+	require.NoError(t, os.WriteFile(filename, []byte(content), 0644))
+	return filename
+}
+
+func TestFindArtifactRuns(t *testing.T) {
+	artifactsDir := t.TempDir()
+	upgradeDir := filepath.Join(artifactsDir, "e2e-upgrade", "openshift-e2e-test", "artifacts", "junit")
+	conformanceDir := filepath.Join(artifactsDir, "e2e-upgrade", "openshift-e2e-test", "artifacts", "junit", "conformance")
+	outputDir := filepath.Join(artifactsDir, "output")
+
+	upgradeIntervals := writeArtifact(t, filepath.Join(upgradeDir, "e2e-events_20240101-100000.json"), "{}")
+	upgradeClusterData := writeArtifact(t, filepath.Join(upgradeDir, "cluster-data_20240101-100000.json"), `{"Release": "4.16", "FromRelease": "4.15", "Platform": "aws", "Architecture": "amd64", "Network": "ovn", "Topology": "ha", "CloudRegion": "us-east-1"}`)
+	conformanceIntervals := writeArtifact(t, filepath.Join(conformanceDir, "e2e-events_20240101-120000.json"), "{}")
+	writeArtifact(t, filepath.Join(outputDir, "e2e-events_20240101-100000.json"), "{}")
+
+	runs, err := findArtifactRuns(artifactsDir, outputDir)
+	require.NoError(t, err)
+	assert.Equal(t, []artifactRun{
+		{IntervalsFile: upgradeIntervals, ClusterDataFile: upgradeClusterData},
+		{IntervalsFile: conformanceIntervals, ClusterDataFile: upgradeClusterData},
+	}, runs)
+
+	clusterData, err := clusterDataFor(runs[0], jobTypeOverrides{})
+	require.NoError(t, err)
+	assert.Equal(t, "4.15", clusterData.FromRelease)
+	assert.Equal(t, "us-east-1", clusterData.CloudRegion)
+
+	clusterData, err = clusterDataFor(runs[1], jobTypeOverrides{Platform: "gcp"})
+	require.NoError(t, err)
+	assert.Equal(t, "gcp", clusterData.Platform)
+	assert.Equal(t, "4.16", clusterData.Release)
+}
+
+func TestClusterDataForWithoutClusterData(t *testing.T) {
+	run := artifactRun{IntervalsFile: "e2e-events_20240101-100000.json"}
+
+	_, err := clusterDataFor(run, jobTypeOverrides{Release: "4.16", Platform: "aws"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "specify --arch, --network, --topology")
+
+	clusterData, err := clusterDataFor(run, jobTypeOverrides{Release: "4.16", Platform: "aws", Architecture: "amd64", Network: "ovn", Topology: "single"})
+	require.NoError(t, err)
+	assert.Equal(t, "", clusterData.FromRelease)
+	assert.Equal(t, "single", clusterData.Topology)
+}
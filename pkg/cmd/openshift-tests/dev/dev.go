@@ -14,10 +14,11 @@ import (
 	"github.com/openshift/origin/pkg/monitortests/testframework/legacytestframeworkmonitortests"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/kubectl/pkg/util/templates"
 )
 
-func NewDevCommand() *cobra.Command {
+func NewDevCommand(streams genericclioptions.IOStreams) *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:   "dev",
@@ -27,6 +28,7 @@ func NewDevCommand() *cobra.Command {
 	cmd.AddCommand(
 		newRunAlertInvariantsCommand(),
 		newRunDisruptionInvariantsCommand(),
+		newRunMonitorTestsCommand(streams),
 	)
 	return cmd
 }
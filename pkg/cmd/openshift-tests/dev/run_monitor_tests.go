@@ -0,0 +1,175 @@
+package dev
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/openshift/origin/pkg/cmd/openshift-tests/monitor/replay"
+	"github.com/openshift/origin/pkg/defaultmonitortests"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+type RunMonitorTestsFlags struct {
+	FromArtifacts                   string
+	ArtifactDir                     string
+	JUnitSuiteName                  string
+	ClusterStability                string
+	ExactMonitorTests               []string
+	DisableMonitorTests             []string
+	PathologicalEventAllowanceFiles []string
+
+	JobTypeOverrides jobTypeOverrides
+
+	genericclioptions.IOStreams
+}
+
+func NewRunMonitorTestsFlags(streams genericclioptions.IOStreams) *RunMonitorTestsFlags {
+	return &RunMonitorTestsFlags{
+		JUnitSuiteName:   "openshift-tests-dev-monitor",
+		ClusterStability: string(monitortestframework.Stable),
+		IOStreams:        streams,
+	}
+}
+
+func newRunMonitorTestsCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	f := NewRunMonitorTestsFlags(streams)
+
+	cmd := &cobra.Command{
+		Use:   "run-monitor-tests",
+		Short: "Run monitor tests against the artifacts of a CI run",
+		Long: templates.LongDesc(`
+		Run monitor tests against the artifacts of a CI run.
+
+		Point --from-artifacts at an unpacked artifacts directory.  Every e2e-events_<timestamp>.json file found
+		beneath it is replayed through the selected monitor tests, along with the resource-<type>_<timestamp>.zip
+		files next to it.  The job variants (release, platform, network, topology, etc) used to match alerts,
+		disruption, pathological events and operator state against historical data are read from the
+		cluster-data_<timestamp>.json file written by the same run.  Individual variants can be overridden
+		with flags, which is required when the run did not write cluster data.  Monitor tests that depend on
+		the data they collect from the cluster are reported as skipped.
+		`),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o, err := f.ToOptions()
+			if err != nil {
+				return err
+			}
+			return o.Run(context.Background())
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (f *RunMonitorTestsFlags) BindFlags(flags *pflag.FlagSet) {
+	monitorNames := defaultmonitortests.ListAllMonitorTests()
+
+	flags.StringVar(&f.FromArtifacts, "from-artifacts", f.FromArtifacts, "Directory holding the unpacked artifacts of a CI run, searched recursively for intervals files.")
+	flags.StringVar(&f.ArtifactDir, "artifact-dir", f.ArtifactDir, "The directory where junit and monitor test output will be written, mirroring the layout of --from-artifacts.")
+	flags.StringVar(&f.JUnitSuiteName, "junit-suite-name", f.JUnitSuiteName, "The junit suite name to report monitor tests under.")
+	flags.StringVar(&f.ClusterStability, "cluster-stability", f.ClusterStability, "cluster stability during the original run: Stable or Disruptive.")
+	flags.StringSliceVar(&f.ExactMonitorTests, "monitor", f.ExactMonitorTests,
+		fmt.Sprintf("list of exactly which monitors to run. All others will be disabled.  Current monitors are: [%s]", strings.Join(monitorNames, ", ")))
+	flags.StringSliceVar(&f.DisableMonitorTests, "disable-monitor", f.DisableMonitorTests, "list of monitors to disable.  Defaults for others will be honored.")
+	flags.StringSliceVar(&f.PathologicalEventAllowanceFiles, "pathological-event-allowances", f.PathologicalEventAllowanceFiles, "YAML or JSON files of additional pathological event matchers allowing known repeated events.")
+
+	flags.StringVar(&f.JobTypeOverrides.Platform, "platform", f.JobTypeOverrides.Platform, "Override the platform of the cluster under test (aws, azure, gcp, metal, vsphere, etc)")
+	flags.StringVar(&f.JobTypeOverrides.Network, "network", f.JobTypeOverrides.Network, "Override the network plugin of the cluster under test (ovn, sdn)")
+	flags.StringVar(&f.JobTypeOverrides.Release, "release", f.JobTypeOverrides.Release, "Override the release of the cluster under test")
+	flags.StringVar(&f.JobTypeOverrides.FromRelease, "from-release", f.JobTypeOverrides.FromRelease, "Override the release the cluster under test was upgraded from")
+	flags.StringVar(&f.JobTypeOverrides.Architecture, "arch", f.JobTypeOverrides.Architecture, "Override the architecture of the cluster under test")
+	flags.StringVar(&f.JobTypeOverrides.Topology, "topology", f.JobTypeOverrides.Topology, "Override the topology of the cluster under test (ha, single)")
+}
+
+func (f *RunMonitorTestsFlags) ToOptions() (*RunMonitorTestsOptions, error) {
+	if len(f.FromArtifacts) == 0 {
+		return nil, fmt.Errorf("missing --from-artifacts")
+	}
+	if len(f.ArtifactDir) == 0 {
+		return nil, fmt.Errorf("missing --artifact-dir")
+	}
+
+	runs, err := findArtifactRuns(filepath.Clean(f.FromArtifacts), filepath.Clean(f.ArtifactDir))
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return nil, fmt.Errorf("no %s*.json intervals files found in %s", intervalsFilePrefix, f.FromArtifacts)
+	}
+
+	replays := []artifactReplay{}
+	for _, run := range runs {
+		clusterData, err := clusterDataFor(run, f.JobTypeOverrides)
+		if err != nil {
+			return nil, err
+		}
+
+		relativeDir, err := filepath.Rel(f.FromArtifacts, filepath.Dir(run.IntervalsFile))
+		if err != nil {
+			return nil, err
+		}
+		replayFlags := replay.NewReplayMonitorFlags(f.IOStreams)
+		replayFlags.IntervalsFile = run.IntervalsFile
+		replayFlags.ArtifactDir = filepath.Join(f.ArtifactDir, relativeDir)
+		replayFlags.JUnitSuiteName = f.JUnitSuiteName
+		replayFlags.ClusterStability = f.ClusterStability
+		replayFlags.ExactMonitorTests = f.ExactMonitorTests
+		replayFlags.DisableMonitorTests = f.DisableMonitorTests
+		replayFlags.PathologicalEventAllowanceFiles = f.PathologicalEventAllowanceFiles
+
+		replays = append(replays, artifactReplay{
+			artifactRun: run,
+			clusterData: clusterData,
+			flags:       replayFlags,
+		})
+	}
+
+	return &RunMonitorTestsOptions{
+		replays:   replays,
+		IOStreams: f.IOStreams,
+	}, nil
+}
+
+type artifactReplay struct {
+	artifactRun
+	clusterData *platformidentification.ClusterData
+	flags       *replay.ReplayMonitorFlags
+}
+
+type RunMonitorTestsOptions struct {
+	replays []artifactReplay
+
+	genericclioptions.IOStreams
+}
+
+func (o *RunMonitorTestsOptions) Run(ctx context.Context) error {
+	errs := []error{}
+	for _, r := range o.replays {
+		fmt.Fprintf(o.Out, "Running monitor tests against %s with cluster data from %q: %+v\n", r.IntervalsFile, r.ClusterDataFile, r.clusterData.JobType)
+		// monitor tests have no cluster to ask for the job type, they get it from the cluster data instead.
+		replayCtx := platformidentification.WithClusterDataOverride(ctx, r.clusterData)
+
+		// monitor tests hold state, every run gets a fresh set of them.
+		replayOptions, err := r.flags.ToOptions()
+		if err != nil {
+			return err
+		}
+		if err := replayOptions.Run(replayCtx); err != nil {
+			fmt.Fprintf(o.ErrOut, "%s: %v\n", r.IntervalsFile, err)
+			errs = append(errs, fmt.Errorf("%s: %w", r.IntervalsFile, err))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,84 @@
+package dev
+
+import (
+	"context"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/openshift/origin/pkg/monitor/backenddisruption"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+func TestRunMonitorTestsFromArtifacts(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	artifactsDir := t.TempDir()
+	junitDir := filepath.Join(artifactsDir, "e2e-aws", "openshift-e2e-test", "artifacts", "junit")
+	outputDir := filepath.Join(t.TempDir(), "output")
+
+	kubeAPINewConnections := backenddisruption.APIServerBackendLocator("kube-api", monitorapi.NewConnectionType)
+	intervals := monitorapi.Intervals{
+		monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Info).Locator(kubeAPINewConnections).
+			Message(monitorapi.NewMessage().Reason(monitorapi.DisruptionEndedEventReason).HumanMessage("started responding")).
+			Build(start, start.Add(time.Hour)),
+		monitorapi.NewInterval(monitorapi.SourceAlert, monitorapi.Warning).
+			Locator(monitorapi.Locator{
+				Type: monitorapi.LocatorTypeAlert,
+				Keys: map[monitorapi.LocatorKey]string{
+					monitorapi.LocatorAlertKey:     "FakeAlert",
+					monitorapi.LocatorNamespaceKey: "openshift-fake",
+				},
+			}).
+			Message(monitorapi.NewMessage().HumanMessage("fake alert").
+				WithAnnotation(monitorapi.AnnotationAlertState, "firing").
+				WithAnnotation(monitorapi.AnnotationSeverity, "warning")).
+			Build(start.Add(10*time.Minute), start.Add(20*time.Minute)),
+	}
+	// the alert tests find firing alerts by the state in the legacy message.
+	intervals[1].Message = `alertstate="firing" severity="warning" fake alert`
+	require.NoError(t, os.MkdirAll(junitDir, 0755))
+	require.NoError(t, monitorserialization.EventsToFile(filepath.Join(junitDir, "e2e-events_20240101-100000.json"), intervals))
+	writeArtifact(t, filepath.Join(junitDir, "cluster-data_20240101-100000.json"), `{"Release": "4.15", "Platform": "aws", "Architecture": "amd64", "Network": "sdn", "Topology": "ha"}`)
+
+	f := NewRunMonitorTestsFlags(genericclioptions.NewTestIOStreamsDiscard())
+	f.FromArtifacts = artifactsDir
+	f.ArtifactDir = outputDir
+	f.ExactMonitorTests = []string{"legacy-test-framework-invariants", "apiserver-availability"}
+	o, err := f.ToOptions()
+	require.NoError(t, err)
+	// an alert that never fired before fails its test.
+	require.Error(t, o.Run(context.Background()))
+
+	junitFiles, err := filepath.Glob(filepath.Join(outputDir, "e2e-aws", "openshift-e2e-test", "artifacts", "junit", "e2e-monitor-tests_*.xml"))
+	require.NoError(t, err)
+	require.Len(t, junitFiles, 1)
+	content, err := os.ReadFile(junitFiles[0])
+	require.NoError(t, err)
+	suite := &junitapi.JUnitTestSuite{}
+	require.NoError(t, xml.Unmarshal(content, suite))
+
+	results := map[string]string{}
+	for _, junit := range suite.TestCases {
+		switch {
+		case junit.SkipMessage != nil:
+			results[junit.Name] = "skip"
+		case junit.FailureOutput != nil:
+			results[junit.Name] = "fail"
+		default:
+			results[junit.Name] = "pass"
+		}
+	}
+	assert.Equal(t, "pass", results[`[Jira:"Test Framework"] monitor test legacy-test-framework-invariants setup`])
+	assert.Equal(t, "pass", results[`[Jira:"kube-apiserver"] monitor test apiserver-availability setup`])
+	assert.Equal(t, "fail", results["[sig-trt][invariant] No new alerts should be firing"])
+	assert.Equal(t, "pass", results["[sig-api-machinery] disruption/kube-api connection/new should be available throughout the test"])
+	assert.Equal(t, "skip", results["[sig-api-machinery] disruption/kube-api connection/reused should be available throughout the test"])
+}
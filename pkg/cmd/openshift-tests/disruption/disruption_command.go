@@ -2,6 +2,8 @@ package disruption
 
 import (
 	poll_service "github.com/openshift/origin/pkg/cmd/openshift-tests/disruption/poll-service"
+	"github.com/openshift/origin/pkg/cmd/openshift-tests/disruption/run"
+	serve_records "github.com/openshift/origin/pkg/cmd/openshift-tests/disruption/serve-records"
 	watch_endpointslice "github.com/openshift/origin/pkg/cmd/openshift-tests/disruption/watch-endpointslice"
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -16,6 +18,8 @@ func NewDisruptionCommand(streams genericclioptions.IOStreams) *cobra.Command {
 	cmd.AddCommand(
 		watch_endpointslice.NewWatchEndpointSlice(streams),
 		poll_service.NewPollService(streams),
+		run.NewRunCommand(streams),
+		serve_records.NewServeRecords(streams),
 	)
 	return cmd
 }
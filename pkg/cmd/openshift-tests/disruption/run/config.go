@@ -0,0 +1,475 @@
+package run
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/yaml"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	backendsampler "github.com/openshift/origin/pkg/disruption/backend/sampler"
+	"github.com/openshift/origin/pkg/disruption/ci"
+	"github.com/openshift/origin/pkg/disruption/sampler"
+	"github.com/openshift/origin/pkg/monitor/backenddisruption"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+const (
+	defaultPath     = "/"
+	defaultInterval = time.Second
+	defaultTimeout  = 15 * time.Second
+
+	defaultQuietPeriod = 30 * time.Second
+)
+
+// BackendList is the content of a backend config file, the file may be YAML or JSON.
+type BackendList struct {
+	Backends []Backend `json:"backends"`
+}
+
+// Backend is a backend to sample, it is reached either by URL or through an OpenShift route.
+type Backend struct {
+	// Name identifies the backend, the disruption backend name in the intervals and the
+	// backend-disruption file is built from it, the connection type and the protocol.
+	Name string `json:"name"`
+	// URL is the scheme and host of the backend, https://my-app.example.com for instance.  A backend
+	// that is probed below HTTP uses the scheme of its protocol instead, tcp://10.0.0.1:30080,
+	// dns://kubernetes.default.svc or grpc://etcd.example.com:2379.
+	URL string `json:"url,omitempty"`
+	// Route is the route the backend is exposed through, its host is looked up once at start.
+	Route *RouteReference `json:"route,omitempty"`
+	// Path is the request path that is sampled, / by default.
+	Path string `json:"path,omitempty"`
+
+	// ExpectedStatusCode is the status code a healthy backend answers with, any 2xx or 3xx by default.
+	ExpectedStatusCode int `json:"expectedStatusCode,omitempty"`
+	// ExpectedBodyRegex, if set, must match the body a healthy backend answers with.
+	ExpectedBodyRegex string `json:"expectedBodyRegex,omitempty"`
+
+	// ConnectionTypes are the connection types to sample with, each gets its own sampler, both by default.
+	ConnectionTypes []monitorapi.BackendConnectionType `json:"connectionTypes,omitempty"`
+	// Protocol is http1, http2, or one of tcp, dns and grpc to probe the backend below HTTP, http1
+	// by default.  tcp opens a new connection, dns resolves the host of the url, and grpc calls
+	// the standard gRPC health service.
+	Protocol backend.ProtocolType `json:"protocol,omitempty"`
+	// GRPCService is the service the gRPC health service is asked about, the whole server by default.
+	GRPCService string `json:"grpcService,omitempty"`
+	// Interval is how often the backend is sampled, 1s by default.
+	Interval metav1.Duration `json:"interval,omitempty"`
+	// Timeout is how long a single sample may take, 15s by default.
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+	// AdaptiveSampling, if set, steps up to a faster rate while an outage is suspected.
+	AdaptiveSampling *AdaptiveSampling `json:"adaptiveSampling,omitempty"`
+
+	Auth Auth `json:"auth,omitempty"`
+}
+
+// AdaptiveSampling measures short disruptions with a sub-second resolution.
+type AdaptiveSampling struct {
+	// Interval is how often the backend is sampled after a failed, or slow, sample, it must be
+	// shorter than the regular interval.
+	Interval metav1.Duration `json:"interval"`
+	// QuietPeriod is how long the samples must be healthy to step back down, 30s by default.
+	QuietPeriod metav1.Duration `json:"quietPeriod,omitempty"`
+	// LatencyThreshold, if set, is the round trip latency above which a successful sample is slow.
+	LatencyThreshold metav1.Duration `json:"latencyThreshold,omitempty"`
+}
+
+type RouteReference struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+type AuthSource string
+
+const (
+	// AuthSourceNone sends anonymous requests
+	AuthSourceNone AuthSource = "None"
+	// AuthSourceKubeconfig uses the credentials of the kubeconfig, they are only sent to the
+	// kube-apiserver of the kubeconfig unless Auth.AllowNonAPIServerHost is set
+	AuthSourceKubeconfig AuthSource = "Kubeconfig"
+	// AuthSourceBearerTokenFile sends the token in Auth.BearerTokenFile
+	AuthSourceBearerTokenFile AuthSource = "BearerTokenFile"
+)
+
+// Auth is how the samplers authenticate to the backend, and verify its certificate.
+type Auth struct {
+	// Source is where the credentials come from, None by default.
+	Source          AuthSource `json:"source,omitempty"`
+	BearerTokenFile string     `json:"bearerTokenFile,omitempty"`
+	// AllowNonAPIServerHost sends the credentials of the kubeconfig, usually cluster-admin, to a
+	// backend that is not the kube-apiserver of the kubeconfig, a route for instance.
+	AllowNonAPIServerHost bool `json:"allowNonAPIServerHost,omitempty"`
+
+	// CAFile is the CA bundle the certificate of the backend is verified with, by default the
+	// system roots are used, or the CA of the kubeconfig with the Kubeconfig source.
+	CAFile                string `json:"caFile,omitempty"`
+	InsecureSkipTLSVerify bool   `json:"insecureSkipTLSVerify,omitempty"`
+}
+
+// ReadBackendList reads and validates a backend config file.
+func ReadBackendList(filename string) (*BackendList, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	list := &BackendList{}
+	if err := yaml.UnmarshalStrict(content, list); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filename, err)
+	}
+	if err := list.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid backend config %s: %w", filename, err)
+	}
+	return list, nil
+}
+
+func (l *BackendList) Validate() error {
+	if len(l.Backends) == 0 {
+		return fmt.Errorf("must specify at least one backend")
+	}
+	names := sets.NewString()
+	for _, b := range l.Backends {
+		if err := b.Validate(); err != nil {
+			return err
+		}
+		if names.Has(b.Name) {
+			return fmt.Errorf("backend %q is specified more than once", b.Name)
+		}
+		names.Insert(b.Name)
+	}
+	return nil
+}
+
+func (b Backend) Validate() error {
+	if len(b.Name) == 0 {
+		return fmt.Errorf("must specify a name for every backend")
+	}
+	if b.isProbed() {
+		return b.validateProbed()
+	}
+	switch {
+	case len(b.URL) > 0 && b.Route != nil:
+		return fmt.Errorf("%q must specify either url or route, not both", b.Name)
+	case len(b.URL) > 0:
+		u, err := url.Parse(b.URL)
+		if err != nil {
+			return fmt.Errorf("%q url is invalid: %w", b.Name, err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+			return fmt.Errorf("%q url must be of the form http(s)://host[:port], got: %q", b.Name, b.URL)
+		}
+		if len(u.Path) > 0 && u.Path != "/" {
+			return fmt.Errorf("%q url must not have a path, use path instead", b.Name)
+		}
+	case b.Route != nil:
+		if len(b.Route.Namespace) == 0 || len(b.Route.Name) == 0 {
+			return fmt.Errorf("%q route must specify a namespace and a name", b.Name)
+		}
+	default:
+		return fmt.Errorf("%q must specify either url or route", b.Name)
+	}
+	if len(b.ExpectedBodyRegex) > 0 {
+		if _, err := regexp.Compile(b.ExpectedBodyRegex); err != nil {
+			return fmt.Errorf("%q expectedBodyRegex is invalid: %w", b.Name, err)
+		}
+	}
+	for _, connectionType := range b.ConnectionTypes {
+		if connectionType != monitorapi.NewConnectionType && connectionType != monitorapi.ReusedConnectionType {
+			return fmt.Errorf("%q connectionTypes must be %s or %s, got: %q", b.Name, monitorapi.NewConnectionType, monitorapi.ReusedConnectionType, connectionType)
+		}
+	}
+	if len(b.Protocol) > 0 && b.Protocol != backend.ProtocolHTTP1 && b.Protocol != backend.ProtocolHTTP2 {
+		return fmt.Errorf("%q protocol must be one of %s, %s, %s, %s or %s, got: %q", b.Name,
+			backend.ProtocolHTTP1, backend.ProtocolHTTP2, backend.ProtocolTCP, backend.ProtocolDNS, backend.ProtocolGRPC, b.Protocol)
+	}
+	if len(b.GRPCService) > 0 {
+		return fmt.Errorf("%q grpcService is only valid with the %s protocol", b.Name, backend.ProtocolGRPC)
+	}
+	if err := b.validateSampling(); err != nil {
+		return err
+	}
+
+	if b.Auth.AllowNonAPIServerHost && b.Auth.Source != AuthSourceKubeconfig {
+		return fmt.Errorf("%q auth allowNonAPIServerHost is only valid with the %s source", b.Name, AuthSourceKubeconfig)
+	}
+	switch b.Auth.Source {
+	case "", AuthSourceNone:
+	case AuthSourceKubeconfig:
+		// the host of a route is never the kube-apiserver
+		if b.Route != nil && !b.Auth.AllowNonAPIServerHost {
+			return fmt.Errorf("%q auth must specify allowNonAPIServerHost to send the kubeconfig credentials to a route", b.Name)
+		}
+	case AuthSourceBearerTokenFile:
+		if len(b.Auth.BearerTokenFile) == 0 {
+			return fmt.Errorf("%q auth must specify a bearerTokenFile", b.Name)
+		}
+		// the bearer token is only sent over a verified connection
+		if len(b.Auth.CAFile) == 0 && !b.Auth.InsecureSkipTLSVerify {
+			return fmt.Errorf("%q auth must specify a caFile or insecureSkipTLSVerify with a bearerTokenFile", b.Name)
+		}
+	default:
+		return fmt.Errorf("%q auth source must be one of %s, %s or %s, got: %q", b.Name, AuthSourceNone, AuthSourceKubeconfig, AuthSourceBearerTokenFile, b.Auth.Source)
+	}
+	return nil
+}
+
+func (b Backend) validateSampling() error {
+	if b.Interval.Duration < 0 || b.Timeout.Duration < 0 {
+		return fmt.Errorf("%q interval and timeout must not be negative", b.Name)
+	}
+	if b.AdaptiveSampling != nil {
+		if b.AdaptiveSampling.Interval.Duration <= 0 {
+			return fmt.Errorf("%q adaptiveSampling must specify an interval", b.Name)
+		}
+		if err := b.adaptiveConfig().Validate(b.sampleInterval()); err != nil {
+			return fmt.Errorf("%q adaptiveSampling is invalid: %w", b.Name, err)
+		}
+	}
+	return nil
+}
+
+// isProbed returns true if the backend is probed below HTTP.
+func (b Backend) isProbed() bool {
+	switch b.Protocol {
+	case backend.ProtocolTCP, backend.ProtocolDNS, backend.ProtocolGRPC:
+		return true
+	}
+	return false
+}
+
+func (b Backend) validateProbed() error {
+	if b.Route != nil || len(b.URL) == 0 {
+		return fmt.Errorf("%q must specify a url with the %s protocol", b.Name, b.Protocol)
+	}
+	u, err := url.Parse(b.URL)
+	if err != nil {
+		return fmt.Errorf("%q url is invalid: %w", b.Name, err)
+	}
+	if u.Scheme != string(b.Protocol) || len(u.Hostname()) == 0 || (len(u.Path) > 0 && u.Path != "/") {
+		return fmt.Errorf("%q url must be of the form %s://host[:port], got: %q", b.Name, b.Protocol, b.URL)
+	}
+	if b.Protocol != backend.ProtocolDNS && len(u.Port()) == 0 {
+		return fmt.Errorf("%q url must specify a port with the %s protocol", b.Name, b.Protocol)
+	}
+	if len(b.Path) > 0 || b.ExpectedStatusCode != 0 || len(b.ExpectedBodyRegex) > 0 {
+		return fmt.Errorf("%q path, expectedStatusCode and expectedBodyRegex are only valid with http1 or http2", b.Name)
+	}
+	if len(b.GRPCService) > 0 && b.Protocol != backend.ProtocolGRPC {
+		return fmt.Errorf("%q grpcService is only valid with the %s protocol", b.Name, backend.ProtocolGRPC)
+	}
+	for _, connectionType := range b.ConnectionTypes {
+		switch {
+		case connectionType == monitorapi.NewConnectionType:
+		case connectionType == monitorapi.ReusedConnectionType && b.Protocol == backend.ProtocolGRPC:
+		default:
+			return fmt.Errorf("%q connectionTypes must be %s with the %s protocol, got: %q", b.Name, monitorapi.NewConnectionType, b.Protocol, connectionType)
+		}
+	}
+	if err := b.validateSampling(); err != nil {
+		return err
+	}
+
+	if len(b.Auth.Source) > 0 && b.Auth.Source != AuthSourceNone {
+		return fmt.Errorf("%q auth source must be %s with the %s protocol, got: %q", b.Name, AuthSourceNone, b.Protocol, b.Auth.Source)
+	}
+	if b.Protocol != backend.ProtocolGRPC && (len(b.Auth.CAFile) > 0 || b.Auth.InsecureSkipTLSVerify) {
+		return fmt.Errorf("%q caFile and insecureSkipTLSVerify are not valid with the %s protocol", b.Name, b.Protocol)
+	}
+	return nil
+}
+
+// NeedsCluster returns true if the backend can only be reached with the cluster credentials.
+func (b Backend) NeedsCluster() bool {
+	return b.Route != nil || b.Auth.Source == AuthSourceKubeconfig
+}
+
+// RestConfig returns the rest Config the samplers of the backend are created from, clusterConfig
+// is only used, and may be nil, when NeedsCluster is false.
+func (b Backend) RestConfig(clusterConfig *rest.Config) (*rest.Config, error) {
+	host := strings.TrimSuffix(b.URL, "/")
+	if b.Route != nil {
+		var err error
+		host, err = backenddisruption.NewRouteHostGetter(clusterConfig, b.Route.Namespace, b.Route.Name).GetHost()
+		if err != nil {
+			return nil, fmt.Errorf("%q failed to get the host of route %s/%s: %w", b.Name, b.Route.Namespace, b.Route.Name, err)
+		}
+	}
+
+	config := &rest.Config{}
+	switch b.Auth.Source {
+	case AuthSourceKubeconfig:
+		if !b.Auth.AllowNonAPIServerHost && !sameHost(host, clusterConfig.Host) {
+			return nil, fmt.Errorf("%q is not the kube-apiserver %s of the kubeconfig, auth must specify allowNonAPIServerHost to send it the kubeconfig credentials", b.Name, clusterConfig.Host)
+		}
+		config = rest.CopyConfig(clusterConfig)
+	case AuthSourceBearerTokenFile:
+		config.BearerTokenFile = b.Auth.BearerTokenFile
+	}
+	config.Host = host
+	config.APIPath = ""
+	if len(b.Auth.CAFile) > 0 {
+		config.TLSClientConfig.CAFile = b.Auth.CAFile
+		config.TLSClientConfig.CAData = nil
+	}
+	if b.Auth.InsecureSkipTLSVerify {
+		config.TLSClientConfig.Insecure = true
+		config.TLSClientConfig.CAFile = ""
+		config.TLSClientConfig.CAData = nil
+	}
+	return config, nil
+}
+
+// sameHost returns true if both URLs have the same scheme, host and port.
+func sameHost(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	port := func(u *url.URL) string {
+		switch {
+		case len(u.Port()) > 0:
+			return u.Port()
+		case u.Scheme == "http":
+			return "80"
+		default:
+			return "443"
+		}
+	}
+	return ua.Scheme == ub.Scheme && strings.EqualFold(ua.Hostname(), ub.Hostname()) && port(ua) == port(ub)
+}
+
+// TestConfigurations returns the configuration of a sampler for each connection type of the backend.
+func (b Backend) TestConfigurations() []ci.TestConfiguration {
+	connectionTypes := b.connectionTypes()
+	protocol := b.Protocol
+	if len(protocol) == 0 {
+		protocol = backend.ProtocolHTTP1
+	}
+	path := b.Path
+	if len(path) == 0 {
+		path = defaultPath
+	}
+	ret := []ci.TestConfiguration{}
+	for _, connectionType := range connectionTypes {
+		ret = append(ret, ci.TestConfiguration{
+			TestDescriptor: ci.TestDescriptor{
+				TargetServer:     ci.ServerNameType(b.Name),
+				LoadBalancerType: backend.ExternalLoadBalancerType,
+				ConnectionType:   connectionType,
+				Protocol:         protocol,
+			},
+			Path:               path,
+			Timeout:            b.timeout(),
+			SampleInterval:     b.sampleInterval(),
+			AdaptiveSampling:   b.adaptiveConfig(),
+			ExpectedStatusCode: b.ExpectedStatusCode,
+			ExpectedBodyRegex:  b.ExpectedBodyRegex,
+		})
+	}
+	return ret
+}
+
+// ProbeTestConfigurations returns the configuration of a sampler for each connection type of a
+// backend that is probed below HTTP, every sampler gets its own Prober.
+func (b Backend) ProbeTestConfigurations() ([]ci.ProbeTestConfiguration, error) {
+	u, err := url.Parse(b.URL)
+	if err != nil {
+		return nil, fmt.Errorf("%q url is invalid: %w", b.Name, err)
+	}
+
+	ret := []ci.ProbeTestConfiguration{}
+	for _, connectionType := range b.connectionTypes() {
+		var prober backendsampler.Prober
+		switch b.Protocol {
+		case backend.ProtocolTCP:
+			prober = backendsampler.NewTCPProber(u.Host)
+		case backend.ProtocolDNS:
+			prober = backendsampler.NewDNSProber(u.Hostname(), nil)
+		case backend.ProtocolGRPC:
+			transportCredentials := insecure.NewCredentials()
+			switch {
+			case b.Auth.InsecureSkipTLSVerify:
+				transportCredentials = credentials.NewTLS(&tls.Config{InsecureSkipVerify: true})
+			case len(b.Auth.CAFile) > 0:
+				transportCredentials, err = credentials.NewClientTLSFromFile(b.Auth.CAFile, "")
+				if err != nil {
+					return nil, fmt.Errorf("%q failed to read caFile: %w", b.Name, err)
+				}
+			}
+			prober = backendsampler.NewGRPCHealthProber(u.Host, b.GRPCService, connectionType, grpc.WithTransportCredentials(transportCredentials))
+		default:
+			return nil, fmt.Errorf("%q protocol %s can not be probed", b.Name, b.Protocol)
+		}
+
+		ret = append(ret, ci.ProbeTestConfiguration{
+			TestDescriptor: ci.TestDescriptor{
+				TargetServer:     ci.ServerNameType(b.Name),
+				LoadBalancerType: backend.ExternalLoadBalancerType,
+				ConnectionType:   connectionType,
+				Protocol:         b.Protocol,
+			},
+			Prober:           prober,
+			Timeout:          b.timeout(),
+			SampleInterval:   b.sampleInterval(),
+			AdaptiveSampling: b.adaptiveConfig(),
+		})
+	}
+	return ret, nil
+}
+
+func (b Backend) connectionTypes() []monitorapi.BackendConnectionType {
+	switch {
+	case len(b.ConnectionTypes) > 0:
+		return b.ConnectionTypes
+	case b.Protocol == backend.ProtocolTCP || b.Protocol == backend.ProtocolDNS:
+		// every probe opens a new connection, or resolves the name again
+		return []monitorapi.BackendConnectionType{monitorapi.NewConnectionType}
+	default:
+		return []monitorapi.BackendConnectionType{monitorapi.NewConnectionType, monitorapi.ReusedConnectionType}
+	}
+}
+
+func (b Backend) timeout() time.Duration {
+	if b.Timeout.Duration == 0 {
+		return defaultTimeout
+	}
+	return b.Timeout.Duration
+}
+
+func (b Backend) sampleInterval() time.Duration {
+	if b.Interval.Duration == 0 {
+		return defaultInterval
+	}
+	return b.Interval.Duration
+}
+
+func (b Backend) adaptiveConfig() sampler.AdaptiveConfig {
+	if b.AdaptiveSampling == nil {
+		return sampler.AdaptiveConfig{}
+	}
+	quietPeriod := b.AdaptiveSampling.QuietPeriod.Duration
+	if quietPeriod == 0 {
+		quietPeriod = defaultQuietPeriod
+	}
+	return sampler.AdaptiveConfig{
+		Interval:         b.AdaptiveSampling.Interval.Duration,
+		QuietPeriod:      quietPeriod,
+		LatencyThreshold: b.AdaptiveSampling.LatencyThreshold.Duration,
+	}
+}
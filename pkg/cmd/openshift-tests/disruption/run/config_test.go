@@ -0,0 +1,369 @@
+package run
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestReadBackendList(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{
+			name: "url",
+			content: `
+backends:
+- name: my-app
+  url: https://my-app.example.com
+  path: /healthz
+  expectedStatusCode: 200
+  expectedBodyRegex: ^ok$
+  interval: 500ms
+`,
+		},
+		{
+			name: "route with kubeconfig credentials",
+			content: `
+backends:
+- name: my-app
+  route:
+    namespace: my-namespace
+    name: my-app
+  auth:
+    source: Kubeconfig
+    allowNonAPIServerHost: true
+`,
+		},
+		{
+			name: "route with kubeconfig credentials without allowing it",
+			content: `
+backends:
+- name: my-app
+  route:
+    namespace: my-namespace
+    name: my-app
+  auth:
+    source: Kubeconfig
+`,
+			err: "must specify allowNonAPIServerHost",
+		},
+		{
+			name:    "no backends",
+			content: `backends: []`,
+			err:     "must specify at least one backend",
+		},
+		{
+			name: "unknown field",
+			content: `
+backends:
+- name: my-app
+  url: https://my-app.example.com
+  method: POST
+`,
+			err: "unknown field",
+		},
+		{
+			name: "duplicate names",
+			content: `
+backends:
+- name: my-app
+  url: https://my-app.example.com
+- name: my-app
+  url: https://my-other-app.example.com
+`,
+			err: "specified more than once",
+		},
+		{
+			name: "url and route",
+			content: `
+backends:
+- name: my-app
+  url: https://my-app.example.com
+  route:
+    namespace: my-namespace
+    name: my-app
+`,
+			err: "not both",
+		},
+		{
+			name: "url with a path",
+			content: `
+backends:
+- name: my-app
+  url: https://my-app.example.com/healthz
+`,
+			err: "must not have a path",
+		},
+		{
+			name: "invalid body regex",
+			content: `
+backends:
+- name: my-app
+  url: https://my-app.example.com
+  expectedBodyRegex: "(ok"
+`,
+			err: "expectedBodyRegex is invalid",
+		},
+		{
+			name: "unknown protocol",
+			content: `
+backends:
+- name: my-app
+  url: https://my-app.example.com
+  protocol: http3
+`,
+			err: "protocol must be",
+		},
+		{
+			name: "adaptive sampling",
+			content: `
+backends:
+- name: my-app
+  url: https://my-app.example.com
+  adaptiveSampling:
+    interval: 100ms
+    latencyThreshold: 2s
+`,
+		},
+		{
+			name: "adaptive sampling slower than the interval",
+			content: `
+backends:
+- name: my-app
+  url: https://my-app.example.com
+  interval: 500ms
+  adaptiveSampling:
+    interval: 1s
+`,
+			err: "must be shorter than the sample interval",
+		},
+		{
+			name: "tcp",
+			content: `
+backends:
+- name: my-node-port
+  protocol: tcp
+  url: tcp://10.0.0.1:30080
+`,
+		},
+		{
+			name: "grpc with a verified connection",
+			content: `
+backends:
+- name: etcd
+  protocol: grpc
+  url: grpc://etcd.example.com:2379
+  grpcService: etcd
+  auth:
+    caFile: /etc/etcd/ca.crt
+`,
+		},
+		{
+			name: "probed with a url of another protocol",
+			content: `
+backends:
+- name: my-node-port
+  protocol: tcp
+  url: https://10.0.0.1:30080
+`,
+			err: "url must be of the form tcp://host[:port]",
+		},
+		{
+			name: "tcp without a port",
+			content: `
+backends:
+- name: my-node-port
+  protocol: tcp
+  url: tcp://10.0.0.1
+`,
+			err: "must specify a port",
+		},
+		{
+			name: "dns with reused connections",
+			content: `
+backends:
+- name: cluster-dns
+  protocol: dns
+  url: dns://kubernetes.default.svc
+  connectionTypes: [reused]
+`,
+			err: "connectionTypes must be new",
+		},
+		{
+			name: "probed with an http check",
+			content: `
+backends:
+- name: my-node-port
+  protocol: tcp
+  url: tcp://10.0.0.1:30080
+  expectedStatusCode: 200
+`,
+			err: "only valid with http1 or http2",
+		},
+		{
+			name: "bearer token without verification",
+			content: `
+backends:
+- name: my-app
+  url: https://my-app.example.com
+  auth:
+    source: BearerTokenFile
+    bearerTokenFile: /var/run/secrets/token
+`,
+			err: "must specify a caFile or insecureSkipTLSVerify",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "backends.yaml")
+			if err := os.WriteFile(filename, []byte(test.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := ReadBackendList(filename)
+			switch {
+			case len(test.err) == 0 && err != nil:
+				t.Errorf("expected no error, but got: %v", err)
+			case len(test.err) > 0 && (err == nil || !strings.Contains(err.Error(), test.err)):
+				t.Errorf("expected an error containing %q, but got: %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestBackendTestConfigurations(t *testing.T) {
+	b := Backend{Name: "my-app", URL: "https://my-app.example.com"}
+	configurations := b.TestConfigurations()
+	if len(configurations) != 2 {
+		t.Fatalf("expected a configuration for each connection type, but got: %d", len(configurations))
+	}
+	for i, connectionType := range []monitorapi.BackendConnectionType{monitorapi.NewConnectionType, monitorapi.ReusedConnectionType} {
+		tc := configurations[i]
+		if tc.ConnectionType != connectionType {
+			t.Errorf("expected connection type %s, but got: %s", connectionType, tc.ConnectionType)
+		}
+		if tc.Protocol != backend.ProtocolHTTP1 || tc.Path != "/" || tc.SampleInterval != time.Second || tc.Timeout != 15*time.Second {
+			t.Errorf("expected the defaults, but got: %+v", tc)
+		}
+		if want, got := "my-app-http1-external-lb-"+string(connectionType)+"-connections", tc.Name(); want != got {
+			t.Errorf("expected name %q, but got: %q", want, got)
+		}
+	}
+}
+
+func TestBackendRestConfig(t *testing.T) {
+	b := Backend{
+		Name: "my-app",
+		URL:  "https://my-app.example.com/",
+		Auth: Auth{Source: AuthSourceBearerTokenFile, BearerTokenFile: "/var/run/secrets/token", InsecureSkipTLSVerify: true},
+	}
+	config, err := b.RestConfig(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Host != "https://my-app.example.com" {
+		t.Errorf("expected the host without the trailing slash, but got: %q", config.Host)
+	}
+	if config.BearerTokenFile != "/var/run/secrets/token" || !config.Insecure {
+		t.Errorf("expected the bearer token file and an insecure connection, but got: %+v", config)
+	}
+}
+
+func TestBackendProbeTestConfigurations(t *testing.T) {
+	tests := []struct {
+		backend         Backend
+		target          string
+		connectionTypes []monitorapi.BackendConnectionType
+	}{
+		{
+			backend:         Backend{Name: "my-node-port", Protocol: backend.ProtocolTCP, URL: "tcp://10.0.0.1:30080"},
+			target:          "tcp://10.0.0.1:30080",
+			connectionTypes: []monitorapi.BackendConnectionType{monitorapi.NewConnectionType},
+		},
+		{
+			backend:         Backend{Name: "cluster-dns", Protocol: backend.ProtocolDNS, URL: "dns://kubernetes.default.svc"},
+			target:          "dns://kubernetes.default.svc",
+			connectionTypes: []monitorapi.BackendConnectionType{monitorapi.NewConnectionType},
+		},
+		{
+			backend:         Backend{Name: "etcd", Protocol: backend.ProtocolGRPC, URL: "grpc://etcd.example.com:2379"},
+			target:          "grpc://etcd.example.com:2379",
+			connectionTypes: []monitorapi.BackendConnectionType{monitorapi.NewConnectionType, monitorapi.ReusedConnectionType},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.backend.Name, func(t *testing.T) {
+			if err := test.backend.Validate(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			configurations, err := test.backend.ProbeTestConfigurations()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(configurations) != len(test.connectionTypes) {
+				t.Fatalf("expected a configuration for each of %v, but got: %d", test.connectionTypes, len(configurations))
+			}
+			for i, tc := range configurations {
+				if err := tc.Validate(); err != nil {
+					t.Errorf("expected a valid configuration, but got: %v", err)
+				}
+				if tc.ConnectionType != test.connectionTypes[i] || tc.Prober.Target() != test.target {
+					t.Errorf("expected a %s prober of %s, but got: %s of %s", test.connectionTypes[i], test.target, tc.ConnectionType, tc.Prober.Target())
+				}
+				if tc.SampleInterval != time.Second || tc.Timeout != 15*time.Second {
+					t.Errorf("expected the defaults, but got: %+v", tc)
+				}
+			}
+		})
+	}
+}
+
+func TestBackendRestConfigKubeconfig(t *testing.T) {
+	clusterConfig := &rest.Config{Host: "https://api.example.com:6443", BearerToken: "cluster-admin"}
+	tests := []struct {
+		name    string
+		backend Backend
+		err     string
+	}{
+		{
+			name:    "kube-apiserver",
+			backend: Backend{Name: "kube-api", URL: "https://API.example.com:6443/", Auth: Auth{Source: AuthSourceKubeconfig}},
+		},
+		{
+			name:    "another host",
+			backend: Backend{Name: "my-app", URL: "https://my-app.example.com", Auth: Auth{Source: AuthSourceKubeconfig}},
+			err:     "is not the kube-apiserver",
+		},
+		{
+			name:    "another port",
+			backend: Backend{Name: "my-app", URL: "https://api.example.com", Auth: Auth{Source: AuthSourceKubeconfig}},
+			err:     "is not the kube-apiserver",
+		},
+		{
+			name:    "another host explicitly allowed",
+			backend: Backend{Name: "my-app", URL: "https://my-app.example.com", Auth: Auth{Source: AuthSourceKubeconfig, AllowNonAPIServerHost: true}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, err := test.backend.RestConfig(clusterConfig)
+			switch {
+			case len(test.err) == 0 && err != nil:
+				t.Fatalf("expected no error, but got: %v", err)
+			case len(test.err) > 0 && (err == nil || !strings.Contains(err.Error(), test.err)):
+				t.Fatalf("expected an error containing %q, but got: %v", test.err, err)
+			case err == nil && config.BearerToken != "cluster-admin":
+				t.Errorf("expected the kubeconfig credentials, but got: %+v", config)
+			}
+		})
+	}
+}
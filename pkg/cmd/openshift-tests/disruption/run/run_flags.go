@@ -0,0 +1,171 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+type RunFlags struct {
+	ConfigFlags *genericclioptions.ConfigFlags
+
+	BackendConfigFile string
+	Duration          time.Duration
+	ArtifactDir       string
+
+	genericclioptions.IOStreams
+}
+
+func NewRunFlags(streams genericclioptions.IOStreams) *RunFlags {
+	return &RunFlags{
+		ConfigFlags: genericclioptions.NewConfigFlags(false),
+		ArtifactDir: ".",
+		IOStreams:   streams,
+	}
+}
+
+func NewRunCommand(ioStreams genericclioptions.IOStreams) *cobra.Command {
+	f := NewRunFlags(ioStreams)
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Sample the availability of the backends in a config file",
+		Long: templates.LongDesc(`
+		Sample the availability of the backends listed in a config file, with the same samplers
+		and disruption accounting CI uses, for the given duration or until interrupted.
+
+		A backend is reached by URL, or through a route of the cluster in the kubeconfig:
+
+		  backends:
+		  - name: my-app
+		    route:
+		      namespace: my-namespace
+		      name: my-app
+		    path: /healthz
+		    expectedStatusCode: 200
+		    expectedBodyRegex: ^ok$
+		    connectionTypes: [new, reused]
+		    protocol: http1
+		    interval: 1s
+		    timeout: 15s
+		    adaptiveSampling:
+		      interval: 100ms
+		      quietPeriod: 30s
+		    auth:
+		      source: None
+
+		The Kubeconfig auth source sends the credentials of the kubeconfig, they are only sent to
+		the kube-apiserver of the kubeconfig unless the backend sets allowNonAPIServerHost.
+
+		A backend that is not served over HTTP is probed with the tcp, dns or grpc protocol, the
+		scheme of its url is the protocol, grpc calls the standard gRPC health service:
+
+		  backends:
+		  - name: my-node-port
+		    protocol: tcp
+		    url: tcp://10.0.0.1:30080
+		  - name: etcd
+		    protocol: grpc
+		    url: grpc://etcd.example.com:2379
+		    grpcService: ""
+		    connectionTypes: [new, reused]
+		    auth:
+		      caFile: /etc/etcd/ca.crt
+
+		The intervals, the backend-disruption and backend-latency files, and a summary of
+		the disruption of each backend are written to the artifact directory.
+		`),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancelFn := context.WithCancel(context.Background())
+			defer cancelFn()
+			abortCh := make(chan os.Signal, 2)
+			go func() {
+				<-abortCh
+				fmt.Fprintf(f.ErrOut, "Interrupted, terminating\n")
+				cancelFn()
+
+				sig := <-abortCh
+				fmt.Fprintf(f.ErrOut, "Interrupted twice, exiting (%s)\n", sig)
+				switch sig {
+				case syscall.SIGINT:
+					os.Exit(130)
+				default:
+					os.Exit(0)
+				}
+			}()
+			signal.Notify(abortCh, syscall.SIGINT, syscall.SIGTERM)
+
+			if err := f.Validate(); err != nil {
+				return err
+			}
+			o, err := f.ToOptions()
+			if err != nil {
+				return err
+			}
+			return o.Run(ctx)
+		},
+	}
+
+	f.BindOptions(cmd.Flags())
+
+	return cmd
+}
+
+func (f *RunFlags) BindOptions(flags *pflag.FlagSet) {
+	flags.StringVar(&f.BackendConfigFile, "config", f.BackendConfigFile, "The YAML or JSON file listing the backends to sample.")
+	flags.DurationVar(&f.Duration, "duration", f.Duration, "How long to sample the backends for, until interrupted if zero.")
+	flags.StringVar(&f.ArtifactDir, "artifact-dir", f.ArtifactDir, "The directory where the intervals, the backend-disruption files and the summary are written.")
+	f.ConfigFlags.AddFlags(flags)
+}
+
+func (f *RunFlags) Validate() error {
+	if len(f.BackendConfigFile) == 0 {
+		return fmt.Errorf("--config must be specified")
+	}
+	if f.Duration < 0 {
+		return fmt.Errorf("--duration must not be negative")
+	}
+	if len(f.ArtifactDir) == 0 {
+		return fmt.Errorf("--artifact-dir must be specified")
+	}
+	return nil
+}
+
+func (f *RunFlags) ToOptions() (*RunOptions, error) {
+	backends, err := ReadBackendList(f.BackendConfigFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// the cluster is only needed to look up routes, or for its credentials
+	var clusterConfig *rest.Config
+	for _, b := range backends.Backends {
+		if !b.NeedsCluster() {
+			continue
+		}
+		if clusterConfig, err = f.ConfigFlags.ToRESTConfig(); err != nil {
+			return nil, fmt.Errorf("backend %q needs a kubeconfig: %w", b.Name, err)
+		}
+		break
+	}
+
+	return &RunOptions{
+		Backends:      backends.Backends,
+		ClusterConfig: clusterConfig,
+		Duration:      f.Duration,
+		ArtifactDir:   f.ArtifactDir,
+		IOStreams:     f.IOStreams,
+	}, nil
+}
@@ -0,0 +1,137 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/disruption/ci"
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/monitortests/testframework/disruptiondiagnosticsserializer"
+	"github.com/openshift/origin/pkg/monitortests/testframework/disruptionlatencyserializer"
+	"github.com/openshift/origin/pkg/monitortests/testframework/disruptionserializer"
+)
+
+type RunOptions struct {
+	Backends []Backend
+	// ClusterConfig is only set if a backend needs the cluster
+	ClusterConfig *rest.Config
+	// Duration is how long to sample for, until the context is done if zero
+	Duration    time.Duration
+	ArtifactDir string
+
+	genericclioptions.IOStreams
+}
+
+func (o *RunOptions) Run(ctx context.Context) error {
+	samplers := []ci.Sampler{}
+	for _, b := range o.Backends {
+		if b.isProbed() {
+			configurations, err := b.ProbeTestConfigurations()
+			if err != nil {
+				return err
+			}
+			for _, tc := range configurations {
+				sampler, err := ci.NewProbeSampler(tc)
+				if err != nil {
+					return fmt.Errorf("failed to create the sampler of %q: %w", b.Name, err)
+				}
+				samplers = append(samplers, sampler)
+			}
+			continue
+		}
+
+		restConfig, err := b.RestConfig(o.ClusterConfig)
+		if err != nil {
+			return err
+		}
+		// one factory per backend, the samplers of a factory share the rest Config
+		factory := ci.NewBackendTestFactory(restConfig)
+		for _, tc := range b.TestConfigurations() {
+			sampler, err := factory.New(tc)
+			if err != nil {
+				return fmt.Errorf("failed to create the sampler of %q: %w", b.Name, err)
+			}
+			samplers = append(samplers, sampler)
+		}
+	}
+	if err := os.MkdirAll(o.ArtifactDir, 0755); err != nil {
+		return err
+	}
+
+	if o.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.Duration)
+		defer cancel()
+	}
+
+	recorder := monitor.NewRecorder()
+	wg := sync.WaitGroup{}
+	for i := range samplers {
+		sampler := samplers[i]
+		url, _ := sampler.GetURL()
+		fmt.Fprintf(o.Out, "Sampling %s at %s\n", sampler.GetDisruptionBackendName(), url)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := sampler.RunEndpointMonitoring(ctx, recorder, nil); err != nil {
+				fmt.Fprintf(o.ErrOut, "Sampler %s failed: %v\n", sampler.GetDisruptionBackendName(), err)
+			}
+		}()
+	}
+	<-ctx.Done()
+	fmt.Fprintf(o.Out, "Waiting for the samplers to finish...\n")
+	wg.Wait()
+
+	return o.writeResults(samplers, recorder.Intervals(time.Time{}, time.Time{}))
+}
+
+func (o *RunOptions) writeResults(samplers []ci.Sampler, intervals monitorapi.Intervals) error {
+	timeSuffix := fmt.Sprintf("_%s", time.Now().UTC().Format("20060102-150405"))
+
+	eventDir := filepath.Join(o.ArtifactDir, monitorapi.EventDir)
+	if err := os.MkdirAll(eventDir, 0755); err != nil {
+		return err
+	}
+	if err := monitorserialization.EventsToFile(filepath.Join(eventDir, fmt.Sprintf("e2e-events%s.json", timeSuffix)), intervals); err != nil {
+		return fmt.Errorf("failed to write the intervals: %w", err)
+	}
+
+	// the same files the monitor tests write at the end of a CI job
+	if err := disruptionserializer.NewDisruptionSummarySerializer().WriteContentToStorage(context.Background(), o.ArtifactDir, timeSuffix, intervals, nil); err != nil {
+		return fmt.Errorf("failed to write the backend disruption: %w", err)
+	}
+	if err := disruptionlatencyserializer.NewDisruptionLatencySerializer().WriteContentToStorage(context.Background(), o.ArtifactDir, timeSuffix, intervals, nil); err != nil {
+		return fmt.Errorf("failed to write the backend latency: %w", err)
+	}
+	if err := disruptiondiagnosticsserializer.NewDisruptionDiagnosticsSerializer().WriteContentToStorage(context.Background(), o.ArtifactDir, timeSuffix, intervals, nil); err != nil {
+		return fmt.Errorf("failed to write the backend disruption diagnostics: %w", err)
+	}
+
+	summary := &bytes.Buffer{}
+	w := tabwriter.NewWriter(summary, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "BACKEND\tURL\tDISRUPTION\tDISRUPTION (MS)\tDISRUPTIONS")
+	disruptionIntervals := intervals.Filter(monitorapi.IsDisruptionEvent)
+	for _, sampler := range samplers {
+		name := sampler.GetDisruptionBackendName()
+		url, _ := sampler.GetURL()
+		duration, messages := monitorapi.BackendDisruptionSeconds(name, disruptionIntervals)
+		milliseconds := monitorapi.BackendDisruptionMilliseconds(name, disruptionIntervals)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\n", name, url, duration, milliseconds, len(messages))
+	}
+	w.Flush()
+
+	fmt.Fprintf(o.Out, "\n%s", summary.String())
+	return os.WriteFile(filepath.Join(o.ArtifactDir, fmt.Sprintf("disruption-summary%s.txt", timeSuffix)), summary.Bytes(), 0644)
+}
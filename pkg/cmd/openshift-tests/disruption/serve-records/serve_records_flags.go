@@ -0,0 +1,110 @@
+package serve_records
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// ServeRecordsFlags is used to run the record server the stateful workload
+// availability monitor test writes to and reads from.
+type ServeRecordsFlags struct {
+	DataDir       string
+	ListenPort    uint16
+	DelayShutdown time.Duration
+
+	genericclioptions.IOStreams
+}
+
+func NewServeRecordsFlags(streams genericclioptions.IOStreams) *ServeRecordsFlags {
+	return &ServeRecordsFlags{
+		ListenPort:    8080,
+		DelayShutdown: 20 * time.Second,
+		IOStreams:     streams,
+	}
+}
+
+func NewServeRecords(ioStreams genericclioptions.IOStreams) *cobra.Command {
+	f := NewServeRecordsFlags(ioStreams)
+	cmd := &cobra.Command{
+		Use:   "serve-records",
+		Short: "Serve records that are durably stored in a data directory",
+		Long: `Serve records that are durably stored in a data directory.
+
+A write is acknowledged only once it has been synced to the data directory, so a
+record that was acknowledged must be read back, whatever happened to the process
+or the node it ran on in between, as long as the data directory survived.
+`,
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancelFn := context.WithCancel(context.Background())
+			defer cancelFn()
+			abortCh := make(chan os.Signal, 2)
+			go func() {
+				<-abortCh
+				fmt.Fprintf(f.ErrOut, "Interrupted, terminating\n")
+				cancelFn()
+
+				sig := <-abortCh
+				fmt.Fprintf(f.ErrOut, "Interrupted twice, exiting (%s)\n", sig)
+				switch sig {
+				case syscall.SIGINT:
+					os.Exit(130)
+				default:
+					os.Exit(0)
+				}
+			}()
+			signal.Notify(abortCh, syscall.SIGINT, syscall.SIGTERM)
+
+			if err := f.Validate(); err != nil {
+				return err
+			}
+			o, err := f.ToOptions()
+			if err != nil {
+				return err
+			}
+			return o.Run(ctx)
+		},
+	}
+
+	f.BindOptions(cmd.Flags())
+
+	return cmd
+}
+
+func (f *ServeRecordsFlags) BindOptions(flags *pflag.FlagSet) {
+	flags.StringVar(&f.DataDir, "data-dir", f.DataDir, "the directory the records are stored in, it should be on a persistent volume")
+	flags.Uint16Var(&f.ListenPort, "listen-port", f.ListenPort, "the port to serve the records on")
+	flags.DurationVar(&f.DelayShutdown, "delay-shutdown", f.DelayShutdown, "how long to keep serving, not ready, once terminated, so the endpoints are updated first")
+}
+
+func (f *ServeRecordsFlags) Validate() error {
+	if len(f.DataDir) == 0 {
+		return fmt.Errorf("data-dir must be specified")
+	}
+	if f.ListenPort == 0 {
+		return fmt.Errorf("listen-port must be specified")
+	}
+	if f.DelayShutdown < 0 {
+		return fmt.Errorf("delay-shutdown must not be negative")
+	}
+	return nil
+}
+
+func (f *ServeRecordsFlags) ToOptions() (*ServeRecordsOptions, error) {
+	return &ServeRecordsOptions{
+		DataDir:       f.DataDir,
+		ListenPort:    f.ListenPort,
+		DelayShutdown: f.DelayShutdown,
+		IOStreams:     f.IOStreams,
+	}, nil
+}
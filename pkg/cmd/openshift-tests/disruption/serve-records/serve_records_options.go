@@ -0,0 +1,68 @@
+package serve_records
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/recordstore"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+type ServeRecordsOptions struct {
+	DataDir       string
+	ListenPort    uint16
+	DelayShutdown time.Duration
+
+	genericclioptions.IOStreams
+}
+
+func (o *ServeRecordsOptions) Run(ctx context.Context) error {
+	store, err := recordstore.Open(o.DataDir)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	fmt.Fprintf(o.Out, "Loaded %d records from %s\n", len(store.List()), o.DataDir)
+	if skipped := store.Skipped(); skipped > 0 {
+		fmt.Fprintf(o.ErrOut, "Skipped %d corrupted records in %s\n", skipped, o.DataDir)
+	}
+
+	shuttingDown := &atomic.Bool{}
+	server := &http.Server{
+		Addr:    net.JoinHostPort("", strconv.Itoa(int(o.ListenPort))),
+		Handler: recordstore.NewHandler(store, shuttingDown),
+	}
+	serverErr := make(chan error, 1)
+	go func() {
+		fmt.Fprintf(o.Out, "Serving records on %s\n", server.Addr)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	// keep serving, not ready, until the endpoints no longer include us
+	shuttingDown.Store(true)
+	fmt.Fprintf(o.Out, "Terminating, shutting down in %s\n", o.DelayShutdown)
+	time.Sleep(o.DelayShutdown)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-serverErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	fmt.Fprintf(o.Out, "Exiting...\n")
+	return nil
+}
@@ -0,0 +1,212 @@
+package merge_results
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortests/testframework/disruptionserializer"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	junitFilePrefix              = "junit_e2e_"
+	intervalsFilePrefix          = "e2e-events"
+	backendDisruptionFilePrefix  = "backend-disruption"
+	testFailureSummaryFilePrefix = "test-failures-summary"
+)
+
+// timeSuffixFromJUnitFile returns the time suffix shared by the files of the run that wrote the JUnit file,
+// junit_e2e__20240101-000000.xml is written alongside e2e-events_20240101-000000.json.
+func timeSuffixFromJUnitFile(filename string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(filepath.Base(filename), junitFilePrefix), ".xml")
+}
+
+// shardName returns the shard the suite was run as, or the fallback if the run was not sharded.
+func shardName(suites []*junitapi.JUnitTestSuite, fallback string) string {
+	for _, suite := range suites {
+		for _, property := range suite.Properties {
+			if property.Name == junitapi.ShardProperty {
+				return property.Value
+			}
+		}
+	}
+	return fallback
+}
+
+// mergeJUnitSuites combines the suites of every shard in one.  The shards ran different tests, but the invariants
+// evaluated by the monitor tests are reported by every shard, so each attempt of a test keeps at most one passing
+// result and one failure, that has the failures of every shard.  A test that failed and passed on retry in a shard
+// is a flake, but a test that failed in a shard fails the merged result, even if it passed in another shard.
+func mergeJUnitSuites(shards []*shardResults) *junitapi.JUnitTestSuite {
+	merged := &junitapi.JUnitTestSuite{}
+
+	// the attempts of a retried test are kept apart, to keep the history of the retries
+	type attempt struct {
+		name    string
+		attempt int
+	}
+	type result struct {
+		passed  *junitapi.JUnitTestCase
+		failed  *junitapi.JUnitTestCase
+		skipped *junitapi.JUnitTestCase
+	}
+	results := map[attempt]*result{}
+	attempts := []attempt{}
+	// ran are the tests a shard did not skip, failing the tests a shard failed without passing them on retry
+	ran, failing := map[string]bool{}, map[string]bool{}
+	for _, shard := range shards {
+		// the tests that failed or passed in this shard
+		failedInShard, passedInShard := map[string]bool{}, map[string]bool{}
+		for _, suite := range shard.suites {
+			if len(merged.Name) == 0 {
+				merged.Name = suite.Name
+				for _, property := range suite.Properties {
+					if property.Name != junitapi.ShardProperty {
+						merged.Properties = append(merged.Properties, property)
+					}
+				}
+			}
+			// the shards run at the same time
+			if suite.Duration > merged.Duration {
+				merged.Duration = suite.Duration
+			}
+
+			for _, testCase := range suite.TestCases {
+				key := attempt{name: testCase.Name, attempt: testCase.Attempt}
+				curr, ok := results[key]
+				if !ok {
+					curr = &result{}
+					results[key] = curr
+					attempts = append(attempts, key)
+				}
+				switch {
+				case testCase.SkipMessage != nil:
+					if curr.skipped == nil {
+						curr.skipped = testCase
+					}
+				case testCase.FailureOutput != nil:
+					failedInShard[testCase.Name] = true
+					if curr.failed == nil {
+						failed := *testCase
+						failed.FailureOutput = &junitapi.FailureOutput{Message: testCase.FailureOutput.Message}
+						failed.SystemOut = ""
+						curr.failed = &failed
+					}
+					curr.failed.FailureOutput.Output += fmt.Sprintf("shard %s:\n%s\n", shard.name, testCase.FailureOutput.Output)
+					if len(testCase.SystemOut) > 0 {
+						curr.failed.SystemOut += fmt.Sprintf("shard %s:\n%s\n", shard.name, testCase.SystemOut)
+					}
+				default:
+					passedInShard[testCase.Name] = true
+					if curr.passed == nil {
+						curr.passed = testCase
+					}
+				}
+			}
+		}
+		for name := range failedInShard {
+			ran[name] = true
+			if !passedInShard[name] {
+				failing[name] = true
+			}
+		}
+		for name := range passedInShard {
+			ran[name] = true
+		}
+	}
+
+	for _, key := range attempts {
+		curr := results[key]
+		if curr.failed != nil {
+			merged.NumTests++
+			merged.NumFailed++
+			merged.TestCases = append(merged.TestCases, curr.failed)
+		}
+		// a passing result next to the failure would report the test as a flake
+		if curr.passed != nil && !failing[key.name] {
+			merged.NumTests++
+			merged.TestCases = append(merged.TestCases, curr.passed)
+		}
+		// skipped only if no shard ran it
+		if !ran[key.name] && curr.skipped != nil {
+			merged.NumTests++
+			merged.NumSkipped++
+			merged.TestCases = append(merged.TestCases, curr.skipped)
+		}
+	}
+	return merged
+}
+
+// failingAndFlakyTests returns the tests that only failed, and the tests that both failed and passed.
+func failingAndFlakyTests(suite *junitapi.JUnitTestSuite) (failing, flaky []string) {
+	passed := map[string]bool{}
+	for _, testCase := range suite.TestCases {
+		if testCase.SkipMessage == nil && testCase.FailureOutput == nil {
+			passed[testCase.Name] = true
+		}
+	}
+	// a test that failed several attempts is reported once
+	reported := map[string]bool{}
+	for _, testCase := range suite.TestCases {
+		if testCase.FailureOutput == nil || reported[testCase.Name] {
+			continue
+		}
+		reported[testCase.Name] = true
+		if passed[testCase.Name] {
+			flaky = append(flaky, testCase.Name)
+		} else {
+			failing = append(failing, testCase.Name)
+		}
+	}
+	sort.Strings(failing)
+	sort.Strings(flaky)
+	return failing, flaky
+}
+
+// annotateWithShard marks the intervals with the shard that recorded them, since the shards may have run against
+// different clusters.
+func annotateWithShard(intervals monitorapi.Intervals, shard string) monitorapi.Intervals {
+	ret := make(monitorapi.Intervals, 0, len(intervals))
+	for _, interval := range intervals {
+		annotations := map[monitorapi.AnnotationKey]string{}
+		for k, v := range interval.StructuredMessage.Annotations {
+			annotations[k] = v
+		}
+		annotations[monitorapi.AnnotationShard] = shard
+		interval.StructuredMessage.Annotations = annotations
+		ret = append(ret, interval)
+	}
+	return ret
+}
+
+// mergeBackendDisruption keeps the worst disruption of every backend across the shards.  Each shard measures the
+// disruption of its own cluster, so adding them up would not compare to the disruption of a single cluster.
+func mergeBackendDisruption(shards map[string]*disruptionserializer.BackendDisruptionList) *disruptionserializer.BackendDisruptionList {
+	merged := &disruptionserializer.BackendDisruptionList{
+		BackendDisruptions: map[string]*disruptionserializer.BackendDisruption{},
+	}
+
+	shardNames := []string{}
+	for name := range shards {
+		shardNames = append(shardNames, name)
+	}
+	sort.Strings(shardNames)
+	for _, shardName := range shardNames {
+		for name, disruption := range shards[shardName].BackendDisruptions {
+			curr, ok := merged.BackendDisruptions[name]
+			if ok && curr.DisruptedDuration.Duration >= disruption.DisruptedDuration.Duration {
+				continue
+			}
+			worst := *disruption
+			worst.DisruptionMessages = []string{}
+			for _, message := range disruption.DisruptionMessages {
+				worst.DisruptionMessages = append(worst.DisruptionMessages, fmt.Sprintf("shard %s: %s", shardName, message))
+			}
+			merged.BackendDisruptions[name] = &worst
+		}
+	}
+	return merged
+}
@@ -0,0 +1,81 @@
+package merge_results
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+// MergeResultsFlags is used to combine the results of the shards of a sharded run.
+type MergeResultsFlags struct {
+	OutputDir string
+
+	genericclioptions.IOStreams
+}
+
+func NewMergeResultsFlags(streams genericclioptions.IOStreams) *MergeResultsFlags {
+	return &MergeResultsFlags{
+		IOStreams: streams,
+	}
+}
+
+func NewMergeResultsCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	f := NewMergeResultsFlags(streams)
+
+	cmd := &cobra.Command{
+		Use:   "merge-results SHARD_JUNIT_DIR...",
+		Short: "Merge the results of the shards of a sharded run",
+		Long: templates.LongDesc(`
+		Merge the results of the shards of a sharded run
+
+		A suite run with --shard-index and --shard-count only runs a part of the suite. This
+		command combines the junit_e2e, e2e-events, test-failures-summary and backend-disruption
+		files the shards wrote to their --junit-dir into one result in --output-dir.
+
+		A test that failed in a shard fails the merged result, even if it passed in another
+		shard. Only a test that passed on retry in the shard it failed in is a flake, and every
+		attempt of a retried test is kept. The intervals are annotated with the shard that
+		recorded them, and the disruption of a backend is the worst disruption of the shards.
+		`),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.Validate(); err != nil {
+				return err
+			}
+			o, err := f.ToOptions(args)
+			if err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (f *MergeResultsFlags) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&f.OutputDir, "output-dir", f.OutputDir, "The directory to write the merged results to.")
+}
+
+func (f *MergeResultsFlags) Validate() error {
+	if len(f.OutputDir) == 0 {
+		return fmt.Errorf("--output-dir must be specified")
+	}
+	return nil
+}
+
+func (f *MergeResultsFlags) ToOptions(shardDirs []string) (*MergeResultsOptions, error) {
+	return &MergeResultsOptions{
+		ShardDirs: shardDirs,
+		OutputDir: f.OutputDir,
+		IOStreams: f.IOStreams,
+	}, nil
+}
@@ -0,0 +1,120 @@
+package merge_results
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/monitortests/testframework/disruptionserializer"
+	"github.com/openshift/origin/pkg/riskanalysis"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+type MergeResultsOptions struct {
+	ShardDirs []string
+	OutputDir string
+
+	genericclioptions.IOStreams
+}
+
+func (o *MergeResultsOptions) Run() error {
+	shards := []*shardResults{}
+	for _, dir := range o.ShardDirs {
+		shard, err := loadShardResults(dir)
+		if err != nil {
+			return err
+		}
+		shards = append(shards, shard)
+	}
+	if err := os.MkdirAll(o.OutputDir, 0755); err != nil {
+		return fmt.Errorf("could not create --output-dir: %w", err)
+	}
+
+	// the merged files are named after the shard that started first
+	timeSuffix := ""
+	for _, shard := range shards {
+		for _, junitFile := range shard.junitFiles {
+			if curr := timeSuffixFromJUnitFile(junitFile); len(timeSuffix) == 0 || curr < timeSuffix {
+				timeSuffix = curr
+			}
+		}
+	}
+
+	merged := mergeJUnitSuites(shards)
+	junitContent, err := xml.MarshalIndent(merged, "", "    ")
+	if err != nil {
+		return err
+	}
+	junitFile := filepath.Join(o.OutputDir, fmt.Sprintf("%s%s.xml", junitFilePrefix, timeSuffix))
+	if err := os.WriteFile(junitFile, junitContent, 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "Wrote merged JUnit report to %s\n", junitFile)
+
+	intervals := monitorapi.Intervals{}
+	for _, shard := range shards {
+		intervals = append(intervals, shard.intervals...)
+	}
+	if len(intervals) > 0 {
+		sort.Sort(intervals)
+		intervalsFile := filepath.Join(o.OutputDir, fmt.Sprintf("%s%s.json", intervalsFilePrefix, timeSuffix))
+		if err := monitorserialization.EventsToFile(intervalsFile, intervals); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "Wrote %d merged intervals to %s\n", len(intervals), intervalsFile)
+	}
+
+	backendDisruptions := map[string]*disruptionserializer.BackendDisruptionList{}
+	for _, shard := range shards {
+		if shard.backendDisruption != nil {
+			backendDisruptions[shard.name] = shard.backendDisruption
+		}
+	}
+	if len(backendDisruptions) > 0 {
+		backendDisruptionContent, err := json.MarshalIndent(mergeBackendDisruption(backendDisruptions), "", "    ")
+		if err != nil {
+			return err
+		}
+		backendDisruptionFile := filepath.Join(o.OutputDir, fmt.Sprintf("%s%s.json", backendDisruptionFilePrefix, timeSuffix))
+		if err := os.WriteFile(backendDisruptionFile, backendDisruptionContent, 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "Wrote merged backend disruption to %s\n", backendDisruptionFile)
+	}
+
+	// the job run and the cluster data are the same for every shard, only the tests are recomputed.
+	for _, shard := range shards {
+		if len(shard.testFailureSummaryFile) == 0 {
+			continue
+		}
+		content, err := os.ReadFile(shard.testFailureSummaryFile)
+		if err != nil {
+			return err
+		}
+		jobRun := riskanalysis.ProwJobRun{}
+		if err := json.Unmarshal(content, &jobRun); err != nil {
+			return fmt.Errorf("unable to read %s: %w", shard.testFailureSummaryFile, err)
+		}
+		if err := riskanalysis.WriteProwJobRunTestFailureSummary(o.OutputDir, timeSuffix, jobRun, merged, ""); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "Wrote merged %s\n", testFailureSummaryFilePrefix)
+		break
+	}
+
+	failing, flaky := failingAndFlakyTests(merged)
+	if len(flaky) > 0 {
+		fmt.Fprintf(o.Out, "Flaky tests:\n\n%s\n\n", strings.Join(flaky, "\n"))
+	}
+	if len(failing) > 0 {
+		fmt.Fprintf(o.Out, "Failing tests:\n\n%s\n\n", strings.Join(failing, "\n"))
+	}
+	fmt.Fprintf(o.Out, "Merged %d shards, %d tests, %d failed, %d flaked\n", len(shards), merged.NumTests, len(failing), len(flaky))
+	return nil
+}
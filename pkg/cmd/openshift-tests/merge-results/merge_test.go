@@ -0,0 +1,144 @@
+package merge_results
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/monitortests/testframework/disruptionserializer"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func writeShard(t *testing.T, dir, timeSuffix, junit string, intervals monitorapi.Intervals) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, junitFilePrefix+timeSuffix+".xml"), []byte(junit), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := monitorserialization.EventsToFile(filepath.Join(dir, intervalsFilePrefix+timeSuffix+".json"), intervals); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMergeShards(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	interval := monitorapi.NewInterval(monitorapi.SourceE2ETest, monitorapi.Info).
+		Locator(monitorapi.NewLocator().E2ETest("test a")).
+		Message(monitorapi.NewMessage().HumanMessage("started").Reason(monitorapi.E2ETestStarted)).
+		Build(start, start.Add(time.Minute))
+
+	writeShard(t, filepath.Join(dir, "shard-0"), "_20240101-000000", `<testsuite name="openshift-tests" tests="4" skipped="1" failures="2" time="600">
+    <properties name="TestVersion" value="v4.16.0"></properties>
+    <properties name="Shard" value="0/2"></properties>
+    <testcase name="test a" time="60"></testcase>
+    <testcase name="test c" time="0">
+        <skipped message="skipped"></skipped>
+    </testcase>
+    <testcase name="[sig-arch] invariant" time="0">
+        <failure message="">broken on the first cluster</failure>
+    </testcase>
+    <testcase name="[sig-arch] always broken" time="0">
+        <failure message="">broken on the first cluster</failure>
+    </testcase>
+</testsuite>`, monitorapi.Intervals{interval})
+	writeShard(t, filepath.Join(dir, "shard-1"), "_20240101-000100", `<testsuite name="openshift-tests" tests="7" skipped="1" failures="3" time="900">
+    <properties name="TestVersion" value="v4.16.0"></properties>
+    <properties name="Shard" value="1/2"></properties>
+    <testcase name="test b" time="90"></testcase>
+    <testcase name="test d" time="30" attempt="1">
+        <failure message="">failed on the first attempt</failure>
+    </testcase>
+    <testcase name="test d" time="30" attempt="2">
+        <failure message="">failed on the second attempt</failure>
+    </testcase>
+    <testcase name="test d" time="30" attempt="3"></testcase>
+    <testcase name="test c" time="0">
+        <skipped message="skipped"></skipped>
+    </testcase>
+    <testcase name="[sig-arch] invariant" time="0"></testcase>
+    <testcase name="[sig-arch] always broken" time="0">
+        <failure message="">broken on the second cluster</failure>
+    </testcase>
+</testsuite>`, monitorapi.Intervals{interval})
+
+	shards := []*shardResults{}
+	for _, shardDir := range []string{"shard-0", "shard-1"} {
+		shard, err := loadShardResults(filepath.Join(dir, shardDir))
+		if err != nil {
+			t.Fatal(err)
+		}
+		shards = append(shards, shard)
+	}
+	if shards[1].name != "1/2" {
+		t.Errorf("expected the shard name from the JUnit properties, got %q", shards[1].name)
+	}
+	if got := shards[1].intervals[0].StructuredMessage.Annotations[monitorapi.AnnotationShard]; got != "1/2" {
+		t.Errorf("expected the intervals to be annotated with the shard, got %q", got)
+	}
+	if got := timeSuffixFromJUnitFile(shards[0].junitFiles[0]); got != "_20240101-000000" {
+		t.Errorf("unexpected time suffix %q", got)
+	}
+
+	merged := mergeJUnitSuites(shards)
+	if merged.Duration != 900 {
+		t.Errorf("expected the duration of the longest shard, got %v", merged.Duration)
+	}
+	if expected := []*junitapi.TestSuiteProperty{{Name: "TestVersion", Value: "v4.16.0"}}; !reflect.DeepEqual(expected, merged.Properties) {
+		t.Errorf("expected the properties without the shard, got %v", merged.Properties)
+	}
+	// test a, test c, the invariant that failed in a shard once, the broken invariant once, test b, every attempt of
+	// the flaky test d
+	if merged.NumTests != 8 || merged.NumFailed != 4 || merged.NumSkipped != 1 {
+		t.Errorf("unexpected counts, %d tests, %d failed, %d skipped", merged.NumTests, merged.NumFailed, merged.NumSkipped)
+	}
+
+	failing, flaky := failingAndFlakyTests(merged)
+	if expected := []string{"[sig-arch] always broken", "[sig-arch] invariant"}; !reflect.DeepEqual(expected, failing) {
+		t.Errorf("expected failing %v, got %v", expected, failing)
+	}
+	if expected := []string{"test d"}; !reflect.DeepEqual(expected, flaky) {
+		t.Errorf("expected flaky %v, got %v", expected, flaky)
+	}
+	var attempts []int
+	for _, testCase := range merged.TestCases {
+		if testCase.Name == "test d" {
+			attempts = append(attempts, testCase.Attempt)
+		}
+		if testCase.Name == "[sig-arch] always broken" && testCase.FailureOutput.Output != "shard 0/2:\nbroken on the first cluster\nshard 1/2:\nbroken on the second cluster\n" {
+			t.Errorf("expected the failures of both shards, got %q", testCase.FailureOutput.Output)
+		}
+	}
+	if expected := []int{1, 2, 3}; !reflect.DeepEqual(expected, attempts) {
+		t.Errorf("expected every attempt of the retried test, got %v", attempts)
+	}
+
+	if _, err := loadShardResults(filepath.Join(dir, "missing")); err == nil {
+		t.Errorf("expected an error for a shard without results")
+	}
+}
+
+func TestMergeBackendDisruption(t *testing.T) {
+	disruption := func(seconds int, message string) *disruptionserializer.BackendDisruption {
+		return &disruptionserializer.BackendDisruption{
+			Name:               "kube-api-new-connections",
+			DisruptedDuration:  metav1.Duration{Duration: time.Duration(seconds) * time.Second},
+			DisruptionMessages: []string{message},
+		}
+	}
+	merged := mergeBackendDisruption(map[string]*disruptionserializer.BackendDisruptionList{
+		"0/2": {BackendDisruptions: map[string]*disruptionserializer.BackendDisruption{"kube-api-new-connections": disruption(3, "short")}},
+		"1/2": {BackendDisruptions: map[string]*disruptionserializer.BackendDisruption{"kube-api-new-connections": disruption(5, "long")}},
+	})
+	worst := merged.BackendDisruptions["kube-api-new-connections"]
+	if worst.DisruptedDuration.Duration != 5*time.Second || !reflect.DeepEqual([]string{"shard 1/2: long"}, worst.DisruptionMessages) {
+		t.Errorf("expected the worst disruption, got %+v", worst)
+	}
+}
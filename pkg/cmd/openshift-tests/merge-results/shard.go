@@ -0,0 +1,109 @@
+package merge_results
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/monitortests/testframework/disruptionserializer"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// shardResults are the results one shard of a sharded run wrote to its --junit-dir.
+type shardResults struct {
+	dir string
+	// name is the shard the run was, or the directory if it was not sharded.
+	name string
+
+	junitFiles []string
+	suites     []*junitapi.JUnitTestSuite
+
+	intervals         monitorapi.Intervals
+	backendDisruption *disruptionserializer.BackendDisruptionList
+	// testFailureSummaryFile is the summary of the failures written for risk analysis, if any.
+	testFailureSummaryFile string
+}
+
+func globSorted(dir, pattern string) ([]string, error) {
+	filenames, err := filepath.Glob(filepath.Join(dir, pattern))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(filenames)
+	return filenames, nil
+}
+
+// loadShardResults reads the results of the shard from dir.  Only the JUnit report is required, the other files
+// are merged when the shard wrote them.
+func loadShardResults(dir string) (*shardResults, error) {
+	shard := &shardResults{dir: dir}
+
+	var err error
+	shard.junitFiles, err = globSorted(dir, junitFilePrefix+"*.xml")
+	if err != nil {
+		return nil, err
+	}
+	if len(shard.junitFiles) == 0 {
+		return nil, fmt.Errorf("no %s*.xml found in %s", junitFilePrefix, dir)
+	}
+	for _, junitFile := range shard.junitFiles {
+		content, err := os.ReadFile(junitFile)
+		if err != nil {
+			return nil, err
+		}
+		suite := &junitapi.JUnitTestSuite{}
+		if err := xml.Unmarshal(content, suite); err != nil {
+			return nil, fmt.Errorf("unable to read %s: %w", junitFile, err)
+		}
+		shard.suites = append(shard.suites, suite)
+	}
+	shard.name = shardName(shard.suites, dir)
+
+	intervalsFiles, err := globSorted(dir, intervalsFilePrefix+"_*.json")
+	if err != nil {
+		return nil, err
+	}
+	for _, intervalsFile := range intervalsFiles {
+		intervals, err := monitorserialization.EventsFromFile(intervalsFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s: %w", intervalsFile, err)
+		}
+		shard.intervals = append(shard.intervals, annotateWithShard(intervals, shard.name)...)
+	}
+
+	backendDisruptionFiles, err := globSorted(dir, backendDisruptionFilePrefix+"_*.json")
+	if err != nil {
+		return nil, err
+	}
+	for _, backendDisruptionFile := range backendDisruptionFiles {
+		content, err := os.ReadFile(backendDisruptionFile)
+		if err != nil {
+			return nil, err
+		}
+		backendDisruption := &disruptionserializer.BackendDisruptionList{}
+		if err := json.Unmarshal(content, backendDisruption); err != nil {
+			return nil, fmt.Errorf("unable to read %s: %w", backendDisruptionFile, err)
+		}
+		if shard.backendDisruption == nil {
+			shard.backendDisruption = backendDisruption
+			continue
+		}
+		for name, disruption := range backendDisruption.BackendDisruptions {
+			shard.backendDisruption.BackendDisruptions[name] = disruption
+		}
+	}
+
+	testFailureSummaryFiles, err := globSorted(dir, testFailureSummaryFilePrefix+"*.json")
+	if err != nil {
+		return nil, err
+	}
+	if len(testFailureSummaryFiles) > 0 {
+		shard.testFailureSummaryFile = testFailureSummaryFiles[0]
+	}
+	return shard, nil
+}
@@ -1,8 +1,10 @@
 package monitor
 
 import (
+	"github.com/openshift/origin/pkg/cmd/openshift-tests/monitor/replay"
 	"github.com/openshift/origin/pkg/cmd/openshift-tests/monitor/run"
 	summarize_audit_logs "github.com/openshift/origin/pkg/cmd/openshift-tests/monitor/summarize-audit-logs"
+	"github.com/openshift/origin/pkg/cmd/openshift-tests/monitor/tail"
 	"github.com/openshift/origin/pkg/monitor/apiserveravailability"
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -15,6 +17,8 @@ func NewMonitorCommand(streams genericclioptions.IOStreams) *cobra.Command {
 	}
 	cmd.AddCommand(
 		run.NewRunCommand(streams),
+		replay.NewReplayCommand(streams),
+		tail.NewTailCommand(streams),
 		summarize_audit_logs.AuditLogSummaryCommand(),
 		apiserveravailability.LogSummaryCommand(),
 	)
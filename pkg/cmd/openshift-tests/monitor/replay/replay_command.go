@@ -0,0 +1,263 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/defaultmonitortests"
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+// intervalsFileTimeSuffix matches the _<timestamp> suffix of e2e-events_20230214-203340.json
+var intervalsFileTimeSuffix = regexp.MustCompile(`_[0-9]{8}-[0-9]{6}`)
+
+type ReplayMonitorFlags struct {
+	IntervalsFile                   string
+	ResourcesDir                    string
+	ClusterDataFile                 string
+	ArtifactDir                     string
+	JUnitSuiteName                  string
+	ClusterStability                string
+	ExactMonitorTests               []string
+	DisableMonitorTests             []string
+	PathologicalEventAllowanceFiles []string
+
+	genericclioptions.IOStreams
+}
+
+func NewReplayMonitorFlags(streams genericclioptions.IOStreams) *ReplayMonitorFlags {
+	return &ReplayMonitorFlags{
+		JUnitSuiteName:   "openshift-tests-monitor-replay",
+		ClusterStability: string(monitortestframework.Stable),
+		IOStreams:        streams,
+	}
+}
+
+func NewReplayCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	f := NewReplayMonitorFlags(streams)
+
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Re-evaluate monitor tests against the artifacts of a prior run",
+		Long: templates.LongDesc(`
+		Re-evaluate monitor tests against the artifacts of a prior run.
+
+		Intervals are read from an e2e-events_<timestamp>.json file and tracked resources are read from
+		the resource-<type>_<timestamp>.zip files next to it.  Every selected monitor test that supports
+		replay computes its intervals and evaluates its tests exactly as it would at the end of a live run,
+		but no data is collected and no cluster is required.  Monitor tests that depend on the data they
+		collect from the cluster are reported as skipped.  Monitor tests that match the run against
+		historical data, like the alert, disruption and pathological event tests, read the job variants
+		from --cluster-data and are reported as skipped without it.  The same junit and storage files a
+		live run writes are written to --artifact-dir.
+		`),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o, err := f.ToOptions()
+			if err != nil {
+				return err
+			}
+			return o.Run(context.Background())
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (f *ReplayMonitorFlags) BindFlags(flags *pflag.FlagSet) {
+	monitorNames := defaultmonitortests.ListAllMonitorTests()
+
+	flags.StringVar(&f.IntervalsFile, "intervals-file", f.IntervalsFile, "Path to an intervals file (i.e. e2e-events_20230214-203340.json). Can be obtained from a CI run in openshift-tests junit artifacts.")
+	flags.StringVar(&f.ResourcesDir, "resources-dir", f.ResourcesDir, "Directory holding the resource-<type>_<timestamp>.zip files of the run. Defaults to the directory of --intervals-file.")
+	flags.StringVar(&f.ClusterDataFile, "cluster-data", f.ClusterDataFile, "Path to the cluster data file of the run (i.e. cluster-data_20230214-203340.json), used to determine the job variants.")
+	flags.StringVar(&f.ArtifactDir, "artifact-dir", f.ArtifactDir, "The directory where junit and monitor test output will be written.")
+	flags.StringVar(&f.JUnitSuiteName, "junit-suite-name", f.JUnitSuiteName, "The junit suite name to report monitor tests under.")
+	flags.StringVar(&f.ClusterStability, "cluster-stability", f.ClusterStability, "cluster stability during the original run: Stable or Disruptive.")
+	flags.StringSliceVar(&f.ExactMonitorTests, "monitor", f.ExactMonitorTests,
+		fmt.Sprintf("list of exactly which monitors to enable. All others will be disabled.  Current monitors are: [%s]", strings.Join(monitorNames, ", ")))
+	flags.StringSliceVar(&f.DisableMonitorTests, "disable-monitor", f.DisableMonitorTests, "list of monitors to disable.  Defaults for others will be honored.")
+	flags.StringSliceVar(&f.PathologicalEventAllowanceFiles, "pathological-event-allowances", f.PathologicalEventAllowanceFiles, "YAML or JSON files of additional pathological event matchers allowing known repeated events.")
+}
+
+func (f *ReplayMonitorFlags) ToOptions() (*ReplayMonitorOptions, error) {
+	if len(f.IntervalsFile) == 0 {
+		return nil, fmt.Errorf("missing --intervals-file")
+	}
+	if len(f.ArtifactDir) == 0 {
+		return nil, fmt.Errorf("missing --artifact-dir")
+	}
+
+	var clusterStability monitortestframework.ClusterStabilityDuringTest
+	switch f.ClusterStability {
+	case "", string(monitortestframework.Stable):
+		clusterStability = monitortestframework.Stable
+	case string(monitortestframework.Disruptive):
+		clusterStability = monitortestframework.Disruptive
+	default:
+		return nil, fmt.Errorf("unknown --cluster-stability, %q, expected Stable or Disruptive", f.ClusterStability)
+	}
+
+	monitorTestInfo := monitortestframework.MonitorTestInitializationInfo{
+		ClusterStabilityDuringTest:      clusterStability,
+		ExactMonitorTests:               f.ExactMonitorTests,
+		DisableMonitorTests:             f.DisableMonitorTests,
+		PathologicalEventAllowanceFiles: f.PathologicalEventAllowanceFiles,
+	}
+	monitorTestRegistry, err := defaultmonitortests.NewMonitorTestsFor(monitorTestInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	var clusterData *platformidentification.ClusterData
+	if len(f.ClusterDataFile) > 0 {
+		clusterData, err = platformidentification.ReadClusterDataFromFile(f.ClusterDataFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resourcesDir := f.ResourcesDir
+	if len(resourcesDir) == 0 {
+		resourcesDir = filepath.Dir(f.IntervalsFile)
+	}
+
+	return &ReplayMonitorOptions{
+		IntervalsFile:  f.IntervalsFile,
+		ResourcesDir:   resourcesDir,
+		ClusterData:    clusterData,
+		ArtifactDir:    f.ArtifactDir,
+		JUnitSuiteName: f.JUnitSuiteName,
+		MonitorTests:   monitorTestRegistry,
+		IOStreams:      f.IOStreams,
+	}, nil
+}
+
+type ReplayMonitorOptions struct {
+	IntervalsFile string
+	ResourcesDir  string
+	// ClusterData, if set, overrides the cluster data of the context the monitor tests are run with.
+	ClusterData    *platformidentification.ClusterData
+	ArtifactDir    string
+	JUnitSuiteName string
+	MonitorTests   monitortestframework.MonitorTestRegistry
+
+	genericclioptions.IOStreams
+}
+
+func (o *ReplayMonitorOptions) Run(ctx context.Context) error {
+	if o.ClusterData != nil {
+		// monitor tests have no cluster to ask for the job type, they get it from the cluster data instead.
+		ctx = platformidentification.WithClusterDataOverride(ctx, o.ClusterData)
+	}
+
+	fmt.Fprintf(o.Out, "Loading intervals from %s\n", o.IntervalsFile)
+	intervals, err := monitorserialization.EventsFromFile(o.IntervalsFile)
+	if err != nil {
+		return fmt.Errorf("unable to read intervals: %w", err)
+	}
+	fmt.Fprintf(o.Out, "Loaded %d intervals\n", len(intervals))
+
+	resources, err := readResourcesFromDir(o.ResourcesDir)
+	if err != nil {
+		return fmt.Errorf("unable to read tracked resources: %w", err)
+	}
+	for resourceType, instances := range resources {
+		fmt.Fprintf(o.Out, "Loaded %d %s\n", len(instances), resourceType)
+	}
+
+	if err := os.MkdirAll(o.ArtifactDir, 0755); err != nil {
+		return fmt.Errorf("could not create --artifact-dir: %w", err)
+	}
+
+	startTime, stopTime := intervalsTimeBounds(intervals)
+	timeSuffix := intervalsFileTimeSuffix.FindString(filepath.Base(o.IntervalsFile))
+	if len(timeSuffix) == 0 {
+		timeSuffix = fmt.Sprintf("_%s", startTime.UTC().Format("20060102-150405"))
+	}
+
+	m := monitor.NewReplayMonitor(
+		monitor.NewRecorderWithContent(intervals, resources),
+		o.ArtifactDir,
+		o.MonitorTests,
+		startTime,
+		stopTime,
+	)
+	if err := m.Start(ctx); err != nil {
+		return err
+	}
+	resultState, err := m.Stop(ctx)
+	if err != nil {
+		return err
+	}
+	if err := m.SerializeResults(ctx, o.JUnitSuiteName, timeSuffix); err != nil {
+		return err
+	}
+
+	if resultState != monitor.Succeeded {
+		return fmt.Errorf("failed due to a MonitorTest failure")
+	}
+	fmt.Fprintf(o.Out, "All monitor tests passed\n")
+	return nil
+}
+
+// readResourcesFromDir reads every resource-<type>_<timestamp>.zip file in dir.
+func readResourcesFromDir(dir string) (monitorapi.ResourcesMap, error) {
+	filenames, err := filepath.Glob(filepath.Join(dir, "resource-*.zip"))
+	if err != nil {
+		return nil, err
+	}
+
+	resources := monitorapi.ResourcesMap{}
+	for _, filename := range filenames {
+		resourceType, ok := monitorserialization.ResourceTypeFromFilename(filename)
+		if !ok {
+			continue
+		}
+		instances, err := monitorserialization.InstanceMapFromFile(filename, resourceType)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := resources[resourceType]; !ok {
+			resources[resourceType] = monitorapi.InstanceMap{}
+		}
+		for key, obj := range instances {
+			resources[resourceType][key] = obj
+		}
+	}
+	return resources, nil
+}
+
+// intervalsTimeBounds returns the earliest From and latest To of the intervals, standing in for the
+// start and stop time of the monitor that recorded them.
+func intervalsTimeBounds(intervals monitorapi.Intervals) (time.Time, time.Time) {
+	var start, stop time.Time
+	for _, interval := range intervals {
+		if !interval.From.IsZero() && (start.IsZero() || interval.From.Before(start)) {
+			start = interval.From
+		}
+		if interval.To.After(stop) {
+			stop = interval.To
+		}
+		if interval.From.After(stop) {
+			stop = interval.From
+		}
+	}
+	return start, stop
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/openshift/origin/pkg/defaultmonitortests"
 	"github.com/openshift/origin/pkg/disruption/backend/sampler"
 	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/intervalstream"
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/kubectl/pkg/util/templates"
@@ -33,6 +34,7 @@ type RunMonitorFlags struct {
 	ExactMonitorTests   []string
 	DisableMonitorTests []string
 	FromRepository      string
+	StreamAddress       string
 
 	genericclioptions.IOStreams
 }
@@ -90,6 +92,7 @@ func (f *RunMonitorFlags) BindFlags(flags *pflag.FlagSet) {
 		fmt.Sprintf("list of exactly which monitors to enable. All others will be disabled.  Current monitors are: [%s]", strings.Join(monitorNames, ", ")))
 	flags.StringSliceVar(&f.DisableMonitorTests, "disable-monitor", f.DisableMonitorTests, "list of monitors to disable.  Defaults for others will be honored.")
 	flags.StringVar(&f.FromRepository, "from-repository", f.FromRepository, "A container image repository to retrieve test images from.")
+	flags.StringVar(&f.StreamAddress, "interval-stream-address", f.StreamAddress, "If set, serve a live stream of monitor intervals on this local address (i.e. 127.0.0.1:9911) for use by the monitor tail command.")
 }
 
 func (f *RunMonitorFlags) ToOptions() (*RunMonitorOptions, error) {
@@ -115,6 +118,7 @@ func (f *RunMonitorFlags) ToOptions() (*RunMonitorOptions, error) {
 		MonitorTests:    monitorTestRegistry,
 		IOStreams:       f.IOStreams,
 		FromRepository:  f.FromRepository,
+		StreamAddress:   f.StreamAddress,
 	}, nil
 }
 
@@ -132,6 +136,7 @@ type RunMonitorOptions struct {
 	DisplayFilterFn monitorapi.EventIntervalMatchesFunc
 	MonitorTests    monitortestframework.MonitorTestRegistry
 	FromRepository  string
+	StreamAddress   string
 
 	genericclioptions.IOStreams
 }
@@ -171,6 +176,12 @@ func (o *RunMonitorOptions) Run() error {
 	signal.Notify(abortCh, syscall.SIGINT, syscall.SIGTERM)
 
 	recorder := monitor.WrapWithJSONLRecorder(monitor.NewRecorder(), o.Out, o.DisplayFilterFn)
+	if len(o.StreamAddress) > 0 {
+		recorder, err = intervalstream.StreamIntervals(ctx, o.StreamAddress, recorder, o.Out)
+		if err != nil {
+			return err
+		}
+	}
 	m := monitor.NewMonitor(
 		recorder,
 		restConfig,
@@ -0,0 +1,167 @@
+package tail
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/intervalstream"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+// maxBarWidth bounds the width of the duration bar drawn for each interval.
+const maxBarWidth = 60
+
+type TailFlags struct {
+	URL          string
+	Sources      []string
+	LocatorTypes []string
+	Level        string
+	BarScale     time.Duration
+
+	genericclioptions.IOStreams
+}
+
+func NewTailFlags(streams genericclioptions.IOStreams) *TailFlags {
+	return &TailFlags{
+		URL:       "http://127.0.0.1:9911",
+		Level:     monitorapi.Info.String(),
+		BarScale:  time.Second,
+		IOStreams: streams,
+	}
+}
+
+func NewTailCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	f := NewTailFlags(streams)
+
+	cmd := &cobra.Command{
+		Use:   "tail",
+		Short: "Follow the intervals of a running monitor",
+		Long: templates.LongDesc(`
+		Follow the intervals of a running monitor.
+
+		Connects to the stream served by a monitor started with --interval-stream-address and prints
+		each interval as it is recorded, with a bar showing its duration.
+
+		openshift-tests monitor tail --source=Disruption --source=OperatorState --level=Warning
+		`),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o, err := f.ToOptions()
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			abortCh := make(chan os.Signal, 1)
+			signal.Notify(abortCh, syscall.SIGINT, syscall.SIGTERM)
+			go func() {
+				<-abortCh
+				cancel()
+			}()
+
+			return o.Run(ctx)
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (f *TailFlags) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&f.URL, "url", f.URL, "URL of the monitor interval stream, as set by --interval-stream-address.")
+	flags.StringSliceVar(&f.Sources, "source", f.Sources, "Only show intervals from these sources (i.e. Disruption, OperatorState). May be repeated.")
+	flags.StringSliceVar(&f.LocatorTypes, "locator-type", f.LocatorTypes, "Only show intervals with these locator types (i.e. Disruption, ClusterOperator). May be repeated.")
+	flags.StringVar(&f.Level, "level", f.Level, "Only show intervals at or above this level: Info, Warning, or Error.")
+	flags.DurationVar(&f.BarScale, "bar-scale", f.BarScale, "Duration represented by each character of the duration bar.")
+}
+
+func (f *TailFlags) ToOptions() (*TailOptions, error) {
+	level, err := monitorapi.ConditionLevelFromString(f.Level)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --level: %w", err)
+	}
+	if f.BarScale <= 0 {
+		return nil, fmt.Errorf("--bar-scale must be positive")
+	}
+
+	return &TailOptions{
+		URL: f.URL,
+		Filter: intervalstream.Filter{
+			Sources:      sets.NewString(f.Sources...),
+			LocatorTypes: sets.NewString(f.LocatorTypes...),
+			MinimumLevel: level,
+		},
+		BarScale:  f.BarScale,
+		IOStreams: f.IOStreams,
+	}, nil
+}
+
+type TailOptions struct {
+	URL      string
+	Filter   intervalstream.Filter
+	BarScale time.Duration
+
+	genericclioptions.IOStreams
+}
+
+func (o *TailOptions) Run(ctx context.Context) error {
+	fmt.Fprintf(o.ErrOut, "Following intervals from %s\n", o.URL)
+	return intervalstream.Tail(ctx, o.URL, o.Filter, &timelinePrinter{out: o.Out, barScale: o.BarScale})
+}
+
+// timelinePrinter renders each interval on one line: start, level, source, a bar scaled to the duration,
+// and the locator and message.
+type timelinePrinter struct {
+	out      io.Writer
+	barScale time.Duration
+}
+
+func (p *timelinePrinter) Interval(interval monitorapi.Interval) {
+	fmt.Fprintln(p.out, p.render(interval))
+}
+
+func (p *timelinePrinter) Dropped(count int64) {
+	fmt.Fprintf(p.out, "... %d intervals dropped, tail could not keep up ...\n", count)
+}
+
+func (p *timelinePrinter) render(interval monitorapi.Interval) string {
+	duration := time.Duration(0)
+	if !interval.To.IsZero() && interval.To.After(interval.From) {
+		duration = interval.To.Sub(interval.From)
+	}
+
+	bar := "|"
+	if duration > 0 {
+		width := int((duration + p.barScale - 1) / p.barScale)
+		if width > maxBarWidth {
+			bar = strings.Repeat("=", maxBarWidth-1) + ">"
+		} else {
+			bar = strings.Repeat("=", width)
+		}
+	}
+
+	return fmt.Sprintf("%s %s %-24s %8s %-*s %s %s",
+		interval.From.Local().Format("15:04:05.000"),
+		interval.Level.String()[:1],
+		interval.Source,
+		duration.Round(time.Millisecond),
+		maxBarWidth, bar,
+		interval.StructuredLocator.OldLocator(),
+		strings.ReplaceAll(interval.StructuredMessage.HumanMessage, "\n", "\\n"),
+	)
+}
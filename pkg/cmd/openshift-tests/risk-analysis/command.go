@@ -21,6 +21,12 @@ files after an invocation of openshift-tests. If multiple files are present
 Results are then submitted to sippy which will return an analysis of per-test
 and overall risk level given historical pass rates on the failed tests.
 The resulting analysis is then also written to the junit artifacts directory.
+
+With --backend=local no network access is required. Historical pass rates are
+computed from the test failure summary json, junit xml, and test-pass-rates json
+files of prior runs found under --history-dir, which is required. Test failure
+summaries are only used for runs without junit xml, and only count as a run of
+the tests that failed in them.
 `),
 
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -34,5 +40,11 @@ The resulting analysis is then also written to the junit artifacts directory.
 	cmd.Flags().StringVar(&riskAnalysisOpts.SippyURL,
 		"sippy-url", sippyDefaultURL,
 		"Sippy URL API endpoint")
+	cmd.Flags().StringVar(&riskAnalysisOpts.Backend,
+		"backend", riskanalysis.BackendSippy,
+		"Risk analysis backend to use: sippy or local")
+	cmd.Flags().StringVar(&riskAnalysisOpts.HistoryDir,
+		"history-dir", riskAnalysisOpts.HistoryDir,
+		"The directory holding results of prior runs, required by the local backend.")
 	return cmd
 }
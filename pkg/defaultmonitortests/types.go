@@ -17,6 +17,7 @@ import (
 	"github.com/openshift/origin/pkg/monitortests/kubeapiserver/apiservergracefulrestart"
 
 	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/pathologicaleventlibrary"
 	"github.com/openshift/origin/pkg/monitortests/authentication/legacyauthenticationmonitortests"
 	"github.com/openshift/origin/pkg/monitortests/authentication/requiredsccmonitortests"
 	azuremetrics "github.com/openshift/origin/pkg/monitortests/cloud/azure/metrics"
@@ -38,10 +39,14 @@ import (
 	"github.com/openshift/origin/pkg/monitortests/node/nodestateanalyzer"
 	"github.com/openshift/origin/pkg/monitortests/node/watchnodes"
 	"github.com/openshift/origin/pkg/monitortests/node/watchpods"
+	"github.com/openshift/origin/pkg/monitortests/storage/disruptionstatefulworkload"
 	"github.com/openshift/origin/pkg/monitortests/storage/legacystoragemonitortests"
 	"github.com/openshift/origin/pkg/monitortests/testframework/additionaleventscollector"
 	"github.com/openshift/origin/pkg/monitortests/testframework/clusterinfoserializer"
+	"github.com/openshift/origin/pkg/monitortests/testframework/disruptioncauseanalyzer"
+	"github.com/openshift/origin/pkg/monitortests/testframework/disruptiondiagnosticsserializer"
 	"github.com/openshift/origin/pkg/monitortests/testframework/disruptionexternalservicemonitoring"
+	"github.com/openshift/origin/pkg/monitortests/testframework/disruptionlatencyserializer"
 	"github.com/openshift/origin/pkg/monitortests/testframework/disruptionserializer"
 	"github.com/openshift/origin/pkg/monitortests/testframework/e2etestanalyzer"
 	"github.com/openshift/origin/pkg/monitortests/testframework/intervalserializer"
@@ -73,14 +78,19 @@ func ListAllMonitorTests() []string {
 
 func NewMonitorTestsFor(info monitortestframework.MonitorTestInitializationInfo) (monitortestframework.MonitorTestRegistry, error) {
 
+	allowances, err := pathologicaleventlibrary.LoadPathologicalEventAllowances(info.PathologicalEventAllowanceFiles...)
+	if err != nil {
+		return nil, fmt.Errorf("could not load pathological event allowances: %w", err)
+	}
+
 	// get tests and apply any filtering defined in info
 	var startingRegistry monitortestframework.MonitorTestRegistry
 
 	switch info.ClusterStabilityDuringTest {
 	case monitortestframework.Stable:
-		startingRegistry = newDefaultMonitorTests(info)
+		startingRegistry = newDefaultMonitorTests(info, allowances)
 	case monitortestframework.Disruptive:
-		startingRegistry = newDisruptiveMonitorTests(info)
+		startingRegistry = newDisruptiveMonitorTests(info, allowances)
 	default:
 		panic(fmt.Sprintf("unknown cluster stability level: %q", info.ClusterStabilityDuringTest))
 	}
@@ -98,10 +108,10 @@ func NewMonitorTestsFor(info monitortestframework.MonitorTestInitializationInfo)
 	return startingRegistry, nil
 }
 
-func newDefaultMonitorTests(info monitortestframework.MonitorTestInitializationInfo) monitortestframework.MonitorTestRegistry {
+func newDefaultMonitorTests(info monitortestframework.MonitorTestInitializationInfo, allowances []pathologicaleventlibrary.EventMatcher) monitortestframework.MonitorTestRegistry {
 	monitorTestRegistry := monitortestframework.NewMonitorTestRegistry()
 
-	monitorTestRegistry.AddRegistryOrDie(newUniversalMonitorTests(info))
+	monitorTestRegistry.AddRegistryOrDie(newUniversalMonitorTests(info, allowances))
 
 	monitorTestRegistry.AddMonitorTestOrDie("image-registry-availability", "Image Registry", disruptionimageregistry.NewAvailabilityInvariant())
 
@@ -111,6 +121,10 @@ func newDefaultMonitorTests(info monitortestframework.MonitorTestInitializationI
 	monitorTestRegistry.AddMonitorTestOrDie("pod-network-avalibility", "Network / ovn-kubernetes", disruptionpodnetwork.NewPodNetworkAvalibilityInvariant(info))
 	monitorTestRegistry.AddMonitorTestOrDie("service-type-load-balancer-availability", "Networking / router", disruptionserviceloadbalancer.NewAvailabilityInvariant())
 	monitorTestRegistry.AddMonitorTestOrDie("ingress-availability", "Networking / router", disruptioningress.NewAvailabilityInvariant())
+	// the workload only sees node drains and reboots during an upgrade, it is not worth its volume otherwise.
+	if len(info.UpgradeTargetPayloadImagePullSpec) > 0 {
+		monitorTestRegistry.AddMonitorTestOrDie("stateful-workload-availability", "Storage", disruptionstatefulworkload.NewStatefulWorkloadAvailabilityInvariant(info))
+	}
 
 	monitorTestRegistry.AddMonitorTestOrDie("alert-summary-serializer", "Test Framework", alertanalyzer.NewAlertSummarySerializer())
 	monitorTestRegistry.AddMonitorTestOrDie("external-service-availability", "Test Framework", disruptionexternalservicemonitoring.NewAvailabilityInvariant())
@@ -119,16 +133,19 @@ func newDefaultMonitorTests(info monitortestframework.MonitorTestInitializationI
 	monitorTestRegistry.AddMonitorTestOrDie("external-azure-cloud-service-availability", "Test Framework", disruptionexternalazurecloudservicemonitoring.NewCloudAvailabilityInvariant())
 	monitorTestRegistry.AddMonitorTestOrDie("pathological-event-analyzer", "Test Framework", pathologicaleventanalyzer.NewAnalyzer())
 	monitorTestRegistry.AddMonitorTestOrDie("disruption-summary-serializer", "Test Framework", disruptionserializer.NewDisruptionSummarySerializer())
+	monitorTestRegistry.AddMonitorTestOrDie("disruption-latency-serializer", "Test Framework", disruptionlatencyserializer.NewDisruptionLatencySerializer())
+	monitorTestRegistry.AddMonitorTestOrDie("disruption-cause-analyzer", "Test Framework", disruptioncauseanalyzer.NewDisruptionCauseAnalyzer())
+	monitorTestRegistry.AddMonitorTestOrDie("disruption-diagnostics-serializer", "Test Framework", disruptiondiagnosticsserializer.NewDisruptionDiagnosticsSerializer())
 
 	monitorTestRegistry.AddMonitorTestOrDie("monitoring-statefulsets-recreation", "Monitoring", statefulsetsrecreation.NewStatefulsetsChecker())
 
 	return monitorTestRegistry
 }
 
-func newDisruptiveMonitorTests(info monitortestframework.MonitorTestInitializationInfo) monitortestframework.MonitorTestRegistry {
+func newDisruptiveMonitorTests(info monitortestframework.MonitorTestInitializationInfo, allowances []pathologicaleventlibrary.EventMatcher) monitortestframework.MonitorTestRegistry {
 	monitorTestRegistry := monitortestframework.NewMonitorTestRegistry()
 
-	monitorTestRegistry.AddRegistryOrDie(newUniversalMonitorTests(info))
+	monitorTestRegistry.AddRegistryOrDie(newUniversalMonitorTests(info, allowances))
 
 	// this data would be interesting, but I'm betting we cannot scrub the data after the fact to exclude these.
 	// monitorTestRegistry.AddMonitorTestOrDie("image-registry-availability", "Image Registry", disruptionimageregistry.NewRecordAvailabilityOnly())
@@ -140,7 +157,7 @@ func newDisruptiveMonitorTests(info monitortestframework.MonitorTestInitializati
 	return monitorTestRegistry
 }
 
-func newUniversalMonitorTests(info monitortestframework.MonitorTestInitializationInfo) monitortestframework.MonitorTestRegistry {
+func newUniversalMonitorTests(info monitortestframework.MonitorTestInitializationInfo, allowances []pathologicaleventlibrary.EventMatcher) monitortestframework.MonitorTestRegistry {
 	monitorTestRegistry := monitortestframework.NewMonitorTestRegistry()
 
 	monitorTestRegistry.AddMonitorTestOrDie("legacy-authentication-invariants", "apiserver-auth", legacyauthenticationmonitortests.NewLegacyTests())
@@ -166,7 +183,7 @@ func newUniversalMonitorTests(info monitortestframework.MonitorTestInitializatio
 
 	monitorTestRegistry.AddMonitorTestOrDie("legacy-storage-invariants", "Storage", legacystoragemonitortests.NewLegacyTests())
 
-	monitorTestRegistry.AddMonitorTestOrDie("legacy-test-framework-invariants", "Test Framework", legacytestframeworkmonitortests.NewLegacyTests(info))
+	monitorTestRegistry.AddMonitorTestOrDie("legacy-test-framework-invariants", "Test Framework", legacytestframeworkmonitortests.NewLegacyTests(info, allowances))
 	monitorTestRegistry.AddMonitorTestOrDie("timeline-serializer", "Test Framework", timelineserializer.NewTimelineSerializer())
 	monitorTestRegistry.AddMonitorTestOrDie("interval-serializer", "Test Framework", intervalserializer.NewIntervalSerializer())
 	monitorTestRegistry.AddMonitorTestOrDie("tracked-resources-serializer", "Test Framework", trackedresourcesserializer.NewTrackedResourcesSerializer())
@@ -174,7 +191,7 @@ func newUniversalMonitorTests(info monitortestframework.MonitorTestInitializatio
 	monitorTestRegistry.AddMonitorTestOrDie("additional-events-collector", "Test Framework", additionaleventscollector.NewIntervalSerializer())
 	monitorTestRegistry.AddMonitorTestOrDie("known-image-checker", "Test Framework", knownimagechecker.NewEnsureValidImages())
 	monitorTestRegistry.AddMonitorTestOrDie("e2e-test-analyzer", "Test Framework", e2etestanalyzer.NewAnalyzer())
-	monitorTestRegistry.AddMonitorTestOrDie("event-collector", "Test Framework", watchevents.NewEventWatcher())
+	monitorTestRegistry.AddMonitorTestOrDie("event-collector", "Test Framework", watchevents.NewEventWatcher(allowances))
 	monitorTestRegistry.AddMonitorTestOrDie("clusteroperator-collector", "Test Framework", watchclusteroperators.NewOperatorWatcher())
 
 	monitorTestRegistry.AddMonitorTestOrDie("azure-metrics-collector", "Test Framework", azuremetrics.NewAzureMetricsCollector())
@@ -0,0 +1,25 @@
+package defaultmonitortests
+
+import (
+	"testing"
+
+	"github.com/openshift/origin/pkg/monitortestframework"
+)
+
+func TestPhaseDeadlinesCoverPhaseWaits(t *testing.T) {
+	for _, stability := range []monitortestframework.ClusterStabilityDuringTest{monitortestframework.Stable, monitortestframework.Disruptive} {
+		t.Run(string(stability), func(t *testing.T) {
+			registry, err := NewMonitorTestsFor(monitortestframework.MonitorTestInitializationInfo{
+				ClusterStabilityDuringTest: stability,
+				// the monitor tests that only run during upgrades are registered too
+				UpgradeTargetPayloadImagePullSpec: "registry.example.com/openshift/release:upgrade",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := registry.CheckPhaseDeadlines(); err != nil {
+				t.Errorf("monitor tests would be cancelled before they give up waiting: %v", err)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package backend
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"time"
 )
@@ -37,6 +38,19 @@ type RequestContextAssociatedData struct {
 	// DNSErr is set if the there was an error during DNS lookup,
 	// this is obtained from the DNSDone client connection trace.
 	DNSErr error
+	// DNSAddrs are the addresses the host name resolved to, this
+	// is obtained from the DNSDone client connection trace.
+	DNSAddrs []string
+
+	// ConnectInfo is the result of dialing a new connection, obtained
+	// from the ConnectDone client connection trace, it is nil if the
+	// request was sent over a connection obtained from the idle pool.
+	ConnectInfo *ConnectInfo
+
+	// TLSHandshake holds the details of the TLS handshake, obtained from
+	// the TLSHandshakeDone client connection trace, it is nil if there
+	// was no handshake for this request.
+	TLSHandshake *TLSHandshakeInfo
 
 	// RoundTripDuration is the latency incurred in the
 	// round trip for this request.
@@ -78,3 +92,57 @@ type GotConnInfo struct {
 func (ci GotConnInfo) String() string {
 	return fmt.Sprintf("reused: %t wasIdle: %t idleTime: %s remote-address: %s", ci.Reused, ci.WasIdle, ci.IdleTime, ci.RemoteAddr)
 }
+
+// ConnectInfo is the result of dialing a new connection
+type ConnectInfo struct {
+	// Network and Addr are the network and the resolved IP address
+	// that was actually dialed.
+	Network string
+	Addr    string
+
+	// Err is set if the dial failed.
+	Err error
+}
+
+func (ci ConnectInfo) String() string {
+	if ci.Err != nil {
+		return fmt.Sprintf("dialed: %s/%s err: %v", ci.Network, ci.Addr, ci.Err)
+	}
+	return fmt.Sprintf("dialed: %s/%s", ci.Network, ci.Addr)
+}
+
+// TLSHandshakeInfo is the part of the tls.ConnectionState that is
+// useful to diagnose a failed request.
+type TLSHandshakeInfo struct {
+	Version            string
+	CipherSuite        string
+	ServerName         string
+	NegotiatedProtocol string
+
+	// PeerSubject and PeerNotAfter describe the leaf certificate
+	// presented by the server, if any.
+	PeerSubject  string
+	PeerNotAfter time.Time
+
+	// Err is set if the handshake failed.
+	Err error
+}
+
+// NewTLSHandshakeInfo returns the TLSHandshakeInfo of the given
+// connection state, as reported by the TLSHandshakeDone client trace.
+func NewTLSHandshakeInfo(cs tls.ConnectionState, err error) *TLSHandshakeInfo {
+	info := &TLSHandshakeInfo{
+		ServerName:         cs.ServerName,
+		NegotiatedProtocol: cs.NegotiatedProtocol,
+		Err:                err,
+	}
+	if cs.Version != 0 {
+		info.Version = tls.VersionName(cs.Version)
+		info.CipherSuite = tls.CipherSuiteName(cs.CipherSuite)
+	}
+	if len(cs.PeerCertificates) > 0 {
+		info.PeerSubject = cs.PeerCertificates[0].Subject.String()
+		info.PeerNotAfter = cs.PeerCertificates[0].NotAfter
+	}
+	return info
+}
@@ -9,6 +9,12 @@ type ProtocolType string
 const (
 	ProtocolHTTP1 ProtocolType = "http1"
 	ProtocolHTTP2 ProtocolType = "http2"
+
+	// ProtocolTCP, ProtocolDNS and ProtocolGRPC are used by the samplers
+	// that probe a backend below HTTP, see backend/sampler.Prober
+	ProtocolTCP  ProtocolType = "tcp"
+	ProtocolDNS  ProtocolType = "dns"
+	ProtocolGRPC ProtocolType = "grpc"
 )
 
 type LoadBalancerType string
@@ -36,6 +42,14 @@ func ParseStringToLoadBalancerType(input string) LoadBalancerType {
 }
 
 // TestDescriptor describes a backend disruption test
+// AdaptiveSamplingDescriptor is optionally implemented by a TestDescriptor,
+// the disruption intervals of a test whose sampler steps up to a sub-second
+// rate while an outage is suspected are marked as such, so their duration
+// is accounted without padding.
+type AdaptiveSamplingDescriptor interface {
+	AdaptiveSamplingEnabled() bool
+}
+
 type TestDescriptor interface {
 	Name() string
 	DisruptionLocator() monitorapi.Locator
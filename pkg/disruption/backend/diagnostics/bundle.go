@@ -0,0 +1,151 @@
+package diagnostics
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+)
+
+// Bundle holds the client side network diagnostics captured when a
+// backend became unavailable, it is attached to the disruption interval.
+type Bundle struct {
+	BackendName string    `json:"backendName"`
+	SampleID    uint64    `json:"sampleID"`
+	CapturedAt  time.Time `json:"capturedAt"`
+
+	// Error and ErrorClass are the error of the first failed sample, and
+	// how the local socket saw it: refused, reset, timed out, etc.
+	Error      string             `json:"error"`
+	ErrorClass backend.ErrorClass `json:"errorClass"`
+
+	// DNS is the resolution of the backend host as seen by the failed
+	// sample, and by a lookup made as soon as the backend became unavailable.
+	DNS *DNS `json:"dns,omitempty"`
+
+	// Connection is the connection the failed sample was sent over.
+	Connection *Connection `json:"connection,omitempty"`
+
+	// TLS holds the details of the TLS handshake of the failed sample,
+	// it is nil if there was no handshake, over a reused connection
+	// for instance.
+	TLS *TLS `json:"tls,omitempty"`
+
+	// RecentErrors are the last sample errors, oldest first, including
+	// the error of the failed sample.
+	RecentErrors []SampleError `json:"recentErrors,omitempty"`
+}
+
+type DNS struct {
+	Host string `json:"host,omitempty"`
+
+	// SampleAnswers and SampleErr are what the failed sample resolved
+	// the host to, it is empty if the sample did not resolve the host.
+	SampleAnswers []string `json:"sampleAnswers,omitempty"`
+	SampleErr     string   `json:"sampleErr,omitempty"`
+
+	// Answers and Err are the result of the lookup made at the transition.
+	Answers []string `json:"answers,omitempty"`
+	Err     string   `json:"err,omitempty"`
+}
+
+type Connection struct {
+	// DialedAddr is the resolved address that was actually dialed, it is
+	// empty if the connection was obtained from the idle pool.
+	DialedAddr string `json:"dialedAddr,omitempty"`
+	DialErr    string `json:"dialErr,omitempty"`
+
+	RemoteAddr string `json:"remoteAddr,omitempty"`
+	Reused     bool   `json:"reused"`
+}
+
+type TLS struct {
+	Version            string     `json:"version,omitempty"`
+	CipherSuite        string     `json:"cipherSuite,omitempty"`
+	ServerName         string     `json:"serverName,omitempty"`
+	NegotiatedProtocol string     `json:"negotiatedProtocol,omitempty"`
+	PeerSubject        string     `json:"peerSubject,omitempty"`
+	PeerNotAfter       *time.Time `json:"peerNotAfter,omitempty"`
+	Err                string     `json:"err,omitempty"`
+}
+
+type SampleError struct {
+	SampleID   uint64             `json:"sampleID"`
+	At         time.Time          `json:"at"`
+	Error      string             `json:"error"`
+	ErrorClass backend.ErrorClass `json:"errorClass"`
+}
+
+// newBundle returns the bundle of the given failed sample, without the
+// lookup made at the transition and the recent errors.
+func newBundle(backendName, host string, result *backend.SampleResult) *Bundle {
+	bundle := &Bundle{
+		BackendName: backendName,
+		SampleID:    result.Sample.ID,
+		CapturedAt:  result.Sample.StartedAt,
+		Error:       result.Error(),
+		ErrorClass:  backend.ClassifyError(result.Err()),
+		DNS:         &DNS{Host: host, SampleAnswers: result.DNSAddrs, SampleErr: errorString(result.DNSErr)},
+	}
+
+	connection := &Connection{}
+	if ci := result.ConnectInfo; ci != nil {
+		connection.DialedAddr = ci.Addr
+		connection.DialErr = errorString(ci.Err)
+	}
+	if ci := result.GotConnInfo; ci != nil {
+		connection.RemoteAddr = ci.RemoteAddr
+		connection.Reused = ci.Reused
+	}
+	if *connection != (Connection{}) {
+		bundle.Connection = connection
+	}
+
+	if hs := result.TLSHandshake; hs != nil {
+		bundle.TLS = &TLS{
+			Version:            hs.Version,
+			CipherSuite:        hs.CipherSuite,
+			ServerName:         hs.ServerName,
+			NegotiatedProtocol: hs.NegotiatedProtocol,
+			PeerSubject:        hs.PeerSubject,
+			Err:                errorString(hs.Err),
+		}
+		if !hs.PeerNotAfter.IsZero() {
+			notAfter := hs.PeerNotAfter
+			bundle.TLS.PeerNotAfter = &notAfter
+		}
+	}
+	return bundle
+}
+
+// Encode returns the bundle in a form that can be used as the value of an
+// interval annotation, which must not contain any space.
+func Encode(bundle *Bundle) (string, error) {
+	content, err := json.Marshal(bundle)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(content), nil
+}
+
+// Decode is the reverse of Encode.
+func Decode(s string) (*Bundle, error) {
+	content, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid diagnostics %q: %w", s, err)
+	}
+	bundle := &Bundle{}
+	if err := json.Unmarshal(content, bundle); err != nil {
+		return nil, fmt.Errorf("invalid diagnostics %q: %w", s, err)
+	}
+	return bundle, nil
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,151 @@
+package diagnostics
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+)
+
+// Config controls how much, and how often, the diagnostics are captured.
+type Config struct {
+	// RecentErrors is the number of the last sample errors a bundle includes.
+	RecentErrors int
+
+	// MinInterval is the minimum interval between two bundles of the
+	// backend, so a flapping backend does not flood the intervals.
+	MinInterval time.Duration
+
+	// LookupTimeout bounds the lookup of the backend host made as soon
+	// as the backend becomes unavailable.
+	LookupTimeout time.Duration
+}
+
+// DefaultConfig returns the configuration the disruption tests use.
+func DefaultConfig() Config {
+	return Config{
+		RecentErrors:  5,
+		MinInterval:   time.Minute,
+		LookupTimeout: 2 * time.Second,
+	}
+}
+
+// Resolver looks up the addresses of a host, net.Resolver satisfies it.
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// NewCollector returns a Collector that captures the diagnostics of the
+// given backend, target is the base URL of the backend, the host it
+// names is looked up as soon as the backend becomes unavailable.
+func NewCollector(backendName, target string, config Config) *Collector {
+	return newCollector(backendName, target, config, net.DefaultResolver)
+}
+
+func newCollector(backendName, target string, config Config, resolver Resolver) *Collector {
+	return &Collector{
+		backendName: backendName,
+		host:        hostOf(target),
+		config:      config,
+		resolver:    resolver,
+		pending:     map[uint64]*pendingBundle{},
+	}
+}
+
+// Collector remembers the last sample errors of a backend, and captures
+// a diagnostic Bundle at each Available->Unavailable transition, at most
+// one every MinInterval.
+// The interval tracker invokes it as it consumes the samples, in order,
+// so it is not safe for concurrent use.  A nil Collector captures nothing.
+type Collector struct {
+	backendName string
+	host        string
+	config      Config
+	resolver    Resolver
+
+	recent         []SampleError
+	lastCapturedAt time.Time
+	pending        map[uint64]*pendingBundle
+}
+
+// pendingBundle is a bundle whose lookup may still be in progress,
+// done is closed once the lookup has completed.
+type pendingBundle struct {
+	bundle *Bundle
+	done   chan struct{}
+}
+
+// Observe is invoked for every sample.
+func (c *Collector) Observe(result backend.SampleResult) {
+	if c == nil || result.Sample == nil || result.Succeeded() || c.config.RecentErrors == 0 {
+		return
+	}
+	c.recent = append(c.recent, SampleError{
+		SampleID:   result.Sample.ID,
+		At:         result.Sample.StartedAt,
+		Error:      result.Error(),
+		ErrorClass: backend.ClassifyError(result.Err()),
+	})
+	if len(c.recent) > c.config.RecentErrors {
+		c.recent = c.recent[len(c.recent)-c.config.RecentErrors:]
+	}
+}
+
+// Capture is invoked with the first failed sample of an Unavailable
+// window, that follows an Available one, it starts the lookup of the
+// backend host right away, so it reflects the moment of the transition.
+func (c *Collector) Capture(result *backend.SampleResult) {
+	if c == nil || result == nil || result.Sample == nil || result.Succeeded() {
+		return
+	}
+	at := result.Sample.StartedAt
+	if !c.lastCapturedAt.IsZero() && at.Sub(c.lastCapturedAt) < c.config.MinInterval {
+		return
+	}
+	c.lastCapturedAt = at
+
+	bundle := newBundle(c.backendName, c.host, result)
+	bundle.RecentErrors = append([]SampleError{}, c.recent...)
+	pending := &pendingBundle{bundle: bundle, done: make(chan struct{})}
+	c.pending[result.Sample.ID] = pending
+
+	if len(c.host) == 0 {
+		close(pending.done)
+		return
+	}
+	go func() {
+		defer close(pending.done)
+		ctx, cancel := context.WithTimeout(context.Background(), c.config.LookupTimeout)
+		defer cancel()
+		answers, err := c.resolver.LookupHost(ctx, c.host)
+		bundle.DNS.Answers = answers
+		bundle.DNS.Err = errorString(err)
+	}()
+}
+
+// Bundle returns the bundle captured for the given sample, it waits for
+// the lookup to complete, it returns nil if no bundle was captured.
+func (c *Collector) Bundle(sampleID uint64) *Bundle {
+	if c == nil {
+		return nil
+	}
+	pending, ok := c.pending[sampleID]
+	if !ok {
+		return nil
+	}
+	delete(c.pending, sampleID)
+	<-pending.done
+	return pending.bundle
+}
+
+// hostOf returns the host name in the given base URL, or the
+// given target itself if it is not a URL.
+func hostOf(target string) string {
+	u, err := url.Parse(target)
+	if err != nil || len(u.Host) == 0 {
+		return target
+	}
+	return u.Hostname()
+}
@@ -0,0 +1,124 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/disruption/sampler"
+)
+
+type fakeResolver struct {
+	answers []string
+	err     error
+	hosts   []string
+}
+
+func (r *fakeResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	r.hosts = append(r.hosts, host)
+	return r.answers, r.err
+}
+
+func TestCollector(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	failed := func(id uint64, at time.Duration, err error) backend.SampleResult {
+		return backend.SampleResult{Sample: &sampler.Sample{ID: id, StartedAt: start.Add(at), Err: err}}
+	}
+	reset := fmt.Errorf("read: %w", syscall.ECONNRESET)
+
+	resolver := &fakeResolver{answers: []string{"10.0.0.1", "10.0.0.2"}}
+	collector := newCollector("kube-api-new-connections", "https://api.ci.example.com:6443", Config{
+		RecentErrors:  2,
+		MinInterval:   time.Minute,
+		LookupTimeout: time.Second,
+	}, resolver)
+
+	first := failed(1, 0, fmt.Errorf("dial: %w", syscall.ECONNREFUSED))
+	first.DNSAddrs = []string{"10.0.0.1"}
+	first.ConnectInfo = &backend.ConnectInfo{Network: "tcp", Addr: "10.0.0.1:6443", Err: syscall.ECONNREFUSED}
+	second := failed(2, time.Second, reset)
+	second.GotConnInfo = &backend.GotConnInfo{RemoteAddr: "10.0.0.2:6443", Reused: true}
+	third := failed(3, 30*time.Second, reset)
+	fourth := failed(4, 2*time.Minute, reset)
+
+	for _, result := range []backend.SampleResult{first, second, third, fourth} {
+		result := result
+		collector.Observe(result)
+		collector.Capture(&result)
+	}
+
+	if bundle := collector.Bundle(3); bundle != nil {
+		t.Errorf("expected the bundle of sample 3 to be rate limited, but got: %+v", bundle)
+	}
+	if bundle := collector.Bundle(42); bundle != nil {
+		t.Errorf("expected no bundle for a sample that was not captured, but got: %+v", bundle)
+	}
+
+	bundle := collector.Bundle(1)
+	if bundle == nil {
+		t.Fatalf("expected a bundle for sample 1")
+	}
+	if want, got := backend.ErrorClassConnectionRefused, bundle.ErrorClass; want != got {
+		t.Errorf("expected error class: %s, but got: %s", want, got)
+	}
+	expectedDNS := &DNS{Host: "api.ci.example.com", SampleAnswers: []string{"10.0.0.1"}, Answers: []string{"10.0.0.1", "10.0.0.2"}}
+	if !reflect.DeepEqual(expectedDNS, bundle.DNS) {
+		t.Errorf("expected DNS: %+v, but got: %+v", expectedDNS, bundle.DNS)
+	}
+	expectedConnection := &Connection{DialedAddr: "10.0.0.1:6443", DialErr: syscall.ECONNREFUSED.Error()}
+	if !reflect.DeepEqual(expectedConnection, bundle.Connection) {
+		t.Errorf("expected connection: %+v, but got: %+v", expectedConnection, bundle.Connection)
+	}
+	if bundle := collector.Bundle(1); bundle != nil {
+		t.Errorf("expected the bundle to be returned once, but got: %+v", bundle)
+	}
+
+	bundle = collector.Bundle(4)
+	if bundle == nil {
+		t.Fatalf("expected a bundle for sample 4")
+	}
+	if want, got := []uint64{3, 4}, sampleIDs(bundle.RecentErrors); !reflect.DeepEqual(want, got) {
+		t.Errorf("expected the recent errors of samples %v, but got: %v", want, got)
+	}
+	if want, got := backend.ErrorClassConnectionReset, bundle.ErrorClass; want != got {
+		t.Errorf("expected error class: %s, but got: %s", want, got)
+	}
+	if want, got := []string{"api.ci.example.com", "api.ci.example.com"}, resolver.hosts; !reflect.DeepEqual(want, got) {
+		t.Errorf("expected lookups: %v, but got: %v", want, got)
+	}
+
+	// the bundle must survive the round trip through the interval annotation
+	encoded, err := Encode(bundle)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	decoded, err := Decode(encoded)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(bundle, decoded) {
+		t.Errorf("expected decoded bundle: %+v, but got: %+v", bundle, decoded)
+	}
+}
+
+func TestNilCollector(t *testing.T) {
+	var collector *Collector
+	result := backend.SampleResult{Sample: &sampler.Sample{ID: 1, Err: fmt.Errorf("error")}}
+	collector.Observe(result)
+	collector.Capture(&result)
+	if bundle := collector.Bundle(1); bundle != nil {
+		t.Errorf("expected no bundle, but got: %+v", bundle)
+	}
+}
+
+func sampleIDs(errs []SampleError) []uint64 {
+	ids := []uint64{}
+	for _, err := range errs {
+		ids = append(ids, err.SampleID)
+	}
+	return ids
+}
@@ -7,6 +7,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/disruption/backend/diagnostics"
 	"github.com/openshift/origin/pkg/monitor/backenddisruption"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 
@@ -18,14 +19,17 @@ import (
 // that can record the availability and unavailability
 // interval in CI using the Monitor API and the event handler.
 //
+//	diagnostics: the diagnostics captured at each Available->Unavailable
+//	  transition, attached to the unavailable interval, it can be nil
 //	monitor: Monitor API to start and end an interval in CI
 //	eventRecorder: to create events associated with the intervals
 //	locator: the CI locator assigned to this disruption test
 //	name: name of the disruption test
 //	connType: user specified BackendConnectionType used in this test
-func newCIHandler(descriptor backend.TestDescriptor, monitor monitorapi.RecorderWriter, eventRecorder events.EventRecorder) *ciHandler {
+func newCIHandler(descriptor backend.TestDescriptor, diagnostics *diagnostics.Collector, monitor monitorapi.RecorderWriter, eventRecorder events.EventRecorder) *ciHandler {
 	return &ciHandler{
 		descriptor:      descriptor,
+		diagnostics:     diagnostics,
 		monitorRecorder: monitor,
 		eventRecorder:   eventRecorder,
 		openIntervalID:  -1,
@@ -38,6 +42,7 @@ var _ backend.WantEventRecorderAndMonitorRecorder = &ciHandler{}
 // ciHandler records the availability and unavailability interval in CI
 type ciHandler struct {
 	descriptor      backend.TestDescriptor
+	diagnostics     *diagnostics.Collector
 	monitorRecorder monitorapi.RecorderWriter
 	eventRecorder   events.EventRecorder
 
@@ -76,7 +81,19 @@ func (h *ciHandler) Unavailable(from, to *backend.SampleResult) {
 		&v1.ObjectReference{Kind: "OpenShiftTest", Namespace: "kube-system", Name: h.descriptor.Name()},
 		nil, v1.EventTypeWarning, string(eventReason), "detected", message.BuildString())
 
-	interval := monitorapi.NewInterval(monitorapi.SourceDisruption, level).Locator(h.descriptor.DisruptionLocator()).
+	if adaptive, ok := h.descriptor.(backend.AdaptiveSamplingDescriptor); ok && adaptive.AdaptiveSamplingEnabled() {
+		message = message.WithAnnotation(monitorapi.AnnotationAdaptiveSampling, "true")
+	}
+	// the diagnostics are too verbose for the event, the interval carries them
+	if bundle := h.diagnostics.Bundle(fs.ID); bundle != nil {
+		if encoded, err := diagnostics.Encode(bundle); err != nil {
+			klog.Errorf("failed to encode the diagnostics of %s: %v", h.descriptor.Name(), err)
+		} else {
+			message = message.WithAnnotation(monitorapi.AnnotationDiagnostics, encoded)
+		}
+	}
+
+	interval := monitorapi.NewInterval(monitorapi.SourceDisruption, level).Locator(instanceLocator(h.descriptor, from)).
 		Message(message).Build(fs.StartedAt, time.Time{})
 	openIntervalID := h.monitorRecorder.StartInterval(interval)
 	// TODO: unlikely in the real world, if from == to for some reason,
@@ -104,3 +121,20 @@ func (h *ciHandler) Available(from, to *backend.SampleResult) {
 	openIntervalID := h.monitorRecorder.StartInterval(interval)
 	h.monitorRecorder.EndInterval(openIntervalID, ts.StartedAt)
 }
+
+// instanceLocator returns the disruption locator of the test, with
+// the server instance, and its node, the first failed sample of the
+// interval was served by, if known, so the disruption can be broken
+// down per instance.
+func instanceLocator(descriptor backend.TestDescriptor, result *backend.SampleResult) monitorapi.Locator {
+	locator := descriptor.DisruptionLocator()
+	instance := result.ServerInstance()
+	if len(instance.Name) == 0 {
+		return locator
+	}
+	locator.Keys[monitorapi.LocatorServerInstanceKey] = instance.Name
+	if len(instance.Node) > 0 {
+		locator.Keys[monitorapi.LocatorNodeKey] = instance.Node
+	}
+	return locator
+}
@@ -2,6 +2,7 @@ package disruption
 
 import (
 	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/disruption/backend/diagnostics"
 	backendsampler "github.com/openshift/origin/pkg/disruption/backend/sampler"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	"k8s.io/client-go/tools/events"
@@ -14,7 +15,12 @@ import (
 //
 //   - records each interval window in CI appropriately
 //
+//   - captures the client side network diagnostics at each
+//     Available->Unavailable transition, and attaches them
+//     to the unavailable interval
+//
 //     delegate: the next SampleCollector in the chain to be invoked
+//     diagnostics: captures the diagnostics, it can be nil
 //     monitor: Monitor API to start and end an interval in CI
 //     eventRecorder: to create events associated with the intervals
 //     locator: the CI locator assigned to this disruption test
@@ -28,12 +34,13 @@ import (
 // it will generate the following disruption intervals
 //
 //	unavailable[s2,s4] available[s5,s6] unavailable[s7] available[s8]
-func NewIntervalTracker(delegate backendsampler.SampleCollector, descriptor backend.TestDescriptor, monitorRecorder monitorapi.RecorderWriter,
-	eventRecorder events.EventRecorder) (backendsampler.SampleCollector, backend.WantEventRecorderAndMonitorRecorder) {
-	handler := newCIHandler(descriptor, monitorRecorder, eventRecorder)
+func NewIntervalTracker(delegate backendsampler.SampleCollector, descriptor backend.TestDescriptor, diagnostics *diagnostics.Collector,
+	monitorRecorder monitorapi.RecorderWriter, eventRecorder events.EventRecorder) (backendsampler.SampleCollector, backend.WantEventRecorderAndMonitorRecorder) {
+	handler := newCIHandler(descriptor, diagnostics, monitorRecorder, eventRecorder)
 	return &intervalTracker{
-		delegate: delegate,
-		handler:  handler,
+		delegate:    delegate,
+		handler:     handler,
+		diagnostics: diagnostics,
 	}, handler
 }
 
@@ -57,8 +64,9 @@ type intervalHandler interface {
 }
 
 type intervalTracker struct {
-	delegate backendsampler.SampleCollector
-	handler  intervalHandler
+	delegate    backendsampler.SampleCollector
+	handler     intervalHandler
+	diagnostics *diagnostics.Collector
 
 	from     *backend.SampleResult
 	previous *backend.SampleResult
@@ -87,6 +95,7 @@ func (t *intervalTracker) collect(result backend.SampleResult) {
 	}
 
 	current := &result
+	t.diagnostics.Observe(result)
 	if t.previous == nil {
 		// this is the very first sample
 		switch {
@@ -97,6 +106,7 @@ func (t *intervalTracker) collect(result backend.SampleResult) {
 			// the very first sample failed, we will need to start
 			// an Unavailable window from this sample.
 			t.from = current
+			t.diagnostics.Capture(current)
 		}
 		t.previous = current
 		return
@@ -109,6 +119,10 @@ func (t *intervalTracker) collect(result backend.SampleResult) {
 	case previous.Succeeded() && current.Succeeded():
 		return
 	case !previous.Succeeded() && !current.Succeeded():
+		// a change of server instance alone does not start a new interval,
+		// every interval is padded to a second when the disruption is
+		// accounted, the interval is attributed to the server instance of
+		// its first failed sample.
 		if previous.Error() == current.Error() {
 			return
 		}
@@ -126,6 +140,7 @@ func (t *intervalTracker) collect(result backend.SampleResult) {
 		if t.from != nil {
 			t.handler.Available(t.from, current)
 		}
+		t.diagnostics.Capture(current)
 	}
 	t.from = current
 }
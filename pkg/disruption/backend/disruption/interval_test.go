@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 
 	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/disruption/backend/diagnostics"
 	"github.com/openshift/origin/pkg/disruption/sampler"
 )
 
@@ -17,6 +19,7 @@ func TestDisruptionTracker(t *testing.T) {
 		samples                []backend.SampleResult
 		unavailable, available int
 		intervals              []interval
+		disruption             time.Duration
 	}{
 		{
 			name: "no samples",
@@ -164,6 +167,48 @@ func TestDisruptionTracker(t *testing.T) {
 				{from: 4, to: 4},
 			},
 		},
+		{
+			name: "same failures from different server instances, a single interval expected",
+			samples: []backend.SampleResult{
+				{Sample: &sampler.Sample{ID: 1}},
+				{Sample: &sampler.Sample{ID: 2, Err: fmt.Errorf("error")}, RequestResponse: servedBy("apiserver-1")},
+				{Sample: &sampler.Sample{ID: 3, Err: fmt.Errorf("error")}, RequestResponse: servedBy("apiserver-1")},
+				{Sample: &sampler.Sample{ID: 4, Err: fmt.Errorf("error")}, RequestResponse: servedBy("apiserver-2")},
+				{Sample: &sampler.Sample{ID: 5}},
+				{Sample: nil},
+			},
+			unavailable: 1,
+			available:   2,
+			intervals: []interval{
+				{from: 1, to: 1},
+				{from: 2, to: 5},
+				{from: 5, to: 5},
+			},
+		},
+		{
+			name: "variable spacing, the sampler steps up after a failure and back down",
+			samples: []backend.SampleResult{
+				{Sample: startedAt(1, 0)},
+				{Sample: startedAt(2, time.Second)},
+				{Sample: failedAt(3, 2*time.Second)},
+				{Sample: failedAt(4, 2100*time.Millisecond)},
+				{Sample: startedAt(5, 2200*time.Millisecond)},
+				{Sample: failedAt(6, 2300*time.Millisecond)},
+				{Sample: startedAt(7, 2400*time.Millisecond)},
+				{Sample: startedAt(8, 3400*time.Millisecond)},
+				{Sample: nil},
+			},
+			unavailable: 2,
+			available:   3,
+			intervals: []interval{
+				{from: 1, to: 1},
+				{from: 3, to: 5},
+				{from: 5, to: 6},
+				{from: 6, to: 7},
+				{from: 7, to: 8},
+			},
+			disruption: 300 * time.Millisecond,
+		},
 	}
 
 	for _, test := range tests {
@@ -187,6 +232,9 @@ func TestDisruptionTracker(t *testing.T) {
 			if test.unavailable != handler.unavailable {
 				t.Errorf("expected %d unavailable intervals, but got: %d", test.unavailable, handler.unavailable)
 			}
+			if test.disruption != handler.disruption {
+				t.Errorf("expected disruption of %s, but got: %s", test.disruption, handler.disruption)
+			}
 			if !reflect.DeepEqual(test.intervals, handler.intervals) {
 				t.Errorf("expected intervals: %v", test.intervals)
 				t.Errorf("actual intervals: %v", handler.intervals)
@@ -196,6 +244,57 @@ func TestDisruptionTracker(t *testing.T) {
 	}
 }
 
+func TestDisruptionTrackerCapturesDiagnostics(t *testing.T) {
+	collector := diagnostics.NewCollector("kube-api-new-connections", "", diagnostics.DefaultConfig())
+	tracker := &intervalTracker{handler: &fakeHandler{t: t}, diagnostics: collector}
+
+	samples := []backend.SampleResult{
+		{Sample: failedAt(1, 0)},
+		{Sample: failedAt(2, time.Second)},
+		{Sample: startedAt(3, 2*time.Second)},
+		// a transition shortly after the previous one is rate limited
+		{Sample: failedAt(4, 3*time.Second)},
+		{Sample: startedAt(5, 4*time.Second)},
+		{Sample: failedAt(6, 2*time.Minute)},
+		{Sample: nil},
+	}
+	for i := range samples {
+		tracker.Collect(samples[i])
+	}
+
+	recentErrors := map[uint64]int{1: 1, 6: 4}
+	for id := uint64(1); id <= 6; id++ {
+		bundle := collector.Bundle(id)
+		want, captured := recentErrors[id]
+		switch {
+		case !captured && bundle != nil:
+			t.Errorf("expected no diagnostics for sample %d, but got: %+v", id, bundle)
+		case captured && bundle == nil:
+			t.Errorf("expected diagnostics for sample %d", id)
+		case captured && len(bundle.RecentErrors) != want:
+			t.Errorf("expected %d recent errors for sample %d, but got: %+v", want, id, bundle.RecentErrors)
+		}
+	}
+}
+
+func servedBy(identity string) backend.RequestResponse {
+	return backend.RequestResponse{
+		RequestContextAssociatedData: backend.RequestContextAssociatedData{
+			ShutdownResponse: &backend.ShutdownResponse{APIServerIdentity: identity, Hostname: identity},
+		},
+	}
+}
+
+var start = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func startedAt(id uint64, at time.Duration) *sampler.Sample {
+	return &sampler.Sample{ID: id, StartedAt: start.Add(at)}
+}
+
+func failedAt(id uint64, at time.Duration) *sampler.Sample {
+	return &sampler.Sample{ID: id, StartedAt: start.Add(at), Err: fmt.Errorf("error")}
+}
+
 type interval struct {
 	from, to uint64
 }
@@ -203,12 +302,14 @@ type fakeHandler struct {
 	t                      *testing.T
 	available, unavailable int
 	intervals              []interval
+	disruption             time.Duration
 	openIntervalID         int
 	err                    error
 }
 
 func (f *fakeHandler) Unavailable(from, to *backend.SampleResult) {
 	f.unavailable++
+	f.disruption += to.Sample.StartedAt.Sub(from.Sample.StartedAt)
 	f.intervals = append(f.intervals, interval{from: from.Sample.ID, to: to.Sample.ID})
 }
 func (f *fakeHandler) Available(from, to *backend.SampleResult) {
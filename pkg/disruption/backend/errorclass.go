@@ -0,0 +1,71 @@
+package backend
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+)
+
+// ErrorClass is a coarse classification of the error of a failed sample,
+// as seen by the local socket, it tells apart a server that actively
+// refused or reset the connection from a network that dropped it.
+type ErrorClass string
+
+const (
+	ErrorClassNone               ErrorClass = ""
+	ErrorClassDNS                ErrorClass = "dns"
+	ErrorClassConnectionRefused  ErrorClass = "connection-refused"
+	ErrorClassConnectionReset    ErrorClass = "connection-reset"
+	ErrorClassBrokenPipe         ErrorClass = "broken-pipe"
+	ErrorClassHostUnreachable    ErrorClass = "host-unreachable"
+	ErrorClassNetworkUnreachable ErrorClass = "network-unreachable"
+	ErrorClassTLS                ErrorClass = "tls"
+	ErrorClassTimeout            ErrorClass = "timeout"
+	ErrorClassEOF                ErrorClass = "eof"
+	ErrorClassOther              ErrorClass = "other"
+)
+
+// ClassifyError returns the ErrorClass of the given error, it
+// returns ErrorClassNone if err is nil.
+func ClassifyError(err error) ErrorClass {
+	if err == nil {
+		return ErrorClassNone
+	}
+
+	var (
+		dnsErr       *net.DNSError
+		recordErr    tls.RecordHeaderError
+		alertErr     tls.AlertError
+		verifyErr    *tls.CertificateVerificationError
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+		netErr       net.Error
+	)
+	switch {
+	case errors.As(err, &dnsErr):
+		return ErrorClassDNS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return ErrorClassConnectionRefused
+	case errors.Is(err, syscall.ECONNRESET):
+		return ErrorClassConnectionReset
+	case errors.Is(err, syscall.EPIPE):
+		return ErrorClassBrokenPipe
+	case errors.Is(err, syscall.EHOSTUNREACH):
+		return ErrorClassHostUnreachable
+	case errors.Is(err, syscall.ENETUNREACH):
+		return ErrorClassNetworkUnreachable
+	case errors.As(err, &recordErr), errors.As(err, &alertErr), errors.As(err, &verifyErr),
+		errors.As(err, &authorityErr), errors.As(err, &hostnameErr), errors.As(err, &invalidErr):
+		return ErrorClassTLS
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorClassTimeout
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return ErrorClassEOF
+	}
+	return ErrorClassOther
+}
@@ -0,0 +1,47 @@
+package backend
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestClassifyError(t *testing.T) {
+	// the http client wraps the socket errors the same way
+	wrap := func(op string, err error) error {
+		return &url.Error{Op: "Get", URL: "https://api.cluster:6443/healthz", Err: &net.OpError{Op: op, Net: "tcp", Err: os.NewSyscallError(op, err)}}
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected ErrorClass
+	}{
+		{name: "no error", expected: ErrorClassNone},
+		{name: "connection refused", err: wrap("dial", syscall.ECONNREFUSED), expected: ErrorClassConnectionRefused},
+		{name: "connection reset", err: wrap("read", syscall.ECONNRESET), expected: ErrorClassConnectionReset},
+		{name: "broken pipe", err: wrap("write", syscall.EPIPE), expected: ErrorClassBrokenPipe},
+		{name: "no route to host", err: wrap("dial", syscall.EHOSTUNREACH), expected: ErrorClassHostUnreachable},
+		{name: "network is unreachable", err: wrap("dial", syscall.ENETUNREACH), expected: ErrorClassNetworkUnreachable},
+		{name: "no such host", err: &url.Error{Op: "Get", URL: "https://api.cluster:6443", Err: &net.DNSError{Err: "no such host", Name: "api.cluster", IsNotFound: true}}, expected: ErrorClassDNS},
+		{name: "dns timeout", err: &net.DNSError{Err: "i/o timeout", Name: "api.cluster", IsTimeout: true}, expected: ErrorClassDNS},
+		{name: "unknown authority", err: fmt.Errorf("tls: %w", x509.UnknownAuthorityError{}), expected: ErrorClassTLS},
+		{name: "context deadline", err: fmt.Errorf("request failed: %w", context.DeadlineExceeded), expected: ErrorClassTimeout},
+		{name: "eof", err: &url.Error{Op: "Get", URL: "https://api.cluster:6443", Err: io.EOF}, expected: ErrorClassEOF},
+		{name: "unexpected status code", err: errors.New("expected status code 200, got: 503"), expected: ErrorClassOther},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := ClassifyError(test.err); test.expected != got {
+				t.Errorf("expected error class: %q, but got: %q", test.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package latency
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBuckets are the upper bounds of the latency histogram buckets, they
+// go from what a healthy backend answers in to well past the sample timeout.
+var DefaultBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+}
+
+// Histogram counts round trip latencies in buckets, like a prometheus
+// histogram, except the counts are not cumulative.
+type Histogram struct {
+	// Buckets are the upper bounds of the buckets, in increasing order.
+	Buckets []time.Duration
+	// Counts holds one count per bucket, plus a last one for the
+	// latencies over the last bucket.
+	Counts []int64
+}
+
+// NewHistogram returns an empty histogram with the DefaultBuckets.
+func NewHistogram() *Histogram {
+	return &Histogram{
+		Buckets: DefaultBuckets,
+		Counts:  make([]int64, len(DefaultBuckets)+1),
+	}
+}
+
+// Observe counts the given latency.
+func (h *Histogram) Observe(latency time.Duration) {
+	for i, upper := range h.Buckets {
+		if latency <= upper {
+			h.Counts[i]++
+			return
+		}
+	}
+	h.Counts[len(h.Buckets)]++
+}
+
+// Add adds the counts of the other histogram, both must have the same buckets.
+func (h *Histogram) Add(other *Histogram) error {
+	if len(h.Buckets) != len(other.Buckets) {
+		return fmt.Errorf("histograms have a different number of buckets: %d and %d", len(h.Buckets), len(other.Buckets))
+	}
+	for i := range h.Buckets {
+		if h.Buckets[i] != other.Buckets[i] {
+			return fmt.Errorf("histograms have different buckets: %s and %s", h.Buckets[i], other.Buckets[i])
+		}
+	}
+	for i := range h.Counts {
+		h.Counts[i] += other.Counts[i]
+	}
+	return nil
+}
+
+// Count returns the number of latencies observed.
+func (h *Histogram) Count() int64 {
+	count := int64(0)
+	for _, c := range h.Counts {
+		count += c
+	}
+	return count
+}
+
+// Quantile estimates the latency at the given quantile, 0.99 for instance,
+// by linear interpolation within the bucket the quantile falls in, the way
+// prometheus' histogram_quantile does.  A quantile over the last bucket is
+// reported as the upper bound of the last bucket.
+func (h *Histogram) Quantile(q float64) time.Duration {
+	count := h.Count()
+	if count == 0 {
+		return 0
+	}
+	rank := q * float64(count)
+	cumulative := int64(0)
+	for i, upper := range h.Buckets {
+		if h.Counts[i] == 0 || float64(cumulative+h.Counts[i]) < rank {
+			cumulative += h.Counts[i]
+			continue
+		}
+		lower := time.Duration(0)
+		if i > 0 {
+			lower = h.Buckets[i-1]
+		}
+		fraction := (rank - float64(cumulative)) / float64(h.Counts[i])
+		return lower + time.Duration(math.Round(fraction*float64(upper-lower)))
+	}
+	return h.Buckets[len(h.Buckets)-1]
+}
+
+// String encodes the histogram as a comma separated list of
+// upper-bound:count pairs, for instance 5ms:10,10ms:2,+Inf:0
+func (h *Histogram) String() string {
+	pairs := make([]string, 0, len(h.Counts))
+	for i, upper := range h.Buckets {
+		pairs = append(pairs, fmt.Sprintf("%s:%d", upper, h.Counts[i]))
+	}
+	pairs = append(pairs, fmt.Sprintf("+Inf:%d", h.Counts[len(h.Buckets)]))
+	return strings.Join(pairs, ",")
+}
+
+// ParseHistogram decodes a histogram encoded by String.
+func ParseHistogram(encoded string) (*Histogram, error) {
+	h := &Histogram{}
+	pairs := strings.Split(encoded, ",")
+	for i, pair := range pairs {
+		upper, count, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid histogram bucket %q", pair)
+		}
+		c, err := strconv.ParseInt(count, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid count in histogram bucket %q: %w", pair, err)
+		}
+		h.Counts = append(h.Counts, c)
+
+		if i == len(pairs)-1 {
+			if upper != "+Inf" {
+				return nil, fmt.Errorf("the last histogram bucket must be +Inf, not %q", upper)
+			}
+			break
+		}
+		bound, err := time.ParseDuration(upper)
+		if err != nil {
+			return nil, fmt.Errorf("invalid upper bound in histogram bucket %q: %w", pair, err)
+		}
+		if len(h.Buckets) > 0 && bound <= h.Buckets[len(h.Buckets)-1] {
+			return nil, fmt.Errorf("histogram buckets must be in increasing order: %q", encoded)
+		}
+		h.Buckets = append(h.Buckets, bound)
+	}
+	if len(h.Buckets) == 0 {
+		return nil, fmt.Errorf("histogram has no buckets: %q", encoded)
+	}
+	return h, nil
+}
@@ -0,0 +1,93 @@
+package latency
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHistogramQuantile(t *testing.T) {
+	tests := []struct {
+		name      string
+		latencies []time.Duration
+		quantile  float64
+		expected  time.Duration
+	}{
+		{
+			name:     "no latencies",
+			quantile: 0.99,
+			expected: 0,
+		},
+		{
+			name:      "all in the first bucket",
+			latencies: []time.Duration{time.Millisecond, 2 * time.Millisecond, 3 * time.Millisecond, 4 * time.Millisecond},
+			quantile:  0.50,
+			expected:  2500 * time.Microsecond,
+		},
+		{
+			name: "interpolated within the bucket",
+			latencies: []time.Duration{
+				time.Millisecond, time.Millisecond, time.Millisecond, time.Millisecond,
+				200 * time.Millisecond, 200 * time.Millisecond, 200 * time.Millisecond, 200 * time.Millisecond,
+			},
+			quantile: 0.75,
+			expected: 175 * time.Millisecond,
+		},
+		{
+			name:      "over the last bucket",
+			latencies: []time.Duration{time.Millisecond, time.Minute},
+			quantile:  0.99,
+			expected:  30 * time.Second,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := NewHistogram()
+			for _, latency := range test.latencies {
+				h.Observe(latency)
+			}
+			if want, got := int64(len(test.latencies)), h.Count(); want != got {
+				t.Errorf("expected count: %d, but got: %d", want, got)
+			}
+			if got := h.Quantile(test.quantile); test.expected != got {
+				t.Errorf("expected quantile %v to be: %s, but got: %s", test.quantile, test.expected, got)
+			}
+		})
+	}
+}
+
+func TestHistogramStringAndParse(t *testing.T) {
+	h := NewHistogram()
+	h.Observe(3 * time.Millisecond)
+	h.Observe(70 * time.Millisecond)
+	h.Observe(time.Minute)
+
+	encoded := h.String()
+	if want := "5ms:1,10ms:0,25ms:0,50ms:0,100ms:1,250ms:0,500ms:0,1s:0,2.5s:0,5s:0,10s:0,30s:0,+Inf:1"; want != encoded {
+		t.Errorf("expected encoded histogram: %s, but got: %s", want, encoded)
+	}
+
+	parsed, err := ParseHistogram(encoded)
+	if err != nil {
+		t.Fatalf("expected no error, but got: %v", err)
+	}
+	if !reflect.DeepEqual(h, parsed) {
+		t.Errorf("expected the parsed histogram to match, want: %v, got: %v", h, parsed)
+	}
+
+	if err := parsed.Add(h); err != nil {
+		t.Errorf("expected no error, but got: %v", err)
+	}
+	if want, got := int64(6), parsed.Count(); want != got {
+		t.Errorf("expected count: %d, but got: %d", want, got)
+	}
+	if err := parsed.Add(&Histogram{Buckets: []time.Duration{time.Second}, Counts: []int64{1, 0}}); err == nil {
+		t.Errorf("expected an error adding a histogram with different buckets")
+	}
+
+	for _, invalid := range []string{"", "5ms:1", "5ms:1,+Inf", "10ms:1,5ms:1,+Inf:0", "+Inf:0", "5ms:a,+Inf:0"} {
+		if _, err := ParseHistogram(invalid); err == nil {
+			t.Errorf("expected an error parsing %q", invalid)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package latency
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"k8s.io/client-go/tools/events"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	backendsampler "github.com/openshift/origin/pkg/disruption/backend/sampler"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// minWindowSamples is the number of successful samples the window must
+// hold before its percentile is compared with the threshold, so a single
+// slow sample right after start up does not mark the backend degraded.
+const minWindowSamples = 5
+
+// Config is the latency objective of a backend, a sliding window
+// percentile of the round trip latency that should stay at or under
+// the threshold.
+type Config struct {
+	// Percentile of the round trip latency of the successful
+	// samples in the window, 95 for instance.
+	Percentile float64
+
+	// Window is how far back the samples used to calculate
+	// the percentile go.
+	Window time.Duration
+
+	// Threshold is the latency the percentile should not go over,
+	// zero disables the degraded intervals, the histogram of the
+	// round trip latency is recorded regardless.
+	Threshold time.Duration
+}
+
+func (c Config) Validate() error {
+	if c.Threshold == 0 {
+		return nil
+	}
+	if c.Threshold < 0 {
+		return fmt.Errorf("latency Threshold must not be negative")
+	}
+	if c.Percentile <= 0 || c.Percentile > 100 {
+		return fmt.Errorf("latency Percentile must be in (0, 100], got: %v", c.Percentile)
+	}
+	if c.Window <= 0 {
+		return fmt.Errorf("latency Window must be greater than zero")
+	}
+	return nil
+}
+
+// NewLatencyTracker returns a SampleCollector that does the following:
+//
+//   - counts the round trip latency of each successful sample
+//     in a histogram, and records the histogram in a summary
+//     interval once there are no more samples, and
+//
+//   - records a degraded interval whenever the configured
+//     percentile of the latency of the samples in the sliding
+//     window goes over the configured threshold.
+//
+//     delegate: the next SampleCollector in the chain to be invoked
+//     descriptor: the disruption test the samples belong to
+//     config: the latency objective of the backend
+//     monitor: Monitor API to record the intervals in CI
+//     eventRecorder: unused, the latency intervals are not reported as events
+//
+// Failed samples are left to the disruption interval tracker, they
+// count neither in the window nor in the histogram.
+func NewLatencyTracker(delegate backendsampler.SampleCollector, descriptor backend.TestDescriptor, config Config,
+	monitorRecorder monitorapi.RecorderWriter, eventRecorder events.EventRecorder) (*Tracker, backend.WantEventRecorderAndMonitorRecorder) {
+	t := &Tracker{
+		delegate:        delegate,
+		descriptor:      descriptor,
+		config:          config,
+		monitorRecorder: monitorRecorder,
+		histogram:       NewHistogram(),
+	}
+	return t, t
+}
+
+var _ backendsampler.SampleCollector = &Tracker{}
+var _ backend.WantEventRecorderAndMonitorRecorder = &Tracker{}
+
+// Tracker tracks the round trip latency of the samples of a disruption test
+type Tracker struct {
+	delegate        backendsampler.SampleCollector
+	descriptor      backend.TestDescriptor
+	config          Config
+	monitorRecorder monitorapi.RecorderWriter
+
+	histogram *Histogram
+	window    []windowSample
+	first     *backend.SampleResult
+	last      *backend.SampleResult
+
+	// degradedFrom is the sample the open degraded interval began at,
+	// and worst is the highest percentile seen while it is open.
+	degradedFrom *backend.SampleResult
+	worst        time.Duration
+}
+
+type windowSample struct {
+	at      time.Time
+	latency time.Duration
+}
+
+// SetEventRecorder is a no-op, the latency intervals are not reported as events
+func (t *Tracker) SetEventRecorder(events.EventRecorder) {}
+
+// SetMonitorRecorder sets the interval recorder provided by the monitor API
+func (t *Tracker) SetMonitorRecorder(monitorRecorder monitorapi.RecorderWriter) {
+	t.monitorRecorder = monitorRecorder
+}
+
+// Histogram returns the latency histogram of the samples collected so far,
+// it must not be called concurrently with Collect.
+func (t *Tracker) Histogram() *Histogram {
+	return t.histogram
+}
+
+func (t *Tracker) Collect(bs backend.SampleResult) {
+	// we receive sample in ordered sequence, 1, 2, ... n
+	if t.delegate != nil {
+		t.delegate.Collect(bs)
+	}
+	t.collect(bs)
+}
+
+func (t *Tracker) collect(result backend.SampleResult) {
+	if result.Sample == nil {
+		// no more sample arriving, close the open degraded interval, if any
+		if t.degradedFrom != nil {
+			t.degraded(t.degradedFrom, t.last)
+		}
+		t.summary()
+		return
+	}
+
+	current := &result
+	if t.first == nil {
+		t.first = current
+	}
+	t.last = current
+	if !current.Succeeded() {
+		return
+	}
+
+	latency := current.RoundTripDuration
+	t.histogram.Observe(latency)
+	if t.config.Threshold <= 0 {
+		return
+	}
+
+	at := current.Sample.StartedAt
+	t.window = append(t.window, windowSample{at: at, latency: latency})
+	for len(t.window) > 0 && !t.window[0].at.After(at.Add(-t.config.Window)) {
+		t.window = t.window[1:]
+	}
+	if len(t.window) < minWindowSamples {
+		return
+	}
+
+	observed := windowPercentile(t.window, t.config.Percentile)
+	switch {
+	case observed > t.config.Threshold && t.degradedFrom == nil:
+		t.degradedFrom, t.worst = current, observed
+	case observed > t.config.Threshold:
+		if observed > t.worst {
+			t.worst = observed
+		}
+	case t.degradedFrom != nil:
+		t.degraded(t.degradedFrom, current)
+		t.degradedFrom, t.worst = nil, 0
+	}
+}
+
+// windowPercentile returns the latency at the given percentile of the
+// samples in the window, using the nearest rank method.
+func windowPercentile(window []windowSample, percentile float64) time.Duration {
+	latencies := make([]time.Duration, 0, len(window))
+	for _, s := range window {
+		latencies = append(latencies, s.latency)
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	rank := int(math.Ceil(percentile / 100 * float64(len(latencies))))
+	if rank < 1 {
+		rank = 1
+	}
+	return latencies[rank-1]
+}
+
+// degraded records a degraded interval in this range [from ... to)
+func (t *Tracker) degraded(from, to *backend.SampleResult) {
+	if t.monitorRecorder == nil {
+		return
+	}
+	percentile := strconv.FormatFloat(t.config.Percentile, 'f', -1, 64)
+	message := monitorapi.NewMessage().
+		Reason(monitorapi.DisruptionLatencyDegradedEventReason).
+		WithAnnotation(monitorapi.AnnotationPercentile, percentile).
+		WithAnnotation(monitorapi.AnnotationLatency, t.worst.Round(time.Millisecond).String()).
+		WithAnnotation(monitorapi.AnnotationThreshold, t.config.Threshold.String()).
+		HumanMessagef("P%s round trip latency over %s went up to %s, over the %s threshold - range=[%d-%d]",
+			percentile, t.config.Window, t.worst.Round(time.Millisecond), t.config.Threshold, from.Sample.ID, to.Sample.ID)
+	klog.V(4).Infof("%s: %s", t.descriptor.Name(), message.BuildString())
+
+	t.monitorRecorder.AddIntervals(
+		monitorapi.NewInterval(monitorapi.SourceDisruptionLatency, monitorapi.Warning).
+			Locator(t.descriptor.DisruptionLocator()).
+			Message(message).
+			Build(from.Sample.StartedAt, to.Sample.StartedAt),
+	)
+}
+
+// summary records the latency histogram of all the samples
+func (t *Tracker) summary() {
+	if t.monitorRecorder == nil || t.first == nil {
+		return
+	}
+	message := monitorapi.NewMessage().
+		Reason(monitorapi.DisruptionLatencySummaryEventReason).
+		WithAnnotation(monitorapi.AnnotationCount, strconv.FormatInt(t.histogram.Count(), 10)).
+		WithAnnotation(monitorapi.AnnotationHistogram, t.histogram.String()).
+		HumanMessagef("round trip latency P50=%s P99=%s",
+			t.histogram.Quantile(0.50).Round(time.Millisecond), t.histogram.Quantile(0.99).Round(time.Millisecond))
+
+	t.monitorRecorder.AddIntervals(
+		monitorapi.NewInterval(monitorapi.SourceDisruptionLatency, monitorapi.Info).
+			Locator(t.descriptor.DisruptionLocator()).
+			Message(message).
+			Build(t.first.Sample.StartedAt, t.last.Sample.StartedAt),
+	)
+}
@@ -0,0 +1,149 @@
+package latency
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/disruption/sampler"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+type fakeDescriptor struct {
+	backend.TestDescriptor
+}
+
+func (fakeDescriptor) Name() string { return "fake-backend" }
+func (fakeDescriptor) DisruptionLocator() monitorapi.Locator {
+	return monitorapi.NewLocator().Disruption("fake-backend", "", "", "", "", monitorapi.NewConnectionType)
+}
+
+type fakeRecorder struct {
+	monitorapi.RecorderWriter
+	intervals monitorapi.Intervals
+}
+
+func (r *fakeRecorder) AddIntervals(intervals ...monitorapi.Interval) {
+	r.intervals = append(r.intervals, intervals...)
+}
+
+func TestLatencyTracker(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	// one sample a second, the latency is in milliseconds, and a
+	// negative latency makes a failed sample.
+	samples := func(latencies ...int) []backend.SampleResult {
+		results := []backend.SampleResult{}
+		for i, latency := range latencies {
+			s := &sampler.Sample{ID: uint64(i + 1), StartedAt: start.Add(time.Duration(i) * time.Second)}
+			if latency < 0 {
+				s.Err = fmt.Errorf("error")
+			}
+			result := backend.SampleResult{Sample: s}
+			result.RoundTripDuration = time.Duration(latency) * time.Millisecond
+			results = append(results, result)
+		}
+		return append(results, backend.SampleResult{})
+	}
+	config := Config{Percentile: 50, Window: 5 * time.Second, Threshold: 100 * time.Millisecond}
+
+	tests := []struct {
+		name     string
+		config   Config
+		samples  []backend.SampleResult
+		count    string
+		degraded []string
+	}{
+		{
+			name:    "no samples",
+			config:  config,
+			samples: []backend.SampleResult{{}},
+		},
+		{
+			name:    "fast samples",
+			config:  config,
+			samples: samples(10, 10, 10, 10, 10, 10, 10),
+			count:   "7",
+		},
+		{
+			name:    "slow samples, not enough in the window",
+			config:  config,
+			samples: samples(500, 500, 500, 500),
+			count:   "4",
+		},
+		{
+			name:     "slow samples, and then fast again",
+			config:   config,
+			samples:  samples(10, 10, 10, 500, 500, 500, 600, 10, 10, 10, 10, 10),
+			count:    "12",
+			degraded: []string{"range=[6-10]"},
+		},
+		{
+			name:     "slow until the end",
+			config:   config,
+			samples:  samples(500, 500, 500, 500, 500, 500),
+			count:    "6",
+			degraded: []string{"range=[5-6]"},
+		},
+		{
+			name:    "failed samples are not counted",
+			config:  config,
+			samples: samples(10, -1, -1, -1, -1, -1, 10),
+			count:   "2",
+		},
+		{
+			name:    "no threshold",
+			config:  Config{},
+			samples: samples(500, 500, 500, 500, 500, 500),
+			count:   "6",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := &fakeRecorder{}
+			tracker, _ := NewLatencyTracker(nil, fakeDescriptor{}, test.config, recorder, nil)
+			for i := range test.samples {
+				tracker.Collect(test.samples[i])
+			}
+
+			degraded := recorder.intervals.Filter(func(i monitorapi.Interval) bool {
+				return i.StructuredMessage.Reason == monitorapi.DisruptionLatencyDegradedEventReason
+			})
+			if len(test.degraded) != len(degraded) {
+				t.Fatalf("expected %d degraded intervals, but got: %v", len(test.degraded), degraded.Strings())
+			}
+			for i := range degraded {
+				if got := degraded[i].StructuredMessage.HumanMessage; !strings.Contains(got, test.degraded[i]) {
+					t.Errorf("expected degraded interval to have %q, but got: %s", test.degraded[i], got)
+				}
+				if degraded[i].Level != monitorapi.Warning {
+					t.Errorf("expected degraded interval to be a warning, but got: %s", degraded[i].Level)
+				}
+				if degraded[i].Source != monitorapi.SourceDisruptionLatency {
+					t.Errorf("expected degraded interval to be kept apart from the disruption, but got: %s", degraded[i].Source)
+				}
+			}
+
+			summary := recorder.intervals.Filter(func(i monitorapi.Interval) bool {
+				return i.StructuredMessage.Reason == monitorapi.DisruptionLatencySummaryEventReason
+			})
+			if len(test.count) == 0 {
+				if len(summary) != 0 {
+					t.Errorf("expected no summary interval, but got: %v", summary.Strings())
+				}
+				return
+			}
+			if len(summary) != 1 {
+				t.Fatalf("expected a summary interval, but got: %v", summary.Strings())
+			}
+			annotations := summary[0].StructuredMessage.Annotations
+			if want, got := test.count, annotations[monitorapi.AnnotationCount]; want != got {
+				t.Errorf("expected count: %s, but got: %s", want, got)
+			}
+			if want, got := tracker.Histogram().String(), annotations[monitorapi.AnnotationHistogram]; want != got {
+				t.Errorf("expected histogram: %s, but got: %s", want, got)
+			}
+		})
+	}
+}
@@ -29,6 +29,9 @@ func (rr RequestResponse) String() string {
 	if rr.ShutdownResponse != nil {
 		s = fmt.Sprintf("%s %s", s, rr.ShutdownResponse.String())
 	}
+	if instance := rr.ServerInstance(); len(instance.Name) > 0 {
+		s = fmt.Sprintf("%s %s", s, instance.String())
+	}
 	return s
 }
 
@@ -45,10 +48,49 @@ func (rr RequestResponse) Fields() map[string]interface{} {
 			fields[k] = v
 		}
 	}
+	if instance := rr.ServerInstance(); len(instance.Name) > 0 {
+		fields["server-instance"] = instance.Name
+		if len(instance.Node) > 0 {
+			fields["node"] = instance.Node
+		}
+	}
 
 	return fields
 }
 
+// ServerInstance identifies the server instance that served a request.
+type ServerInstance struct {
+	// Name is the identity the server sent in the shutdown response
+	// header, the remote address of the connection is not used since
+	// behind a load balancer it is the same for every server instance.
+	Name string
+
+	// Node is the node the server instance runs on, it is only known
+	// when the HostNameDecoder resolved the identity of the server.
+	Node string
+}
+
+func (si ServerInstance) String() string {
+	if len(si.Node) == 0 {
+		return fmt.Sprintf("server-instance=%s", si.Name)
+	}
+	return fmt.Sprintf("server-instance=%s node=%s", si.Name, si.Node)
+}
+
+// ServerInstance returns the server instance that served the request, the
+// name is empty if the request never made it to a server, or the server
+// did not identify itself.
+func (rr RequestResponse) ServerInstance() ServerInstance {
+	if sr := rr.ShutdownResponse; sr != nil && len(sr.APIServerIdentity) > 0 {
+		instance := ServerInstance{Name: sr.APIServerIdentity}
+		if sr.Hostname != sr.APIServerIdentity {
+			instance.Node = sr.Hostname
+		}
+		return instance
+	}
+	return ServerInstance{}
+}
+
 func (rr RequestResponse) GetAuditID() string {
 	if rr.Request != nil {
 		return rr.Request.Header.Get("Audit-ID")
@@ -0,0 +1,58 @@
+package backend
+
+import (
+	"testing"
+)
+
+func TestServerInstance(t *testing.T) {
+	connectedTo := func(remoteAddr string) RequestResponse {
+		rr := RequestResponse{}
+		rr.GotConnInfo = &GotConnInfo{RemoteAddr: remoteAddr}
+		return rr
+	}
+	servedBy := func(identity, hostname string) RequestResponse {
+		rr := RequestResponse{}
+		rr.ShutdownResponse = &ShutdownResponse{APIServerIdentity: identity, Hostname: hostname}
+		return rr
+	}
+
+	tests := []struct {
+		name     string
+		rr       RequestResponse
+		expected ServerInstance
+		node     bool
+	}{
+		{
+			name: "never made it to a server",
+		},
+		{
+			name: "behind a load balancer, the remote address is not a server instance",
+			rr:   connectedTo("10.0.0.1:6443"),
+		},
+		{
+			name:     "identity without a decoded host name",
+			rr:       servedBy("master-0", "master-0"),
+			expected: ServerInstance{Name: "master-0"},
+		},
+		{
+			name:     "identity with a decoded host name",
+			rr:       servedBy("kube-apiserver-7d9f", "master-0"),
+			expected: ServerInstance{Name: "kube-apiserver-7d9f", Node: "master-0"},
+			node:     true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.rr.ServerInstance(); test.expected != got {
+				t.Errorf("expected server instance %+v, but got: %+v", test.expected, got)
+			}
+			fields := test.rr.Fields()
+			if _, ok := fields["server-instance"]; ok != (len(test.expected.Name) > 0) {
+				t.Errorf("unexpected server-instance field: %v", fields)
+			}
+			if _, ok := fields["node"]; ok != test.node {
+				t.Errorf("unexpected node field: %v", fields)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package roundtripper
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
@@ -80,14 +81,19 @@ func WithAuditID(delegate backend.Client) backend.Client {
 	})
 }
 
-// WithGotConnTrace attaches a 'GotConn' and 'DNSDone' client
-// trace to the given request.
+// WithGotConnTrace attaches a 'GotConn', 'DNSDone', 'ConnectDone' and
+// 'TLSHandshakeDone' client trace to the given request.
 //
 //	 GotConn: this client trace is called after a successful connection is
 //		  obtained, using this trace we can infer whether this connection has
 //		  been previously used for another HTTP request.
 //	 DNSDone: this client trace is called when a DNS lookup ends, and we
-//	   can obtain the error that occurred during the DNS lookup, if any.
+//	   can obtain the addresses, or the error that occurred during the DNS
+//	   lookup, if any.
+//	 ConnectDone: this client trace is called when a new connection's dial
+//	   completes, we can obtain the resolved address that was actually dialed.
+//	 TLSHandshakeDone: this client trace is called after the TLS handshake,
+//	   we can obtain the negotiated connection state, or the handshake error.
 //
 // This function will attach the data obtained from the client trace
 // to the request context so it can be retrieved later.
@@ -116,6 +122,30 @@ func WithGotConnTrace(delegate backend.Client) backend.Client {
 				lock.Lock()
 				if data := backend.RequestContextAssociatedDataFrom(req.Context()); data != nil {
 					data.DNSErr = d.Err
+					data.DNSAddrs = nil
+					for _, addr := range d.Addrs {
+						data.DNSAddrs = append(data.DNSAddrs, addr.String())
+					}
+				}
+				lock.Unlock()
+			},
+			ConnectDone: func(network, addr string, err error) {
+				lock.Lock()
+				if data := backend.RequestContextAssociatedDataFrom(req.Context()); data != nil {
+					// with multiple addresses the dialer may try several, we keep
+					// the successful one, or the last one that failed.
+					if data.ConnectInfo == nil || data.ConnectInfo.Err != nil {
+						data.ConnectInfo = &backend.ConnectInfo{Network: network, Addr: addr, Err: err}
+					}
+				}
+				lock.Unlock()
+			},
+			TLSHandshakeDone: func(cs tls.ConnectionState, err error) {
+				info := backend.NewTLSHandshakeInfo(cs, err)
+
+				lock.Lock()
+				if data := backend.RequestContextAssociatedDataFrom(req.Context()); data != nil {
+					data.TLSHandshake = info
 				}
 				lock.Unlock()
 			},
@@ -71,3 +71,49 @@ func TestWrapClient(t *testing.T) {
 		t.Errorf("expected remote address to be set")
 	}
 }
+
+func TestWithGotConnTraceNewConnection(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	client := WrapClient(ts.Client(), 0, "my-client", false, nil)
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/echo", nil)
+	if err != nil {
+		t.Fatalf("failed to create a new HTTP request")
+	}
+	req = req.WithContext(backend.WithRequestContextAssociatedData(req.Context(), &backend.RequestContextAssociatedData{}))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status code: %d, but got: %d", http.StatusOK, resp.StatusCode)
+	}
+
+	infoGot := backend.RequestContextAssociatedDataFrom(req.Context())
+	if infoGot.ConnectInfo == nil {
+		t.Fatalf("expected a non nil %T", backend.ConnectInfo{})
+	}
+	if want, got := ts.Listener.Addr().String(), infoGot.ConnectInfo.Addr; want != got {
+		t.Errorf("expected dialed address: %s, but got: %s", want, got)
+	}
+	if infoGot.ConnectInfo.Err != nil {
+		t.Errorf("expected no dial error, but got: %v", infoGot.ConnectInfo.Err)
+	}
+	if infoGot.TLSHandshake == nil {
+		t.Fatalf("expected a non nil %T", backend.TLSHandshakeInfo{})
+	}
+	if infoGot.TLSHandshake.Err != nil {
+		t.Errorf("expected no handshake error, but got: %v", infoGot.TLSHandshake.Err)
+	}
+	if len(infoGot.TLSHandshake.Version) == 0 || len(infoGot.TLSHandshake.CipherSuite) == 0 {
+		t.Errorf("expected the negotiated version and cipher suite, but got: %+v", infoGot.TLSHandshake)
+	}
+	if want, got := "h2", infoGot.TLSHandshake.NegotiatedProtocol; want != got {
+		t.Errorf("expected negotiated protocol: %s, but got: %s", want, got)
+	}
+}
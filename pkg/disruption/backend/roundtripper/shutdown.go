@@ -73,5 +73,6 @@ func parse(csv string) (*backend.ShutdownResponse, error) {
 		ShutdownDelayDuration: shutdownDelayDuration,
 		Elapsed:               elapsedDuration,
 		Hostname:              host,
+		APIServerIdentity:     host,
 	}, nil
 }
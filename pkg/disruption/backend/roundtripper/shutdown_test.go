@@ -35,6 +35,7 @@ func TestWithShutdownResponseHeaderExtractor(t *testing.T) {
 					ShutdownDelayDuration: 70 * time.Second,
 					Elapsed:               time.Duration(0),
 					Hostname:              "foo",
+					APIServerIdentity:     "foo",
 				},
 			},
 		},
@@ -47,6 +48,7 @@ func TestWithShutdownResponseHeaderExtractor(t *testing.T) {
 					ShutdownDelayDuration: 70 * time.Second,
 					Elapsed:               10 * time.Second,
 					Hostname:              "foo",
+					APIServerIdentity:     "foo",
 				},
 			},
 		},
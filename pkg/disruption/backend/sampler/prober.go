@@ -0,0 +1,219 @@
+package sampler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/disruption/sampler"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Prober checks a backend that is not served over HTTP, for example a
+// DNS name, a TCP port or the gRPC health service of a server.
+// If it returns an error, the sample is deemed to have failed.
+type Prober interface {
+	// Target returns a URL like description of what is being probed,
+	// it is used wherever the HTTP samplers use the base URL.
+	Target() string
+
+	// Probe checks the backend once, it can use the given sample ID
+	// and must honor the deadline of the given context.
+	Probe(ctx context.Context, sampleID uint64) error
+}
+
+// NewProbeProducerConsumer returns a ProducerConsumer, the Producer uses
+// the given Prober to generate a sample, and the consumer feeds the result
+// to the specified SampleCollector, exactly as NewSampleProducerConsumer
+// does for HTTP samples, so the disruption interval tracker and the
+// logger can be used as is.
+//
+//	prober: the Prober that checks the backend once per sample
+//	timeout: the maximum amount of time a single probe may take
+//	collector: user specified SampleCollector that will collect each
+//	 sample result for further analysis.
+//
+// If the Prober implements io.Closer it is closed once there are no
+// more samples.
+func NewProbeProducerConsumer(prober Prober, timeout time.Duration, collector SampleCollector) sampler.ProducerConsumer {
+	return &probeProducerConsumer{
+		prober:    prober,
+		timeout:   timeout,
+		collector: collector,
+	}
+}
+
+type probeProducerConsumer struct {
+	prober    Prober
+	timeout   time.Duration
+	collector SampleCollector
+}
+
+func (pc *probeProducerConsumer) Produce(stop context.Context, sampleID uint64) (interface{}, error) {
+	rr := backend.RequestResponse{}
+
+	// like the HTTP producer, we intentionally don't use the stop context
+	// so a probe in progress can complete even if the stop context is Canceled.
+	ctx, cancel := context.WithTimeout(context.Background(), pc.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := pc.prober.Probe(ctx, sampleID)
+	rr.RoundTripDuration = time.Since(start)
+	return rr, err
+}
+
+func (pc *probeProducerConsumer) Consume(s *sampler.Sample, custom interface{}) {
+	// should never happen, we panic if for some programmer error
+	rr := custom.(backend.RequestResponse)
+	pc.collector.Collect(backend.SampleResult{
+		Sample:          s,
+		RequestResponse: rr,
+	})
+}
+
+func (pc *probeProducerConsumer) Close() {
+	if closer, ok := pc.prober.(io.Closer); ok {
+		closer.Close()
+	}
+	// no more sample available, send an empty value
+	pc.collector.Collect(backend.SampleResult{})
+}
+
+// NewTCPProber returns a Prober that opens, and immediately closes, a new
+// TCP connection to the given address, for example a node port.
+func NewTCPProber(address string) Prober {
+	return &tcpProber{address: address}
+}
+
+type tcpProber struct {
+	address string
+	dialer  net.Dialer
+}
+
+func (p *tcpProber) Target() string {
+	return fmt.Sprintf("tcp://%s", p.address)
+}
+
+func (p *tcpProber) Probe(ctx context.Context, _ uint64) error {
+	conn, err := p.dialer.DialContext(ctx, "tcp", p.address)
+	if err != nil {
+		return &KnownError{category: "TCPConnectError", err: err}
+	}
+	return conn.Close()
+}
+
+// HostResolver resolves a host name, it is satisfied by net.Resolver.
+type HostResolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// NewDNSProber returns a Prober that resolves the given host name, for
+// example kubernetes.default.svc, with the given resolver.  If resolver
+// is nil net.DefaultResolver is used.
+func NewDNSProber(host string, resolver HostResolver) Prober {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	return &dnsProber{host: host, resolver: resolver}
+}
+
+type dnsProber struct {
+	host     string
+	resolver HostResolver
+}
+
+func (p *dnsProber) Target() string {
+	return fmt.Sprintf("dns://%s", p.host)
+}
+
+func (p *dnsProber) Probe(ctx context.Context, _ uint64) error {
+	addrs, err := p.resolver.LookupHost(ctx, p.host)
+	if err != nil {
+		return &KnownError{category: "DNSError", err: err}
+	}
+	if len(addrs) == 0 {
+		return &KnownError{category: "DNSError", err: fmt.Errorf("no addresses found for %s", p.host)}
+	}
+	return nil
+}
+
+// NewGRPCHealthProber returns a Prober that calls the standard gRPC health
+// service of the server at target, for example etcd, and expects the given
+// service to be SERVING.  With NewConnectionType every probe dials a new
+// connection, otherwise a single connection is shared by all the probes.
+func NewGRPCHealthProber(target, service string, connectionType monitorapi.BackendConnectionType, opts ...grpc.DialOption) Prober {
+	return &grpcHealthProber{
+		target:         target,
+		service:        service,
+		connectionType: connectionType,
+		opts:           opts,
+	}
+}
+
+type grpcHealthProber struct {
+	target         string
+	service        string
+	connectionType monitorapi.BackendConnectionType
+	opts           []grpc.DialOption
+
+	lock sync.Mutex
+	conn *grpc.ClientConn
+}
+
+func (p *grpcHealthProber) Target() string {
+	return fmt.Sprintf("grpc://%s", p.target)
+}
+
+func (p *grpcHealthProber) Probe(ctx context.Context, _ uint64) error {
+	conn, err := p.clientConn()
+	if err != nil {
+		return &KnownError{category: "GRPCConnectError", err: err}
+	}
+	if p.connectionType == monitorapi.NewConnectionType {
+		defer conn.Close()
+	}
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: p.service})
+	if err != nil {
+		return &KnownError{category: "GRPCHealthError", err: err}
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		return &KnownError{category: "GRPCHealthError", err: fmt.Errorf("unexpected health status: %v", resp.Status)}
+	}
+	return nil
+}
+
+func (p *grpcHealthProber) clientConn() (*grpc.ClientConn, error) {
+	if p.connectionType == monitorapi.NewConnectionType {
+		return grpc.Dial(p.target, p.opts...)
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.conn == nil {
+		// the connection reconnects on its own, so it is dialed only once.
+		conn, err := grpc.Dial(p.target, p.opts...)
+		if err != nil {
+			return nil, err
+		}
+		p.conn = conn
+	}
+	return p.conn, nil
+}
+
+func (p *grpcHealthProber) Close() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.conn == nil {
+		return nil
+	}
+	return p.conn.Close()
+}
@@ -0,0 +1,172 @@
+package sampler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/disruption/sampler"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestTCPProber(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	address := listener.Addr().String()
+
+	prober := NewTCPProber(address)
+	if want, got := "tcp://"+address, prober.Target(); want != got {
+		t.Errorf("expected target: %s, but got: %s", want, got)
+	}
+	if err := prober.Probe(context.TODO(), 1); err != nil {
+		t.Errorf("expected no error, but got: %v", err)
+	}
+
+	listener.Close()
+	err = prober.Probe(context.TODO(), 2)
+	var known *KnownError
+	if !errors.As(err, &known) || known.Category() != "TCPConnectError" {
+		t.Errorf("expected a TCPConnectError, but got: %v", err)
+	}
+}
+
+type fakeResolver struct {
+	addrs []string
+	err   error
+}
+
+func (r fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	return r.addrs, r.err
+}
+
+func TestDNSProber(t *testing.T) {
+	tests := []struct {
+		name     string
+		resolver fakeResolver
+		wantErr  bool
+	}{
+		{
+			name:     "resolved",
+			resolver: fakeResolver{addrs: []string{"172.30.0.1"}},
+		},
+		{
+			name:     "lookup failed",
+			resolver: fakeResolver{err: fmt.Errorf("i/o timeout")},
+			wantErr:  true,
+		},
+		{
+			name:     "no addresses",
+			resolver: fakeResolver{},
+			wantErr:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := NewDNSProber("kubernetes.default.svc", test.resolver).Probe(context.TODO(), 1)
+			if !test.wantErr {
+				if err != nil {
+					t.Errorf("expected no error, but got: %v", err)
+				}
+				return
+			}
+			var known *KnownError
+			if !errors.As(err, &known) || known.Category() != "DNSError" {
+				t.Errorf("expected a DNSError, but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestGRPCHealthProber(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	healthServer := health.NewServer()
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	for _, connectionType := range []monitorapi.BackendConnectionType{monitorapi.NewConnectionType, monitorapi.ReusedConnectionType} {
+		t.Run(string(connectionType), func(t *testing.T) {
+			prober := NewGRPCHealthProber(listener.Addr().String(), "etcd", connectionType, grpc.WithTransportCredentials(insecure.NewCredentials()))
+			defer prober.(*grpcHealthProber).Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			healthServer.SetServingStatus("etcd", healthpb.HealthCheckResponse_SERVING)
+			if err := prober.Probe(ctx, 1); err != nil {
+				t.Errorf("expected no error, but got: %v", err)
+			}
+
+			healthServer.SetServingStatus("etcd", healthpb.HealthCheckResponse_NOT_SERVING)
+			err := prober.Probe(ctx, 2)
+			var known *KnownError
+			if !errors.As(err, &known) || known.Category() != "GRPCHealthError" {
+				t.Errorf("expected a GRPCHealthError, but got: %v", err)
+			}
+		})
+	}
+}
+
+type fakeProber struct {
+	errs   map[uint64]error
+	closed bool
+}
+
+func (p *fakeProber) Target() string { return "fake://" }
+func (p *fakeProber) Probe(ctx context.Context, sampleID uint64) error {
+	return p.errs[sampleID]
+}
+func (p *fakeProber) Close() error {
+	p.closed = true
+	return nil
+}
+
+type collected []backend.SampleResult
+
+func (c *collected) Collect(s backend.SampleResult) {
+	*c = append(*c, s)
+}
+
+func TestProbeProducerConsumer(t *testing.T) {
+	prober := &fakeProber{errs: map[uint64]error{2: fmt.Errorf("connection refused")}}
+	results := &collected{}
+	pc := NewProbeProducerConsumer(prober, time.Second, results)
+
+	for id := uint64(1); id <= 3; id++ {
+		s := &sampler.Sample{ID: id}
+		custom, err := pc.Produce(context.TODO(), id)
+		s.Err = err
+		pc.Consume(s, custom)
+	}
+	pc.Close()
+
+	if len(*results) != 4 {
+		t.Fatalf("expected 3 samples and the end marker, but got: %d", len(*results))
+	}
+	for i, result := range (*results)[:3] {
+		if want, got := i != 1, result.Succeeded(); want != got {
+			t.Errorf("expected sample %d to succeed: %t, but got: %t", result.Sample.ID, want, got)
+		}
+	}
+	if (*results)[3].Sample != nil {
+		t.Errorf("expected an empty SampleResult to mark the end of the samples")
+	}
+	if !prober.closed {
+		t.Errorf("expected the prober to be closed")
+	}
+}
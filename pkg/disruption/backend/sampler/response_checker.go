@@ -2,6 +2,7 @@ package sampler
 
 import (
 	"fmt"
+	"regexp"
 
 	"github.com/openshift/origin/pkg/disruption/backend"
 )
@@ -10,7 +11,17 @@ func NewResponseChecker() ResponseChecker {
 	return &checker{}
 }
 
-type checker struct{}
+// NewExpectedResponseChecker returns a ResponseChecker that expects the
+// given status code, rather than any 2xx or 3xx, if statusCode is not zero,
+// and a response body that matches bodyRegex, if it is not nil.
+func NewExpectedResponseChecker(statusCode int, bodyRegex *regexp.Regexp) ResponseChecker {
+	return &checker{statusCode: statusCode, bodyRegex: bodyRegex}
+}
+
+type checker struct {
+	statusCode int
+	bodyRegex  *regexp.Regexp
+}
 
 func (c checker) CheckResponse(rr backend.RequestResponse) error {
 	resp := rr.Response
@@ -42,12 +53,24 @@ func (c checker) CheckResponse(rr backend.RequestResponse) error {
 		}
 	}
 
-	if resp.StatusCode < 200 || resp.StatusCode > 399 {
+	switch {
+	case c.statusCode != 0 && resp.StatusCode != c.statusCode:
+		return &KnownError{
+			category: "UnexpectedStatusCode",
+			err:      fmt.Errorf("expected HTTP status code %d, but got: %v body: %v", c.statusCode, resp.Status, string(rr.ResponseBody)),
+		}
+	case c.statusCode == 0 && (resp.StatusCode < 200 || resp.StatusCode > 399):
 		return &KnownError{
 			category: "APIServerAvailability",
 			err:      fmt.Errorf("unexpected HTTP status code: %v body: %v", resp.Status, string(rr.ResponseBody)),
 		}
 	}
+	if c.bodyRegex != nil && !c.bodyRegex.Match(rr.ResponseBody) {
+		return &KnownError{
+			category: "UnexpectedResponseBody",
+			err:      fmt.Errorf("response body did not match %q: %v", c.bodyRegex, string(rr.ResponseBody)),
+		}
+	}
 	return nil
 }
 
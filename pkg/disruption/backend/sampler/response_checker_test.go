@@ -0,0 +1,74 @@
+package sampler
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+)
+
+func TestResponseChecker(t *testing.T) {
+	response := func(code int, body string) backend.RequestResponse {
+		rr := backend.RequestResponse{Response: &http.Response{StatusCode: code, Status: http.StatusText(code)}}
+		rr.ResponseBody = []byte(body)
+		return rr
+	}
+
+	tests := []struct {
+		name     string
+		checker  ResponseChecker
+		rr       backend.RequestResponse
+		category string
+	}{
+		{
+			name:    "default, ok",
+			checker: NewResponseChecker(),
+			rr:      response(http.StatusOK, ""),
+		},
+		{
+			name:     "default, server error",
+			checker:  NewResponseChecker(),
+			rr:       response(http.StatusInternalServerError, ""),
+			category: "APIServerAvailability",
+		},
+		{
+			name:    "expected status code",
+			checker: NewExpectedResponseChecker(http.StatusUnauthorized, nil),
+			rr:      response(http.StatusUnauthorized, ""),
+		},
+		{
+			name:     "unexpected status code",
+			checker:  NewExpectedResponseChecker(http.StatusNoContent, nil),
+			rr:       response(http.StatusOK, ""),
+			category: "UnexpectedStatusCode",
+		},
+		{
+			name:    "body matches",
+			checker: NewExpectedResponseChecker(0, regexp.MustCompile(`^ok$`)),
+			rr:      response(http.StatusOK, "ok"),
+		},
+		{
+			name:     "body does not match",
+			checker:  NewExpectedResponseChecker(0, regexp.MustCompile(`^ok$`)),
+			rr:       response(http.StatusOK, "default backend - 404"),
+			category: "UnexpectedResponseBody",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.checker.CheckResponse(test.rr)
+			if len(test.category) == 0 {
+				if err != nil {
+					t.Errorf("expected no error, but got: %v", err)
+				}
+				return
+			}
+			var known *KnownError
+			if !errors.As(err, &known) || known.Category() != test.category {
+				t.Errorf("expected an error of category %s, but got: %v", test.category, err)
+			}
+		})
+	}
+}
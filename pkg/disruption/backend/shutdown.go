@@ -24,8 +24,12 @@ type ShutdownResponse struct {
 	// down then this value is zero.
 	Elapsed time.Duration
 
-	// Hostname is the hostname of the apiserver process
+	// Hostname is the hostname of the apiserver process, it is decoded
+	// from APIServerIdentity when a HostNameDecoder is in use.
 	Hostname string
+
+	// APIServerIdentity is the host as sent by the server.
+	APIServerIdentity string
 }
 
 func (sr ShutdownResponse) String() string {
@@ -3,11 +3,14 @@ package ci
 import (
 	"fmt"
 	"net/http"
+	"regexp"
 	"sync"
 	"time"
 
 	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/disruption/backend/diagnostics"
 	"github.com/openshift/origin/pkg/disruption/backend/disruption"
+	"github.com/openshift/origin/pkg/disruption/backend/latency"
 	"github.com/openshift/origin/pkg/disruption/backend/logger"
 	"github.com/openshift/origin/pkg/disruption/backend/roundtripper"
 	backendsampler "github.com/openshift/origin/pkg/disruption/backend/sampler"
@@ -42,6 +45,19 @@ func NewDisruptionTestFactory(config *rest.Config) Factory {
 	}
 }
 
+// NewBackendTestFactory returns a shared disruption test factory, like
+// NewDisruptionTestFactory, for a backend that is not a kube-apiserver,
+// the host in the rest Config is the base URL of the backend and the
+// samplers do not try to decode the apiserver identity of the responses.
+func NewBackendTestFactory(config *rest.Config) Factory {
+	return &testFactory{
+		dependency: &restConfigDependency{
+			config:            config,
+			noHostNameDecoder: true,
+		},
+	}
+}
+
 // TestConfiguration allows a user to specify the disruption test parameters
 type TestConfiguration struct {
 	TestDescriptor
@@ -62,10 +78,35 @@ type TestConfiguration struct {
 	//  it to 1s.
 	SampleInterval time.Duration
 
+	// AdaptiveSampling, if enabled, allows the sampler to step up to a
+	// sub-second rate while an outage is suspected.  It is opt-in, the
+	// disruption tests CI runs leave it disabled so their disruption can
+	// still be compared with the historical data, it is enabled per
+	// backend by the 'disruption run' config.
+	AdaptiveSampling sampler.AdaptiveConfig
+
 	// EnableShutdownResponseHeader indicates whether to include the shutdown
 	// response header extractor, this should be true only when the
 	// request(s) are being sent to the kube-apiserver.
 	EnableShutdownResponseHeader bool
+
+	// ExpectedStatusCode, if set, is the only status code a successful
+	// sample may have, otherwise any 2xx or 3xx is accepted.
+	ExpectedStatusCode int
+
+	// ExpectedBodyRegex, if set, is a regular expression the body of
+	// the response of a successful sample must match.
+	ExpectedBodyRegex string
+
+	// Latency is the latency objective of the backend, samples whose
+	// round trip latency goes over it are recorded as degraded intervals.
+	// The zero value only records the latency histogram.
+	Latency latency.Config
+}
+
+// AdaptiveSamplingEnabled implements backend.AdaptiveSamplingDescriptor
+func (c TestConfiguration) AdaptiveSamplingEnabled() bool {
+	return c.AdaptiveSampling.Enabled()
 }
 
 // TestDescriptor defines the disruption test type, the user must
@@ -160,6 +201,19 @@ func (b *testFactory) New(c TestConfiguration) (Sampler, error) {
 	if err := c.Validate(); err != nil {
 		return nil, err
 	}
+	if err := c.Latency.Validate(); err != nil {
+		return nil, err
+	}
+	if err := c.AdaptiveSampling.Validate(c.SampleInterval); err != nil {
+		return nil, err
+	}
+	var bodyRegex *regexp.Regexp
+	if len(c.ExpectedBodyRegex) > 0 {
+		var err error
+		if bodyRegex, err = regexp.Compile(c.ExpectedBodyRegex); err != nil {
+			return nil, fmt.Errorf("ExpectedBodyRegex is invalid: %w", err)
+		}
+	}
 	b.once.Do(func() {
 		// we want all test instances using this factory to share
 		// a single apiserver shutdown interval tracker.
@@ -182,17 +236,19 @@ func (b *testFactory) New(c TestConfiguration) (Sampler, error) {
 		return nil, err
 	}
 	requestor := backendsampler.NewHostPathRequestor(b.dependency.HostName(), c.Path)
+	diagnosticsCollector := diagnostics.NewCollector(c.Name(), requestor.GetBaseURL(), diagnostics.DefaultConfig())
 
 	// we don't have access to the monitor and event recorder yet
-	collector, want := disruption.NewIntervalTracker(b.sharedShutdownInterval, c, nil, nil)
+	latencyTracker, wantLatency := latency.NewLatencyTracker(b.sharedShutdownInterval, c, c.Latency, nil, nil)
+	collector, want := disruption.NewIntervalTracker(latencyTracker, c, diagnosticsCollector, nil, nil)
 	collector = logger.NewLogger(collector, c)
 
-	pc := backendsampler.NewSampleProducerConsumer(client, requestor, backendsampler.NewResponseChecker(), collector)
-	runner := sampler.NewWithProducerConsumer(c.SampleInterval, pc)
+	pc := backendsampler.NewSampleProducerConsumer(client, requestor, backendsampler.NewExpectedResponseChecker(c.ExpectedStatusCode, bodyRegex), collector)
+	runner := sampler.NewAdaptiveWithProducerConsumer(c.SampleInterval, c.AdaptiveSampling, pc)
 	backendSampler := &BackendSampler{
 		TestConfiguration:           c,
 		SampleRunner:                runner,
-		wantEventRecorderAndMonitor: []backend.WantEventRecorderAndMonitorRecorder{b.wantMonitorAndRecorder, want},
+		wantEventRecorderAndMonitor: []backend.WantEventRecorderAndMonitorRecorder{b.wantMonitorAndRecorder, wantLatency, want},
 		baseURL:                     requestor.GetBaseURL(),
 		hostNameDecoder:             b.hostNameDecoder,
 	}
@@ -203,6 +259,8 @@ func (b *testFactory) New(c TestConfiguration) (Sampler, error) {
 // a disruption test instance from a rest Config.
 type restConfigDependency struct {
 	config *rest.Config
+	// noHostNameDecoder is set when the target server is not a kube-apiserver
+	noHostNameDecoder bool
 }
 
 func (r *restConfigDependency) NewTransport(tc TestConfiguration) (http.RoundTripper, error) {
@@ -223,6 +281,9 @@ func (r *restConfigDependency) NewTransport(tc TestConfiguration) (http.RoundTri
 }
 func (r *restConfigDependency) HostName() string { return r.config.Host }
 func (r *restConfigDependency) GetHostNameDecoder() (backend.HostNameDecoderWithRunner, error) {
+	if r.noHostNameDecoder {
+		return nil, nil
+	}
 	return NewAPIServerIdentityToHostNameDecoder(r.config)
 }
 func (r *restConfigDependency) GetRestConfig() *rest.Config {
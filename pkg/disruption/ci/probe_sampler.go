@@ -0,0 +1,111 @@
+package ci
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/disruption/backend/diagnostics"
+	"github.com/openshift/origin/pkg/disruption/backend/disruption"
+	"github.com/openshift/origin/pkg/disruption/backend/latency"
+	"github.com/openshift/origin/pkg/disruption/backend/logger"
+	backendsampler "github.com/openshift/origin/pkg/disruption/backend/sampler"
+	"github.com/openshift/origin/pkg/disruption/sampler"
+)
+
+const (
+	// ClusterDNS is the target of a disruption test that resolves
+	// service names like kubernetes.default.svc
+	ClusterDNS ServerNameType = "cluster-dns"
+	// Etcd is the target of a disruption test that checks etcd health
+	Etcd ServerNameType = "etcd"
+	// NodePort is the target of a disruption test that connects to a node port
+	NodePort ServerNameType = "node-port"
+)
+
+// ProbeTestConfiguration allows a user to specify the parameters of a
+// disruption test for a backend that is not served over HTTP, the
+// Protocol of the TestDescriptor is expected to be one of
+// ProtocolTCP, ProtocolDNS or ProtocolGRPC.
+type ProbeTestConfiguration struct {
+	TestDescriptor
+
+	// Prober checks the backend once per sample
+	Prober backendsampler.Prober
+
+	// Timeout is the maximum amount of time a single probe may take.
+	Timeout time.Duration
+
+	// SampleInterval is the interval that the sampler will
+	// wait before generating the next sample.
+	SampleInterval time.Duration
+
+	// AdaptiveSampling, see TestConfiguration.AdaptiveSampling
+	AdaptiveSampling sampler.AdaptiveConfig
+
+	// Latency is the latency objective of the backend, see
+	// TestConfiguration.Latency
+	Latency latency.Config
+}
+
+// AdaptiveSamplingEnabled implements backend.AdaptiveSamplingDescriptor
+func (c ProbeTestConfiguration) AdaptiveSamplingEnabled() bool {
+	return c.AdaptiveSampling.Enabled()
+}
+
+func (c ProbeTestConfiguration) Validate() error {
+	if err := c.TestDescriptor.Validate(); err != nil {
+		return err
+	}
+	switch c.Protocol {
+	case backend.ProtocolTCP, backend.ProtocolDNS, backend.ProtocolGRPC:
+	default:
+		return fmt.Errorf("Protocol %q can not be probed, expected one of %s, %s or %s",
+			c.Protocol, backend.ProtocolTCP, backend.ProtocolDNS, backend.ProtocolGRPC)
+	}
+	if c.Prober == nil {
+		return fmt.Errorf("Prober must be specified")
+	}
+	if c.Timeout <= 0 {
+		return fmt.Errorf("Timeout must be greater than zero")
+	}
+	if c.SampleInterval <= 0 {
+		return fmt.Errorf("SampleInterval must be greater than zero")
+	}
+	if err := c.AdaptiveSampling.Validate(c.SampleInterval); err != nil {
+		return err
+	}
+	return c.Latency.Validate()
+}
+
+// NewProbeSampler returns a disruption test that samples the backend with
+// the given Prober.  The samples go through the same interval tracker as
+// the HTTP samplers, so the disruption intervals, and the accounting of
+// disruption against historical data, work the same way.
+func NewProbeSampler(c ProbeTestConfiguration) (Sampler, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	diagnosticsCollector := diagnostics.NewCollector(c.Name(), c.Prober.Target(), diagnostics.DefaultConfig())
+
+	// we don't have access to the monitor and event recorder yet
+	latencyTracker, wantLatency := latency.NewLatencyTracker(nil, c, c.Latency, nil, nil)
+	collector, want := disruption.NewIntervalTracker(latencyTracker, c, diagnosticsCollector, nil, nil)
+	collector = logger.NewLogger(collector, c)
+
+	pc := backendsampler.NewProbeProducerConsumer(c.Prober, c.Timeout, collector)
+	runner := sampler.NewAdaptiveWithProducerConsumer(c.SampleInterval, c.AdaptiveSampling, pc)
+	return &BackendSampler{
+		TestConfiguration: TestConfiguration{
+			TestDescriptor:   c.TestDescriptor,
+			Timeout:          c.Timeout,
+			SampleInterval:   c.SampleInterval,
+			AdaptiveSampling: c.AdaptiveSampling,
+			Latency:          c.Latency,
+		},
+		SampleRunner:                runner,
+		wantEventRecorderAndMonitor: []backend.WantEventRecorderAndMonitorRecorder{wantLatency, want},
+		baseURL:                     c.Prober.Target(),
+	}, nil
+}
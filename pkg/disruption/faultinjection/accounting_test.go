@@ -0,0 +1,301 @@
+package faultinjection
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/disruption/ci"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortests/testframework/disruptionserializer"
+)
+
+// TestDisruptionAccounting drives the disruption test samplers, as built by
+// the disruption test factory, against a fault injection server, and checks
+// the disruption and the shutdown intervals they record.  The samplers run
+// ten times faster than in CI to keep the test short, so the disruption is
+// checked to within a sample interval, TestDisruptionAccountingInCI checks
+// the disruption CI reports to the second.
+// shutdownDelayDuration is how long the fault injection server keeps
+// serving once it is told to shut down.
+const shutdownDelayDuration = 10 * time.Second
+
+func TestDisruptionAccounting(t *testing.T) {
+	const (
+		sampleInterval = 100 * time.Millisecond
+		sampleTimeout  = 500 * time.Millisecond
+	)
+
+	tests := []struct {
+		name           string
+		protocol       backend.ProtocolType
+		connectionType monitorapi.BackendConnectionType
+		timeline       []Step
+		// disruptedSamples is the number of samples the disruption
+		// accounted for the backend should last
+		disruptedSamples int
+		// shutdownLevels are the levels of the graceful shutdown intervals
+		shutdownLevels []monitorapi.IntervalLevel
+	}{
+		{
+			name:             "server errors, reused http/2.0 connections",
+			protocol:         backend.ProtocolHTTP2,
+			connectionType:   monitorapi.ReusedConnectionType,
+			timeline:         []Step{{Samples: 3}, {Samples: 3, Fault: ServerError}, {Samples: 3}},
+			disruptedSamples: 3,
+		},
+		{
+			name:             "server errors, new http/1.1 connections",
+			protocol:         backend.ProtocolHTTP1,
+			connectionType:   monitorapi.NewConnectionType,
+			timeline:         []Step{{Samples: 3}, {Samples: 3, Fault: ServerError, StatusCode: http.StatusBadGateway}, {Samples: 3}},
+			disruptedSamples: 3,
+		},
+		{
+			name:             "dropped connections, reused http/1.1 connections",
+			protocol:         backend.ProtocolHTTP1,
+			connectionType:   monitorapi.ReusedConnectionType,
+			timeline:         []Step{{Samples: 3}, {Samples: 2, Fault: DropConnection}, {Samples: 3}},
+			disruptedSamples: 2,
+		},
+		{
+			name:             "reset connections, new http/2.0 connections",
+			protocol:         backend.ProtocolHTTP2,
+			connectionType:   monitorapi.NewConnectionType,
+			timeline:         []Step{{Samples: 3}, {Samples: 4, Fault: ResetConnection}, {Samples: 3}},
+			disruptedSamples: 4,
+		},
+		{
+			name:             "responses slower than the timeout",
+			protocol:         backend.ProtocolHTTP2,
+			connectionType:   monitorapi.ReusedConnectionType,
+			timeline:         []Step{{Samples: 3}, {Samples: 2, Fault: Delay, Delay: 2 * sampleTimeout}, {Samples: 3}},
+			disruptedSamples: 2,
+		},
+		{
+			name:           "responses slower than usual, but within the timeout",
+			protocol:       backend.ProtocolHTTP2,
+			connectionType: monitorapi.ReusedConnectionType,
+			timeline:       []Step{{Samples: 3}, {Samples: 2, Fault: Delay, Delay: sampleInterval / 2}, {Samples: 3}},
+		},
+		{
+			name:           "graceful shutdown",
+			protocol:       backend.ProtocolHTTP2,
+			connectionType: monitorapi.NewConnectionType,
+			timeline:       []Step{{Samples: 3}, {Samples: 3, ShuttingDown: true}, {Samples: 3}},
+			shutdownLevels: []monitorapi.IntervalLevel{monitorapi.Info},
+		},
+		{
+			name:           "graceful shutdown with failures",
+			protocol:       backend.ProtocolHTTP2,
+			connectionType: monitorapi.NewConnectionType,
+			timeline: []Step{
+				{Samples: 3},
+				{Samples: 2, ShuttingDown: true},
+				{Samples: 2, ShuttingDown: true, Fault: ServerError},
+				{Samples: 3},
+			},
+			disruptedSamples: 2,
+			shutdownLevels:   []monitorapi.IntervalLevel{monitorapi.Error},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			name, intervals := runTimeline(t, test.timeline, ci.TestConfiguration{
+				TestDescriptor: ci.TestDescriptor{
+					TargetServer:     "fault-injection",
+					LoadBalancerType: backend.ExternalLoadBalancerType,
+					ConnectionType:   test.connectionType,
+					Protocol:         test.protocol,
+				},
+				Path:                         "/healthz",
+				Timeout:                      sampleTimeout,
+				SampleInterval:               sampleInterval,
+				EnableShutdownResponseHeader: true,
+			})
+
+			disruptionIntervals := intervals.Filter(monitorapi.IsDisruptionEvent)
+			_, messages := monitorapi.BackendDisruptionSeconds(name, disruptionIntervals)
+			disruption := time.Duration(monitorapi.BackendDisruptionMilliseconds(name, disruptionIntervals)) * time.Millisecond
+			want := time.Duration(test.disruptedSamples) * sampleInterval
+			switch {
+			case test.disruptedSamples == 0 && len(messages) > 0:
+				t.Errorf("expected no disruption, but got: %s - %v", disruption, messages)
+			case disruption < want-sampleInterval || disruption > want+sampleInterval:
+				t.Errorf("expected disruption: %s (+/- %s), but got: %s - %v", want, sampleInterval, disruption, messages)
+			}
+
+			shutdowns := intervals.Filter(func(i monitorapi.Interval) bool {
+				return i.Source == monitorapi.SourceAPIServerShutdown
+			})
+			if len(test.shutdownLevels) != len(shutdowns) {
+				t.Fatalf("expected %d shutdown intervals, but got: %v", len(test.shutdownLevels), shutdowns.Strings())
+			}
+			for i, shutdown := range shutdowns {
+				if test.shutdownLevels[i] != shutdown.Level {
+					t.Errorf("expected shutdown interval with level %s, but got: %s", test.shutdownLevels[i], shutdown.String())
+				}
+				// the shutdown interval begins when the server received the
+				// TERM signal, and is expected to last the shutdown delay
+				// duration plus the time the server takes to drain.
+				if want, got := shutdownDelayDuration+15*time.Second, shutdown.To.Sub(shutdown.From); want != got {
+					t.Errorf("expected a shutdown interval of %s, but got: %s", want, got)
+				}
+			}
+		})
+	}
+}
+
+// TestDisruptionAccountingInCI drives the samplers at the sample interval
+// they use in CI, and checks the disruption the monitor tests report for
+// the backend, rounded to the second, to the exact second.
+func TestDisruptionAccountingInCI(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeline []Step
+		// disruption is the disruption reported for the backend
+		disruption time.Duration
+	}{
+		{
+			name:       "three server errors",
+			timeline:   []Step{{Samples: 2}, {Samples: 3, Fault: ServerError}, {Samples: 2}},
+			disruption: 3 * time.Second,
+		},
+		{
+			name:       "a single dropped connection",
+			timeline:   []Step{{Samples: 2}, {Samples: 1, Fault: DropConnection}, {Samples: 2}},
+			disruption: time.Second,
+		},
+		{
+			name:     "no disruption",
+			timeline: []Step{{Samples: 3}},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			name, intervals := runTimeline(t, test.timeline, ci.TestConfiguration{
+				TestDescriptor: ci.TestDescriptor{
+					TargetServer:     "fault-injection",
+					LoadBalancerType: backend.ExternalLoadBalancerType,
+					ConnectionType:   monitorapi.ReusedConnectionType,
+					Protocol:         backend.ProtocolHTTP2,
+				},
+				Path:           "/healthz",
+				Timeout:        15 * time.Second,
+				SampleInterval: time.Second,
+			})
+
+			// the backend-disruption file is what CI reports
+			storageDir := t.TempDir()
+			if err := disruptionserializer.NewDisruptionSummarySerializer().WriteContentToStorage(context.Background(), storageDir, "_test", intervals, nil); err != nil {
+				t.Fatalf("failed to write the backend disruption: %v", err)
+			}
+			content, err := os.ReadFile(filepath.Join(storageDir, "backend-disruption_test.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			disruptions := &disruptionserializer.BackendDisruptionList{}
+			if err := json.Unmarshal(content, disruptions); err != nil {
+				t.Fatal(err)
+			}
+			backendDisruption, ok := disruptions.BackendDisruptions[name]
+			if !ok {
+				t.Fatalf("expected the disruption of %s to be reported, but got: %s", name, content)
+			}
+			if got := backendDisruption.DisruptedDuration.Duration; test.disruption != got {
+				t.Errorf("expected disruption: %s, but got: %s - %v", test.disruption, got, backendDisruption.DisruptionMessages)
+			}
+		})
+	}
+}
+
+// runTimeline runs the sampler the disruption test factory builds for the
+// configuration against a fault injection server until it answered every
+// sample of the timeline, and returns the name of the backend and the
+// intervals the sampler recorded.
+func runTimeline(t *testing.T, timeline []Step, config ci.TestConfiguration) (string, monitorapi.Intervals) {
+	t.Helper()
+	server, err := NewServer(Config{
+		Path:                  "/healthz",
+		Timeline:              timeline,
+		Hostname:              "master-0",
+		ShutdownDelayDuration: shutdownDelayDuration,
+		HTTP2:                 config.Protocol == backend.ProtocolHTTP2,
+	})
+	if err != nil {
+		t.Fatalf("failed to start the server: %v", err)
+	}
+	defer server.Close()
+
+	sampler, err := ci.NewDisruptionTestFactory(server.RestConfig()).New(config)
+	if err != nil {
+		t.Fatalf("failed to build the sampler: %v", err)
+	}
+
+	recorder := &intervalRecorder{}
+	doneCh := make(chan error, 1)
+	go func() {
+		doneCh <- sampler.RunEndpointMonitoring(context.Background(), recorder, nil)
+	}()
+
+	// the first sample past the timeline tells us every sample of
+	// the timeline has been answered.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	waitErr := server.WaitForSample(ctx, sampler.GetDisruptionBackendName(), server.TimelineSamples()+1)
+	sampler.Stop()
+	if err := <-doneCh; err != nil {
+		t.Fatalf("unexpected error from the sampler: %v", err)
+	}
+	if waitErr != nil {
+		t.Fatalf("the sampler did not finish the timeline: %v", waitErr)
+	}
+	return sampler.GetDisruptionBackendName(), recorder.get()
+}
+
+// intervalRecorder keeps the intervals the disruption test records
+type intervalRecorder struct {
+	monitorapi.RecorderWriter
+
+	lock      sync.Mutex
+	intervals monitorapi.Intervals
+}
+
+func (r *intervalRecorder) AddIntervals(intervals ...monitorapi.Interval) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.intervals = append(r.intervals, intervals...)
+}
+
+func (r *intervalRecorder) StartInterval(interval monitorapi.Interval) int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.intervals = append(r.intervals, interval)
+	return len(r.intervals) - 1
+}
+
+func (r *intervalRecorder) EndInterval(startedInterval int, t time.Time) *monitorapi.Interval {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.intervals[startedInterval].To = t
+	interval := r.intervals[startedInterval]
+	return &interval
+}
+
+func (r *intervalRecorder) get() monitorapi.Intervals {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append(monitorapi.Intervals{}, r.intervals...)
+}
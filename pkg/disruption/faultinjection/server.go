@@ -0,0 +1,292 @@
+package faultinjection
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+// Fault is what the server does with a sample request
+type Fault string
+
+const (
+	// Healthy answers the request with 200
+	Healthy Fault = ""
+	// DropConnection closes the connection the request arrived
+	// on without sending a response.
+	DropConnection Fault = "DropConnection"
+	// ResetConnection aborts the connection the request arrived
+	// on, the client sees a TCP reset.
+	ResetConnection Fault = "ResetConnection"
+	// Delay answers the request with 200 after Step.Delay, or
+	// never if the client gives up first.
+	Delay Fault = "Delay"
+	// ServerError answers the request with Step.StatusCode, 500
+	// if not specified.
+	ServerError Fault = "ServerError"
+)
+
+// Step is a part of the scripted timeline of the server, it applies the
+// same fault to a number of consecutive samples.
+type Step struct {
+	// Samples is the number of consecutive samples the step applies to
+	Samples int
+
+	// Fault is what the server does with each sample of the step
+	Fault Fault
+
+	// Delay is how long the server waits before it answers, used
+	// with the Delay fault.
+	Delay time.Duration
+
+	// StatusCode is the response code, used with the ServerError fault
+	StatusCode int
+
+	// ShuttingDown makes the 'X-OpenShift-Disruption' response header
+	// say that a graceful shutdown is in progress, the elapsed time is
+	// counted from the first sample the server answered while shutting
+	// down.  The header is only sent if Config.Hostname is set.
+	ShuttingDown bool
+}
+
+// Config is the configuration of a fault injection server
+type Config struct {
+	// Path is the request path the disruption test samples, requests
+	// for any other path are answered with 404.
+	Path string
+
+	// Timeline is the script the server follows, sample N of each client
+	// gets the fault of the step it falls in, in order.  Samples past
+	// the end of the timeline are healthy.
+	Timeline []Step
+
+	// Hostname, if set, makes the server send the 'X-OpenShift-Disruption'
+	// response header the kube-apiserver sends, with this host name.
+	Hostname string
+
+	// ShutdownDelayDuration is the shutdown-delay-duration the
+	// 'X-OpenShift-Disruption' response header carries.
+	ShutdownDelayDuration time.Duration
+
+	// HTTP2 enables http/2.0, otherwise the server only speaks http/1.x
+	HTTP2 bool
+}
+
+func (c Config) Validate() error {
+	if len(c.Path) == 0 {
+		return fmt.Errorf("Path must be specified")
+	}
+	for i, step := range c.Timeline {
+		if step.Samples <= 0 {
+			return fmt.Errorf("step %d: Samples must be greater than zero", i)
+		}
+		switch step.Fault {
+		case Healthy, DropConnection, ResetConnection, ServerError:
+		case Delay:
+			if step.Delay <= 0 {
+				return fmt.Errorf("step %d: Delay must be greater than zero", i)
+			}
+		default:
+			return fmt.Errorf("step %d: unknown fault %q", i, step.Fault)
+		}
+	}
+	return nil
+}
+
+// Server is a local TLS server that plays a scripted timeline of faults
+// against the disruption test samplers, so the disruption and shutdown
+// intervals they record can be checked end to end.
+//
+// Disruption test clients are told apart by their User-Agent, every
+// sampler created by the disruption test factory sets its own, and the
+// timeline is keyed by the 'sample-id' query parameter each sample
+// request carries, so a request the client transport retries gets the
+// same fault as the original one.
+type Server struct {
+	config Config
+	server *httptest.Server
+
+	lock sync.Mutex
+	// highest is the highest sample ID seen for each client
+	highest map[string]uint64
+	// waiting are the channels of the WaitForSample calls in progress
+	waiting       map[string][]waiter
+	shutdownSince time.Time
+}
+
+type waiter struct {
+	sampleID uint64
+	ch       chan struct{}
+}
+
+type connKey struct{}
+
+// NewServer starts a new fault injection server with the given
+// configuration, Close must be called to stop it.
+func NewServer(config Config) (*Server, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	s := &Server{
+		config:  config,
+		highest: map[string]uint64{},
+		waiting: map[string][]waiter{},
+	}
+	s.server = httptest.NewUnstartedServer(http.HandlerFunc(s.serveHTTP))
+	s.server.Config.ConnContext = func(ctx context.Context, c net.Conn) context.Context {
+		return context.WithValue(ctx, connKey{}, c)
+	}
+	s.server.EnableHTTP2 = config.HTTP2
+	s.server.StartTLS()
+	return s, nil
+}
+
+// URL returns the base URL of the server
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// RestConfig returns a rest Config for the server, so the disruption test
+// factory can be pointed at it the same way it is pointed at a cluster.
+func (s *Server) RestConfig() *rest.Config {
+	return &rest.Config{
+		Host: s.server.URL,
+		TLSClientConfig: rest.TLSClientConfig{
+			Insecure: true,
+		},
+	}
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// TimelineSamples returns the number of samples the timeline covers
+func (s *Server) TimelineSamples() uint64 {
+	total := uint64(0)
+	for _, step := range s.config.Timeline {
+		total += uint64(step.Samples)
+	}
+	return total
+}
+
+// WaitForSample blocks until the given client has sent the sample with
+// the given ID, or a later one, or the context is done.
+func (s *Server) WaitForSample(ctx context.Context, client string, sampleID uint64) error {
+	s.lock.Lock()
+	if s.highest[client] >= sampleID {
+		s.lock.Unlock()
+		return nil
+	}
+	ch := make(chan struct{})
+	s.waiting[client] = append(s.waiting[client], waiter{sampleID: sampleID, ch: ch})
+	s.lock.Unlock()
+
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("client %q has not sent sample %d: %w", client, sampleID, ctx.Err())
+	}
+}
+
+// step returns the step of the timeline the given sample falls in
+func (s *Server) step(sampleID uint64) Step {
+	from := uint64(1)
+	for _, step := range s.config.Timeline {
+		if sampleID >= from && sampleID < from+uint64(step.Samples) {
+			return step
+		}
+		from += uint64(step.Samples)
+	}
+	return Step{Fault: Healthy}
+}
+
+// seen records the sample of the given client, and returns the time
+// elapsed since the shutdown started if the step is shutting down.
+func (s *Server) seen(client string, sampleID uint64, step Step) time.Duration {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if sampleID > s.highest[client] {
+		s.highest[client] = sampleID
+	}
+	remaining := []waiter{}
+	for _, w := range s.waiting[client] {
+		if sampleID >= w.sampleID {
+			close(w.ch)
+			continue
+		}
+		remaining = append(remaining, w)
+	}
+	s.waiting[client] = remaining
+
+	if !step.ShuttingDown {
+		return 0
+	}
+	if s.shutdownSince.IsZero() {
+		s.shutdownSince = time.Now()
+	}
+	return time.Since(s.shutdownSince)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != s.config.Path {
+		http.NotFound(w, r)
+		return
+	}
+	sampleID, err := strconv.ParseUint(r.URL.Query().Get("sample-id"), 10, 64)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid sample-id: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	step := s.step(sampleID)
+	elapsed := s.seen(r.UserAgent(), sampleID, step)
+
+	switch step.Fault {
+	case DropConnection, ResetConnection:
+		conn, ok := r.Context().Value(connKey{}).(net.Conn)
+		if !ok {
+			panic(http.ErrAbortHandler)
+		}
+		if tlsConn, ok := conn.(*tls.Conn); ok {
+			conn = tlsConn.NetConn()
+		}
+		if tcpConn, ok := conn.(*net.TCPConn); ok && step.Fault == ResetConnection {
+			// with no linger, close sends a RST rather than a FIN
+			tcpConn.SetLinger(0)
+		}
+		conn.Close()
+		return
+	case Delay:
+		select {
+		case <-time.After(step.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if len(s.config.Hostname) > 0 {
+		w.Header().Set("X-OpenShift-Disruption", fmt.Sprintf("shutdown=%t shutdown-delay-duration=%s elapsed=%s host=%s",
+			step.ShuttingDown, s.config.ShutdownDelayDuration, elapsed, s.config.Hostname))
+	}
+	if step.Fault == ServerError {
+		code := step.StatusCode
+		if code == 0 {
+			code = http.StatusInternalServerError
+		}
+		w.WriteHeader(code)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,141 @@
+package faultinjection
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServerTimeline(t *testing.T) {
+	server, err := NewServer(Config{
+		Path: "/healthz",
+		Timeline: []Step{
+			{Samples: 1},
+			{Samples: 1, Fault: ServerError, StatusCode: http.StatusServiceUnavailable},
+			{Samples: 1, Fault: DropConnection},
+			{Samples: 1, Fault: ResetConnection},
+			{Samples: 1, Fault: Delay, Delay: 5 * time.Second},
+			{Samples: 1, ShuttingDown: true},
+		},
+		Hostname:              "master-0",
+		ShutdownDelayDuration: 10 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("failed to start the server: %v", err)
+	}
+	defer server.Close()
+
+	client := &http.Client{
+		Timeout: time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+			DisableKeepAlives: true,
+		},
+	}
+	get := func(path string, sampleID uint64) (*http.Response, error) {
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s%s?sample-id=%d", server.URL(), path, sampleID), nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		req.Header.Set("User-Agent", "test-client")
+		return client.Do(req)
+	}
+
+	tests := []struct {
+		sampleID uint64
+		code     int
+		wantErr  bool
+		shutdown string
+	}{
+		{sampleID: 1, code: http.StatusOK, shutdown: "shutdown=false"},
+		{sampleID: 2, code: http.StatusServiceUnavailable, shutdown: "shutdown=false"},
+		{sampleID: 3, wantErr: true},
+		{sampleID: 4, wantErr: true},
+		{sampleID: 5, wantErr: true},
+		{sampleID: 6, code: http.StatusOK, shutdown: "shutdown=true shutdown-delay-duration=10s"},
+		{sampleID: 7, code: http.StatusOK, shutdown: "shutdown=false"},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("sample %d", test.sampleID), func(t *testing.T) {
+			resp, err := get("/healthz", test.sampleID)
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, but got a response: %s", resp.Status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, but got: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != test.code {
+				t.Errorf("expected status code: %d, but got: %d", test.code, resp.StatusCode)
+			}
+			if header := resp.Header.Get("X-OpenShift-Disruption"); !strings.HasPrefix(header, test.shutdown) || !strings.HasSuffix(header, "host=master-0") {
+				t.Errorf("expected the shutdown response header to start with %q, but got: %q", test.shutdown, header)
+			}
+		})
+	}
+
+	resp, err := get("/api", 1)
+	if err != nil {
+		t.Fatalf("expected no error, but got: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status code: %d, but got: %d", http.StatusNotFound, resp.StatusCode)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := server.WaitForSample(ctx, "test-client", server.TimelineSamples()); err != nil {
+		t.Errorf("expected the timeline to be done, but got: %v", err)
+	}
+	if err := server.WaitForSample(ctx, "other-client", 1); err == nil {
+		t.Errorf("expected an error waiting for a client that never sent a sample")
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{
+			name:   "valid",
+			config: Config{Path: "/healthz", Timeline: []Step{{Samples: 1}, {Samples: 2, Fault: Delay, Delay: time.Second}}},
+		},
+		{
+			name:    "no path",
+			config:  Config{},
+			wantErr: true,
+		},
+		{
+			name:    "no samples",
+			config:  Config{Path: "/healthz", Timeline: []Step{{Fault: ServerError}}},
+			wantErr: true,
+		},
+		{
+			name:    "delay without duration",
+			config:  Config{Path: "/healthz", Timeline: []Step{{Samples: 1, Fault: Delay}}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown fault",
+			config:  Config{Path: "/healthz", Timeline: []Step{{Samples: 1, Fault: "Flood"}}},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.config.Validate(); test.wantErr != (err != nil) {
+				t.Errorf("expected error: %t, but got: %v", test.wantErr, err)
+			}
+		})
+	}
+}
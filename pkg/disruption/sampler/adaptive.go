@@ -0,0 +1,126 @@
+package sampler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// AdaptiveConfig allows the sampler to step up to a faster rate when it
+// suspects an outage, so short disruptions are measured with a better
+// resolution than the regular interval, and to step back down once the
+// backend has been quiet for a while.
+// The zero value disables adaptive sampling.
+type AdaptiveConfig struct {
+	// Interval is the interval between the samples while an outage is
+	// suspected, it must be shorter than the regular interval.
+	Interval time.Duration
+
+	// QuietPeriod is how long the samples must be healthy before the
+	// sampler steps back down to the regular interval.
+	QuietPeriod time.Duration
+
+	// LatencyThreshold, if set, is the round trip latency above which a
+	// successful sample raises the suspicion of an outage as well.
+	LatencyThreshold time.Duration
+}
+
+// Enabled returns true if the sampler should adapt its rate.
+func (c AdaptiveConfig) Enabled() bool {
+	return c.Interval > 0
+}
+
+// Validate checks the adaptive configuration against the regular
+// interval of the sampler.
+func (c AdaptiveConfig) Validate(interval time.Duration) error {
+	if !c.Enabled() {
+		if c.Interval < 0 {
+			return fmt.Errorf("adaptive Interval must not be negative")
+		}
+		return nil
+	}
+	if c.Interval >= interval {
+		return fmt.Errorf("adaptive Interval %s must be shorter than the sample interval %s", c.Interval, interval)
+	}
+	if c.QuietPeriod <= 0 {
+		return fmt.Errorf("adaptive QuietPeriod must be greater than zero")
+	}
+	if c.LatencyThreshold < 0 {
+		return fmt.Errorf("adaptive LatencyThreshold must not be negative")
+	}
+	return nil
+}
+
+// pacer decides how long the sampler waits before generating the next sample.
+type pacer interface {
+	// observe is invoked as soon as a sample completes, before it is consumed.
+	observe(s *Sample)
+	// next returns the interval between the last sample and the next one.
+	next(now time.Time) time.Duration
+	// steppedUp is notified when the pacer steps up to a faster rate,
+	// so the sampler does not wait out the regular interval.
+	steppedUp() <-chan struct{}
+}
+
+type fixedPacer time.Duration
+
+func (p fixedPacer) observe(*Sample)              {}
+func (p fixedPacer) next(time.Time) time.Duration { return time.Duration(p) }
+func (p fixedPacer) steppedUp() <-chan struct{}   { return nil }
+
+func newAdaptivePacer(interval time.Duration, config AdaptiveConfig) *adaptivePacer {
+	return &adaptivePacer{
+		interval: interval,
+		config:   config,
+		stepUpCh: make(chan struct{}, 1),
+	}
+}
+
+type adaptivePacer struct {
+	interval time.Duration
+	config   AdaptiveConfig
+	stepUpCh chan struct{}
+
+	lock sync.Mutex
+	// suspectedAt is when the last failed, or slow, sample completed
+	suspectedAt time.Time
+}
+
+func (p *adaptivePacer) observe(s *Sample) {
+	if s.Err == nil && (p.config.LatencyThreshold <= 0 || s.FinishedAt.Sub(s.StartedAt) <= p.config.LatencyThreshold) {
+		return
+	}
+
+	p.lock.Lock()
+	steppingUp := !p.suspected(s.FinishedAt)
+	if s.FinishedAt.After(p.suspectedAt) {
+		p.suspectedAt = s.FinishedAt
+	}
+	p.lock.Unlock()
+
+	if steppingUp {
+		select {
+		case p.stepUpCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (p *adaptivePacer) next(now time.Time) time.Duration {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.suspected(now) {
+		return p.config.Interval
+	}
+	return p.interval
+}
+
+func (p *adaptivePacer) steppedUp() <-chan struct{} {
+	return p.stepUpCh
+}
+
+// suspected returns true if an outage is still suspected at the given
+// time, the caller must hold the lock.
+func (p *adaptivePacer) suspected(at time.Time) bool {
+	return !p.suspectedAt.IsZero() && at.Sub(p.suspectedAt) < p.config.QuietPeriod
+}
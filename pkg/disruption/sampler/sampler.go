@@ -40,7 +40,17 @@ type ProducerConsumer interface {
 }
 
 func NewWithProducerConsumer(interval time.Duration, pc ProducerConsumer) Runner {
-	return &sampler{interval: interval, producer: pc, consumer: pc}
+	return &sampler{pacer: fixedPacer(interval), producer: pc, consumer: pc}
+}
+
+// NewAdaptiveWithProducerConsumer returns a Runner that generates a sample
+// every interval, and steps up to the faster adaptive rate while it suspects
+// an outage, see AdaptiveConfig.
+func NewAdaptiveWithProducerConsumer(interval time.Duration, adaptive AdaptiveConfig, pc ProducerConsumer) Runner {
+	if !adaptive.Enabled() {
+		return NewWithProducerConsumer(interval, pc)
+	}
+	return &sampler{pacer: newAdaptivePacer(interval, adaptive), producer: pc, consumer: pc}
 }
 
 type result struct {
@@ -50,13 +60,13 @@ type result struct {
 }
 
 type sampler struct {
-	interval time.Duration
+	pacer    pacer
 	producer Producer
 	consumer Consumer
 }
 
 func (s sampler) Run(stop context.Context) context.Context {
-	resultCh, producerDoneCh := produce(stop, s.interval, s.producer)
+	resultCh, producerDoneCh := produce(stop, s.pacer, s.producer)
 	consumerDoneCh := consume(resultCh, s.consumer)
 
 	done, cancel := context.WithCancel(context.Background())
@@ -68,17 +78,15 @@ func (s sampler) Run(stop context.Context) context.Context {
 	return done
 }
 
-func produce(stop context.Context, interval time.Duration, p Producer) (<-chan result, <-chan struct{}) {
+func produce(stop context.Context, pacer pacer, p Producer) (<-chan result, <-chan struct{}) {
 	resultCh := make(chan result, 1)
 	producerDoneCh := make(chan struct{})
 	go func() {
 		wg := sync.WaitGroup{}
-		ticker := time.NewTicker(interval)
 		defer func() {
 			// wait for all the sample generating goroutines to be done.
 			wg.Wait()
 
-			ticker.Stop()
 			// closing resultCh ensures that the consumer reading
 			// from this channel will terminate.
 			close(resultCh)
@@ -107,6 +115,7 @@ func produce(stop context.Context, interval time.Duration, p Producer) (<-chan r
 					}()
 					result.custom, sample.Err = p.Produce(stop, sample.ID)
 				}()
+				pacer.observe(sample)
 
 				// we want the write to the resultCh channel be in order as well
 				// this guarantees that the consumer will see the samples
@@ -120,9 +129,7 @@ func produce(stop context.Context, interval time.Duration, p Producer) (<-chan r
 			// the next goroutine will wait for this channel to be closed
 			waitCh = thisOneDoneCh
 
-			select {
-			case <-ticker.C:
-			case <-stop.Done():
+			if !wait(stop, pacer, now) {
 				return
 			}
 		}
@@ -131,6 +138,27 @@ func produce(stop context.Context, interval time.Duration, p Producer) (<-chan r
 	return resultCh, producerDoneCh
 }
 
+// wait waits until the next sample is due, counting from the start of the
+// last sample, it returns false if the stop context is done before then.
+func wait(stop context.Context, pacer pacer, lastStartedAt time.Time) bool {
+	timer := time.NewTimer(time.Until(lastStartedAt.Add(pacer.next(time.Now()))))
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			return true
+		case <-pacer.steppedUp():
+			// an outage is suspected, the next sample is due sooner
+			if !timer.Stop() {
+				return true
+			}
+			timer.Reset(time.Until(lastStartedAt.Add(pacer.next(time.Now()))))
+		case <-stop.Done():
+			return false
+		}
+	}
+}
+
 func consume(resultCh <-chan result, consumer Consumer) <-chan struct{} {
 	consumerDoneCh := make(chan struct{})
 	go func() {
@@ -83,3 +83,84 @@ func (f *fakeProducerConsumer) verify() error {
 	}
 	return nil
 }
+
+func TestAdaptivePacer(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+	sample := func(from, to time.Duration, err error) *Sample {
+		return &Sample{StartedAt: at(from), FinishedAt: at(to), Err: err}
+	}
+
+	p := newAdaptivePacer(time.Second, AdaptiveConfig{
+		Interval:         100 * time.Millisecond,
+		QuietPeriod:      5 * time.Second,
+		LatencyThreshold: 500 * time.Millisecond,
+	})
+	steps := []struct {
+		name      string
+		sample    *Sample
+		now       time.Duration
+		next      time.Duration
+		steppedUp bool
+	}{
+		{name: "healthy", sample: sample(0, 10*time.Millisecond, nil), now: 0, next: time.Second},
+		{name: "failed", sample: sample(time.Second, 1010*time.Millisecond, fmt.Errorf("error")), now: time.Second, next: 100 * time.Millisecond, steppedUp: true},
+		{name: "failed again", sample: sample(1100*time.Millisecond, 1110*time.Millisecond, fmt.Errorf("error")), now: 1100 * time.Millisecond, next: 100 * time.Millisecond},
+		{name: "healthy, but not for long", sample: sample(1200*time.Millisecond, 1210*time.Millisecond, nil), now: 6 * time.Second, next: 100 * time.Millisecond},
+		{name: "quiet", sample: sample(7*time.Second, 7010*time.Millisecond, nil), now: 7 * time.Second, next: time.Second},
+		{name: "slow", sample: sample(8*time.Second, 9*time.Second, nil), now: 9 * time.Second, next: 100 * time.Millisecond, steppedUp: true},
+	}
+	for _, step := range steps {
+		p.observe(step.sample)
+		if want, got := step.next, p.next(at(step.now)); want != got {
+			t.Errorf("%s: expected the next sample in %s, but got: %s", step.name, want, got)
+		}
+		steppedUp := false
+		select {
+		case <-p.steppedUp():
+			steppedUp = true
+		default:
+		}
+		if step.steppedUp != steppedUp {
+			t.Errorf("%s: expected stepped up: %t, but got: %t", step.name, step.steppedUp, steppedUp)
+		}
+	}
+}
+
+func TestAdaptiveSampler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// the first sample fails, with an hour between the regular samples
+	// only the adaptive rate can produce more than one sample.
+	fake := &fakeProducerConsumer{t: t}
+	pc := &failingProducerConsumer{fakeProducerConsumer: fake, failures: 1}
+	runner := NewAdaptiveWithProducerConsumer(time.Hour, AdaptiveConfig{Interval: 10 * time.Millisecond, QuietPeriod: 100 * time.Millisecond}, pc)
+	stopped := runner.Run(ctx)
+
+	<-ctx.Done()
+	<-stopped.Done()
+	count := atomic.LoadInt64(&fake.count)
+	if count != int64(len(fake.samples)) {
+		t.Errorf("expected %d samples collected, but got: %d", count, len(fake.samples))
+	}
+	// about ten samples during the quiet period, and then back to one an hour
+	if count < 2 || count > 50 {
+		t.Errorf("expected the sampler to step up, and then back down, but got %d samples", count)
+	}
+	if !sort.IsSorted(SortedByID(fake.samples)) {
+		t.Errorf("expected resulting samples to be in 1, 2, 3, ... N sequence")
+	}
+}
+
+type failingProducerConsumer struct {
+	*fakeProducerConsumer
+	failures int64
+}
+
+func (f *failingProducerConsumer) Produce(_ context.Context, id uint64) (interface{}, error) {
+	if count := atomic.AddInt64(&f.count, 1); count <= f.failures {
+		return nil, fmt.Errorf("error")
+	}
+	return nil, nil
+}
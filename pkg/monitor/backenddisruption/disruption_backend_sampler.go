@@ -127,10 +127,15 @@ func NewSimpleBackendWithLocator(locator monitorapi.Locator, host, path string,
 	return ret
 }
 
-// NewAPIServerBackend constructs a BackendSampler suitable for use against a kube-like API server
-func NewAPIServerBackend(clientConfig *rest.Config, disruptionBackendName, path string, connectionType monitorapi.BackendConnectionType) (*BackendSampler, error) {
+// APIServerBackendLocator returns the locator of the BackendSampler NewAPIServerBackend constructs, which identifies
+// its samples in the intervals.
+func APIServerBackendLocator(disruptionBackendName string, connectionType monitorapi.BackendConnectionType) monitorapi.Locator {
 	historicalBackendDisruptionDataName := fmt.Sprintf("%s-%v-connections", disruptionBackendName, connectionType)
+	return monitorapi.NewLocator().LocateDisruptionCheck(historicalBackendDisruptionDataName, OpenshiftTestsSource, connectionType)
+}
 
+// NewAPIServerBackend constructs a BackendSampler suitable for use against a kube-like API server
+func NewAPIServerBackend(clientConfig *rest.Config, disruptionBackendName, path string, connectionType monitorapi.BackendConnectionType) (*BackendSampler, error) {
 	kubeTransportConfig, err := clientConfig.TransportConfig()
 	if err != nil {
 		return nil, err
@@ -142,7 +147,7 @@ func NewAPIServerBackend(clientConfig *rest.Config, disruptionBackendName, path
 
 	ret := &BackendSampler{
 		connectionType:      connectionType,
-		locator:             monitorapi.NewLocator().LocateDisruptionCheck(historicalBackendDisruptionDataName, OpenshiftTestsSource, connectionType),
+		locator:             APIServerBackendLocator(disruptionBackendName, connectionType),
 		path:                path,
 		hostGetter:          NewKubeAPIHostGetter(clientConfig),
 		tlsConfig:           tlsConfig,
@@ -159,13 +164,18 @@ func NewAPIServerBackend(clientConfig *rest.Config, disruptionBackendName, path
 	return ret, nil
 }
 
-// NewRouteBackend constructs a BackendSampler suitable for use against a routes.route.openshift.io
-func NewRouteBackend(clientConfig *rest.Config, namespace, name, disruptionBackendName, path string, connectionType monitorapi.BackendConnectionType) *BackendSampler {
+// RouteBackendLocator returns the locator of the BackendSampler NewRouteBackend constructs, which identifies its
+// samples in the intervals.
+func RouteBackendLocator(namespace, name, disruptionBackendName string, connectionType monitorapi.BackendConnectionType) monitorapi.Locator {
 	historicalBackendDisruptionDataName := fmt.Sprintf("%s-%v-connections", disruptionBackendName, connectionType)
+	return monitorapi.NewLocator().LocateRouteForDisruptionCheck(historicalBackendDisruptionDataName, OpenshiftTestsSource, namespace, name, connectionType)
+}
 
+// NewRouteBackend constructs a BackendSampler suitable for use against a routes.route.openshift.io
+func NewRouteBackend(clientConfig *rest.Config, namespace, name, disruptionBackendName, path string, connectionType monitorapi.BackendConnectionType) *BackendSampler {
 	ret := &BackendSampler{
 		connectionType:      connectionType,
-		locator:             monitorapi.NewLocator().LocateRouteForDisruptionCheck(historicalBackendDisruptionDataName, OpenshiftTestsSource, namespace, name, connectionType),
+		locator:             RouteBackendLocator(namespace, name, disruptionBackendName, connectionType),
 		path:                path,
 		hostGetter:          NewRouteHostGetter(clientConfig, namespace, name),
 		consumptionFinished: make(chan struct{}),
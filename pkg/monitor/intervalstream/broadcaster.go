@@ -0,0 +1,81 @@
+package intervalstream
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// defaultSubscriberBuffer is how many intervals may be queued for a subscriber before new intervals are dropped.
+const defaultSubscriberBuffer = 1000
+
+// Broadcaster fans out published intervals to every subscriber whose filter matches.  Publishing never blocks:
+// a subscriber that falls behind has intervals dropped and counted instead of slowing the recorder down.
+type Broadcaster struct {
+	lock        sync.Mutex
+	subscribers map[*Subscription]struct{}
+}
+
+func NewBroadcaster() *Broadcaster {
+	return &Broadcaster{
+		subscribers: map[*Subscription]struct{}{},
+	}
+}
+
+type Subscription struct {
+	filter    Filter
+	intervals chan monitorapi.Interval
+	dropped   int64
+}
+
+// Intervals returns the channel matching intervals are delivered on.  It is closed on Unsubscribe.
+func (s *Subscription) Intervals() <-chan monitorapi.Interval {
+	return s.intervals
+}
+
+// Dropped returns and resets the number of intervals dropped since the last call because the subscriber was too slow.
+func (s *Subscription) Dropped() int64 {
+	return atomic.SwapInt64(&s.dropped, 0)
+}
+
+func (b *Broadcaster) Subscribe(filter Filter) *Subscription {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	subscription := &Subscription{
+		filter:    filter,
+		intervals: make(chan monitorapi.Interval, defaultSubscriberBuffer),
+	}
+	b.subscribers[subscription] = struct{}{}
+	return subscription
+}
+
+func (b *Broadcaster) Unsubscribe(subscription *Subscription) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if _, ok := b.subscribers[subscription]; !ok {
+		return
+	}
+	delete(b.subscribers, subscription)
+	close(subscription.intervals)
+}
+
+func (b *Broadcaster) Publish(intervals ...monitorapi.Interval) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for subscription := range b.subscribers {
+		for _, interval := range intervals {
+			if !subscription.filter.Matches(interval) {
+				continue
+			}
+			select {
+			case subscription.intervals <- interval:
+			default:
+				atomic.AddInt64(&subscription.dropped, 1)
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package intervalstream
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+)
+
+// Handler is called by Tail for every streamed interval, and with dropped set when the server
+// skipped intervals because the client could not keep up.
+type Handler interface {
+	Interval(interval monitorapi.Interval)
+	Dropped(count int64)
+}
+
+// Tail connects to the interval stream served at baseURL and calls handler until ctx is done or the server
+// closes the stream.
+func Tail(ctx context.Context, baseURL string, filter Filter, handler Handler) error {
+	streamURL := strings.TrimSuffix(baseURL, "/") + IntervalsPath
+	if query := filter.ToQuery().Encode(); len(query) > 0 {
+		streamURL += "?" + query
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, streamURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status from %s: %s", streamURL, resp.Status)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	event, data := "", ""
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case len(line) == 0:
+			if err := dispatch(event, data, handler); err != nil {
+				return err
+			}
+			event, data = "", ""
+		case strings.HasPrefix(line, ":"):
+			// comment, used for keep-alive
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data += strings.TrimPrefix(line, "data: ")
+		}
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return scanner.Err()
+}
+
+func dispatch(event, data string, handler Handler) error {
+	switch event {
+	case intervalEvent:
+		interval, err := monitorserialization.IntervalFromJSON([]byte(data))
+		if err != nil {
+			return fmt.Errorf("unable to decode streamed interval: %w", err)
+		}
+		handler.Interval(*interval)
+	case droppedEvent:
+		count, err := strconv.ParseInt(data, 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode dropped count: %w", err)
+		}
+		handler.Dropped(count)
+	}
+	return nil
+}
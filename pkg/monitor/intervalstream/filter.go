@@ -0,0 +1,69 @@
+package intervalstream
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	sourceParam      = "source"
+	locatorTypeParam = "locatorType"
+	levelParam       = "level"
+)
+
+// Filter selects which intervals are streamed to a client.  Empty fields match everything.
+type Filter struct {
+	// Sources limits the stream to intervals from any of these sources.
+	Sources sets.String
+	// LocatorTypes limits the stream to intervals whose structured locator has any of these types.
+	LocatorTypes sets.String
+	// MinimumLevel limits the stream to intervals at or above this level.
+	MinimumLevel monitorapi.IntervalLevel
+}
+
+// Matches returns true if the interval should be streamed.
+func (f Filter) Matches(interval monitorapi.Interval) bool {
+	if f.Sources.Len() > 0 && !f.Sources.Has(string(interval.Source)) {
+		return false
+	}
+	if f.LocatorTypes.Len() > 0 && !f.LocatorTypes.Has(string(interval.StructuredLocator.Type)) {
+		return false
+	}
+	return interval.Level >= f.MinimumLevel
+}
+
+// ToQuery encodes the filter as url query parameters understood by FilterFromQuery.
+func (f Filter) ToQuery() url.Values {
+	query := url.Values{}
+	for _, source := range f.Sources.List() {
+		query.Add(sourceParam, source)
+	}
+	for _, locatorType := range f.LocatorTypes.List() {
+		query.Add(locatorTypeParam, locatorType)
+	}
+	if f.MinimumLevel != monitorapi.Info {
+		query.Set(levelParam, f.MinimumLevel.String())
+	}
+	return query
+}
+
+// FilterFromQuery builds a filter from url query parameters.  source and locatorType may be repeated,
+// level is the minimum level: Info, Warning, or Error.
+func FilterFromQuery(query url.Values) (Filter, error) {
+	filter := Filter{
+		Sources:      sets.NewString(query[sourceParam]...),
+		LocatorTypes: sets.NewString(query[locatorTypeParam]...),
+		MinimumLevel: monitorapi.Info,
+	}
+	if level := query.Get(levelParam); len(level) > 0 {
+		minimumLevel, err := monitorapi.ConditionLevelFromString(level)
+		if err != nil {
+			return Filter{}, fmt.Errorf("invalid %s: %w", levelParam, err)
+		}
+		filter.MinimumLevel = minimumLevel
+	}
+	return filter, nil
+}
@@ -0,0 +1,81 @@
+package intervalstream
+
+import (
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type streamingRecorder struct {
+	delegate    monitorapi.Recorder
+	broadcaster *Broadcaster
+}
+
+// WrapWithStreamingRecorder publishes every interval written through the recorder to the broadcaster.
+// Like the JSONL recorder, intervals are published when they are added or ended.
+func WrapWithStreamingRecorder(delegate monitorapi.Recorder, broadcaster *Broadcaster) monitorapi.Recorder {
+	return &streamingRecorder{
+		delegate:    delegate,
+		broadcaster: broadcaster,
+	}
+}
+
+var _ monitorapi.Recorder = &streamingRecorder{}
+
+func (m *streamingRecorder) CurrentResourceState() monitorapi.ResourcesMap {
+	return m.delegate.CurrentResourceState()
+}
+
+func (m *streamingRecorder) RecordResource(resourceType string, obj runtime.Object) {
+	m.delegate.RecordResource(resourceType, obj)
+}
+
+// Record captures one or more conditions at the current time. All conditions are recorded
+// in monotonic order as EventInterval objects.
+func (m *streamingRecorder) Record(conditions ...monitorapi.Condition) {
+	m.RecordAt(time.Now().UTC(), conditions...)
+}
+
+// RecordAt captures one or more conditions at the provided time. All conditions are recorded
+// as EventInterval objects.
+func (m *streamingRecorder) RecordAt(t time.Time, conditions ...monitorapi.Condition) {
+	if len(conditions) == 0 {
+		return
+	}
+	intervals := monitorapi.Intervals{}
+	for _, condition := range conditions {
+		intervals = append(intervals, monitorapi.Interval{
+			Condition: condition,
+			From:      t,
+			To:        t,
+		})
+	}
+	m.AddIntervals(intervals...)
+}
+
+// AddIntervals provides a mechanism to directly inject eventIntervals
+func (m *streamingRecorder) AddIntervals(intervals ...monitorapi.Interval) {
+	m.delegate.AddIntervals(intervals...)
+	m.broadcaster.Publish(intervals...)
+}
+
+// StartInterval inserts a record at time t with the provided condition and returns an opaque
+// locator to the interval. The caller may close the sample at any point by invoking EndInterval().
+func (m *streamingRecorder) StartInterval(interval monitorapi.Interval) int {
+	return m.delegate.StartInterval(interval)
+}
+
+// EndInterval updates the To of the interval started by StartInterval if it is greater than
+// the from.
+func (m *streamingRecorder) EndInterval(startedInterval int, t time.Time) *monitorapi.Interval {
+	ret := m.delegate.EndInterval(startedInterval, t)
+	if ret != nil {
+		m.broadcaster.Publish(*ret)
+	}
+	return ret
+}
+
+func (m *streamingRecorder) Intervals(from, to time.Time) monitorapi.Intervals {
+	return m.delegate.Intervals(from, to)
+}
@@ -0,0 +1,141 @@
+package intervalstream
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+)
+
+const (
+	// IntervalsPath serves a server-sent events stream of intervals as they are recorded.
+	IntervalsPath = "/intervals"
+
+	intervalEvent = "interval"
+	droppedEvent  = "dropped"
+
+	keepAliveInterval = 15 * time.Second
+)
+
+// Server streams intervals published to a broadcaster to local HTTP clients using server-sent events.
+type Server struct {
+	broadcaster *Broadcaster
+	httpServer  *http.Server
+	listener    net.Listener
+}
+
+// NewServer listens on address, for instance 127.0.0.1:9911, and serves the interval stream once Start is called.
+func NewServer(address string, broadcaster *Broadcaster) (*Server, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("unable to listen for interval streaming: %w", err)
+	}
+
+	s := &Server{
+		broadcaster: broadcaster,
+		listener:    listener,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(IntervalsPath, s.serveIntervals)
+	s.httpServer = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s, nil
+}
+
+// StreamIntervals serves the intervals recorded through the returned recorder on address until ctx is done, and
+// reports where they are served to out.
+func StreamIntervals(ctx context.Context, address string, delegate monitorapi.Recorder, out io.Writer) (monitorapi.Recorder, error) {
+	broadcaster := NewBroadcaster()
+	streamServer, err := NewServer(address, broadcaster)
+	if err != nil {
+		return nil, err
+	}
+	streamServer.Start(ctx)
+	fmt.Fprintf(out, "Streaming monitor intervals on http://%s%s\n", streamServer.Address(), IntervalsPath)
+	return WrapWithStreamingRecorder(delegate, broadcaster), nil
+}
+
+// Address returns the address the server is listening on.
+func (s *Server) Address() string {
+	return s.listener.Addr().String()
+}
+
+// Start serves until ctx is done.
+func (s *Server) Start(ctx context.Context) {
+	go func() {
+		if err := s.httpServer.Serve(s.listener); err != nil && err != http.ErrServerClosed {
+			fmt.Fprintf(os.Stderr, "interval streaming server failed: %v\n", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.httpServer.Shutdown(shutdownCtx); err != nil {
+			s.httpServer.Close()
+		}
+	}()
+}
+
+func (s *Server) serveIntervals(w http.ResponseWriter, req *http.Request) {
+	filter, err := FilterFromQuery(req.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	subscription := s.broadcaster.Subscribe(filter)
+	defer s.broadcaster.Unsubscribe(subscription)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+
+		case <-keepAlive.C:
+			if _, err := fmt.Fprintf(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+
+		case interval, ok := <-subscription.Intervals():
+			if !ok {
+				return
+			}
+			if dropped := subscription.Dropped(); dropped > 0 {
+				if _, err := fmt.Fprintf(w, "event: %s\ndata: %d\n\n", droppedEvent, dropped); err != nil {
+					return
+				}
+			}
+			intervalJSON, err := monitorserialization.IntervalToPreciseOneLineJSON(interval)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", intervalEvent, intervalJSON); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
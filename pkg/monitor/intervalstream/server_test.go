@@ -0,0 +1,124 @@
+package intervalstream
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+type collectingHandler struct {
+	lock      sync.Mutex
+	intervals monitorapi.Intervals
+}
+
+func (h *collectingHandler) Interval(interval monitorapi.Interval) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.intervals = append(h.intervals, interval)
+}
+
+func (h *collectingHandler) Dropped(count int64) {}
+
+func (h *collectingHandler) messages() []string {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	ret := []string{}
+	for _, interval := range h.intervals {
+		ret = append(ret, interval.StructuredMessage.HumanMessage)
+	}
+	return ret
+}
+
+// testRecorder is the minimal recorder needed to exercise the streaming wrapper.
+type testRecorder struct {
+	monitorapi.Recorder
+	started monitorapi.Intervals
+}
+
+func (r *testRecorder) AddIntervals(eventIntervals ...monitorapi.Interval) {}
+
+func (r *testRecorder) StartInterval(interval monitorapi.Interval) int {
+	r.started = append(r.started, interval)
+	return len(r.started) - 1
+}
+
+func (r *testRecorder) EndInterval(startedInterval int, t time.Time) *monitorapi.Interval {
+	r.started[startedInterval].To = t
+	ret := r.started[startedInterval]
+	return &ret
+}
+
+func TestStreamingRecorder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	broadcaster := NewBroadcaster()
+	server, err := NewServer("127.0.0.1:0", broadcaster)
+	require.NoError(t, err)
+	server.Start(ctx)
+
+	handler := &collectingHandler{}
+	filter := Filter{
+		Sources:      sets.NewString(string(monitorapi.SourceDisruption)),
+		MinimumLevel: monitorapi.Warning,
+	}
+	tailDone := make(chan error)
+	go func() {
+		tailDone <- Tail(ctx, "http://"+server.Address(), filter, handler)
+	}()
+	require.Eventually(t, func() bool {
+		broadcaster.lock.Lock()
+		defer broadcaster.lock.Unlock()
+		return len(broadcaster.subscribers) == 1
+	}, 10*time.Second, 10*time.Millisecond)
+
+	recorder := WrapWithStreamingRecorder(&testRecorder{}, broadcaster)
+	now := time.Now()
+	newInterval := func(source monitorapi.IntervalSource, level monitorapi.IntervalLevel, message string) monitorapi.Interval {
+		return monitorapi.NewInterval(source, level).
+			Locator(monitorapi.NewLocator().DisruptionRequiredOnly("kube-api-new-connections", "kube-api")).
+			Message(monitorapi.NewMessage().HumanMessage(message)).
+			Build(now, now)
+	}
+	recorder.AddIntervals(
+		newInterval(monitorapi.SourceDisruption, monitorapi.Error, "disruption error"),
+		newInterval(monitorapi.SourceDisruption, monitorapi.Info, "disruption info"),
+		newInterval(monitorapi.SourceAlert, monitorapi.Error, "alert error"),
+	)
+	started := recorder.StartInterval(newInterval(monitorapi.SourceDisruption, monitorapi.Warning, "disruption ended"))
+	recorder.EndInterval(started, now.Add(3*time.Second))
+
+	require.Eventually(t, func() bool {
+		return len(handler.messages()) == 2
+	}, 10*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"disruption error", "disruption ended"}, handler.messages())
+	assert.Equal(t, 3*time.Second, handler.intervals[1].To.Sub(handler.intervals[1].From))
+
+	cancel()
+	select {
+	case err := <-tailDone:
+		assert.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("tail did not stop")
+	}
+}
+
+func TestFilterQueryRoundTrip(t *testing.T) {
+	filter := Filter{
+		Sources:      sets.NewString("Disruption", "OperatorState"),
+		LocatorTypes: sets.NewString("ClusterOperator"),
+		MinimumLevel: monitorapi.Error,
+	}
+	actual, err := FilterFromQuery(filter.ToQuery())
+	require.NoError(t, err)
+	assert.Equal(t, filter, actual)
+
+	_, err = FilterFromQuery(map[string][]string{levelParam: {"Fatal"}})
+	assert.Error(t, err)
+}
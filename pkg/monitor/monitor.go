@@ -32,6 +32,12 @@ type Monitor struct {
 	recorder monitorapi.Recorder
 	junits   []*junitapi.JUnitTestCase
 
+	// replay indicates the recorder was pre-populated from a prior run and no cluster is available.
+	replay bool
+	// annotate adds the annotations of the monitor tests to the recorded intervals, it is set once the intervals
+	// have been computed.
+	annotate monitortestframework.IntervalAnnotationFunc
+
 	lock      sync.Mutex
 	stopFn    context.CancelFunc
 	startTime time.Time
@@ -52,6 +58,43 @@ func NewMonitor(
 	}
 }
 
+// NewResumedMonitor creates a monitor for a run resumed after being interrupted.  The recorder must already contain
+// the intervals recorded before the interruption, the monitor tests are started again, and the intervals are
+// evaluated from the start time of the interrupted run.
+func NewResumedMonitor(
+	recorder monitorapi.Recorder,
+	adminKubeConfig *rest.Config,
+	storageDir string,
+	monitorTestRegistry monitortestframework.MonitorTestRegistry,
+	startTime time.Time) Interface {
+	return &Monitor{
+		adminKubeConfig:     adminKubeConfig,
+		recorder:            recorder,
+		monitorTestRegistry: monitorTestRegistry,
+		storageDir:          storageDir,
+		startTime:           startTime,
+	}
+}
+
+// NewReplayMonitor creates a monitor that re-evaluates intervals and resources gathered by a prior run.
+// The recorder must already contain everything that was recorded.  StartCollection and CollectData are skipped,
+// so no cluster is required, and the provided start and stop times bound the evaluation just like a live run.
+// Only the monitor tests implementing monitortestframework.ReplayableMonitorTest are evaluated.
+func NewReplayMonitor(
+	recorder monitorapi.Recorder,
+	storageDir string,
+	monitorTestRegistry monitortestframework.MonitorTestRegistry,
+	startTime, stopTime time.Time) Interface {
+	return &Monitor{
+		recorder:            recorder,
+		monitorTestRegistry: monitorTestRegistry,
+		storageDir:          storageDir,
+		replay:              true,
+		startTime:           startTime,
+		stopTime:            stopTime,
+	}
+}
+
 var _ Interface = &Monitor{}
 
 // Start begins monitoring the cluster referenced by the default kube configuration until context is finished.
@@ -62,7 +105,20 @@ func (m *Monitor) Start(ctx context.Context) error {
 		return fmt.Errorf("monitor already started")
 	}
 	ctx, m.stopFn = context.WithCancel(ctx)
-	m.startTime = time.Now()
+	if m.replay {
+		fmt.Printf("Replaying monitor tests, skipping collection.\n")
+		// monitor tests that depend on what they collect are skipped instead of running without their state.
+		replayRegistry, localJunits, err := m.monitorTestRegistry.PrepareForReplay(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error preparing monitor tests for replay, continuing, junit will reflect this. %v\n", err)
+		}
+		m.monitorTestRegistry = replayRegistry
+		m.junits = append(m.junits, localJunits...)
+		return nil
+	}
+	if m.startTime.IsZero() {
+		m.startTime = time.Now()
+	}
 
 	localJunits, err := m.monitorTestRegistry.StartCollection(ctx, m.adminKubeConfig, m.recorder)
 	if err != nil {
@@ -85,24 +141,27 @@ func (m *Monitor) Stop(ctx context.Context) (ResultState, error) {
 	m.stopFn()
 	m.stopFn = nil
 
-	preStopTime := time.Now()
+	if !m.replay {
+		preStopTime := time.Now()
 
-	fmt.Fprintf(os.Stderr, "Collecting data.\n")
-	collectedIntervals, collectionJunits, err := m.monitorTestRegistry.CollectData(ctx, m.storageDir, m.startTime, preStopTime)
-	if err != nil {
-		// these errors are represented as junit, always continue to the next step
-		fmt.Fprintf(os.Stderr, "Error collecting data, continuing, junit will reflect this. %v\n", err)
-	}
-	m.recorder.AddIntervals(collectedIntervals...)
-	m.junits = append(m.junits, collectionJunits...)
+		fmt.Fprintf(os.Stderr, "Collecting data.\n")
+		collectedIntervals, collectionJunits, err := m.monitorTestRegistry.CollectData(ctx, m.storageDir, m.startTime, preStopTime)
+		if err != nil {
+			// these errors are represented as junit, always continue to the next step
+			fmt.Fprintf(os.Stderr, "Error collecting data, continuing, junit will reflect this. %v\n", err)
+		}
+		m.recorder.AddIntervals(collectedIntervals...)
+		m.junits = append(m.junits, collectionJunits...)
 
-	// set the stop time for after we finished.
-	m.stopTime = time.Now()
+		// set the stop time for after we finished.
+		m.stopTime = time.Now()
+	}
 
 	fmt.Fprintf(os.Stderr, "Computing intervals.\n")
+	startingIntervals := m.recorder.Intervals(time.Time{}, time.Time{}) // compute intervals based on *all* the intervals.
 	computedIntervals, computedJunit, err := m.monitorTestRegistry.ConstructComputedIntervals(
 		ctx,
-		m.recorder.Intervals(time.Time{}, time.Time{}), // compute intervals based on *all* the intervals.
+		startingIntervals,
 		m.recorder.CurrentResourceState(),
 		m.startTime, // still allow computation to understand the beginning and end for bounding.
 		m.stopTime)  // still allow computation to understand the beginning and end for bounding.
@@ -110,11 +169,28 @@ func (m *Monitor) Stop(ctx context.Context) (ResultState, error) {
 		// these errors are represented as junit, always continue to the next step
 		fmt.Fprintf(os.Stderr, "Error computing intervals, continuing, junit will reflect this. %v\n", err)
 	}
+	if m.replay {
+		// replayed intervals already contain the computed intervals of the original run.
+		computedIntervals = withoutDuplicateIntervals(startingIntervals, computedIntervals)
+	}
 	m.recorder.AddIntervals(computedIntervals...)
 	m.junits = append(m.junits, computedJunit...)
+	if !m.replay {
+		// make the timing of every monitor test phase so far visible to the tests and in the timelines.
+		m.recorder.AddIntervals(m.monitorTestRegistry.PhaseIntervals()...)
+	}
+
+	fmt.Fprintf(os.Stderr, "Annotating intervals.\n")
+	annotate, annotationJunits, err := m.monitorTestRegistry.AnnotateIntervals(ctx, m.recorder.Intervals(time.Time{}, time.Time{}))
+	if err != nil {
+		// these errors are represented as junit, always continue to the next step
+		fmt.Fprintf(os.Stderr, "Error annotating intervals, continuing, junit will reflect this. %v\n", err)
+	}
+	m.annotate = annotate
+	m.junits = append(m.junits, annotationJunits...)
 
 	fmt.Fprintf(os.Stderr, "Evaluating tests.\n")
-	finalEvents := m.recorder.Intervals(m.startTime, m.stopTime)
+	finalEvents := m.annotatedIntervals(m.startTime, m.stopTime)
 	filename := fmt.Sprintf("events_used_for_junits_%s.json", m.startTime.UTC().Format("20060102-150405"))
 	if err := monitorserialization.EventsToFile(filepath.Join(m.storageDir, filename), finalEvents); err != nil {
 		fmt.Fprintf(os.Stderr, "error: Failed to junit event info: %v\n", err)
@@ -166,7 +242,7 @@ func (m *Monitor) SerializeResults(ctx context.Context, junitSuiteName, timeSuff
 	// tests that check intervals for the e2e phase will not see intervals during upgrade
 	// phase and vice versa).  If it turns out visibility throughout the entire run yields
 	// useful testing, we can comeback and tweak this accordingly.
-	finalIntervals := m.recorder.Intervals(m.startTime, m.stopTime)
+	finalIntervals := m.annotatedIntervals(m.startTime, m.stopTime)
 
 	finalResources := m.recorder.CurrentResourceState()
 	// TODO stop taking timesuffix as an arg and make this authoritative.
@@ -195,6 +271,12 @@ func (m *Monitor) SerializeResults(ctx context.Context, junitSuiteName, timeSuff
 	}
 	m.junits = append(m.junits, monitorTestJunits...)
 
+	// later phases end after the stop time, so all of them are written separately to show where time was spent.
+	phaseFilename := filepath.Join(m.storageDir, fmt.Sprintf("monitor-test-phases_%s.json", timeSuffix))
+	if err := monitorserialization.EventsToFile(phaseFilename, m.monitorTestRegistry.PhaseIntervals()); err != nil {
+		fmt.Fprintf(os.Stderr, "error: Failed to write monitor test phases: %v\n", err)
+	}
+
 	fmt.Fprintf(os.Stderr, "Writing junits.\n")
 	var junitSuite *junitapi.JUnitTestSuite
 	if junitSuite, err = m.serializeJunit(ctx, m.storageDir, junitSuiteName, timeSuffix); err != nil {
@@ -209,6 +291,20 @@ func (m *Monitor) SerializeResults(ctx context.Context, junitSuiteName, timeSuff
 	return nil
 }
 
+// annotatedIntervals returns the recorded intervals between from and to, with the annotations of the monitor tests.
+func (m *Monitor) annotatedIntervals(from, to time.Time) monitorapi.Intervals {
+	intervals := m.recorder.Intervals(from, to)
+	if m.annotate == nil {
+		return intervals
+	}
+	// the recorder may hand out the intervals it holds, so annotate a copy.
+	ret := make(monitorapi.Intervals, 0, len(intervals))
+	for _, interval := range intervals {
+		ret = append(ret, m.annotate(interval))
+	}
+	return ret
+}
+
 func (m *Monitor) serializeJunit(ctx context.Context, storageDir, junitSuiteName, fileSuffix string) (*junitapi.JUnitTestSuite, error) {
 	junitSuite := junitapi.JUnitTestSuite{
 		Name:       junitSuiteName,
@@ -241,3 +337,23 @@ func (m *Monitor) serializeJunit(ctx context.Context, storageDir, junitSuiteName
 	fmt.Fprintf(os.Stderr, "Writing JUnit report to %s\n", path)
 	return &junitSuite, os.WriteFile(path, test.StripANSI(out), 0640)
 }
+
+// withoutDuplicateIntervals returns the candidates that are not already present in existing.
+func withoutDuplicateIntervals(existing, candidates monitorapi.Intervals) monitorapi.Intervals {
+	seen := sets.NewString()
+	for _, curr := range existing {
+		if key, err := monitorserialization.IntervalToOneLineJSON(curr); err == nil {
+			seen.Insert(string(key))
+		}
+	}
+
+	ret := monitorapi.Intervals{}
+	for _, curr := range candidates {
+		key, err := monitorserialization.IntervalToOneLineJSON(curr)
+		if err == nil && seen.Has(string(key)) {
+			continue
+		}
+		ret = append(ret, curr)
+	}
+	return ret
+}
@@ -397,12 +397,29 @@ func (b *LocatorBuilder) E2ETest(testName string) Locator {
 	return b.Build()
 }
 
+func (b *LocatorBuilder) MonitorTest(name string) Locator {
+	b.targetType = LocatorTypeMonitorTest
+	b.annotations[LocatorMonitorTestKey] = name
+	return b.Build()
+}
+
 func (b *LocatorBuilder) ClusterOperator(name string) Locator {
 	b.targetType = LocatorTypeClusterOperator
 	b.annotations[LocatorClusterOperatorKey] = name
 	return b.Build()
 }
 
+// Resource locates any resource by its group qualified resource name, like clusteroperators.config.openshift.io.
+func (b *LocatorBuilder) Resource(resource, namespace, name string) Locator {
+	b.targetType = LocatorTypeKind
+	b.annotations[LocatorResourceKey] = resource
+	b.annotations[LocatorNameKey] = name
+	if len(namespace) > 0 {
+		b.annotations[LocatorNamespaceKey] = namespace
+	}
+	return b.Build()
+}
+
 func (b *LocatorBuilder) Build() Locator {
 	ret := Locator{
 		Type: b.targetType,
@@ -1,6 +1,8 @@
 package monitorapi
 
 import (
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -17,9 +19,130 @@ func BackendDisruptionSeconds(backendDisruptionName string, events Intervals) (t
 	)
 	disruptionMessages := disruptionEvents.Strings()
 
-	return disruptionEvents.Duration(1 * time.Second).Round(time.Second), disruptionMessages
+	return DisruptionDuration(disruptionEvents).Round(time.Second), disruptionMessages
+}
+
+// DisruptionDuration returns the total duration of the disruption intervals, each interval lasts at least a second,
+// as it takes a failed sample of a sampler running every second, unless it was recorded by an adaptive sampler.  The
+// intervals of an adaptive sampler are counted as they are, padding them would overcount the short disruptions they
+// measure.
+func DisruptionDuration(disruptionIntervals Intervals) time.Duration {
+	var total time.Duration
+	for _, interval := range disruptionIntervals {
+		if interval.StructuredMessage.Annotations[AnnotationAdaptiveSampling] == "true" {
+			total += Intervals{interval}.Duration(0)
+			continue
+		}
+		total += Intervals{interval}.Duration(1 * time.Second)
+	}
+	return total
+}
+
+// BackendDisruptionMilliseconds returns the duration of disruption observed in milliseconds, it is neither rounded nor
+// padded to a one second minimum, it keeps the sub-second resolution of the samplers that step up their rate around
+// suspected outages.
+func BackendDisruptionMilliseconds(backendDisruptionName string, events Intervals) int64 {
+	disruptionEvents := events.Filter(
+		And(
+			IsErrorEvent,
+			IsEventForBackendDisruptionName(backendDisruptionName),
+		),
+	)
+	return disruptionEvents.Duration(0).Milliseconds()
 }
 
 func IsDisruptionEvent(eventInterval Interval) bool {
 	return eventInterval.Source == SourceDisruption
 }
+
+func IsDisruptionLatencyEvent(eventInterval Interval) bool {
+	return eventInterval.Source == SourceDisruptionLatency
+}
+
+// ProbableCauses returns the probable causes a disruption interval is annotated with, most likely first.
+func ProbableCauses(eventInterval Interval) []string {
+	causes := eventInterval.StructuredMessage.Annotations[AnnotationProbableCause]
+	if len(causes) == 0 {
+		return nil
+	}
+	return strings.Split(causes, ",")
+}
+
+// DisruptionCause is the disruption attributed to a probable cause.
+type DisruptionCause struct {
+	// Cause is the most likely cause of the disruption, it is empty for the disruption no probable cause was found for.
+	Cause     string
+	Duration  time.Duration
+	Intervals int
+}
+
+// DisruptionByProbableCause attributes the duration of every disruption interval to its most likely cause.
+// The causes are sorted by decreasing disruption, and their durations are rounded to the nearest second.
+func DisruptionByProbableCause(disruptionIntervals Intervals) []DisruptionCause {
+	byCause := map[string]*DisruptionCause{}
+	for _, interval := range disruptionIntervals {
+		cause := ""
+		if causes := ProbableCauses(interval); len(causes) > 0 {
+			cause = causes[0]
+		}
+		if _, ok := byCause[cause]; !ok {
+			byCause[cause] = &DisruptionCause{Cause: cause}
+		}
+		byCause[cause].Duration += DisruptionDuration(Intervals{interval})
+		byCause[cause].Intervals++
+	}
+
+	ret := []DisruptionCause{}
+	for _, cause := range byCause {
+		cause.Duration = cause.Duration.Round(time.Second)
+		ret = append(ret, *cause)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Duration != ret[j].Duration {
+			return ret[i].Duration > ret[j].Duration
+		}
+		return ret[i].Cause < ret[j].Cause
+	})
+	return ret
+}
+
+// ServerInstanceDisruption is the disruption of the server instance that served the failed requests.
+type ServerInstanceDisruption struct {
+	// ServerInstance is empty for the disruption the server instance of which is not known, the requests
+	// may have never made it to a server.
+	ServerInstance string
+	// Node is the node the server instance runs on, if known.
+	Node      string
+	Duration  time.Duration
+	Intervals int
+}
+
+// DisruptionByServerInstance attributes the duration of every disruption interval to the server instance in its
+// locator, the instance that served the first failed request of the interval.  The instances are sorted by decreasing disruption, and their durations are rounded to the nearest second.
+func DisruptionByServerInstance(disruptionIntervals Intervals) []ServerInstanceDisruption {
+	byInstance := map[string]*ServerInstanceDisruption{}
+	for _, interval := range disruptionIntervals {
+		instance := interval.StructuredLocator.Keys[LocatorServerInstanceKey]
+		if _, ok := byInstance[instance]; !ok {
+			byInstance[instance] = &ServerInstanceDisruption{ServerInstance: instance}
+		}
+		if node := interval.StructuredLocator.Keys[LocatorNodeKey]; len(node) > 0 {
+			byInstance[instance].Node = node
+		}
+		byInstance[instance].Duration += DisruptionDuration(Intervals{interval})
+		byInstance[instance].Intervals++
+	}
+
+	ret := []ServerInstanceDisruption{}
+	for _, instance := range byInstance {
+		instance.Duration = instance.Duration.Round(time.Second)
+		ret = append(ret, *instance)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Duration != ret[j].Duration {
+			return ret[i].Duration > ret[j].Duration
+		}
+		return ret[i].ServerInstance < ret[j].ServerInstance
+	})
+	return ret
+}
@@ -111,6 +111,7 @@ const (
 	LocatorTypeClusterVersion    LocatorType = "ClusterVersion"
 	LocatorTypeKind              LocatorType = "Kind"
 	LocatorTypeCloudMetrics      LocatorType = "CloudMetrics"
+	LocatorTypeMonitorTest       LocatorType = "MonitorTest"
 )
 
 type LocatorKey string
@@ -141,10 +142,13 @@ const (
 	LocatorConnectionKey            LocatorKey = "connection"
 	LocatorProtocolKey              LocatorKey = "protocol"
 	LocatorTargetKey                LocatorKey = "target"
+	LocatorServerInstanceKey        LocatorKey = "server-instance"
 	LocatorRowKey                   LocatorKey = "row"
 	LocatorShutdownKey              LocatorKey = "shutdown"
 	LocatorServerKey                LocatorKey = "server"
 	LocatorMetricKey                LocatorKey = "metric"
+	LocatorMonitorTestKey           LocatorKey = "monitor-test"
+	LocatorResourceKey              LocatorKey = "resource"
 )
 
 type Locator struct {
@@ -162,10 +166,18 @@ const (
 	DisruptionBeganEventReason              IntervalReason = "DisruptionBegan"
 	DisruptionEndedEventReason              IntervalReason = "DisruptionEnded"
 	DisruptionSamplerOutageBeganEventReason IntervalReason = "DisruptionSamplerOutageBegan"
-	GracefulAPIServerShutdown               IntervalReason = "GracefulShutdownWindow"
+	// DisruptionLatencyDegradedEventReason marks a backend that stayed up, but whose round trip latency went over
+	// its threshold.
+	DisruptionLatencyDegradedEventReason IntervalReason = "DisruptionLatencyDegraded"
+	// DisruptionLatencySummaryEventReason carries the latency histogram of a backend for the whole run.
+	DisruptionLatencySummaryEventReason IntervalReason = "DisruptionLatencySummary"
+	GracefulAPIServerShutdown           IntervalReason = "GracefulShutdownWindow"
 
 	HttpClientConnectionLost IntervalReason = "HttpClientConnectionLost"
 
+	// DataLossReason marks an acknowledged write that a stateful workload lost.
+	DataLossReason IntervalReason = "DataLoss"
+
 	PodPendingReason               IntervalReason = "PodIsPending"
 	PodNotPendingReason            IntervalReason = "PodIsNotPending"
 	PodReasonCreated               IntervalReason = "Created"
@@ -206,6 +218,14 @@ const (
 	CloudMetricsExtrenuous                IntervalReason = "CloudMetricsExtrenuous"
 	FailedToDeleteCGroupsPath             IntervalReason = "FailedToDeleteCGroupsPath"
 	FailedToAuthenticateWithOpenShiftUser IntervalReason = "FailedToAuthenticateWithOpenShiftUser"
+
+	MonitorTestPhaseFinished IntervalReason = "MonitorTestPhaseFinished"
+	MonitorTestPhaseFailed   IntervalReason = "MonitorTestPhaseFailed"
+	MonitorTestPhaseTimedOut IntervalReason = "MonitorTestPhaseTimedOut"
+
+	ResourceAdded    IntervalReason = "ResourceAdded"
+	ResourceModified IntervalReason = "ResourceModified"
+	ResourceDeleted  IntervalReason = "ResourceDeleted"
 )
 
 type AnnotationKey string
@@ -237,6 +257,27 @@ const (
 	AnnotationRoles          AnnotationKey = "roles"
 	AnnotationStatus         AnnotationKey = "status"
 	AnnotationCondition      AnnotationKey = "condition"
+
+	AnnotationResourceVersion AnnotationKey = "resource-version"
+	AnnotationUser            AnnotationKey = "user"
+
+	AnnotationPercentile AnnotationKey = "percentile"
+	AnnotationLatency    AnnotationKey = "latency"
+	AnnotationThreshold  AnnotationKey = "threshold"
+	AnnotationHistogram  AnnotationKey = "histogram"
+
+	// AnnotationProbableCause holds the comma separated probable causes of a disruption, most likely first.
+	AnnotationProbableCause AnnotationKey = "probable-cause"
+
+	// AnnotationDiagnostics holds the encoded client side network diagnostics captured when a backend became unavailable.
+	AnnotationDiagnostics AnnotationKey = "diagnostics"
+
+	// AnnotationShard identifies the shard of a sharded run that recorded the interval, in merged results.
+	AnnotationShard AnnotationKey = "shard"
+
+	// AnnotationAdaptiveSampling marks the disruption intervals of a sampler that steps up to a sub-second rate
+	// while an outage is suspected, their duration is not padded to a second.
+	AnnotationAdaptiveSampling AnnotationKey = "adaptive-sampling"
 )
 
 // ConstructionOwner was originally meant to signify that an interval was derived from other intervals.
@@ -273,6 +314,7 @@ const (
 	SourceAlert                   IntervalSource = "Alert"
 	SourceAPIServerShutdown       IntervalSource = "APIServerShutdown"
 	SourceDisruption              IntervalSource = "Disruption"
+	SourceDisruptionLatency       IntervalSource = "DisruptionLatency" // the latency of backends that stayed up, not accounted as disruption
 	SourceE2ETest                 IntervalSource = "E2ETest"
 	SourceKubeEvent               IntervalSource = "KubeEvent"
 	SourceNetworkManagerLog       IntervalSource = "NetworkMangerLog"
@@ -291,6 +333,9 @@ const (
 	SourceNodeState                              = "NodeState"
 	SourcePodState                               = "PodState"
 	SourceCloudMetrics                           = "CloudMetrics"
+	SourceMonitorTestPhase        IntervalSource = "MonitorTestPhase"
+	SourceResourceWatch           IntervalSource = "ResourceWatch"
+	SourceStatefulWorkload        IntervalSource = "StatefulWorkload"
 )
 
 type Interval struct {
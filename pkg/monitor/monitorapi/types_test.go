@@ -109,6 +109,23 @@ func TestIntervals_Duration(t *testing.T) {
 	}
 }
 
+func TestDisruptionDuration(t *testing.T) {
+	start := time.Now()
+	disruption := func(duration time.Duration, adaptive bool) Interval {
+		message := NewMessage().Reason(DisruptionBeganEventReason).HumanMessage("stopped responding")
+		if adaptive {
+			message = message.WithAnnotation(AnnotationAdaptiveSampling, "true")
+		}
+		return NewInterval(SourceDisruption, Error).Message(message).Build(start, start.Add(duration))
+	}
+
+	intervals := Intervals{disruption(250*time.Millisecond, false), disruption(1500*time.Millisecond, false)}
+	assert.Equal(t, 2500*time.Millisecond, DisruptionDuration(intervals))
+
+	intervals = Intervals{disruption(250*time.Millisecond, true), disruption(1500*time.Millisecond, true), disruption(0, true)}
+	assert.Equal(t, 1750*time.Millisecond, DisruptionDuration(intervals))
+}
+
 //	Not sure this test needs to live forever, but while working through the move
 //
 // to structured locators, it would be best if the legacy one kept coming out with
@@ -27,6 +27,23 @@ func NewRecorder() monitorapi.Recorder {
 	}
 }
 
+// NewRecorderWithContent creates a recorder pre-populated with intervals and resources, for instance those
+// read from the artifacts of a prior run.  Resources are stored as provided, their observed counts are not updated.
+func NewRecorderWithContent(intervals monitorapi.Intervals, resources monitorapi.ResourcesMap) monitorapi.Recorder {
+	ret := &recorder{
+		recordedResources: monitorapi.ResourcesMap{},
+	}
+	ret.events = append(ret.events, intervals...)
+	for resourceType, instanceMap := range resources {
+		retInstance := monitorapi.InstanceMap{}
+		for instanceKey, obj := range instanceMap {
+			retInstance[instanceKey] = obj.DeepCopyObject()
+		}
+		ret.recordedResources[resourceType] = retInstance
+	}
+	return ret
+}
+
 var _ monitorapi.Recorder = &recorder{}
 
 func (m *recorder) CurrentResourceState() monitorapi.ResourcesMap {
@@ -17,6 +17,8 @@ type jsonlRecorder struct {
 	intervalDisplayFilter monitorapi.EventIntervalMatchesFunc
 
 	outfile io.Writer
+	// serialize writes an interval as a single line of JSON.
+	serialize func(monitorapi.Interval) ([]byte, error)
 }
 
 func WrapWithJSONLRecorder(delegate monitorapi.Recorder, outfile io.Writer, intervalDisplayFilter monitorapi.EventIntervalMatchesFunc) monitorapi.Recorder {
@@ -24,6 +26,7 @@ func WrapWithJSONLRecorder(delegate monitorapi.Recorder, outfile io.Writer, inte
 		delegate:              delegate,
 		outfile:               outfile,
 		intervalDisplayFilter: intervalDisplayFilter,
+		serialize:             monitorserialization.IntervalToOneLineJSON,
 	}
 }
 
@@ -74,9 +77,10 @@ func (m *jsonlRecorder) writeInterval(interval *monitorapi.Interval) {
 		return
 	}
 
-	intervalJSON, err := monitorserialization.IntervalToOneLineJSON(*interval)
+	intervalJSON, err := m.serialize(*interval)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error serializing: %v\n", err)
+		return
 	}
 	if _, err := m.outfile.Write([]byte(fmt.Sprintf("%v\n", string(intervalJSON)))); err != nil {
 		fmt.Fprintf(os.Stderr, "error writing: %v\n", err)
@@ -0,0 +1,460 @@
+package monitor
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// DefaultIntervalsPerSegment is the number of intervals written to a segment before a new one is started.
+	DefaultIntervalsPerSegment = 10000
+
+	segmentFilePrefix = "intervals-"
+	segmentFileSuffix = ".jsonl"
+	segmentIndexFile  = "index.json"
+	// openIntervalsFile holds the intervals that were started but not yet ended.
+	openIntervalsFile = "open-intervals.json"
+
+	// segmentCacheSize is the number of decoded segments kept in memory, so repeated calls to Intervals
+	// do not decode the same segments again.
+	segmentCacheSize = 4
+)
+
+// segmentInfo is the time index entry for a single segment file.
+type segmentInfo struct {
+	Filename string `json:"filename"`
+	Count    int    `json:"count"`
+	// Size is the number of bytes of complete lines in the segment.
+	Size int64 `json:"size"`
+	// EarliestFrom is the earliest From of any interval in the segment.
+	EarliestFrom time.Time `json:"earliestFrom"`
+	// LatestTime is the latest From or To of any interval in the segment.
+	LatestTime time.Time `json:"latestTime"`
+}
+
+// overlaps returns true if the segment may hold intervals between from and to.  Zero values are unbounded.
+func (s segmentInfo) overlaps(from, to time.Time) bool {
+	if s.Count == 0 {
+		return false
+	}
+	if !to.IsZero() && s.EarliestFrom.After(to) {
+		return false
+	}
+	if !from.IsZero() && s.LatestTime.Before(from) {
+		return false
+	}
+	return true
+}
+
+func (s *segmentInfo) add(from, to time.Time, size int64) {
+	if s.Count == 0 || from.Before(s.EarliestFrom) {
+		s.EarliestFrom = from
+	}
+	if from.After(s.LatestTime) {
+		s.LatestTime = from
+	}
+	if to.After(s.LatestTime) {
+		s.LatestTime = to
+	}
+	s.Count++
+	s.Size += size
+}
+
+// intervalTimes is the part of a serialized interval the segment index needs.
+type intervalTimes struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// openInterval is an interval that was started but not yet ended, as persisted in openIntervalsFile.
+type openInterval struct {
+	ID       int             `json:"id"`
+	Interval json.RawMessage `json:"interval"`
+}
+
+// cachedSegment is the decoded content of the first size bytes of a segment.
+type cachedSegment struct {
+	filename  string
+	size      int64
+	intervals monitorapi.Intervals
+}
+
+// segmentStore is the delegate of the JSONL recorder returned by NewSegmentedRecorder.  The JSONL recorder writes
+// every ended interval to the store, which appends it to the current segment file.  Only the intervals that have
+// been started but not yet ended and the most recently read segments are kept in memory.  Resources are still
+// tracked in memory because only the latest state of each is kept.
+type segmentStore struct {
+	dir                 string
+	intervalsPerSegment int
+
+	lock sync.Mutex
+	// segments is the time index of every segment, in the order they were written.  The last is the open one.
+	segments    []segmentInfo
+	segmentFile *os.File
+
+	nextStartedInterval int
+	startedIntervals    map[int]monitorapi.Interval
+	// openIntervalsChanged is set when an interval ended, the open intervals are persisted again once the
+	// ended interval is written to a segment, so a crash in between does not lose it.
+	openIntervalsChanged bool
+
+	// cache holds the most recently read segments, the most recent last.
+	cache []*cachedSegment
+
+	resources *recorder
+}
+
+// NewSegmentedRecorder creates a recorder that streams intervals to segment files in dir as JSONL.  If dir already
+// holds segments, for instance from a run that crashed, they are indexed and their intervals, including those that
+// were started but not ended, are returned by Intervals.
+// intervalsPerSegment of zero or less uses DefaultIntervalsPerSegment.
+func NewSegmentedRecorder(dir string, intervalsPerSegment int) (monitorapi.Recorder, error) {
+	if intervalsPerSegment <= 0 {
+		intervalsPerSegment = DefaultIntervalsPerSegment
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("unable to create interval segment directory: %w", err)
+	}
+
+	segments, err := indexSegments(dir)
+	if err != nil {
+		return nil, err
+	}
+	startedIntervals, err := readOpenIntervals(dir)
+	if err != nil {
+		return nil, err
+	}
+	nextStartedInterval := 0
+	for id := range startedIntervals {
+		if id >= nextStartedInterval {
+			nextStartedInterval = id + 1
+		}
+	}
+
+	store := &segmentStore{
+		dir:                 dir,
+		intervalsPerSegment: intervalsPerSegment,
+		segments:            segments,
+		nextStartedInterval: nextStartedInterval,
+		startedIntervals:    startedIntervals,
+		resources:           NewRecorder().(*recorder),
+	}
+	return &jsonlRecorder{
+		delegate:  store,
+		outfile:   store,
+		serialize: monitorserialization.IntervalToPreciseOneLineJSON,
+	}, nil
+}
+
+var _ monitorapi.Recorder = &segmentStore{}
+var _ io.Writer = &segmentStore{}
+
+func (m *segmentStore) CurrentResourceState() monitorapi.ResourcesMap {
+	return m.resources.CurrentResourceState()
+}
+
+func (m *segmentStore) RecordResource(resourceType string, obj runtime.Object) {
+	m.resources.RecordResource(resourceType, obj)
+}
+
+// Record is never called by the JSONL recorder, which turns conditions into intervals it writes itself.
+func (m *segmentStore) Record(conditions ...monitorapi.Condition) {
+}
+
+// RecordAt is never called by the JSONL recorder, which turns conditions into intervals it writes itself.
+func (m *segmentStore) RecordAt(t time.Time, conditions ...monitorapi.Condition) {
+}
+
+// AddIntervals does nothing, the JSONL recorder already wrote the intervals to the store.
+func (m *segmentStore) AddIntervals(eventIntervals ...monitorapi.Interval) {
+}
+
+// StartInterval holds the interval in memory until it is ended, and persists it so it survives a crash.
+func (m *segmentStore) StartInterval(interval monitorapi.Interval) int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	startedInterval := m.nextStartedInterval
+	m.nextStartedInterval++
+	m.startedIntervals[startedInterval] = interval
+	if err := m.writeOpenIntervalsLocked(); err != nil {
+		fmt.Fprintf(os.Stderr, "error persisting open intervals: %v\n", err)
+	}
+	return startedInterval
+}
+
+// EndInterval updates the To of the interval started by StartInterval if it is greater than the from.  The JSONL
+// recorder then writes the ended interval to the current segment.
+func (m *segmentStore) EndInterval(startedInterval int, t time.Time) *monitorapi.Interval {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	interval, ok := m.startedIntervals[startedInterval]
+	if !ok {
+		return nil
+	}
+	delete(m.startedIntervals, startedInterval)
+	m.openIntervalsChanged = true
+	if interval.From.Before(t) {
+		interval.To = t
+	}
+	return &interval
+}
+
+// Write appends one serialized interval to the current segment, the JSONL recorder writes every interval with a
+// single call, so a crash leaves at most one partial line behind.
+func (m *segmentStore) Write(line []byte) (int, error) {
+	times := intervalTimes{}
+	if err := json.Unmarshal(line, &times); err != nil {
+		return 0, fmt.Errorf("unable to index interval: %w", err)
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.segmentFile == nil || m.segments[len(m.segments)-1].Count >= m.intervalsPerSegment {
+		if err := m.rotateLocked(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := m.segmentFile.Write(line)
+	if err != nil {
+		return n, err
+	}
+	m.segments[len(m.segments)-1].add(times.From, times.To, int64(n))
+
+	if m.openIntervalsChanged {
+		if err := m.writeOpenIntervalsLocked(); err != nil {
+			fmt.Fprintf(os.Stderr, "error persisting open intervals: %v\n", err)
+		}
+	}
+	return n, nil
+}
+
+// Intervals returns all events that occur between from and to, including
+// any sampled conditions that were encountered during that period.
+// Intervals are returned in order of their occurrence. Only the segments whose time range
+// overlaps from and to are read, and only the part of them that is not cached yet.
+func (m *segmentStore) Intervals(from, to time.Time) monitorapi.Intervals {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	events := monitorapi.Intervals{}
+	for _, interval := range m.startedIntervals {
+		events = append(events, interval)
+	}
+	for _, segment := range m.segments {
+		if !segment.overlaps(from, to) {
+			continue
+		}
+		intervals, err := m.readSegmentLocked(segment)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error reading interval segment %s: %v\n", segment.Filename, err)
+		}
+		events = append(events, intervals...)
+	}
+
+	sort.Sort(events)
+	return events.Slice(from, to)
+}
+
+// readSegmentLocked returns the intervals of the segment, decoding only what was written since it was cached.
+func (m *segmentStore) readSegmentLocked(segment segmentInfo) (monitorapi.Intervals, error) {
+	cached := &cachedSegment{filename: segment.Filename}
+	for i, curr := range m.cache {
+		if curr.filename == segment.Filename {
+			cached = curr
+			m.cache = append(m.cache[:i], m.cache[i+1:]...)
+			break
+		}
+	}
+	m.cache = append(m.cache, cached)
+	if len(m.cache) > segmentCacheSize {
+		m.cache = m.cache[1:]
+	}
+
+	if cached.size < segment.Size {
+		intervals, size, err := readSegment(filepath.Join(m.dir, segment.Filename), cached.size, segment.Size)
+		cached.intervals = append(cached.intervals, intervals...)
+		cached.size += size
+		if err != nil {
+			return cached.intervals, err
+		}
+	}
+	return cached.intervals, nil
+}
+
+// rotateLocked closes the current segment, persists the index, and opens a new segment.
+func (m *segmentStore) rotateLocked() error {
+	if m.segmentFile != nil {
+		if err := m.segmentFile.Close(); err != nil {
+			return err
+		}
+		m.segmentFile = nil
+	}
+	if err := writeSegmentIndex(m.dir, m.segments); err != nil {
+		return err
+	}
+
+	filename := fmt.Sprintf("%s%06d%s", segmentFilePrefix, len(m.segments), segmentFileSuffix)
+	segmentFile, err := os.OpenFile(filepath.Join(m.dir, filename), os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	m.segmentFile = segmentFile
+	m.segments = append(m.segments, segmentInfo{Filename: filename})
+	return nil
+}
+
+func (m *segmentStore) writeOpenIntervalsLocked() error {
+	ids := []int{}
+	for id := range m.startedIntervals {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	openIntervals := []openInterval{}
+	for _, id := range ids {
+		intervalJSON, err := monitorserialization.IntervalToPreciseOneLineJSON(m.startedIntervals[id])
+		if err != nil {
+			return err
+		}
+		openIntervals = append(openIntervals, openInterval{ID: id, Interval: intervalJSON})
+	}
+	openIntervalsJSON, err := json.Marshal(openIntervals)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomically(filepath.Join(m.dir, openIntervalsFile), openIntervalsJSON); err != nil {
+		return err
+	}
+	m.openIntervalsChanged = false
+	return nil
+}
+
+func readOpenIntervals(dir string) (map[int]monitorapi.Interval, error) {
+	startedIntervals := map[int]monitorapi.Interval{}
+	openIntervalsJSON, err := os.ReadFile(filepath.Join(dir, openIntervalsFile))
+	if os.IsNotExist(err) {
+		return startedIntervals, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	openIntervals := []openInterval{}
+	if err := json.Unmarshal(openIntervalsJSON, &openIntervals); err != nil {
+		return nil, fmt.Errorf("unable to read open intervals: %w", err)
+	}
+	for _, curr := range openIntervals {
+		interval, err := monitorserialization.IntervalFromJSON(curr.Interval)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read open interval: %w", err)
+		}
+		startedIntervals[curr.ID] = *interval
+	}
+	return startedIntervals, nil
+}
+
+func writeSegmentIndex(dir string, segments []segmentInfo) error {
+	indexJSON, err := json.MarshalIndent(segments, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomically(filepath.Join(dir, segmentIndexFile), indexJSON)
+}
+
+func writeFileAtomically(filename string, content []byte) error {
+	tmpFile := filename + ".tmp"
+	if err := os.WriteFile(tmpFile, content, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, filename)
+}
+
+// indexSegments builds the time index for existing segments in dir.  The persisted index is used for every
+// segment it covers, the remaining segments (at least the one open during a crash) are scanned.
+func indexSegments(dir string) ([]segmentInfo, error) {
+	filenames, err := filepath.Glob(filepath.Join(dir, segmentFilePrefix+"*"+segmentFileSuffix))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(filenames)
+
+	persisted := map[string]segmentInfo{}
+	if indexJSON, err := os.ReadFile(filepath.Join(dir, segmentIndexFile)); err == nil {
+		persistedSegments := []segmentInfo{}
+		if err := json.Unmarshal(indexJSON, &persistedSegments); err != nil {
+			return nil, fmt.Errorf("unable to read interval segment index: %w", err)
+		}
+		for _, segment := range persistedSegments {
+			persisted[segment.Filename] = segment
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	segments := []segmentInfo{}
+	for _, filename := range filenames {
+		base := filepath.Base(filename)
+		// the size of a segment is not known if the index was written by an older version
+		if segment, ok := persisted[base]; ok && (segment.Size > 0 || segment.Count == 0) {
+			segments = append(segments, segment)
+			continue
+		}
+		intervals, size, err := readSegment(filename, 0, -1)
+		if err != nil {
+			return nil, err
+		}
+		segment := segmentInfo{Filename: base}
+		for _, interval := range intervals {
+			segment.add(interval.From, interval.To, 0)
+		}
+		segment.Size = size
+		segments = append(segments, segment)
+	}
+	return segments, nil
+}
+
+// readSegment reads the intervals of a segment file from offset up to end, a negative end reads everything, and
+// returns the number of bytes of complete lines it read.  A truncated final line, as left behind by a crash, is
+// skipped.
+func readSegment(filename string, offset, end int64) (monitorapi.Intervals, int64, error) {
+	segmentFile, err := os.Open(filename)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer segmentFile.Close()
+	if _, err := segmentFile.Seek(offset, io.SeekStart); err != nil {
+		return nil, 0, err
+	}
+
+	var size int64
+	intervals := monitorapi.Intervals{}
+	reader := bufio.NewReader(segmentFile)
+	for end < 0 || offset+size < end {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			// no trailing newline means the write was interrupted
+			break
+		}
+		size += int64(len(line))
+		line = strings.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		interval, err := monitorserialization.IntervalFromJSON([]byte(line))
+		if err != nil {
+			return intervals, size, fmt.Errorf("unable to decode interval in %s: %w", filename, err)
+		}
+		intervals = append(intervals, *interval)
+	}
+	return intervals, size, nil
+}
@@ -0,0 +1,127 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCondition(message string) monitorapi.Condition {
+	return monitorapi.NewInterval(monitorapi.SourceTestData, monitorapi.Info).
+		Locator(monitorapi.NewLocator().NodeFromName("foo")).
+		Message(monitorapi.NewMessage().HumanMessage(message)).
+		BuildCondition()
+}
+
+func TestSegmentedRecorder_MatchesInMemoryRecorder(t *testing.T) {
+	dir := t.TempDir()
+	segmented, err := NewSegmentedRecorder(dir, 3)
+	require.NoError(t, err)
+	inMemory := NewRecorder()
+
+	base := time.Date(2023, 2, 14, 20, 33, 40, 123456789, time.UTC)
+	for _, r := range []monitorapi.Recorder{segmented, inMemory} {
+		for i := 9; i >= 0; i-- {
+			r.RecordAt(base.Add(time.Duration(i)*time.Minute), testCondition("instant"))
+		}
+		started := r.StartInterval(monitorapi.Interval{Condition: testCondition("long"), From: base.Add(30 * time.Second)})
+		r.EndInterval(started, base.Add(5*time.Minute))
+		r.StartInterval(monitorapi.Interval{Condition: testCondition("open"), From: base.Add(7 * time.Minute)})
+	}
+
+	segmentFiles, err := filepath.Glob(filepath.Join(dir, "intervals-*.jsonl"))
+	require.NoError(t, err)
+	assert.Len(t, segmentFiles, 4)
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+	}{
+		{name: "everything"},
+		{name: "bounded", from: base.Add(2 * time.Minute), to: base.Add(6 * time.Minute)},
+		{name: "after the end", from: base.Add(time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := inMemory.Intervals(tt.from, tt.to)
+			actual := segmented.Intervals(tt.from, tt.to)
+			require.Equal(t, len(expected), len(actual))
+			for i := range expected {
+				assert.Equal(t, expected[i].Message, actual[i].Message)
+				assert.True(t, expected[i].From.Equal(actual[i].From), "from %v != %v", expected[i].From, actual[i].From)
+				assert.True(t, expected[i].To.Equal(actual[i].To), "to %v != %v", expected[i].To, actual[i].To)
+			}
+		})
+	}
+}
+
+func TestSegmentedRecorder_RecoversAfterCrash(t *testing.T) {
+	dir := t.TempDir()
+	segmented, err := NewSegmentedRecorder(dir, 2)
+	require.NoError(t, err)
+
+	base := time.Date(2023, 2, 14, 20, 33, 40, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		segmented.RecordAt(base.Add(time.Duration(i)*time.Second), testCondition("instant"))
+	}
+
+	// simulate a crash part way through writing an interval
+	lastSegment, err := os.OpenFile(filepath.Join(dir, "intervals-000002.jsonl"), os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = lastSegment.WriteString(`{"level":"Info","locator":`)
+	require.NoError(t, err)
+	require.NoError(t, lastSegment.Close())
+
+	recovered, err := NewSegmentedRecorder(dir, 2)
+	require.NoError(t, err)
+	assert.Len(t, recovered.Intervals(time.Time{}, time.Time{}), 5)
+
+	recovered.RecordAt(base.Add(time.Minute), testCondition("after recovery"))
+	intervals := recovered.Intervals(base.Add(30*time.Second), time.Time{})
+	require.Len(t, intervals, 1)
+	assert.Equal(t, "after recovery", intervals[0].StructuredMessage.HumanMessage)
+}
+
+func TestSegmentedRecorder_PersistsOpenIntervals(t *testing.T) {
+	dir := t.TempDir()
+	segmented, err := NewSegmentedRecorder(dir, 2)
+	require.NoError(t, err)
+
+	base := time.Date(2023, 2, 14, 20, 33, 40, 123456789, time.UTC)
+	open := segmented.StartInterval(monitorapi.Interval{Condition: testCondition("open"), From: base})
+	ended := segmented.StartInterval(monitorapi.Interval{Condition: testCondition("ended"), From: base})
+	segmented.EndInterval(ended, base.Add(time.Minute))
+
+	// the process crashed without ending the open interval
+	recovered, err := NewSegmentedRecorder(dir, 2)
+	require.NoError(t, err)
+	intervals := recovered.Intervals(time.Time{}, time.Time{})
+	require.Len(t, intervals, 2)
+	messages := []string{intervals[0].StructuredMessage.HumanMessage, intervals[1].StructuredMessage.HumanMessage}
+	assert.ElementsMatch(t, []string{"open", "ended"}, messages)
+
+	// intervals started after recovery do not reuse the identifier of the open one
+	started := recovered.StartInterval(monitorapi.Interval{Condition: testCondition("after recovery"), From: base.Add(2 * time.Minute)})
+	assert.NotEqual(t, open, started)
+	recovered.EndInterval(started, base.Add(3*time.Minute))
+	assert.Len(t, recovered.Intervals(time.Time{}, time.Time{}), 3)
+}
+
+func TestSegmentedRecorder_ReadsAppendedIntervalsOfCachedSegments(t *testing.T) {
+	segmented, err := NewSegmentedRecorder(t.TempDir(), 3)
+	require.NoError(t, err)
+
+	base := time.Date(2023, 2, 14, 20, 33, 40, 0, time.UTC)
+	for i := 0; i < 20; i++ {
+		segmented.RecordAt(base.Add(time.Duration(i)*time.Second), testCondition("instant"))
+		// every segment is read, and cached, while it is still being written
+		intervals := segmented.Intervals(time.Time{}, time.Time{})
+		require.Len(t, intervals, i+1)
+		assert.True(t, intervals[i].From.Equal(base.Add(time.Duration(i)*time.Second)))
+	}
+}
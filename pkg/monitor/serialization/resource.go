@@ -4,13 +4,18 @@ import (
 	"archive/zip"
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"strings"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/kube-openapi/pkg/util/sets"
 )
 
@@ -73,3 +78,95 @@ func InstanceMapToFile(filename string, resourceType string, instances monitorap
 
 	return ioutil.WriteFile(filename, byteBuffer.Bytes(), 0644)
 }
+
+// knownResourceKinds maps the resourceType names used by RecordResource to their kinds so that
+// InstanceMapFromFile can restore the same typed objects consumers of the live ResourcesMap expect.
+var knownResourceKinds = map[string]schema.GroupVersionKind{
+	"pods":   corev1.SchemeGroupVersion.WithKind("Pod"),
+	"events": corev1.SchemeGroupVersion.WithKind("Event"),
+}
+
+// ResourceTypeFromFilename returns the resourceType encoded in a resource-<resourceType><timeSuffix>.zip filename
+// as written by the tracked resources serializer.
+func ResourceTypeFromFilename(filename string) (string, bool) {
+	base := filepath.Base(filename)
+	if !strings.HasPrefix(base, "resource-") || !strings.HasSuffix(base, ".zip") {
+		return "", false
+	}
+	resourceType := strings.TrimSuffix(strings.TrimPrefix(base, "resource-"), ".zip")
+	// strip the optional _<timestamp> suffix
+	if idx := strings.Index(resourceType, "_"); idx >= 0 {
+		resourceType = resourceType[:idx]
+	}
+	if len(resourceType) == 0 {
+		return "", false
+	}
+	return resourceType, true
+}
+
+// InstanceMapFromFile reads a file written by InstanceMapToFile.  Well known resource types are decoded into
+// their typed objects, all others are returned as unstructured.
+func InstanceMapFromFile(filename string, resourceType string) (monitorapi.InstanceMap, error) {
+	zipReader, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer zipReader.Close()
+
+	ret := monitorapi.InstanceMap{}
+	for _, file := range zipReader.File {
+		if filepath.Base(file.Name) != resourceType+".json" {
+			continue
+		}
+		nsReader, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := ioutil.ReadAll(nsReader)
+		nsReader.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		// the list itself is written without a kind, so it cannot be decoded as an UnstructuredList
+		nsList := struct {
+			Items []map[string]interface{} `json:"items"`
+		}{}
+		if err := json.Unmarshal(data, &nsList); err != nil {
+			return nil, fmt.Errorf("unable to decode %s in %s: %w", file.Name, filename, err)
+		}
+		for i := range nsList.Items {
+			obj, err := toTypedObject(resourceType, &unstructured.Unstructured{Object: nsList.Items[i]})
+			if err != nil {
+				return nil, fmt.Errorf("unable to decode %s in %s: %w", file.Name, filename, err)
+			}
+			metadata, err := meta.Accessor(obj)
+			if err != nil {
+				return nil, err
+			}
+			key := monitorapi.InstanceKey{
+				Namespace: metadata.GetNamespace(),
+				Name:      metadata.GetName(),
+				UID:       fmt.Sprintf("%v", metadata.GetUID()),
+			}
+			ret[key] = obj
+		}
+	}
+
+	return ret, nil
+}
+
+func toTypedObject(resourceType string, in *unstructured.Unstructured) (runtime.Object, error) {
+	gvk, ok := knownResourceKinds[resourceType]
+	if !ok {
+		return in, nil
+	}
+	obj, err := scheme.Scheme.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(in.Object, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
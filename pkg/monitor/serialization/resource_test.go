@@ -0,0 +1,85 @@
+package monitorserialization
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestInstanceMapRoundTrip(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "openshift-etcd",
+			Name:      "etcd-0",
+			UID:       "pod-uid",
+			Annotations: map[string]string{
+				monitorapi.ObservedUpdateCountAnnotation: "3",
+			},
+		},
+		Spec: corev1.PodSpec{NodeName: "master-0"},
+	}
+	podKey := monitorapi.InstanceKey{Namespace: "openshift-etcd", Name: "etcd-0", UID: "pod-uid"}
+
+	filename := filepath.Join(t.TempDir(), "resource-pods_20230214-203340.zip")
+	require.NoError(t, InstanceMapToFile(filename, "pods", monitorapi.InstanceMap{podKey: pod}))
+
+	resourceType, ok := ResourceTypeFromFilename(filename)
+	require.True(t, ok)
+	assert.Equal(t, "pods", resourceType)
+
+	actual, err := InstanceMapFromFile(filename, resourceType)
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+
+	actualPod, ok := actual[podKey].(*corev1.Pod)
+	require.True(t, ok, "expected a typed pod, got %T", actual[podKey])
+	assert.Equal(t, "master-0", actualPod.Spec.NodeName)
+	assert.Equal(t, "3", actualPod.Annotations[monitorapi.ObservedUpdateCountAnnotation])
+}
+
+func TestInstanceMapFromFileUnknownType(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "config.openshift.io/v1",
+		"kind":       "ClusterOperator",
+		"metadata": map[string]interface{}{
+			"name": "etcd",
+			"uid":  "co-uid",
+		},
+	}}
+	key := monitorapi.InstanceKey{Name: "etcd", UID: "co-uid"}
+
+	filename := filepath.Join(t.TempDir(), "resource-clusteroperators.zip")
+	require.NoError(t, InstanceMapToFile(filename, "clusteroperators", monitorapi.InstanceMap{key: obj}))
+
+	actual, err := InstanceMapFromFile(filename, "clusteroperators")
+	require.NoError(t, err)
+	actualObj, ok := actual[key].(*unstructured.Unstructured)
+	require.True(t, ok, "expected unstructured, got %T", actual[key])
+	assert.Equal(t, "ClusterOperator", actualObj.GetKind())
+}
+
+func TestResourceTypeFromFilename(t *testing.T) {
+	tests := []struct {
+		filename string
+		want     string
+		wantOK   bool
+	}{
+		{filename: "resource-pods_20230214-203340.zip", want: "pods", wantOK: true},
+		{filename: "/artifacts/resource-events.zip", want: "events", wantOK: true},
+		{filename: "e2e-events_20230214-203340.json", wantOK: false},
+		{filename: "resource-.zip", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			got, ok := ResourceTypeFromFilename(tt.filename)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
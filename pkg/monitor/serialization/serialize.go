@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"sort"
+	"time"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 
@@ -124,6 +125,25 @@ func IntervalToOneLineJSON(interval monitorapi.Interval) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// preciseEventInterval shadows From and To so they are serialized with nanosecond precision.
+type preciseEventInterval struct {
+	EventInterval
+
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// IntervalToPreciseOneLineJSON is like IntervalToOneLineJSON, but keeps the sub-second precision of From and To.
+// The result is read back by IntervalFromJSON.
+func IntervalToPreciseOneLineJSON(interval monitorapi.Interval) ([]byte, error) {
+	outputEvent := preciseEventInterval{
+		EventInterval: monitorEventIntervalToEventInterval(interval),
+		From:          interval.From.UTC(),
+		To:            interval.To.UTC(),
+	}
+	return json.Marshal(outputEvent)
+}
+
 func IntervalsToJSON(intervals monitorapi.Intervals) ([]byte, error) {
 	outputEvents := []EventInterval{}
 	for _, curr := range intervals {
@@ -0,0 +1,50 @@
+package monitortestframework
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+type fakeIntervalAnnotator struct {
+	fakeMonitorTest
+	value string
+	err   error
+}
+
+func (t *fakeIntervalAnnotator) AnnotateIntervals(ctx context.Context, intervals monitorapi.Intervals) (IntervalAnnotationFunc, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+	return func(interval monitorapi.Interval) monitorapi.Interval {
+		interval.Message = fmt.Sprintf("%s %s", interval.Message, t.value)
+		return interval
+	}, nil
+}
+
+func TestAnnotateIntervals(t *testing.T) {
+	registry := NewMonitorTestRegistry()
+	registry.AddMonitorTestOrDie("b-annotator", "Test Framework", &fakeIntervalAnnotator{value: "b"})
+	registry.AddMonitorTestOrDie("a-annotator", "Test Framework", &fakeIntervalAnnotator{value: "a"})
+	registry.AddMonitorTestOrDie("broken-annotator", "Test Framework", &fakeIntervalAnnotator{err: fmt.Errorf("broken")})
+	registry.AddMonitorTestOrDie("not-an-annotator", "Test Framework", &fakeMonitorTest{})
+
+	annotate, junits, err := registry.AnnotateIntervals(context.Background(), monitorapi.Intervals{})
+	require.Error(t, err)
+	require.Len(t, junits, 3, "only annotators report a junit")
+	for _, junit := range junits {
+		if junit.Name == `[Jira:"Test Framework"] monitor test broken-annotator interval annotation` {
+			assert.NotNil(t, junit.FailureOutput)
+			continue
+		}
+		assert.Nil(t, junit.FailureOutput)
+	}
+
+	annotated := annotate(monitorapi.Interval{Condition: monitorapi.Condition{Message: "disrupted"}})
+	assert.Equal(t, "disrupted a b", annotated.Message, "annotations are added in the order of the monitor test names")
+}
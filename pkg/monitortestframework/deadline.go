@@ -0,0 +1,195 @@
+package monitortestframework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// phaseProgressInterval is how often a monitor test that is still running a phase is logged.
+var phaseProgressInterval = time.Minute
+
+type phaseResult struct {
+	intervals monitorapi.Intervals
+	junits    []*junitapi.JUnitTestCase
+	annotate  IntervalAnnotationFunc
+	err       error
+}
+
+func phaseDeadlineFor(monitorTest MonitorTest, phase MonitorTestPhase) PhaseDeadline {
+	if declarer, ok := monitorTest.(PhaseDeadlineDeclarer); ok {
+		if deadline, ok := declarer.PhaseDeadlines()[phase]; ok {
+			return deadline
+		}
+	}
+	return DefaultPhaseDeadlines[phase]
+}
+
+func (r *monitorTestRegistry) CheckPhaseDeadlines() error {
+	var errs []error
+	for _, name := range sets.StringKeySet(r.monitorTests).List() {
+		monitorTest := r.monitorTests[name].monitorTest
+		declarer, ok := monitorTest.(PhaseWaitDeclarer)
+		if !ok {
+			continue
+		}
+		for phase, wait := range declarer.PhaseWaits() {
+			deadline := phaseDeadlineFor(monitorTest, phase)
+			if deadline.Timeout > 0 && deadline.Timeout <= wait {
+				errs = append(errs, fmt.Errorf("monitor test %q waits up to %v in %s, but its deadline is %v", name, wait, phase, deadline.Timeout))
+			}
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// runPhase runs fn for a single monitor test and stops waiting for it once the deadline for the phase passes.
+// A monitor test that misses its deadline has the context passed to fn cancelled, its result is discarded, and a
+// PhaseTimeoutError is returned instead.  The context is also cancelled when a phase other than StartCollection
+// finishes, the collection started by StartCollection must keep running until CollectData.
+// A monitor test that ignores the cancellation keeps running in the background, so before running its next phase,
+// including Cleanup, the registry waits for the abandoned phase to return, up to the deadline of the next phase.
+// If it still has not returned, the next phase is not run and fails instead of overlapping with it.
+func (r *monitorTestRegistry) runPhase(ctx context.Context, monitorTest *monitorTesttItem, phase MonitorTestPhase, fn func(ctx context.Context) phaseResult) phaseResult {
+	deadline := phaseDeadlineFor(monitorTest.monitorTest, phase)
+	log := logrus.WithField("monitorTest", monitorTest.name).WithField("phase", phase)
+
+	start := time.Now()
+	if err := monitorTest.waitForAbandonedPhase(ctx, deadline.Timeout); err != nil {
+		log.WithError(err).Error("not running monitor test phase")
+		r.recordPhase(monitorTest.name, phase, start, time.Now(), err, false)
+		return phaseResult{err: err}
+	}
+
+	phaseCtx, cancel := context.WithCancel(ctx)
+	timedOut := false
+	defer func() {
+		if timedOut || phase != PhaseStartCollection {
+			cancel()
+		}
+	}()
+	var timeoutCh <-chan time.Time
+	if deadline.Timeout > 0 {
+		timer := time.NewTimer(deadline.Timeout)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
+	progress := time.NewTicker(phaseProgressInterval)
+	defer progress.Stop()
+
+	// buffered so a monitor test that missed its deadline can still finish without blocking forever.
+	resultCh := make(chan phaseResult, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		resultCh <- fn(phaseCtx)
+	}()
+
+	var result phaseResult
+	for waiting := true; waiting; {
+		select {
+		case result = <-resultCh:
+			waiting = false
+		case <-timeoutCh:
+			timedOut = true
+			waiting = false
+			var err error = &PhaseTimeoutError{
+				MonitorTest: monitorTest.name,
+				Phase:       phase,
+				Timeout:     deadline.Timeout,
+			}
+			if deadline.FlakeOnTimeout {
+				err = &FlakeError{Err: err}
+			}
+			result = phaseResult{err: err}
+			monitorTest.abandonPhase(phase, done)
+			log.WithError(err).Error("deadline exceeded, abandoning monitor test phase")
+		case <-progress.C:
+			log.Infof("still running after %v", time.Since(start).Round(time.Second))
+		}
+	}
+
+	r.recordPhase(monitorTest.name, phase, start, time.Now(), result.err, timedOut)
+	return result
+}
+
+// abandonPhase remembers the phase that missed its deadline, done is closed once it returns.
+func (m *monitorTesttItem) abandonPhase(phase MonitorTestPhase, done <-chan struct{}) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.abandonedPhase = phase
+	m.abandonedPhaseDone = done
+}
+
+// waitForAbandonedPhase waits up to timeout for a phase that missed its deadline to return.  Zero waits until
+// ctx is done.
+func (m *monitorTesttItem) waitForAbandonedPhase(ctx context.Context, timeout time.Duration) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.abandonedPhaseDone == nil {
+		return nil
+	}
+
+	var timeoutCh <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
+	select {
+	case <-m.abandonedPhaseDone:
+		m.abandonedPhase = ""
+		m.abandonedPhaseDone = nil
+		return nil
+	case <-timeoutCh:
+	case <-ctx.Done():
+	}
+	return fmt.Errorf("monitor test %q is still running %s, which missed its deadline", m.name, m.abandonedPhase)
+}
+
+func (r *monitorTestRegistry) recordPhase(name string, phase MonitorTestPhase, from, to time.Time, err error, timedOut bool) {
+	duration := to.Sub(from).Round(time.Millisecond)
+	level := monitorapi.Info
+	message := monitorapi.NewMessage().
+		Reason(monitorapi.MonitorTestPhaseFinished).
+		WithAnnotation(monitorapi.AnnotationPhase, string(phase))
+
+	var nsErr *NotSupportedError
+	switch {
+	case timedOut:
+		level = monitorapi.Error
+		message = message.Reason(monitorapi.MonitorTestPhaseTimedOut).HumanMessagef("%v", err)
+	case err != nil && errors.As(err, &nsErr):
+		message = message.HumanMessagef("not supported after %v: %s", duration, nsErr.Reason)
+	case err != nil:
+		level = monitorapi.Warning
+		message = message.Reason(monitorapi.MonitorTestPhaseFailed).HumanMessagef("failed after %v: %v", duration, err)
+	default:
+		message = message.HumanMessagef("finished in %v", duration)
+	}
+
+	interval := monitorapi.NewInterval(monitorapi.SourceMonitorTestPhase, level).
+		Locator(monitorapi.NewLocator().MonitorTest(name)).
+		Message(message).
+		Build(from, to)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.phaseIntervals = append(r.phaseIntervals, interval)
+}
+
+func (r *monitorTestRegistry) PhaseIntervals() monitorapi.Intervals {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	ret := make(monitorapi.Intervals, len(r.phaseIntervals))
+	copy(ret, r.phaseIntervals)
+	return ret
+}
@@ -0,0 +1,182 @@
+package monitortestframework
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+type fakeMonitorTest struct {
+	deadlines PhaseDeadlines
+	waits     map[MonitorTestPhase]time.Duration
+	// hang blocks CollectData until the context is done.
+	hang bool
+	// ignoreCancel, if set, keeps a hanging CollectData running after the context is done until it is closed.
+	ignoreCancel chan struct{}
+}
+
+func (t *fakeMonitorTest) PhaseDeadlines() PhaseDeadlines {
+	return t.deadlines
+}
+
+func (t *fakeMonitorTest) PhaseWaits() map[MonitorTestPhase]time.Duration {
+	return t.waits
+}
+
+func (t *fakeMonitorTest) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (t *fakeMonitorTest) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if t.hang {
+		<-ctx.Done()
+		if t.ignoreCancel != nil {
+			<-t.ignoreCancel
+		}
+	}
+	return monitorapi.Intervals{{From: beginning, To: end}}, nil, nil
+}
+
+func (t *fakeMonitorTest) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, nil
+}
+
+func (t *fakeMonitorTest) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return nil, nil
+}
+
+func (t *fakeMonitorTest) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return nil
+}
+
+func (t *fakeMonitorTest) Cleanup(ctx context.Context) error {
+	return nil
+}
+
+func TestCollectDataDeadline(t *testing.T) {
+	tests := []struct {
+		name           string
+		flakeOnTimeout bool
+	}{
+		{
+			name: "fail on timeout",
+		},
+		{
+			name:           "flake on timeout",
+			flakeOnTimeout: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewMonitorTestRegistry()
+			registry.AddMonitorTestOrDie("stuck", "Test Framework", &fakeMonitorTest{
+				hang: true,
+				deadlines: PhaseDeadlines{
+					PhaseCollectData: {Timeout: 100 * time.Millisecond, FlakeOnTimeout: tt.flakeOnTimeout},
+				},
+			})
+			registry.AddMonitorTestOrDie("healthy", "Test Framework", &fakeMonitorTest{})
+
+			start := time.Now()
+			intervals, junits, _ := registry.CollectData(context.Background(), t.TempDir(), start, start.Add(time.Second))
+			require.Less(t, time.Since(start), 10*time.Second, "CollectData waited for the stuck monitor test")
+
+			assert.Len(t, intervals, 1, "intervals from the healthy monitor test must be kept")
+
+			var stuckJunits, healthyJunits []*junitapi.JUnitTestCase
+			for _, junit := range junits {
+				switch {
+				case strings.Contains(junit.Name, "monitor test stuck"):
+					stuckJunits = append(stuckJunits, junit)
+				case strings.Contains(junit.Name, "monitor test healthy"):
+					healthyJunits = append(healthyJunits, junit)
+				}
+			}
+			require.Len(t, healthyJunits, 1)
+			assert.Nil(t, healthyJunits[0].FailureOutput)
+
+			if tt.flakeOnTimeout {
+				require.Len(t, stuckJunits, 2, "a flake is a failing and a passing junit")
+			} else {
+				require.Len(t, stuckJunits, 1)
+			}
+			require.NotNil(t, stuckJunits[0].FailureOutput)
+			assert.Contains(t, stuckJunits[0].FailureOutput.Output, `monitor test "stuck" did not finish CollectData within 100ms`)
+
+			phases := registry.PhaseIntervals()
+			require.Len(t, phases, 2)
+			for _, phase := range phases {
+				assert.Equal(t, string(PhaseCollectData), phase.StructuredMessage.Annotations[monitorapi.AnnotationPhase])
+				switch phase.StructuredLocator.Keys[monitorapi.LocatorMonitorTestKey] {
+				case "stuck":
+					assert.Equal(t, monitorapi.Error, phase.Level)
+					assert.Equal(t, monitorapi.MonitorTestPhaseTimedOut, phase.StructuredMessage.Reason)
+				case "healthy":
+					assert.Equal(t, monitorapi.Info, phase.Level)
+					assert.Equal(t, monitorapi.MonitorTestPhaseFinished, phase.StructuredMessage.Reason)
+				default:
+					t.Errorf("unexpected phase interval %v", phase)
+				}
+			}
+		})
+	}
+}
+
+func TestAbandonedPhaseDoesNotOverlapCleanup(t *testing.T) {
+	ignoreCancel := make(chan struct{})
+	defer func() {
+		select {
+		case <-ignoreCancel:
+		default:
+			close(ignoreCancel)
+		}
+	}()
+	registry := NewMonitorTestRegistry()
+	registry.AddMonitorTestOrDie("stuck", "Test Framework", &fakeMonitorTest{
+		hang:         true,
+		ignoreCancel: ignoreCancel,
+		deadlines: PhaseDeadlines{
+			PhaseCollectData: {Timeout: 100 * time.Millisecond},
+			PhaseCleanup:     {Timeout: 100 * time.Millisecond},
+		},
+	})
+
+	start := time.Now()
+	registry.CollectData(context.Background(), t.TempDir(), start, start.Add(time.Second))
+
+	_, err := registry.Cleanup(context.Background())
+	require.Error(t, err, "cleanup must not run while CollectData is still running")
+	assert.Contains(t, err.Error(), `monitor test "stuck" is still running CollectData, which missed its deadline`)
+
+	close(ignoreCancel)
+	_, err = registry.Cleanup(context.Background())
+	assert.NoError(t, err, "cleanup must run once CollectData returned")
+}
+
+func TestCheckPhaseDeadlines(t *testing.T) {
+	registry := NewMonitorTestRegistry()
+	registry.AddMonitorTestOrDie("declared", "Test Framework", &fakeMonitorTest{
+		deadlines: PhaseDeadlines{PhaseCleanup: {Timeout: 25 * time.Minute}},
+		waits:     map[MonitorTestPhase]time.Duration{PhaseCleanup: 20 * time.Minute},
+	})
+	registry.AddMonitorTestOrDie("no deadline", "Test Framework", &fakeMonitorTest{
+		deadlines: PhaseDeadlines{PhaseCleanup: {}},
+		waits:     map[MonitorTestPhase]time.Duration{PhaseCleanup: 20 * time.Minute},
+	})
+	require.NoError(t, registry.CheckPhaseDeadlines())
+
+	registry.AddMonitorTestOrDie("default", "Test Framework", &fakeMonitorTest{
+		waits: map[MonitorTestPhase]time.Duration{PhaseCleanup: 20 * time.Minute},
+	})
+	err := registry.CheckPhaseDeadlines()
+	require.Error(t, err)
+	assert.Equal(t, `monitor test "default" waits up to 20m0s in Cleanup, but its deadline is 15m0s`, err.Error())
+}
@@ -20,6 +20,9 @@ import (
 
 type monitorTestRegistry struct {
 	monitorTests map[string]*monitorTesttItem
+
+	lock           sync.Mutex
+	phaseIntervals monitorapi.Intervals
 }
 
 type monitorTesttItem struct {
@@ -27,6 +30,12 @@ type monitorTesttItem struct {
 	jiraComponent string
 
 	monitorTest MonitorTest
+
+	lock sync.Mutex
+	// abandonedPhase is the phase that missed its deadline and may still be running, until abandonedPhaseDone
+	// is closed.
+	abandonedPhase     MonitorTestPhase
+	abandonedPhaseDone <-chan struct{}
 }
 
 func NewMonitorTestRegistry() MonitorTestRegistry {
@@ -92,7 +101,10 @@ func (r *monitorTestRegistry) StartCollection(ctx context.Context, adminRESTConf
 			logrus.Infof("  Starting %v for %v", invariant.name, invariant.jiraComponent)
 
 			start := time.Now()
-			err := startCollectionWithPanicProtection(ctx, invariant.monitorTest, adminRESTConfig, recorder)
+			result := r.runPhase(ctx, invariant, PhaseStartCollection, func(ctx context.Context) phaseResult {
+				return phaseResult{err: startCollectionWithPanicProtection(ctx, invariant.monitorTest, adminRESTConfig, recorder)}
+			})
+			err := result.err
 			end := time.Now()
 			duration := end.Sub(start)
 			if err != nil {
@@ -146,6 +158,59 @@ func (r *monitorTestRegistry) StartCollection(ctx context.Context, adminRESTConf
 	return junits, utilerrors.NewAggregate(errs)
 }
 
+func (r *monitorTestRegistry) PrepareForReplay(ctx context.Context) (MonitorTestRegistry, []*junitapi.JUnitTestCase, error) {
+	ret := NewMonitorTestRegistry().(*monitorTestRegistry)
+	junits := []*junitapi.JUnitTestCase{}
+	errs := []error{}
+
+	for _, name := range sets.StringKeySet(r.monitorTests).List() {
+		monitorTest := r.monitorTests[name]
+		testName := fmt.Sprintf("[Jira:%q] monitor test %v setup", monitorTest.jiraComponent, monitorTest.name)
+
+		replayable, ok := monitorTest.monitorTest.(ReplayableMonitorTest)
+		if !ok {
+			junits = append(junits, &junitapi.JUnitTestCase{
+				Name:        testName,
+				SkipMessage: &junitapi.SkipMessage{Message: "monitor test depends on collection and cannot be replayed"},
+			})
+			continue
+		}
+
+		start := time.Now()
+		err := prepareForReplayWithPanicProtection(ctx, replayable)
+		duration := time.Since(start)
+		if err != nil {
+			var nsErr *NotSupportedError
+			if errors.As(err, &nsErr) {
+				junits = append(junits, &junitapi.JUnitTestCase{
+					Name:        testName,
+					Duration:    duration.Seconds(),
+					SkipMessage: &junitapi.SkipMessage{Message: nsErr.Reason},
+				})
+				continue
+			}
+			errs = append(errs, err)
+			junits = append(junits, &junitapi.JUnitTestCase{
+				Name:     testName,
+				Duration: duration.Seconds(),
+				FailureOutput: &junitapi.FailureOutput{
+					Output: fmt.Sprintf("failed preparing for replay\n%v", err),
+				},
+				SystemOut: fmt.Sprintf("failed preparing for replay\n%v", err),
+			})
+			continue
+		}
+
+		ret.monitorTests[name] = monitorTest
+		junits = append(junits, &junitapi.JUnitTestCase{
+			Name:     testName,
+			Duration: duration.Seconds(),
+		})
+	}
+
+	return ret, junits, utilerrors.NewAggregate(errs)
+}
+
 func (r *monitorTestRegistry) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
 	wg := sync.WaitGroup{}
 	intervalsCh := make(chan monitorapi.Intervals, len(r.monitorTests))
@@ -161,9 +226,13 @@ func (r *monitorTestRegistry) CollectData(ctx context.Context, storageDir string
 
 			start := time.Now()
 			logrus.Infof("  Starting CollectData for %s", testName)
-			localIntervals, localJunits, err := collectDataWithPanicProtection(ctx, monitorTest.monitorTest, storageDir, beginning, end)
-			intervalsCh <- localIntervals
-			junitCh <- localJunits
+			result := r.runPhase(ctx, monitorTest, PhaseCollectData, func(ctx context.Context) phaseResult {
+				localIntervals, localJunits, err := collectDataWithPanicProtection(ctx, monitorTest.monitorTest, storageDir, beginning, end)
+				return phaseResult{intervals: localIntervals, junits: localJunits, err: err}
+			})
+			intervalsCh <- result.intervals
+			junitCh <- result.junits
+			err := result.err
 			end := time.Now()
 			duration := end.Sub(start)
 			if err != nil {
@@ -236,11 +305,16 @@ func (r *monitorTestRegistry) ConstructComputedIntervals(ctx context.Context, st
 	errs := []error{}
 
 	for _, monitorTest := range r.monitorTests {
+		monitorTest := monitorTest // a monitor test that misses its deadline keeps running with this value.
 		testName := fmt.Sprintf("[Jira:%q] monitor test %v interval construction", monitorTest.jiraComponent, monitorTest.name)
 
 		start := time.Now()
-		localIntervals, err := constructComputedIntervalsWithPanicProtection(ctx, monitorTest.monitorTest, startingIntervals, recordedResources, beginning, end)
-		intervals = append(intervals, localIntervals...)
+		result := r.runPhase(ctx, monitorTest, PhaseConstructComputedIntervals, func(ctx context.Context) phaseResult {
+			localIntervals, err := constructComputedIntervalsWithPanicProtection(ctx, monitorTest.monitorTest, startingIntervals, recordedResources, beginning, end)
+			return phaseResult{intervals: localIntervals, err: err}
+		})
+		intervals = append(intervals, result.intervals...)
+		err := result.err
 		end := time.Now()
 		duration := end.Sub(start)
 		if err != nil {
@@ -280,16 +354,89 @@ func (r *monitorTestRegistry) ConstructComputedIntervals(ctx context.Context, st
 	return intervals, junits, utilerrors.NewAggregate(errs)
 }
 
+func (r *monitorTestRegistry) AnnotateIntervals(ctx context.Context, intervals monitorapi.Intervals) (IntervalAnnotationFunc, []*junitapi.JUnitTestCase, error) {
+	annotateFns := []IntervalAnnotationFunc{}
+	junits := []*junitapi.JUnitTestCase{}
+	errs := []error{}
+
+	// sorted so the annotations are added in the same order on every run.
+	for _, name := range sets.StringKeySet(r.monitorTests).List() {
+		monitorTest := r.monitorTests[name]
+		annotator, ok := monitorTest.monitorTest.(IntervalAnnotator)
+		if !ok {
+			continue
+		}
+		testName := fmt.Sprintf("[Jira:%q] monitor test %v interval annotation", monitorTest.jiraComponent, monitorTest.name)
+
+		start := time.Now()
+		result := r.runPhase(ctx, monitorTest, PhaseAnnotateIntervals, func(ctx context.Context) phaseResult {
+			annotate, err := annotateIntervalsWithPanicProtection(ctx, annotator, intervals)
+			return phaseResult{annotate: annotate, err: err}
+		})
+		if result.annotate != nil {
+			annotateFns = append(annotateFns, result.annotate)
+		}
+		err := result.err
+		end := time.Now()
+		duration := end.Sub(start)
+		if err != nil {
+			var nsErr *NotSupportedError
+			if errors.As(err, &nsErr) {
+				junits = append(junits, &junitapi.JUnitTestCase{
+					Name:     testName,
+					Duration: duration.Seconds(),
+					SkipMessage: &junitapi.SkipMessage{
+						Message: nsErr.Reason,
+					},
+				})
+				continue
+			}
+
+			errs = append(errs, err)
+			junits = append(junits, &junitapi.JUnitTestCase{
+				Name:     testName,
+				Duration: duration.Seconds(),
+				FailureOutput: &junitapi.FailureOutput{
+					Output: fmt.Sprintf("failed during interval annotation\n%v", err),
+				},
+				SystemOut: fmt.Sprintf("failed during interval annotation\n%v", err),
+			})
+			var flakeErr *FlakeError
+			if !errors.As(err, &flakeErr) {
+				continue
+			}
+		}
+
+		junits = append(junits, &junitapi.JUnitTestCase{
+			Name:     testName,
+			Duration: duration.Seconds(),
+		})
+	}
+
+	annotate := func(interval monitorapi.Interval) monitorapi.Interval {
+		for _, annotateFn := range annotateFns {
+			interval = annotateFn(interval)
+		}
+		return interval
+	}
+	return annotate, junits, utilerrors.NewAggregate(errs)
+}
+
 func (r *monitorTestRegistry) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
 	junits := []*junitapi.JUnitTestCase{}
 	errs := []error{}
 
 	for _, monitorTest := range r.monitorTests {
+		monitorTest := monitorTest
 		testName := fmt.Sprintf("[Jira:%q] monitor test %v test evaluation", monitorTest.jiraComponent, monitorTest.name)
 
 		start := time.Now()
-		localJunits, err := evaluateTestsFromConstructedIntervalsWithPanicProtection(ctx, monitorTest.monitorTest, finalIntervals)
-		junits = append(junits, localJunits...)
+		result := r.runPhase(ctx, monitorTest, PhaseEvaluateTestsFromConstructedIntervals, func(ctx context.Context) phaseResult {
+			localJunits, err := evaluateTestsFromConstructedIntervalsWithPanicProtection(ctx, monitorTest.monitorTest, finalIntervals)
+			return phaseResult{junits: localJunits, err: err}
+		})
+		junits = append(junits, result.junits...)
+		err := result.err
 		end := time.Now()
 		duration := end.Sub(start)
 		if err != nil {
@@ -334,6 +481,7 @@ func (r *monitorTestRegistry) WriteContentToStorage(ctx context.Context, storage
 	errs := []error{}
 
 	for _, monitorTest := range r.monitorTests {
+		monitorTest := monitorTest
 		testName := fmt.Sprintf("[Jira:%q] monitor test %v writing to storage", monitorTest.jiraComponent, monitorTest.name)
 
 		start := time.Now()
@@ -346,7 +494,10 @@ func (r *monitorTestRegistry) WriteContentToStorage(ctx context.Context, storage
 		}
 		fmt.Fprintf(os.Stderr, "  last interval time: From = %s; To = %s\n", finalIntervals[finalIntervalLength-1].From, finalIntervals[finalIntervalLength-1].To)
 
-		err := writeContentToStorageWithPanicProtection(ctx, monitorTest.monitorTest, storageDir, timeSuffix, finalIntervals, finalResourceState)
+		result := r.runPhase(ctx, monitorTest, PhaseWriteContentToStorage, func(ctx context.Context) phaseResult {
+			return phaseResult{err: writeContentToStorageWithPanicProtection(ctx, monitorTest.monitorTest, storageDir, timeSuffix, finalIntervals, finalResourceState)}
+		})
+		err := result.err
 		end := time.Now()
 		duration := end.Sub(start)
 		if err != nil {
@@ -391,12 +542,16 @@ func (r *monitorTestRegistry) Cleanup(ctx context.Context) ([]*junitapi.JUnitTes
 	errs := []error{}
 
 	for _, monitorTest := range r.monitorTests {
+		monitorTest := monitorTest
 		testName := fmt.Sprintf("[Jira:%q] monitor test %v cleanup", monitorTest.jiraComponent, monitorTest.name)
 		log := logrus.WithField("monitorTest", monitorTest.name)
 
 		start := time.Now()
 		log.Info("beginning cleanup")
-		err := cleanupWithPanicProtection(ctx, monitorTest.monitorTest)
+		result := r.runPhase(ctx, monitorTest, PhaseCleanup, func(ctx context.Context) phaseResult {
+			return phaseResult{err: cleanupWithPanicProtection(ctx, monitorTest.monitorTest)}
+		})
+		err := result.err
 		end := time.Now()
 		duration := end.Sub(start)
 		if err != nil {
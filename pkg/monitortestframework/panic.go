@@ -26,6 +26,19 @@ func startCollectionWithPanicProtection(ctx context.Context, monitortest Monitor
 	return
 }
 
+func prepareForReplayWithPanicProtection(ctx context.Context, monitortest ReplayableMonitorTest) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("caught panic: %v", r)
+			logrus.Error("recovering from panic")
+			fmt.Print(debug.Stack())
+		}
+	}()
+
+	err = monitortest.PrepareForReplay(ctx)
+	return
+}
+
 func collectDataWithPanicProtection(ctx context.Context, monitortest MonitorTest, storageDir string, beginning, end time.Time) (intervals monitorapi.Intervals, junit []*junitapi.JUnitTestCase, err error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -52,6 +65,19 @@ func constructComputedIntervalsWithPanicProtection(ctx context.Context, monitort
 	return
 }
 
+func annotateIntervalsWithPanicProtection(ctx context.Context, annotator IntervalAnnotator, intervals monitorapi.Intervals) (annotate IntervalAnnotationFunc, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("caught panic: %v", r)
+			logrus.Error("recovering from panic")
+			fmt.Print(debug.Stack())
+		}
+	}()
+
+	annotate, err = annotator.AnnotateIntervals(ctx, intervals)
+	return
+}
+
 func evaluateTestsFromConstructedIntervalsWithPanicProtection(ctx context.Context, monitortest MonitorTest, finalIntervals monitorapi.Intervals) (junits []*junitapi.JUnitTestCase, err error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -0,0 +1,50 @@
+package monitortestframework
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeReplayableMonitorTest struct {
+	fakeMonitorTest
+	err error
+}
+
+func (t *fakeReplayableMonitorTest) PrepareForReplay(ctx context.Context) error {
+	return t.err
+}
+
+func TestPrepareForReplay(t *testing.T) {
+	registry := NewMonitorTestRegistry()
+	registry.AddMonitorTestOrDie("replayable", "Test Framework", &fakeReplayableMonitorTest{})
+	registry.AddMonitorTestOrDie("not-supported", "Test Framework", &fakeReplayableMonitorTest{err: &NotSupportedError{Reason: "not here"}})
+	registry.AddMonitorTestOrDie("broken", "Test Framework", &fakeReplayableMonitorTest{err: fmt.Errorf("broken")})
+	registry.AddMonitorTestOrDie("collects", "Test Framework", &fakeMonitorTest{})
+
+	replayRegistry, junits, err := registry.PrepareForReplay(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, []string{"replayable"}, replayRegistry.ListMonitorTests().List(), "only the prepared monitor tests are replayed")
+
+	require.Len(t, junits, 4)
+	results := map[string]string{}
+	for _, junit := range junits {
+		switch {
+		case junit.FailureOutput != nil:
+			results[junit.Name] = "failed"
+		case junit.SkipMessage != nil:
+			results[junit.Name] = "skipped"
+		default:
+			results[junit.Name] = "passed"
+		}
+	}
+	assert.Equal(t, map[string]string{
+		`[Jira:"Test Framework"] monitor test replayable setup`:    "passed",
+		`[Jira:"Test Framework"] monitor test not-supported setup`: "skipped",
+		`[Jira:"Test Framework"] monitor test broken setup`:        "failed",
+		`[Jira:"Test Framework"] monitor test collects setup`:      "skipped",
+	}, results)
+}
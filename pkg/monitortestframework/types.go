@@ -34,6 +34,10 @@ type MonitorTestInitializationInfo struct {
 
 	// DisableMonitorTests will remove any monitor tests contained in the provided list
 	DisableMonitorTests []string
+
+	// PathologicalEventAllowanceFiles are YAML or JSON files of additional pathological event matchers, passed
+	// to the monitor tests that check for pathological events.
+	PathologicalEventAllowanceFiles []string
 }
 
 type MonitorTest interface {
@@ -74,6 +78,73 @@ type MonitorTest interface {
 	Cleanup(ctx context.Context) error
 }
 
+// IntervalAnnotationFunc returns the interval with the annotations it adds, or the interval unchanged.
+type IntervalAnnotationFunc func(interval monitorapi.Interval) monitorapi.Interval
+
+// IntervalAnnotator may be implemented by a MonitorTest that annotates intervals it did not create, based on intervals
+// constructed by other MonitorTests.  AnnotateIntervals is called after ConstructComputedIntervals, with every interval
+// including the constructed ones, and the annotations it returns are visible to EvaluateTestsFromConstructedIntervals
+// and WriteContentToStorage.
+// Errors reported will be indicated as junit test failure and will cause job runs to fail.
+type IntervalAnnotator interface {
+	AnnotateIntervals(ctx context.Context, intervals monitorapi.Intervals) (IntervalAnnotationFunc, error)
+}
+
+// ReplayableMonitorTest may be implemented by a MonitorTest whose later phases only depend on the intervals and
+// resources recorded by a run, so it can be re-evaluated from the artifacts of a prior run.  When replaying, there is
+// no cluster: PrepareForReplay is called instead of StartCollection, and CollectData is not called.  Monitor tests
+// that do not implement it, or that return a NotSupportedError from it, are skipped when replaying rather than
+// being called without the state StartCollection would have set up.
+type ReplayableMonitorTest interface {
+	PrepareForReplay(ctx context.Context) error
+}
+
+// MonitorTestPhase identifies one of the MonitorTest methods driven by the MonitorTestRegistry.
+type MonitorTestPhase string
+
+const (
+	PhaseStartCollection                       MonitorTestPhase = "StartCollection"
+	PhaseCollectData                           MonitorTestPhase = "CollectData"
+	PhaseConstructComputedIntervals            MonitorTestPhase = "ConstructComputedIntervals"
+	PhaseAnnotateIntervals                     MonitorTestPhase = "AnnotateIntervals"
+	PhaseEvaluateTestsFromConstructedIntervals MonitorTestPhase = "EvaluateTestsFromConstructedIntervals"
+	PhaseWriteContentToStorage                 MonitorTestPhase = "WriteContentToStorage"
+	PhaseCleanup                               MonitorTestPhase = "Cleanup"
+)
+
+// PhaseDeadline bounds how long a single MonitorTest may spend in a phase.
+type PhaseDeadline struct {
+	// Timeout is how long the phase may run.  Zero means no deadline.
+	Timeout time.Duration
+	// FlakeOnTimeout reports a missed deadline as a flake instead of a failure.
+	FlakeOnTimeout bool
+}
+
+type PhaseDeadlines map[MonitorTestPhase]PhaseDeadline
+
+// DefaultPhaseDeadlines are used for any phase a MonitorTest does not declare a deadline for.
+var DefaultPhaseDeadlines = PhaseDeadlines{
+	PhaseStartCollection:                       {Timeout: 10 * time.Minute},
+	PhaseCollectData:                           {Timeout: 30 * time.Minute},
+	PhaseConstructComputedIntervals:            {Timeout: 15 * time.Minute},
+	PhaseAnnotateIntervals:                     {Timeout: 15 * time.Minute},
+	PhaseEvaluateTestsFromConstructedIntervals: {Timeout: 15 * time.Minute},
+	PhaseWriteContentToStorage:                 {Timeout: 15 * time.Minute},
+	PhaseCleanup:                               {Timeout: 15 * time.Minute},
+}
+
+// PhaseDeadlineDeclarer may be implemented by a MonitorTest to override DefaultPhaseDeadlines for some phases.
+type PhaseDeadlineDeclarer interface {
+	PhaseDeadlines() PhaseDeadlines
+}
+
+// PhaseWaitDeclarer may be implemented by a MonitorTest that waits for the cluster in some phases, for instance polls
+// for a namespace to be deleted, to report the longest it may wait in each of them.  The deadline of the phase must be
+// longer, or the phase would be cancelled before the wait gives up.
+type PhaseWaitDeclarer interface {
+	PhaseWaits() map[MonitorTestPhase]time.Duration
+}
+
 type MonitorTestRegistry interface {
 	AddRegistryOrDie(registry MonitorTestRegistry)
 
@@ -91,6 +162,11 @@ type MonitorTestRegistry interface {
 	// This allows us to know when setups fail.
 	StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) ([]*junitapi.JUnitTestCase, error)
 
+	// PrepareForReplay is called instead of StartCollection when re-evaluating the artifacts of a prior run.  It
+	// returns a registry of only the monitor tests that implement ReplayableMonitorTest and prepared successfully,
+	// the others are reported as skipped.
+	PrepareForReplay(ctx context.Context) (MonitorTestRegistry, []*junitapi.JUnitTestCase, error)
+
 	// CollectData will only be called once near the end of execution, before all Intervals are inspected.
 	// Errors reported will be indicated as junit test failure and will cause job runs to fail.
 	CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error)
@@ -101,6 +177,11 @@ type MonitorTestRegistry interface {
 	// Errors reported will be indicated as junit test failure and will cause job runs to fail.
 	ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error)
 
+	// AnnotateIntervals is called after ConstructComputedIntervals, with every interval.  It returns a function that
+	// adds the annotations of every InvariantTest implementing IntervalAnnotator to an interval.
+	// Errors reported will be indicated as junit test failure and will cause job runs to fail.
+	AnnotateIntervals(ctx context.Context, intervals monitorapi.Intervals) (IntervalAnnotationFunc, []*junitapi.JUnitTestCase, error)
+
 	// EvaluateTestsFromConstructedIntervals is called after all Intervals are known and can produce
 	// junit tests for reporting purposes.
 	// Errors reported will be indicated as junit test failure and will cause job runs to fail.
@@ -120,5 +201,13 @@ type MonitorTestRegistry interface {
 	// Errors reported will cause job runs to fail to ensure cleanup functions work reliably.
 	Cleanup(ctx context.Context) ([]*junitapi.JUnitTestCase, error)
 
+	// CheckPhaseDeadlines returns an error for every phase of a monitor test that may wait longer than its deadline,
+	// as reported by the monitor tests implementing PhaseWaitDeclarer.
+	CheckPhaseDeadlines() error
+
+	// PhaseIntervals returns one interval for every phase of every monitor test run so far, describing how long
+	// it took and whether it failed or missed its deadline.
+	PhaseIntervals() monitorapi.Intervals
+
 	getMonitorTests() map[string]*monitorTesttItem
 }
@@ -0,0 +1,161 @@
+package allowedbackenddisruption
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+)
+
+// disruption_budgets.yaml declares the disruption a backend is allowed, it gates the backends and the job types we
+// do not have enough historical data for, and caps the disruption of the ones we do.
+//
+//go:embed disruption_budgets.yaml
+var budgetsFile []byte
+
+var (
+	readBudgets sync.Once
+	budgets     *DisruptionBudgetList
+)
+
+// DisruptionBudgetList is the content of disruption_budgets.yaml.
+type DisruptionBudgetList struct {
+	Budgets []DisruptionBudget `json:"budgets"`
+}
+
+// DisruptionBudget is the disruption a backend is allowed in the job types it matches.  The job type fields are
+// optional, an empty field matches any job type.
+type DisruptionBudget struct {
+	BackendName string `json:"backendName"`
+
+	Release      string `json:"release,omitempty"`
+	FromRelease  string `json:"fromRelease,omitempty"`
+	Platform     string `json:"platform,omitempty"`
+	Architecture string `json:"architecture,omitempty"`
+	Network      string `json:"network,omitempty"`
+	Topology     string `json:"topology,omitempty"`
+
+	// MaxDisruption is the hard cap, more disruption fails the test whatever the historical data says.
+	MaxDisruption *metav1.Duration `json:"maxDisruption,omitempty"`
+	// ExpectedZero means the backend should not be disrupted at all.  Any disruption fails the test, or, if
+	// MaxDisruption is set too, flakes it up to MaxDisruption.
+	ExpectedZero bool `json:"expectedZero,omitempty"`
+
+	// Reason documents why the backend has a budget, a bug or the expected behavior of the backend for instance.
+	Reason string `json:"reason"`
+}
+
+func (b DisruptionBudget) String() string {
+	keys := []string{fmt.Sprintf("backendName=%s", b.BackendName)}
+	for _, field := range b.jobTypeFields(platformidentification.JobType{}) {
+		if len(field.value) > 0 {
+			keys = append(keys, fmt.Sprintf("%s=%s", field.name, field.value))
+		}
+	}
+	return fmt.Sprintf("{%s} (%s)", strings.Join(keys, " "), b.Reason)
+}
+
+type jobTypeField struct {
+	name, value, jobTypeValue string
+}
+
+// jobTypeFields pairs the job type fields of the budget with the ones of the given job type.
+func (b DisruptionBudget) jobTypeFields(jobType platformidentification.JobType) []jobTypeField {
+	return []jobTypeField{
+		{name: "release", value: b.Release, jobTypeValue: jobType.Release},
+		{name: "fromRelease", value: b.FromRelease, jobTypeValue: jobType.FromRelease},
+		{name: "platform", value: b.Platform, jobTypeValue: jobType.Platform},
+		{name: "architecture", value: b.Architecture, jobTypeValue: jobType.Architecture},
+		{name: "network", value: b.Network, jobTypeValue: jobType.Network},
+		{name: "topology", value: b.Topology, jobTypeValue: jobType.Topology},
+	}
+}
+
+// matches returns whether the budget applies to the backend in the given job type, and how many job type fields
+// it matched on.
+func (b DisruptionBudget) matches(backendName string, jobType platformidentification.JobType) (bool, int) {
+	if b.BackendName != backendName {
+		return false, 0
+	}
+	specificity := 0
+	for _, field := range b.jobTypeFields(jobType) {
+		if len(field.value) == 0 {
+			continue
+		}
+		if field.value != field.jobTypeValue {
+			return false, 0
+		}
+		specificity++
+	}
+	return true, specificity
+}
+
+// Allowed returns the disruption the budget allows before failing the test.
+func (b DisruptionBudget) Allowed() time.Duration {
+	if b.MaxDisruption != nil {
+		return b.MaxDisruption.Duration
+	}
+	return 0
+}
+
+func (b DisruptionBudget) Validate() error {
+	if len(b.BackendName) == 0 {
+		return fmt.Errorf("every budget must specify a backendName")
+	}
+	if b.MaxDisruption == nil && !b.ExpectedZero {
+		return fmt.Errorf("budget %s must specify maxDisruption, expectedZero, or both", b)
+	}
+	if b.MaxDisruption != nil && b.MaxDisruption.Duration < 0 {
+		return fmt.Errorf("budget %s maxDisruption must not be negative", b)
+	}
+	if len(b.Reason) == 0 {
+		return fmt.Errorf("budget %s must specify a reason", b)
+	}
+	return nil
+}
+
+func NewDisruptionBudgetList(content []byte) (*DisruptionBudgetList, error) {
+	list := &DisruptionBudgetList{}
+	if err := yaml.UnmarshalStrict(content, list); err != nil {
+		return nil, err
+	}
+	for _, budget := range list.Budgets {
+		if err := budget.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// BestMatch returns the budget of the backend in the given job type, the one that matches the most job type fields
+// wins, the first one in the file when several match as many.  It returns nil if the backend has no budget.
+func (l *DisruptionBudgetList) BestMatch(backendName string, jobType platformidentification.JobType) *DisruptionBudget {
+	var best *DisruptionBudget
+	bestSpecificity := -1
+	for i := range l.Budgets {
+		matches, specificity := l.Budgets[i].matches(backendName, jobType)
+		if matches && specificity > bestSpecificity {
+			best, bestSpecificity = &l.Budgets[i], specificity
+		}
+	}
+	return best
+}
+
+func GetCurrentBudgets() *DisruptionBudgetList {
+	readBudgets.Do(
+		func() {
+			var err error
+			budgets, err = NewDisruptionBudgetList(budgetsFile)
+			if err != nil {
+				panic(err)
+			}
+		})
+
+	return budgets
+}
@@ -0,0 +1,126 @@
+package allowedbackenddisruption
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+)
+
+func TestDisruptionBudgetBestMatch(t *testing.T) {
+	budgets, err := NewDisruptionBudgetList([]byte(`
+budgets:
+- backendName: image-registry-new-connections
+  maxDisruption: 20s
+  reason: any job type
+- backendName: image-registry-new-connections
+  platform: vsphere
+  maxDisruption: 10s
+  reason: vsphere
+- backendName: image-registry-new-connections
+  platform: vsphere
+  topology: single
+  expectedZero: true
+  reason: single node vsphere
+- backendName: image-registry-new-connections
+  network: ovn
+  maxDisruption: 5s
+  reason: ovn, as specific as vsphere but later in the file
+`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		backendName string
+		jobType     platformidentification.JobType
+		reason      string
+	}{
+		{
+			name:        "no budget for the backend",
+			backendName: "kube-api-new-connections",
+			jobType:     platformidentification.JobType{Platform: "aws"},
+		},
+		{
+			name:        "any job type",
+			backendName: "image-registry-new-connections",
+			jobType:     platformidentification.JobType{Platform: "aws", Network: "sdn"},
+			reason:      "any job type",
+		},
+		{
+			name:        "most specific wins",
+			backendName: "image-registry-new-connections",
+			jobType:     platformidentification.JobType{Platform: "vsphere", Topology: "single"},
+			reason:      "single node vsphere",
+		},
+		{
+			name:        "first wins when as specific",
+			backendName: "image-registry-new-connections",
+			jobType:     platformidentification.JobType{Platform: "vsphere", Network: "ovn", Topology: "ha"},
+			reason:      "vsphere",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			budget := budgets.BestMatch(test.backendName, test.jobType)
+			if len(test.reason) == 0 {
+				assert.Nil(t, budget)
+				return
+			}
+			if assert.NotNil(t, budget) {
+				assert.Equal(t, test.reason, budget.Reason)
+			}
+		})
+	}
+}
+
+func TestNewDisruptionBudgetList(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{
+			name: "neither a cap nor expected zero",
+			content: `
+budgets:
+- backendName: image-registry-new-connections
+  reason: no budget
+`,
+			err: "must specify maxDisruption, expectedZero, or both",
+		},
+		{
+			name: "no reason",
+			content: `
+budgets:
+- backendName: image-registry-new-connections
+  expectedZero: true
+`,
+			err: "must specify a reason",
+		},
+		{
+			name: "unknown field",
+			content: `
+budgets:
+- backendName: image-registry-new-connections
+  expectedZero: true
+  reason: typo
+  plaform: aws
+`,
+			err: "unknown field",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewDisruptionBudgetList([]byte(test.content))
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), test.err)
+			}
+		})
+	}
+
+	// the budgets we ship must be valid
+	_, err := NewDisruptionBudgetList(budgetsFile)
+	assert.NoError(t, err)
+}
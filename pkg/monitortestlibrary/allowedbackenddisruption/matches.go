@@ -3,6 +3,7 @@ package allowedbackenddisruption
 import (
 	"time"
 
+	"github.com/openshift/origin/pkg/monitortestlibrary/historicaldata"
 	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
 )
 
@@ -22,3 +23,14 @@ const (
 func GetAllowedDisruption(backendName string, jobType platformidentification.JobType) (*time.Duration, string, error) {
 	return GetCurrentResults().BestMatchP99(backendName, jobType)
 }
+
+// GetAllowedDisruptionPercentiles is like GetAllowedDisruption, but returns all the historical percentiles.
+func GetAllowedDisruptionPercentiles(backendName string, jobType platformidentification.JobType) (*historicaldata.StatisticalDuration, string, error) {
+	return GetCurrentResults().BestMatchPercentiles(backendName, jobType)
+}
+
+// GetDisruptionBudget returns the budget of the backend in the given job type from disruption_budgets.yaml, or nil if
+// it has none.
+func GetDisruptionBudget(backendName string, jobType platformidentification.JobType) *DisruptionBudget {
+	return GetCurrentBudgets().BestMatch(backendName, jobType)
+}
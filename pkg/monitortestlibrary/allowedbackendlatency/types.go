@@ -0,0 +1,45 @@
+package allowedbackendlatency
+
+import (
+	_ "embed"
+	"sync"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/historicaldata"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+)
+
+// query_results.json has the same format as the one in allowedbackenddisruption, but the percentiles are of the
+// P99 round trip latency, in seconds, that each job run observed for the backend.
+//
+//go:embed query_results.json
+var queryResults []byte
+
+var (
+	readResults    sync.Once
+	historicalData *historicaldata.DisruptionBestMatcher
+)
+
+func GetCurrentResults() *historicaldata.DisruptionBestMatcher {
+	readResults.Do(
+		func() {
+			var err error
+			historicalData, err = historicaldata.NewDisruptionMatcher(queryResults)
+			if err != nil {
+				panic(err)
+			}
+		})
+
+	return historicalData
+}
+
+// HasHistoricalData returns false until query_results.json holds the latency of at least one backend, without it
+// every backend would be skipped.
+func HasHistoricalData() bool {
+	return len(GetCurrentResults().HistoricalData) > 0
+}
+
+// GetAllowedLatencyPercentiles uses the backend and information about the cluster to choose the best historical
+// percentiles of the P99 round trip latency to operate against.
+func GetAllowedLatencyPercentiles(backendName string, jobType platformidentification.JobType) (*historicaldata.StatisticalDuration, string, error) {
+	return GetCurrentResults().BestMatchPercentiles(backendName, jobType)
+}
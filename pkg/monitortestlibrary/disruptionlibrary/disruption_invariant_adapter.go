@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/openshift/origin/pkg/monitortestlibrary/allowedbackenddisruption"
+	"github.com/openshift/origin/pkg/monitortestlibrary/historicaldata"
 	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
 
 	"github.com/openshift/origin/pkg/monitor/backenddisruption"
@@ -30,6 +31,9 @@ type Availability struct {
 
 	newConnectionDisruptionSampler    *backenddisruption.BackendSampler
 	reusedConnectionDisruptionSampler *backenddisruption.BackendSampler
+
+	// replayed is set when the samples were recorded by a prior run, which may not have sampled the backends at all.
+	replayed bool
 }
 
 func NewAvailabilityInvariant(
@@ -43,6 +47,21 @@ func NewAvailabilityInvariant(
 	}
 }
 
+// NewReplayedAvailabilityInvariant returns an Availability that evaluates the disruption a prior run recorded for
+// the backends identified by newConnectionLocator and reusedConnectionLocator.  Nothing is sampled, so only
+// EvaluateTestsFromConstructedIntervals may be called.  Backends the prior run did not sample are reported as skipped.
+func NewReplayedAvailabilityInvariant(
+	newConnectionTestName, reusedConnectionTestName string,
+	newConnectionLocator, reusedConnectionLocator monitorapi.Locator) *Availability {
+	return &Availability{
+		newConnectionTestName:             newConnectionTestName,
+		reusedConnectionTestName:          reusedConnectionTestName,
+		newConnectionDisruptionSampler:    backenddisruption.NewSimpleBackendWithLocator(newConnectionLocator, "", "", monitorapi.NewConnectionType),
+		reusedConnectionDisruptionSampler: backenddisruption.NewSimpleBackendWithLocator(reusedConnectionLocator, "", "", monitorapi.ReusedConnectionType),
+		replayed:                          true,
+	}
+}
+
 func (w *Availability) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
 	if w == nil {
 		return fmt.Errorf("unable to start collection because instance is nil")
@@ -101,48 +120,129 @@ func (w *Availability) CollectData(ctx context.Context) (monitorapi.Intervals, [
 
 func createDisruptionJunit(
 	testName string,
-	allowedDisruption *time.Duration,
+	historicalPercentiles *historicaldata.StatisticalDuration,
 	disruptionDetails string,
+	budget *allowedbackenddisruption.DisruptionBudget,
 	locator monitorapi.Locator,
 	disruptedIntervals monitorapi.Intervals,
-	jobType *platformidentification.JobType) *junitapi.JUnitTestCase {
+	jobType *platformidentification.JobType) []*junitapi.JUnitTestCase {
 
-	// Not sure what these are, but this will help find them, and we don't get any value from testing these:
-	if jobType.Platform == "" {
-		return &junitapi.JUnitTestCase{
+	// Not sure what these are, but this will help find them, and we don't get any value from testing these,
+	// unless a disruption budget says otherwise:
+	if jobType.Platform == "" && budget == nil {
+		return []*junitapi.JUnitTestCase{{
 			Name: testName,
 			SkipMessage: &junitapi.SkipMessage{
 				Message: "Unknown platform, skipping disruption testing",
 			},
-		}
+		}}
 	}
 
-	// Indicates there is no entry in the query_results.json data file, nor a valid fallback,
-	// we do not wish to run the test. (this likely implies we do not have the required number of
-	// runs in 3 weeks to do a reliable P99)
-	if allowedDisruption == nil {
-		return &junitapi.JUnitTestCase{
+	// Indicates there is no entry in the query_results.json data file, nor a valid fallback, nor a disruption budget
+	// in disruption_budgets.yaml, we do not wish to run the test. (this likely implies we do not have the required
+	// number of runs in 3 weeks to do a reliable P99)
+	if historicalPercentiles == nil && budget == nil {
+		return []*junitapi.JUnitTestCase{{
 			Name: testName,
 			SkipMessage: &junitapi.SkipMessage{
-				Message: "No historical data to calculate allowedDisruption",
+				Message: "No historical data, nor disruption budget, to calculate allowedDisruption",
 			},
-		}
+		}}
 	}
 
-	disruptionDuration := disruptedIntervals.Duration(1 * time.Second)
+	disruptionDuration := monitorapi.DisruptionDuration(disruptedIntervals)
 	roundedDisruptionDuration := disruptionDuration.Round(time.Second)
 
+	allowedDetails := []string{}
+	rankDetails := ""
+	var finalAllowedDisruption time.Duration
+	var decidedBy string
+	if historicalPercentiles != nil {
+		// how unusual this run is matters more for triage than whether it crossed the P99.
+		rankDetails = fmt.Sprintf("observed disruption %s is at %s of historical data for similar jobs (%s)",
+			roundedDisruptionDuration,
+			historicaldata.FormatPercentileRank(historicalPercentiles.PercentileRank(disruptionDuration)),
+			historicalPercentiles.PercentilesString())
+
+		var historicalDetails []string
+		finalAllowedDisruption, historicalDetails = historicalAllowedDisruptionWithGrace(historicalPercentiles.P99)
+		allowedDetails = append(allowedDetails, historicalDetails...)
+		decidedBy = "historical data, exact match"
+		if len(disruptionDetails) > 0 {
+			decidedBy = fmt.Sprintf("historical data %s", disruptionDetails)
+		}
+	}
+
+	// a budget caps the disruption, whatever the historical data says, the stricter of the two decides.
+	expectedZero := false
+	if budget != nil {
+		switch {
+		case budget.MaxDisruption != nil:
+			allowedDetails = append(allowedDetails, fmt.Sprintf("disruption budget %s caps disruption at %s", budget, budget.Allowed()))
+			if historicalPercentiles == nil || budget.Allowed() < finalAllowedDisruption {
+				finalAllowedDisruption = budget.Allowed()
+				decidedBy = fmt.Sprintf("disruption budget %s", budget)
+			}
+			// up to the cap, the disruption we expected none of flakes the test.
+			expectedZero = budget.ExpectedZero
+		case budget.ExpectedZero:
+			allowedDetails = append(allowedDetails, fmt.Sprintf("disruption budget %s expects no disruption", budget))
+			finalAllowedDisruption = 0
+			decidedBy = fmt.Sprintf("disruption budget %s", budget)
+		}
+	}
+	decisionDetails := fmt.Sprintf("allowed disruption decided by: %s", decidedBy)
+	summary := strings.Join(nonEmpty(rankDetails, decisionDetails), "\n")
+
+	if roundedDisruptionDuration <= finalAllowedDisruption && !(expectedZero && roundedDisruptionDuration > 0) {
+		return []*junitapi.JUnitTestCase{{
+			Name:      testName,
+			SystemOut: summary,
+		}}
+	}
+
+	reason := fmt.Sprintf("%v was unreachable during disruption: %v", locator.OldLocator(), disruptionDetails)
+	maxAllowed := fmt.Sprintf("maxAllowed=%s", finalAllowedDisruption)
+	if roundedDisruptionDuration <= finalAllowedDisruption {
+		maxAllowed = fmt.Sprintf("expected none, flaking up to maxAllowed=%s", finalAllowedDisruption)
+	}
+	describe := disruptedIntervals.Strings()
+	failureMessage := fmt.Sprintf("%s for at least %s (%s):\n%s\n%s\n%s\n\n%s", reason,
+		roundedDisruptionDuration, maxAllowed,
+		strings.Join(allowedDetails, "\n"),
+		summary,
+		probableCauseDetails(disruptedIntervals),
+		strings.Join(describe, "\n"))
+
+	junits := []*junitapi.JUnitTestCase{{
+		Name: testName,
+		FailureOutput: &junitapi.FailureOutput{
+			Output: failureMessage,
+		},
+		SystemOut: failureMessage,
+	}}
+	if roundedDisruptionDuration <= finalAllowedDisruption {
+		// a failure and a success of the same test is a flake
+		junits = append(junits, &junitapi.JUnitTestCase{
+			Name:      testName,
+			SystemOut: summary,
+		})
+	}
+	return junits
+}
+
+// historicalAllowedDisruptionWithGrace returns the disruption the historical P99 allows, and how it was calculated.
+func historicalAllowedDisruptionWithGrace(p99 time.Duration) (time.Duration, []string) {
+	allowedDisruption := p99
 	// Determine what amount of disruption we're willing to tolerate before we fail the test. We previously just
 	// enforced being over a P99 over the past 3 weeks, however the P99 fluctuates wildly even under these
 	// conditions, and the tests fail excessively on very low numbers. Thus we now also allow a grace amount to try to
 	// establish this as a first line of defence to detect egregious regressions before they merge.
-	//roundedAllowedDisruption, additionalDetails := calculateAllowedDisruptionWithGrace(*allowedDisruption)
 	allowedDetails := []string{}
 	allowedDetails = append(allowedDetails, fmt.Sprintf("P99 from historical data for similar jobs over past 3 weeks: %s",
-		*allowedDisruption))
-	if *allowedDisruption < 1*time.Second {
-		t := 1 * time.Second
-		allowedDisruption = &t
+		allowedDisruption))
+	if allowedDisruption < 1*time.Second {
+		allowedDisruption = 1 * time.Second
 		allowedDetails = append(allowedDetails, "rounded P99 up to always allow one second")
 	}
 
@@ -157,37 +257,61 @@ func createDisruptionJunit(
 		allowedDetails = append(allowedDetails, "added an additional 5s of grace")
 	}
 	roundedFinal := int64(math.Round(allowedSecsWithGrace))
-	finalAllowedDisruption := time.Duration(roundedFinal) * time.Second
+	return time.Duration(roundedFinal) * time.Second, allowedDetails
+}
 
-	if roundedDisruptionDuration <= finalAllowedDisruption {
-		return &junitapi.JUnitTestCase{
-			Name: testName,
+func nonEmpty(values ...string) []string {
+	ret := []string{}
+	for _, value := range values {
+		if len(value) > 0 {
+			ret = append(ret, value)
 		}
 	}
+	return ret
+}
 
-	reason := fmt.Sprintf("%v was unreachable during disruption: %v", locator.OldLocator(), disruptionDetails)
-	describe := disruptedIntervals.Strings()
-	failureMessage := fmt.Sprintf("%s for at least %s (maxAllowed=%s):\n%s\n\n%s", reason,
-		roundedDisruptionDuration, finalAllowedDisruption,
-		strings.Join(allowedDetails, "\n"),
-		strings.Join(describe, "\n"))
+// probableCauseDetails describes what most likely caused the disruption, from the probable causes the disruption
+// intervals were annotated with.
+func probableCauseDetails(disruptedIntervals monitorapi.Intervals) string {
+	causes := []string{}
+	for _, cause := range monitorapi.DisruptionByProbableCause(disruptedIntervals) {
+		if len(cause.Cause) == 0 {
+			causes = append(causes, fmt.Sprintf("no probable cause found for %s", cause.Duration))
+			continue
+		}
+		causes = append(causes, fmt.Sprintf("%s for %s", cause.Cause, cause.Duration))
+	}
+	if len(causes) == 0 {
+		return "probable causes: unknown"
+	}
+	return fmt.Sprintf("probable causes: %s", strings.Join(causes, ", "))
+}
 
-	return &junitapi.JUnitTestCase{
+// notSampled returns a skipped test when a replayed run recorded no samples of the backend at all, rather than
+// reporting a backend that was never sampled as available throughout.
+func (w *Availability) notSampled(testName string, backend *backenddisruption.BackendSampler, finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	if !w.replayed || len(finalIntervals.Filter(monitorapi.IsEventForLocator(backend.GetLocator()))) > 0 {
+		return nil
+	}
+	return []*junitapi.JUnitTestCase{{
 		Name: testName,
-		FailureOutput: &junitapi.FailureOutput{
-			Output: failureMessage,
+		SkipMessage: &junitapi.SkipMessage{
+			Message: fmt.Sprintf("No samples of %s were recorded, skipping disruption testing", backend.GetDisruptionBackendName()),
 		},
-		SystemOut: failureMessage,
-	}
+	}}
 }
 
-func (w *Availability) junitForNewConnections(ctx context.Context, finalIntervals monitorapi.Intervals, jobType *platformidentification.JobType) (*junitapi.JUnitTestCase, error) {
+func (w *Availability) junitForNewConnections(ctx context.Context, finalIntervals monitorapi.Intervals, jobType *platformidentification.JobType) ([]*junitapi.JUnitTestCase, error) {
+	if skipped := w.notSampled(w.newConnectionTestName, w.newConnectionDisruptionSampler, finalIntervals); skipped != nil {
+		return skipped, nil
+	}
 	newConnectionAllowed, newConnectionDisruptionDetails, err := historicalAllowedDisruption(ctx, w.newConnectionDisruptionSampler, jobType)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get new allowed disruption: %w", err)
 	}
 	return createDisruptionJunit(
-			w.newConnectionTestName, newConnectionAllowed, newConnectionDisruptionDetails, w.newConnectionDisruptionSampler.GetLocator(),
+			w.newConnectionTestName, newConnectionAllowed, newConnectionDisruptionDetails,
+			disruptionBudget(w.newConnectionDisruptionSampler, jobType), w.newConnectionDisruptionSampler.GetLocator(),
 			finalIntervals.Filter(
 				monitorapi.And(
 					monitorapi.IsEventForLocator(w.newConnectionDisruptionSampler.GetLocator()),
@@ -199,13 +323,17 @@ func (w *Availability) junitForNewConnections(ctx context.Context, finalInterval
 		nil
 }
 
-func (w *Availability) junitForReusedConnections(ctx context.Context, finalIntervals monitorapi.Intervals, jobType *platformidentification.JobType) (*junitapi.JUnitTestCase, error) {
+func (w *Availability) junitForReusedConnections(ctx context.Context, finalIntervals monitorapi.Intervals, jobType *platformidentification.JobType) ([]*junitapi.JUnitTestCase, error) {
+	if skipped := w.notSampled(w.reusedConnectionTestName, w.reusedConnectionDisruptionSampler, finalIntervals); skipped != nil {
+		return skipped, nil
+	}
 	reusedConnectionAllowed, reusedConnectionDisruptionDetails, err := historicalAllowedDisruption(ctx, w.reusedConnectionDisruptionSampler, jobType)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get reused allowed disruption: %w", err)
 	}
 	return createDisruptionJunit(
-			w.reusedConnectionTestName, reusedConnectionAllowed, reusedConnectionDisruptionDetails, w.reusedConnectionDisruptionSampler.GetLocator(),
+			w.reusedConnectionTestName, reusedConnectionAllowed, reusedConnectionDisruptionDetails,
+			disruptionBudget(w.reusedConnectionDisruptionSampler, jobType), w.reusedConnectionDisruptionSampler.GetLocator(),
 			finalIntervals.Filter(
 				monitorapi.And(
 					monitorapi.IsEventForLocator(w.reusedConnectionDisruptionSampler.GetLocator()),
@@ -217,8 +345,12 @@ func (w *Availability) junitForReusedConnections(ctx context.Context, finalInter
 		nil
 }
 
-func historicalAllowedDisruption(ctx context.Context, backend *backenddisruption.BackendSampler, jobType *platformidentification.JobType) (*time.Duration, string, error) {
-	return allowedbackenddisruption.GetAllowedDisruption(backend.GetDisruptionBackendName(), *jobType)
+func historicalAllowedDisruption(ctx context.Context, backend *backenddisruption.BackendSampler, jobType *platformidentification.JobType) (*historicaldata.StatisticalDuration, string, error) {
+	return allowedbackenddisruption.GetAllowedDisruptionPercentiles(backend.GetDisruptionBackendName(), *jobType)
+}
+
+func disruptionBudget(backend *backenddisruption.BackendSampler, jobType *platformidentification.JobType) *allowedbackenddisruption.DisruptionBudget {
+	return allowedbackenddisruption.GetDisruptionBudget(backend.GetDisruptionBackendName(), *jobType)
 }
 
 func (w *Availability) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
@@ -232,15 +364,15 @@ func (w *Availability) EvaluateTestsFromConstructedIntervals(ctx context.Context
 		return nil, err
 	}
 
-	newConnectionJunit, err := w.junitForNewConnections(ctx, finalIntervals, jobType)
+	newConnectionJunits, err := w.junitForNewConnections(ctx, finalIntervals, jobType)
 	if err != nil {
 		return nil, err
 	}
 
-	reusedConnectionJunit, err := w.junitForReusedConnections(ctx, finalIntervals, jobType)
+	reusedConnectionJunits, err := w.junitForReusedConnections(ctx, finalIntervals, jobType)
 	if err != nil {
 		return nil, err
 	}
 
-	return []*junitapi.JUnitTestCase{newConnectionJunit, reusedConnectionJunit}, nil
+	return append(newConnectionJunits, reusedConnectionJunits...), nil
 }
@@ -0,0 +1,236 @@
+package disruptionlibrary
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/origin/pkg/monitor/backenddisruption"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/allowedbackenddisruption"
+	"github.com/openshift/origin/pkg/monitortestlibrary/historicaldata"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+)
+
+func TestCreateDisruptionJunit(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	locator := monitorapi.NewLocator().Disruption("image-registry-new-connections", "image-registry", "", "", "", monitorapi.NewConnectionType)
+	disrupted := func(duration time.Duration) monitorapi.Intervals {
+		if duration == 0 {
+			return nil
+		}
+		return monitorapi.Intervals{
+			monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).Locator(locator).
+				Message(monitorapi.NewMessage().Reason(monitorapi.DisruptionBeganEventReason).HumanMessage("stopped responding")).
+				Build(start, start.Add(duration)),
+		}
+	}
+	historical := &historicaldata.StatisticalDuration{P50: time.Second, P75: 2 * time.Second, P95: 5 * time.Second, P99: 10 * time.Second, JobRuns: 500}
+	budget := func(maxDisruption time.Duration, expectedZero bool) *allowedbackenddisruption.DisruptionBudget {
+		b := &allowedbackenddisruption.DisruptionBudget{BackendName: "image-registry-new-connections", ExpectedZero: expectedZero, Reason: "testing"}
+		if maxDisruption > 0 {
+			b.MaxDisruption = &metav1.Duration{Duration: maxDisruption}
+		}
+		return b
+	}
+
+	tests := []struct {
+		name              string
+		historical        *historicaldata.StatisticalDuration
+		disruptionDetails string
+		budget            *allowedbackenddisruption.DisruptionBudget
+		platform          string
+		disruption        time.Duration
+		// results is the expected outcome of every junit, pass, fail, or skip
+		results   []string
+		decidedBy string
+	}{
+		{
+			name:     "no historical data, no budget",
+			platform: "aws",
+			results:  []string{"skip"},
+		},
+		{
+			name:     "unknown platform",
+			platform: "",
+			results:  []string{"skip"},
+		},
+		{
+			name:       "historical data, exact match",
+			historical: historical,
+			platform:   "aws",
+			disruption: 14 * time.Second,
+			results:    []string{"pass"},
+			decidedBy:  "historical data, exact match",
+		},
+		{
+			name:              "historical data, fallback",
+			historical:        historical,
+			disruptionDetails: "(no exact match for 4.15, fell back to 4.14)",
+			platform:          "aws",
+			disruption:        16 * time.Second,
+			results:           []string{"fail"},
+			decidedBy:         "historical data (no exact match for 4.15, fell back to 4.14)",
+		},
+		{
+			name:       "budget without historical data",
+			budget:     budget(5*time.Second, false),
+			platform:   "vsphere",
+			disruption: 6 * time.Second,
+			results:    []string{"fail"},
+			decidedBy:  "disruption budget",
+		},
+		{
+			name:       "budget on an unknown platform",
+			budget:     budget(5*time.Second, false),
+			disruption: 4 * time.Second,
+			results:    []string{"pass"},
+			decidedBy:  "disruption budget",
+		},
+		{
+			name:       "budget stricter than historical data",
+			historical: historical,
+			budget:     budget(5*time.Second, false),
+			platform:   "aws",
+			disruption: 6 * time.Second,
+			results:    []string{"fail"},
+			decidedBy:  "disruption budget",
+		},
+		{
+			name:       "historical data stricter than budget",
+			historical: historical,
+			budget:     budget(time.Minute, false),
+			platform:   "aws",
+			disruption: 20 * time.Second,
+			results:    []string{"fail"},
+			decidedBy:  "historical data, exact match",
+		},
+		{
+			name:       "expected zero",
+			budget:     budget(0, true),
+			platform:   "aws",
+			disruption: time.Second,
+			results:    []string{"fail"},
+			decidedBy:  "disruption budget",
+		},
+		{
+			name:      "expected zero, no disruption",
+			budget:    budget(0, true),
+			platform:  "aws",
+			results:   []string{"pass"},
+			decidedBy: "disruption budget",
+		},
+		{
+			name:       "expected zero, flakes up to the cap",
+			budget:     budget(5*time.Second, true),
+			platform:   "aws",
+			disruption: 3 * time.Second,
+			results:    []string{"fail", "pass"},
+			decidedBy:  "disruption budget",
+		},
+		{
+			name:       "expected zero, fails over the cap",
+			budget:     budget(5*time.Second, true),
+			platform:   "aws",
+			disruption: 6 * time.Second,
+			results:    []string{"fail"},
+			decidedBy:  "disruption budget",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			jobType := &platformidentification.JobType{Platform: test.platform}
+			junits := createDisruptionJunit("[sig-network] disruption", test.historical, test.disruptionDetails, test.budget, locator, disrupted(test.disruption), jobType)
+
+			results := []string{}
+			for _, junit := range junits {
+				switch {
+				case junit.SkipMessage != nil:
+					results = append(results, "skip")
+				case junit.FailureOutput != nil:
+					results = append(results, "fail")
+				default:
+					results = append(results, "pass")
+				}
+				if len(test.decidedBy) > 0 && !strings.Contains(junit.SystemOut, "allowed disruption decided by: "+test.decidedBy) {
+					t.Errorf("expected the allowed disruption to be decided by %q, but got: %s", test.decidedBy, junit.SystemOut)
+				}
+			}
+			if strings.Join(test.results, ",") != strings.Join(results, ",") {
+				t.Errorf("expected %v, but got: %v", test.results, results)
+			}
+		})
+	}
+}
+
+func TestReplayedAvailability(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	newLocator := backenddisruption.APIServerBackendLocator("kube-api", monitorapi.NewConnectionType)
+	reusedLocator := backenddisruption.APIServerBackendLocator("kube-api", monitorapi.ReusedConnectionType)
+	sampled := func(locator monitorapi.Locator, disruption time.Duration) monitorapi.Intervals {
+		intervals := monitorapi.Intervals{
+			monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Info).Locator(locator).
+				Message(monitorapi.NewMessage().Reason(monitorapi.DisruptionEndedEventReason).HumanMessage("started responding")).
+				Build(start, start.Add(time.Hour)),
+		}
+		if disruption > 0 {
+			intervals = append(intervals, monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).Locator(locator).
+				Message(monitorapi.NewMessage().Reason(monitorapi.DisruptionBeganEventReason).HumanMessage("stopped responding")).
+				Build(start.Add(time.Hour), start.Add(time.Hour+disruption)))
+		}
+		return intervals
+	}
+	ctx := platformidentification.WithClusterDataOverride(context.Background(), &platformidentification.ClusterData{
+		JobType: platformidentification.JobType{Release: "4.15", Platform: "aws", Architecture: "amd64", Network: "sdn", Topology: "ha"},
+	})
+
+	tests := []struct {
+		name      string
+		intervals monitorapi.Intervals
+		// results is the expected outcome of the new and the reused connection junits, pass, fail, or skip
+		results []string
+	}{
+		{
+			name:      "not sampled",
+			intervals: nil,
+			results:   []string{"skip", "skip"},
+		},
+		{
+			name:      "only new connections sampled",
+			intervals: sampled(newLocator, 0),
+			results:   []string{"pass", "skip"},
+		},
+		{
+			name:      "disrupted",
+			intervals: append(sampled(newLocator, time.Minute), sampled(reusedLocator, 0)...),
+			results:   []string{"fail", "pass"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			availability := NewReplayedAvailabilityInvariant("new", "reused", newLocator, reusedLocator)
+			junits, err := availability.EvaluateTestsFromConstructedIntervals(ctx, test.intervals)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			results := []string{}
+			for _, junit := range junits {
+				switch {
+				case junit.SkipMessage != nil:
+					results = append(results, "skip")
+				case junit.FailureOutput != nil:
+					results = append(results, "fail")
+				default:
+					results = append(results, "pass")
+				}
+			}
+			if strings.Join(test.results, ",") != strings.Join(results, ",") {
+				t.Errorf("expected %v, but got: %v", test.results, results)
+			}
+		})
+	}
+}
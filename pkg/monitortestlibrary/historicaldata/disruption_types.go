@@ -27,6 +27,9 @@ type StatisticalDuration struct {
 	FirstObserved                  time.Time
 	LastObserved                   time.Time
 	JobRuns                        int64
+	// LowerPercentilesUnknown is set for older historical data without P50 and P75, they are zero and must not
+	// be used.
+	LowerPercentilesUnknown bool
 }
 
 type DisruptionStatisticalData struct {
@@ -38,6 +41,8 @@ type DisruptionStatisticalData struct {
 	FirstObserved time.Time
 	LastObserved  time.Time
 	JobRuns       int64
+	// LowerPercentilesUnknown is set for older query results without P50 and P75.
+	LowerPercentilesUnknown bool
 }
 
 type DataKey struct {
@@ -57,10 +62,14 @@ func NewDisruptionMatcher(historicalJSON []byte) (*DisruptionBestMatcher, error)
 	jsonDecoder := json.NewDecoder(inFile)
 
 	type DecodingPercentile struct {
-		DataKey `json:",inline"`
-		P95     string
-		P99     string
-		JobRuns int64
+		DataKey       `json:",inline"`
+		P50           string
+		P75           string
+		P95           string
+		P99           string
+		FirstObserved time.Time
+		LastObserved  time.Time
+		JobRuns       int64
 	}
 	decodingPercentilesList := []DecodingPercentile{}
 
@@ -69,6 +78,14 @@ func NewDisruptionMatcher(historicalJSON []byte) (*DisruptionBestMatcher, error)
 	}
 
 	for _, currDecoded := range decodingPercentilesList {
+		p50, err := parseOptionalPercentile(currDecoded.P50)
+		if err != nil {
+			return nil, err
+		}
+		p75, err := parseOptionalPercentile(currDecoded.P75)
+		if err != nil {
+			return nil, err
+		}
 		p95, err := strconv.ParseFloat(currDecoded.P95, 64)
 		if err != nil {
 			return nil, err
@@ -78,10 +95,15 @@ func NewDisruptionMatcher(historicalJSON []byte) (*DisruptionBestMatcher, error)
 			return nil, err
 		}
 		curr := DisruptionStatisticalData{
-			DataKey: currDecoded.DataKey,
-			P95:     p95,
-			P99:     p99,
-			JobRuns: currDecoded.JobRuns,
+			DataKey:                 currDecoded.DataKey,
+			P50:                     p50,
+			P75:                     p75,
+			P95:                     p95,
+			P99:                     p99,
+			FirstObserved:           currDecoded.FirstObserved,
+			LastObserved:            currDecoded.LastObserved,
+			JobRuns:                 currDecoded.JobRuns,
+			LowerPercentilesUnknown: len(currDecoded.P50) == 0 || len(currDecoded.P75) == 0,
 		}
 		historicalData[curr.DataKey] = curr
 	}
@@ -91,6 +113,15 @@ func NewDisruptionMatcher(historicalJSON []byte) (*DisruptionBestMatcher, error)
 	}, nil
 }
 
+// parseOptionalPercentile parses a percentile that older query results do not include, a missing one is zero and
+// the data is marked with LowerPercentilesUnknown.
+func parseOptionalPercentile(value string) (float64, error) {
+	if len(value) == 0 {
+		return 0, nil
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
 func NewDisruptionMatcherWithHistoricalData(data map[DataKey]DisruptionStatisticalData) *DisruptionBestMatcher {
 	return &DisruptionBestMatcher{
 		HistoricalData: data,
@@ -172,11 +203,20 @@ func (b *DisruptionBestMatcher) BestMatchDuration(name string, jobType platformi
 }
 
 func (b *DisruptionBestMatcher) BestMatchP99(name string, jobType platformidentification.JobType) (*time.Duration, string, error) {
+	rawData, details, err := b.BestMatchPercentiles(name, jobType)
+	if rawData == nil {
+		return nil, details, err
+	}
+	return &rawData.P99, details, err
+}
+
+// BestMatchPercentiles is like BestMatchP99, but returns every percentile so callers can rank an observed value.
+func (b *DisruptionBestMatcher) BestMatchPercentiles(name string, jobType platformidentification.JobType) (*StatisticalDuration, string, error) {
 	rawData, details, err := b.BestMatchDuration(name, jobType, defaultMinJobRuns)
 	if rawData == (StatisticalDuration{}) {
 		return nil, details, err
 	}
-	return &rawData.P99, details, err
+	return &rawData, details, err
 }
 
 func toStatisticalDuration(in DisruptionStatisticalData) StatisticalDuration {
@@ -189,6 +229,8 @@ func toStatisticalDuration(in DisruptionStatisticalData) StatisticalDuration {
 		FirstObserved: in.FirstObserved,
 		LastObserved:  in.LastObserved,
 		JobRuns:       in.JobRuns,
+
+		LowerPercentilesUnknown: in.LowerPercentilesUnknown,
 	}
 }
 
@@ -199,3 +241,52 @@ func DurationOrDie(seconds float64) time.Duration {
 	}
 	return ret
 }
+
+// PercentileRank estimates the percentile of historical job runs the observed value falls at, by linear
+// interpolation between zero, P50, P75, P95, and P99, or only zero, P95, and P99 when P50 and P75 are unknown.
+// When several percentiles share the observed value, the lowest is returned, so no disruption ranks at 0 even if
+// most runs also had none.  Anything over P99 ranks at 100 because the data does not say how far over it is.
+func (s StatisticalDuration) PercentileRank(observed time.Duration) float64 {
+	type point struct {
+		value      time.Duration
+		percentile float64
+	}
+	points := []point{{value: 0, percentile: 0}}
+	if !s.LowerPercentilesUnknown {
+		points = append(points, point{value: s.P50, percentile: 50}, point{value: s.P75, percentile: 75})
+	}
+	points = append(points, point{value: s.P95, percentile: 95}, point{value: s.P99, percentile: 99})
+
+	if observed > s.P99 {
+		return 100
+	}
+	for i := 1; i < len(points); i++ {
+		lower, upper := points[i-1], points[i]
+		if observed > upper.value {
+			continue
+		}
+		if observed <= lower.value || upper.value == lower.value {
+			return lower.percentile
+		}
+		fraction := float64(observed-lower.value) / float64(upper.value-lower.value)
+		return lower.percentile + fraction*(upper.percentile-lower.percentile)
+	}
+	return 100
+}
+
+// PercentilesString renders the percentiles and job runs for junit output, unknown percentiles are marked as such.
+func (s StatisticalDuration) PercentilesString() string {
+	p50, p75 := s.P50.String(), s.P75.String()
+	if s.LowerPercentilesUnknown {
+		p50, p75 = "unknown", "unknown"
+	}
+	return fmt.Sprintf("P50=%s, P75=%s, P95=%s, P99=%s, JobRuns=%d", p50, p75, s.P95, s.P99, s.JobRuns)
+}
+
+// FormatPercentileRank renders a rank from PercentileRank, for instance P97.3 or >P99.
+func FormatPercentileRank(rank float64) string {
+	if rank >= 100 {
+		return ">P99"
+	}
+	return fmt.Sprintf("P%.1f", rank)
+}
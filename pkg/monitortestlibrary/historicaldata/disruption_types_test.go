@@ -0,0 +1,109 @@
+package historicaldata
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDisruptionMatcherDecodesAllPercentiles(t *testing.T) {
+	historicalJSON := []byte(`[
+  {
+    "BackendName": "kube-api-new-connections",
+    "Release": "4.16",
+    "FromRelease": "4.15",
+    "Platform": "aws",
+    "Architecture": "amd64",
+    "Network": "ovn",
+    "Topology": "ha",
+    "JobRuns": 463,
+    "P50": "1.5",
+    "P75": "2.25",
+    "P95": "4.0",
+    "P99": "8.0",
+    "FirstObserved": "2024-01-01T00:00:00Z",
+    "LastObserved": "2024-01-21T00:00:00Z"
+  },
+  {
+    "BackendName": "kube-api-reused-connections",
+    "Release": "4.16",
+    "FromRelease": "4.15",
+    "Platform": "aws",
+    "Architecture": "amd64",
+    "Network": "ovn",
+    "Topology": "ha",
+    "JobRuns": 463,
+    "P95": "4.0",
+    "P99": "8.0"
+  }
+]`)
+	matcher, err := NewDisruptionMatcher(historicalJSON)
+	require.NoError(t, err)
+
+	jobType := platformidentification.JobType{
+		Release:      "4.16",
+		FromRelease:  "4.15",
+		Platform:     "aws",
+		Architecture: "amd64",
+		Network:      "ovn",
+		Topology:     "ha",
+	}
+	percentiles, _, err := matcher.BestMatchDuration("kube-api-new-connections", jobType, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1500*time.Millisecond, percentiles.P50)
+	assert.Equal(t, 2250*time.Millisecond, percentiles.P75)
+	assert.Equal(t, 4*time.Second, percentiles.P95)
+	assert.Equal(t, 8*time.Second, percentiles.P99)
+	assert.False(t, percentiles.LowerPercentilesUnknown)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), percentiles.FirstObserved)
+	assert.Equal(t, time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC), percentiles.LastObserved)
+
+	// older query results only carry P95 and P99.
+	percentiles, _, err = matcher.BestMatchDuration("kube-api-reused-connections", jobType, 1)
+	require.NoError(t, err)
+	assert.True(t, percentiles.LowerPercentilesUnknown, "missing percentiles are unknown, not zero")
+	assert.Equal(t, 8*time.Second, percentiles.P99)
+	assert.Equal(t, "P50=unknown, P75=unknown, P95=4s, P99=8s, JobRuns=463", percentiles.PercentilesString())
+}
+
+func TestPercentileRank(t *testing.T) {
+	percentiles := StatisticalDuration{
+		P50: 2 * time.Second,
+		P75: 4 * time.Second,
+		P95: 8 * time.Second,
+		P99: 10 * time.Second,
+	}
+	tests := []struct {
+		observed time.Duration
+		expected float64
+	}{
+		{observed: 0, expected: 0},
+		{observed: 1 * time.Second, expected: 25},
+		{observed: 2 * time.Second, expected: 50},
+		{observed: 3 * time.Second, expected: 62.5},
+		{observed: 6 * time.Second, expected: 85},
+		{observed: 9 * time.Second, expected: 97},
+		{observed: 10 * time.Second, expected: 99},
+		{observed: 11 * time.Second, expected: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.observed.String(), func(t *testing.T) {
+			assert.InDelta(t, tt.expected, percentiles.PercentileRank(tt.observed), 0.001)
+		})
+	}
+
+	mostlyUndisrupted := StatisticalDuration{P95: 2 * time.Second, P99: 6 * time.Second}
+	assert.Equal(t, float64(0), mostlyUndisrupted.PercentileRank(0), "no disruption is never unusual")
+	assert.InDelta(t, 85, mostlyUndisrupted.PercentileRank(time.Second), 0.001)
+
+	// older data without P50 and P75 only interpolates between zero, P95, and P99.
+	unknownLowerPercentiles := StatisticalDuration{P95: 2 * time.Second, P99: 6 * time.Second, LowerPercentilesUnknown: true}
+	assert.InDelta(t, 47.5, unknownLowerPercentiles.PercentileRank(time.Second), 0.001)
+	assert.InDelta(t, 97, unknownLowerPercentiles.PercentileRank(4*time.Second), 0.001)
+
+	assert.Equal(t, "P97.0", FormatPercentileRank(97))
+	assert.Equal(t, ">P99", FormatPercentileRank(100))
+}
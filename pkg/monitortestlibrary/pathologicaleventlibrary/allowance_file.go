@@ -0,0 +1,129 @@
+package pathologicaleventlibrary
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	v1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"sigs.k8s.io/yaml"
+)
+
+// PathologicalEventAllowanceList is the content of an allowance file.  Allowance files may be YAML or JSON.
+type PathologicalEventAllowanceList struct {
+	Allowances []PathologicalEventAllowance `json:"allowances"`
+}
+
+// PathologicalEventAllowance is the serialized form of a SimplePathologicalEventMatcher, each field maps onto the
+// matcher field of the same name.  Regexes use go syntax.
+type PathologicalEventAllowance struct {
+	Name                    string                           `json:"name"`
+	LocatorKeyRegexes       map[monitorapi.LocatorKey]string `json:"locatorKeyRegexes,omitempty"`
+	MessageReasonRegex      string                           `json:"messageReasonRegex,omitempty"`
+	MessageHumanRegex       string                           `json:"messageHumanRegex,omitempty"`
+	Jira                    string                           `json:"jira,omitempty"`
+	RepeatThresholdOverride int                              `json:"repeatThresholdOverride,omitempty"`
+	NeverAllow              bool                             `json:"neverAllow,omitempty"`
+	Topology                *v1.TopologyMode                 `json:"topology,omitempty"`
+}
+
+// ToMatcher compiles the allowance into a matcher.
+func (a PathologicalEventAllowance) ToMatcher() (*SimplePathologicalEventMatcher, error) {
+	if len(a.Name) == 0 {
+		return nil, fmt.Errorf("must specify a name for pathological event matchers")
+	}
+	if len(a.LocatorKeyRegexes) == 0 && len(a.MessageReasonRegex) == 0 && len(a.MessageHumanRegex) == 0 {
+		return nil, fmt.Errorf("%q must specify at least one of locatorKeyRegexes, messageReasonRegex, or messageHumanRegex", a.Name)
+	}
+	if a.RepeatThresholdOverride < 0 {
+		return nil, fmt.Errorf("%q repeatThresholdOverride must not be negative", a.Name)
+	}
+
+	matcher := &SimplePathologicalEventMatcher{
+		name:                    a.Name,
+		jira:                    a.Jira,
+		repeatThresholdOverride: a.RepeatThresholdOverride,
+		neverAllow:              a.NeverAllow,
+	}
+	if len(a.LocatorKeyRegexes) > 0 {
+		matcher.locatorKeyRegexes = map[monitorapi.LocatorKey]*regexp.Regexp{}
+		for key, expr := range a.LocatorKeyRegexes {
+			r, err := regexp.Compile(expr)
+			if err != nil {
+				return nil, fmt.Errorf("%q locatorKeyRegexes[%s] is invalid: %w", a.Name, key, err)
+			}
+			matcher.locatorKeyRegexes[key] = r
+		}
+	}
+	if len(a.MessageReasonRegex) > 0 {
+		r, err := regexp.Compile(a.MessageReasonRegex)
+		if err != nil {
+			return nil, fmt.Errorf("%q messageReasonRegex is invalid: %w", a.Name, err)
+		}
+		matcher.messageReasonRegex = r
+	}
+	if len(a.MessageHumanRegex) > 0 {
+		r, err := regexp.Compile(a.MessageHumanRegex)
+		if err != nil {
+			return nil, fmt.Errorf("%q messageHumanRegex is invalid: %w", a.Name, err)
+		}
+		matcher.messageHumanRegex = r
+	}
+	if a.Topology != nil {
+		switch *a.Topology {
+		case v1.HighlyAvailableTopologyMode, v1.SingleReplicaTopologyMode, v1.ExternalTopologyMode:
+		default:
+			return nil, fmt.Errorf("%q topology %q is unknown", a.Name, *a.Topology)
+		}
+		topology := *a.Topology
+		matcher.topology = &topology
+	}
+
+	return matcher, nil
+}
+
+// ReadPathologicalEventAllowancesFromFile reads and compiles the matchers in a YAML or JSON allowance file.
+func ReadPathologicalEventAllowancesFromFile(filename string) ([]*SimplePathologicalEventMatcher, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	list := PathologicalEventAllowanceList{}
+	if err := yaml.UnmarshalStrict(data, &list); err != nil {
+		return nil, fmt.Errorf("unable to parse pathological event allowances in %s: %w", filename, err)
+	}
+
+	matchers := []*SimplePathologicalEventMatcher{}
+	for _, allowance := range list.Allowances {
+		matcher, err := allowance.ToMatcher()
+		if err != nil {
+			return nil, fmt.Errorf("invalid pathological event allowance in %s: %w", filename, err)
+		}
+		matchers = append(matchers, matcher)
+	}
+	return matchers, nil
+}
+
+// LoadPathologicalEventAllowances loads the allowance files, the matchers are meant to be passed to
+// NewUniversalPathologicalEventMatchers and NewUpgradePathologicalEventMatchers.  Names must be unique across the
+// files and the built-in matchers, a duplicate is an error rather than a panic when the registries are created.
+func LoadPathologicalEventAllowances(filenames ...string) ([]EventMatcher, error) {
+	// the upgrade registry is a superset of all the built-in matchers.
+	// AddPathologicalEventMatcher enforces unique names exactly like it does for the built-in matchers.
+	registry := NewUpgradePathologicalEventMatchers(nil, nil)
+	allowances := []EventMatcher{}
+	for _, filename := range filenames {
+		matchers, err := ReadPathologicalEventAllowancesFromFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		for _, matcher := range matchers {
+			if err := registry.AddPathologicalEventMatcher(matcher); err != nil {
+				return nil, fmt.Errorf("invalid pathological event allowance in %s: %w", filename, err)
+			}
+			allowances = append(allowances, matcher)
+		}
+	}
+	return allowances, nil
+}
@@ -0,0 +1,150 @@
+package pathologicaleventlibrary
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	v1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeAllowanceFile(t *testing.T, content string) string {
+	filename := filepath.Join(t.TempDir(), "allowances.yaml")
+	require.NoError(t, os.WriteFile(filename, []byte(content), 0644))
+	return filename
+}
+
+func TestLoadPathologicalEventAllowances(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expectedErr string
+	}{
+		{
+			name: "yaml",
+			content: `
+allowances:
+- name: MyLayerReconcileFailed
+  locatorKeyRegexes:
+    namespace: ^my-layer$
+    pod: ^noisy-
+  messageReasonRegex: ^ReconcileFailed$
+  messageHumanRegex: reconcile of my-layer failed
+  jira: https://issues.redhat.com/browse/MYLAYER-1
+  repeatThresholdOverride: 100
+  topology: SingleReplica
+`,
+		},
+		{
+			name:    "json",
+			content: `{"allowances": [{"name": "MyLayerReconcileFailed", "locatorKeyRegexes": {"namespace": "^my-layer$", "pod": "^noisy-"}, "messageReasonRegex": "^ReconcileFailed$", "repeatThresholdOverride": 100, "topology": "SingleReplica"}]}`,
+		},
+		{
+			name: "duplicate of built-in matcher",
+			content: `
+allowances:
+- name: FailedScheduling
+  messageReasonRegex: ^FailedScheduling$
+`,
+			expectedErr: `"FailedScheduling" is already registered`,
+		},
+		{
+			name: "duplicate within file",
+			content: `
+allowances:
+- name: MyLayerReconcileFailed
+  messageReasonRegex: ^ReconcileFailed$
+- name: MyLayerReconcileFailed
+  messageReasonRegex: ^ReconcileFailed$
+`,
+			expectedErr: `"MyLayerReconcileFailed" is already registered`,
+		},
+		{
+			name: "invalid regex",
+			content: `
+allowances:
+- name: MyLayerReconcileFailed
+  messageHumanRegex: "reconcile ("
+`,
+			expectedErr: `"MyLayerReconcileFailed" messageHumanRegex is invalid`,
+		},
+		{
+			name: "missing name",
+			content: `
+allowances:
+- messageReasonRegex: ^ReconcileFailed$
+`,
+			expectedErr: "must specify a name",
+		},
+		{
+			name: "matches everything",
+			content: `
+allowances:
+- name: MyLayerEverything
+  jira: https://issues.redhat.com/browse/MYLAYER-1
+`,
+			expectedErr: "must specify at least one of",
+		},
+		{
+			name: "unknown field",
+			content: `
+allowances:
+- name: MyLayerReconcileFailed
+  reasonRegex: ^ReconcileFailed$
+`,
+			expectedErr: "unknown field",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := writeAllowanceFile(t, tt.content)
+			allowances, err := LoadPathologicalEventAllowances(filename)
+			if len(tt.expectedErr) > 0 {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				assert.Empty(t, allowances, "no matchers may be loaded from an invalid file")
+				return
+			}
+			require.NoError(t, err)
+
+			registry := NewUniversalPathologicalEventMatchers(nil, nil, allowances...)
+			i := BuildTestDupeKubeEvent("my-layer", "noisy-abcde", "ReconcileFailed", "reconcile of my-layer failed", 50)
+			allowed, matcher := registry.AllowedByAny(i, v1.SingleReplicaTopologyMode)
+			require.True(t, allowed)
+			assert.Equal(t, "MyLayerReconcileFailed", matcher.Name())
+
+			allowed, _ = registry.AllowedByAny(i, v1.HighlyAvailableTopologyMode)
+			assert.False(t, allowed, "topology must be honored")
+
+			i = BuildTestDupeKubeEvent("my-layer", "noisy-abcde", "ReconcileFailed", "reconcile of my-layer failed", 101)
+			allowed, _ = registry.AllowedByAny(i, v1.SingleReplicaTopologyMode)
+			assert.False(t, allowed, "repeatThresholdOverride must be honored")
+
+			// loading the same file twice must collide instead of panicking when the registries are created.
+			_, err = LoadPathologicalEventAllowances(filename, filename)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "already registered")
+
+			_, err = NewUpgradePathologicalEventMatchers(nil, nil, allowances...).GetMatcherByName("MyLayerReconcileFailed")
+			assert.NoError(t, err, "upgrade registries must include the allowances")
+			_, err = NewUpgradePathologicalEventMatchers(nil, nil).GetMatcherByName("MyLayerReconcileFailed")
+			assert.Error(t, err, "allowances must only be added to the registries they are passed to")
+		})
+	}
+}
+
+func TestPathologicalEventAllowanceLocatorKeys(t *testing.T) {
+	allowance := PathologicalEventAllowance{
+		Name: "MyLayerReconcileFailed",
+		LocatorKeyRegexes: map[monitorapi.LocatorKey]string{
+			monitorapi.LocatorNamespaceKey: `^my-layer$`,
+		},
+	}
+	matcher, err := allowance.ToMatcher()
+	require.NoError(t, err)
+	assert.True(t, matcher.Matches(BuildTestDupeKubeEvent("my-layer", "any", "ReconcileFailed", "anything", 50)))
+	assert.False(t, matcher.Matches(BuildTestDupeKubeEvent("other-layer", "any", "ReconcileFailed", "anything", 50)))
+}
@@ -192,8 +192,8 @@ func (r *AllowedPathologicalEventRegistry) GetMatcherByName(name string) (EventM
 }
 
 // AllowedPathologicalEvents is the list of all allowed duplicate events on all jobs. Upgrade has an additional
-// list which is combined with this one.
-func NewUniversalPathologicalEventMatchers(kubeConfig *rest.Config, finalIntervals monitorapi.Intervals) *AllowedPathologicalEventRegistry {
+// list which is combined with this one.  allowances are the matchers loaded by LoadPathologicalEventAllowances.
+func NewUniversalPathologicalEventMatchers(kubeConfig *rest.Config, finalIntervals monitorapi.Intervals, allowances ...EventMatcher) *AllowedPathologicalEventRegistry {
 	registry := &AllowedPathologicalEventRegistry{matchers: map[string]EventMatcher{}}
 
 	// [sig-apps] StatefulSet Basic StatefulSet functionality [StatefulSetBasic] should not deadlock when a pod's predecessor fails [Suite:openshift/conformance/parallel] [Suite:k8s]
@@ -478,15 +478,20 @@ func NewUniversalPathologicalEventMatchers(kubeConfig *rest.Config, finalInterva
 	singleNodeConnectionRefusedMatcher := newSingleNodeConnectionRefusedEventMatcher(finalIntervals)
 	registry.AddPathologicalEventMatcherOrDie(singleNodeConnectionRefusedMatcher)
 
+	// Matchers loaded from allowance files, LoadPathologicalEventAllowances makes sure their names are unique.
+	for _, matcher := range allowances {
+		registry.AddPathologicalEventMatcherOrDie(matcher)
+	}
+
 	return registry
 }
 
 // NewUpgradePathologicalEventMatchers creates the registry for allowed events during upgrade.
 // Contains everything in the universal set as well.
-func NewUpgradePathologicalEventMatchers(kubeConfig *rest.Config, finalIntervals monitorapi.Intervals) *AllowedPathologicalEventRegistry {
+func NewUpgradePathologicalEventMatchers(kubeConfig *rest.Config, finalIntervals monitorapi.Intervals, allowances ...EventMatcher) *AllowedPathologicalEventRegistry {
 
 	// Start with the main list of matchers:
-	registry := NewUniversalPathologicalEventMatchers(kubeConfig, finalIntervals)
+	registry := NewUniversalPathologicalEventMatchers(kubeConfig, finalIntervals, allowances...)
 
 	// Now add in the matchers we only want to apply during upgrade:
 
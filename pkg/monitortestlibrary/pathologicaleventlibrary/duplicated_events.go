@@ -21,14 +21,14 @@ import (
 	"k8s.io/client-go/rest"
 )
 
-func TestDuplicatedEventForUpgrade(events monitorapi.Intervals, kubeClientConfig *rest.Config) []*junitapi.JUnitTestCase {
-	registry := NewUpgradePathologicalEventMatchers(kubeClientConfig, events)
+func TestDuplicatedEventForUpgrade(ctx context.Context, events monitorapi.Intervals, kubeClientConfig *rest.Config, allowances ...EventMatcher) []*junitapi.JUnitTestCase {
+	registry := NewUpgradePathologicalEventMatchers(kubeClientConfig, events, allowances...)
 
 	evaluator := duplicateEventsEvaluator{
 		registry: registry,
 	}
 
-	platform, topology, err := GetClusterInfraInfo(kubeClientConfig)
+	platform, topology, err := GetClusterInfraInfo(ctx, kubeClientConfig)
 	if err != nil {
 		logrus.WithError(err).Error("could not fetch cluster infra info")
 	} else {
@@ -43,14 +43,14 @@ func TestDuplicatedEventForUpgrade(events monitorapi.Intervals, kubeClientConfig
 	return tests
 }
 
-func TestDuplicatedEventForStableSystem(events monitorapi.Intervals, clientConfig *rest.Config) []*junitapi.JUnitTestCase {
-	registry := NewUniversalPathologicalEventMatchers(clientConfig, events)
+func TestDuplicatedEventForStableSystem(ctx context.Context, events monitorapi.Intervals, clientConfig *rest.Config, allowances ...EventMatcher) []*junitapi.JUnitTestCase {
+	registry := NewUniversalPathologicalEventMatchers(clientConfig, events, allowances...)
 
 	evaluator := duplicateEventsEvaluator{
 		registry: registry,
 	}
 
-	platform, topology, err := GetClusterInfraInfo(clientConfig)
+	platform, topology, err := GetClusterInfraInfo(ctx, clientConfig)
 	if err != nil {
 		logrus.WithError(err).Error("could not fetch cluster infra info")
 	} else {
@@ -264,7 +264,10 @@ func GetTimesAnEventHappened(msg monitorapi.Message) int {
 	return int(times)
 }
 
-func GetClusterInfraInfo(c *rest.Config) (platform v1.PlatformType, topology v1.TopologyMode, err error) {
+func GetClusterInfraInfo(ctx context.Context, c *rest.Config) (platform v1.PlatformType, topology v1.TopologyMode, err error) {
+	if platform, topology, ok := platformidentification.GetClusterInfraOverride(ctx); ok {
+		return platform, topology, nil
+	}
 	if c == nil {
 		return
 	}
@@ -273,7 +276,7 @@ func GetClusterInfraInfo(c *rest.Config) (platform v1.PlatformType, topology v1.
 	if err != nil {
 		return "", "", err
 	}
-	infra, err := oc.ConfigV1().Infrastructures().Get(context.Background(), "cluster", metav1.GetOptions{})
+	infra, err := oc.ConfigV1().Infrastructures().Get(ctx, "cluster", metav1.GetOptions{})
 	if err != nil {
 		return "", "", err
 	}
@@ -0,0 +1,95 @@
+package platformidentification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	"github.com/openshift/origin/pkg/monitortestframework"
+)
+
+type clusterDataOverrideKey struct{}
+
+// WithClusterDataOverride returns a context that makes GetJobType, BuildClusterData and GetClusterInfraOverride
+// return clusterData instead of querying a cluster.  It is used when monitor tests are evaluated against the
+// artifacts of a prior run, where there is no cluster to ask.
+func WithClusterDataOverride(ctx context.Context, clusterData *ClusterData) context.Context {
+	clusterDataCopy := *clusterData
+	clusterDataCopy.ClusterVersionHistory = append([]string{}, clusterData.ClusterVersionHistory...)
+	return context.WithValue(ctx, clusterDataOverrideKey{}, &clusterDataCopy)
+}
+
+// getClusterDataOverride returns a copy of the override in ctx, or nil if none is set.
+func getClusterDataOverride(ctx context.Context) *ClusterData {
+	clusterDataOverride, ok := ctx.Value(clusterDataOverrideKey{}).(*ClusterData)
+	if !ok {
+		return nil
+	}
+	clusterDataCopy := *clusterDataOverride
+	clusterDataCopy.ClusterVersionHistory = append([]string{}, clusterDataOverride.ClusterVersionHistory...)
+	return &clusterDataCopy
+}
+
+// RequireClusterDataOverride returns a NotSupportedError unless ctx overrides the cluster data.  Monitor tests that
+// need the job type call it from PrepareForReplay, since there is no cluster to determine it from.
+func RequireClusterDataOverride(ctx context.Context) error {
+	if _, ok := ctx.Value(clusterDataOverrideKey{}).(*ClusterData); !ok {
+		return &monitortestframework.NotSupportedError{Reason: "no cluster data to determine the job type from"}
+	}
+	return nil
+}
+
+// GetClusterInfraOverride returns the platform and control plane topology of the cluster data overridden in ctx,
+// in the form reported by the Infrastructure resource.  ok is false when no override is set.
+func GetClusterInfraOverride(ctx context.Context) (platform configv1.PlatformType, topology configv1.TopologyMode, ok bool) {
+	clusterData := getClusterDataOverride(ctx)
+	if clusterData == nil {
+		return "", "", false
+	}
+
+	switch clusterData.Platform {
+	case "aws":
+		platform = configv1.AWSPlatformType
+	case "gcp":
+		platform = configv1.GCPPlatformType
+	case "azure":
+		platform = configv1.AzurePlatformType
+	case "vsphere":
+		platform = configv1.VSpherePlatformType
+	case "metal":
+		platform = configv1.BareMetalPlatformType
+	case "ovirt":
+		platform = configv1.OvirtPlatformType
+	case "openstack":
+		platform = configv1.OpenStackPlatformType
+	case "libvirt":
+		platform = configv1.LibvirtPlatformType
+	}
+
+	switch clusterData.Topology {
+	case "ha":
+		topology = configv1.HighlyAvailableTopologyMode
+	case "single":
+		topology = configv1.SingleReplicaTopologyMode
+	case "external":
+		topology = configv1.ExternalTopologyMode
+	}
+
+	return platform, topology, true
+}
+
+// ReadClusterDataFromFile reads the cluster-data_<timestamp>.json file written at the end of a run.
+func ReadClusterDataFromFile(filename string) (*ClusterData, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	clusterData := &ClusterData{}
+	if err := json.Unmarshal(data, clusterData); err != nil {
+		return nil, fmt.Errorf("unable to parse cluster data from %s: %w", filename, err)
+	}
+	return clusterData, nil
+}
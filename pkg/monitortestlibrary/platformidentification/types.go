@@ -57,6 +57,9 @@ func CloneJobType(in JobType) JobType {
 }
 
 func BuildClusterData(ctx context.Context, clientConfig *rest.Config) (ClusterData, *[]error) {
+	if clusterData := getClusterDataOverride(ctx); clusterData != nil {
+		return *clusterData, nil
+	}
 
 	errors := make([]error, 0)
 
@@ -150,6 +153,10 @@ func getClusterVersions(versions *configv1.ClusterVersionList) []string {
 
 // GetJobType returns information that can be used to identify a job
 func GetJobType(ctx context.Context, clientConfig *rest.Config) (*JobType, error) {
+	if clusterData := getClusterDataOverride(ctx); clusterData != nil {
+		jobType := CloneJobType(clusterData.JobType)
+		return &jobType, nil
+	}
 	configClient, err := configclient.NewForConfig(clientConfig)
 	if err != nil {
 		return nil, err
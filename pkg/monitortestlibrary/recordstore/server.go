@@ -0,0 +1,82 @@
+package recordstore
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	// RecordsPath lists all the records on GET, RecordsPath/<id> reads a record
+	// on GET, and writes it on PUT, with the value as the body.
+	RecordsPath = "/records"
+	// ReadyzPath fails once the server is shutting down.
+	ReadyzPath = "/readyz"
+
+	maxValueLength = 4096
+)
+
+// NewHandler returns the handler serving the records of the given store.
+// A missing record is not reported with a 404, it could not be told apart
+// from a 404 of a proxy on the way, the returned Record is not Found instead.
+func NewHandler(store *Store, shuttingDown *atomic.Bool) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(ReadyzPath, func(w http.ResponseWriter, r *http.Request) {
+		if shuttingDown.Load() {
+			http.Error(w, "shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc(RecordsPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		writeJSON(w, store.List())
+	})
+	mux.HandleFunc(RecordsPath+"/", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseUint(strings.TrimPrefix(r.URL.Path, RecordsPath+"/"), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid record id", http.StatusBadRequest)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			value, found := store.Get(id)
+			writeJSON(w, Record{ID: id, Value: value, Found: found})
+		case http.MethodPut:
+			value, err := io.ReadAll(io.LimitReader(r.Body, maxValueLength+1))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if len(value) == 0 || len(value) > maxValueLength {
+				http.Error(w, "the value must not be empty or longer than 4096 bytes", http.StatusBadRequest)
+				return
+			}
+			if err := store.Put(id, string(value)); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	content, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(content)
+}
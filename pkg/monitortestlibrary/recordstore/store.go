@@ -0,0 +1,142 @@
+package recordstore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const logFileName = "records.jsonl"
+
+// Record is a record of the store, it is also what the server returns for
+// a single record, Found is false if the server does not have the record.
+type Record struct {
+	ID    uint64 `json:"id"`
+	Value string `json:"value,omitempty"`
+	Found bool   `json:"found,omitempty"`
+}
+
+// Store keeps the records in memory, and appends every write to a log in the
+// data directory, a write returns only once the log has been synced, so an
+// acknowledged record survives the process and the node as long as the data
+// directory does.
+type Store struct {
+	lock    sync.Mutex
+	file    *os.File
+	records map[uint64]string
+	skipped int
+}
+
+// Open loads the records logged in the given data directory.  A torn write
+// at the end of the log, one that was never acknowledged, is discarded.  A
+// complete line that can not be parsed is skipped and counted, the records
+// after it are kept, see Skipped.
+func Open(dataDir string) (*Store, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(filepath.Join(dataDir, logFileName), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	records, valid, skipped, err := load(file)
+	if err == nil {
+		err = file.Truncate(valid)
+	}
+	if err == nil {
+		_, err = file.Seek(valid, io.SeekStart)
+	}
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to load %s: %w", file.Name(), err)
+	}
+	return &Store{file: file, records: records, skipped: skipped}, nil
+}
+
+// load returns the records of the log, the length of the log up to the
+// last complete line, and the number of complete lines that could not be
+// parsed.
+func load(r io.Reader) (map[uint64]string, int64, int, error) {
+	records := map[uint64]string{}
+	reader := bufio.NewReader(r)
+	var valid int64
+	skipped := 0
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// only an incomplete last line is a torn write
+			return records, valid, skipped, nil
+		}
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		valid += int64(len(line))
+		record := Record{}
+		if err := json.Unmarshal(bytes.TrimSpace(line), &record); err != nil {
+			// the line was written in full, it was corrupted afterwards,
+			// the record is lost but the ones after it are not.
+			skipped++
+			continue
+		}
+		records[record.ID] = record.Value
+	}
+}
+
+// Skipped returns the number of complete lines of the log that could not
+// be parsed when the store was opened.
+func (s *Store) Skipped() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.skipped
+}
+
+// Put durably stores the record.
+func (s *Store) Put(id uint64, value string) error {
+	line, err := json.Marshal(Record{ID: id, Value: value})
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if err := s.file.Sync(); err != nil {
+		return err
+	}
+	s.records[id] = value
+	return nil
+}
+
+// Get returns the value of the record, and whether the store has it.
+func (s *Store) Get(id uint64) (string, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	value, ok := s.records[id]
+	return value, ok
+}
+
+// List returns a copy of all the records.
+func (s *Store) List() map[uint64]string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	ret := make(map[uint64]string, len(s.records))
+	for id, value := range s.records {
+		ret[id] = value
+	}
+	return ret
+}
+
+func (s *Store) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.file.Close()
+}
@@ -0,0 +1,153 @@
+package recordstore
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestStoreSurvivesRestart(t *testing.T) {
+	dataDir := t.TempDir()
+	store, err := Open(dataDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for id, value := range map[uint64]string{1: "one", 2: "two"} {
+		if err := store.Put(id, value); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the process died while writing the third record
+	logFile, err := os.OpenFile(filepath.Join(dataDir, logFileName), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := logFile.WriteString(`{"id":3,"val`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logFile.Close()
+
+	store, err = Open(dataDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want, got := map[uint64]string{1: "one", 2: "two"}, store.List(); !reflect.DeepEqual(want, got) {
+		t.Errorf("expected records: %v, but got: %v", want, got)
+	}
+	if err := store.Put(3, "three"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	store.Close()
+
+	store, err = Open(dataDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer store.Close()
+	if want, got := map[uint64]string{1: "one", 2: "two", 3: "three"}, store.List(); !reflect.DeepEqual(want, got) {
+		t.Errorf("expected records after the torn write was discarded: %v, but got: %v", want, got)
+	}
+}
+
+func TestStoreSkipsCorruptedRecords(t *testing.T) {
+	dataDir := t.TempDir()
+	content := `{"id":1,"value":"one"}` + "\n" + `{"id":2,"va` + "\x00\x00\n" + `{"id":3,"value":"three"}` + "\n"
+	if err := os.WriteFile(filepath.Join(dataDir, logFileName), []byte(content), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	store, err := Open(dataDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want, got := map[uint64]string{1: "one", 3: "three"}, store.List(); !reflect.DeepEqual(want, got) {
+		t.Errorf("expected the records around the corrupted one: %v, but got: %v", want, got)
+	}
+	if skipped := store.Skipped(); skipped != 1 {
+		t.Errorf("expected 1 skipped record, but got: %d", skipped)
+	}
+	if err := store.Put(4, "four"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	store.Close()
+
+	store, err = Open(dataDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer store.Close()
+	if want, got := map[uint64]string{1: "one", 3: "three", 4: "four"}, store.List(); !reflect.DeepEqual(want, got) {
+		t.Errorf("expected nothing past the corrupted record to be truncated: %v, but got: %v", want, got)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer store.Close()
+	shuttingDown := &atomic.Bool{}
+	server := httptest.NewServer(NewHandler(store, shuttingDown))
+	defer server.Close()
+
+	do := func(method, path, body string) (int, string) {
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+		content, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(content)
+	}
+
+	if code, _ := do(http.MethodPut, "/records/7", "seven"); code != http.StatusCreated {
+		t.Errorf("expected the write to be acknowledged, but got: %d", code)
+	}
+	if code, _ := do(http.MethodPut, "/records/eight", "eight"); code != http.StatusBadRequest {
+		t.Errorf("expected an invalid id to be rejected, but got: %d", code)
+	}
+
+	for id, expected := range map[string]Record{"7": {ID: 7, Value: "seven", Found: true}, "8": {ID: 8}} {
+		code, body := do(http.MethodGet, "/records/"+id, "")
+		record := Record{}
+		if err := json.Unmarshal([]byte(body), &record); code != http.StatusOK || err != nil {
+			t.Fatalf("expected record %s, but got: %d %s", id, code, body)
+		}
+		if !reflect.DeepEqual(expected, record) {
+			t.Errorf("expected record: %+v, but got: %+v", expected, record)
+		}
+	}
+
+	_, body := do(http.MethodGet, RecordsPath, "")
+	records := map[uint64]string{}
+	if err := json.Unmarshal([]byte(body), &records); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := map[uint64]string{7: "seven"}; !reflect.DeepEqual(want, records) {
+		t.Errorf("expected records: %v, but got: %v", want, records)
+	}
+
+	if code, _ := do(http.MethodGet, ReadyzPath, ""); code != http.StatusOK {
+		t.Errorf("expected the server to be ready, but got: %d", code)
+	}
+	shuttingDown.Store(true)
+	if code, _ := do(http.MethodGet, ReadyzPath, ""); code != http.StatusServiceUnavailable {
+		t.Errorf("expected the server not to be ready while shutting down, but got: %d", code)
+	}
+}
@@ -25,6 +25,12 @@ func (w *legacyMonitorTests) StartCollection(ctx context.Context, adminRESTConfi
 	return nil
 }
 
+// PrepareForReplay evaluates operator state transitions from the intervals alone.  Without a cluster, exceptions that
+// depend on the current state of the cluster are not made.
+func (w *legacyMonitorTests) PrepareForReplay(ctx context.Context) error {
+	return platformidentification.RequireClusterDataOverride(ctx)
+}
+
 func (w *legacyMonitorTests) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
 	return nil, nil, nil
 }
@@ -35,7 +41,7 @@ func (*legacyMonitorTests) ConstructComputedIntervals(ctx context.Context, start
 
 func (w *legacyMonitorTests) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
 	junits := []*junitapi.JUnitTestCase{}
-	junits = append(junits, testOperatorOSUpdateStaged(finalIntervals, w.adminRESTConfig)...)
+	junits = append(junits, testOperatorOSUpdateStaged(ctx, finalIntervals, w.adminRESTConfig)...)
 	junits = append(junits, testOperatorOSUpdateStartedEventRecorded(finalIntervals, w.adminRESTConfig)...)
 
 	isUpgrade := platformidentification.DidUpgradeHappenDuringCollection(finalIntervals, time.Time{}, time.Time{})
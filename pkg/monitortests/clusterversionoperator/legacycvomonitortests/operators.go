@@ -110,6 +110,9 @@ func testUpgradeOperatorStateTransitions(events monitorapi.Intervals, clientConf
 }
 
 func checkReplicas(namespace string, operator string, clientConfig *rest.Config) (int32, error) {
+	if clientConfig == nil {
+		return 0, fmt.Errorf("no cluster to check the replicas of %s/%s", namespace, operator)
+	}
 	kubeClient, err := kubernetes.NewForConfig(clientConfig)
 	if err != nil {
 		return 0, err
@@ -251,7 +254,7 @@ type startedStaged struct {
 	OSUpdateStaged time.Time
 }
 
-func testOperatorOSUpdateStaged(events monitorapi.Intervals, clientConfig *rest.Config) []*junitapi.JUnitTestCase {
+func testOperatorOSUpdateStaged(ctx context.Context, events monitorapi.Intervals, clientConfig *rest.Config) []*junitapi.JUnitTestCase {
 	testName := "[bz-Machine Config Operator] Nodes should reach OSUpdateStaged in a timely fashion"
 	success := &junitapi.JUnitTestCase{Name: testName}
 	flakeThreshold := 5 * time.Minute
@@ -336,7 +339,7 @@ func testOperatorOSUpdateStaged(events monitorapi.Intervals, clientConfig *rest.
 	// Make sure we flake instead of fail the test on platforms that struggle to meet these thresholds.
 	if failTest {
 		// If an error occurs getting the platform, we're just going to let the test result stand.
-		jobType, err := platformidentification2.GetJobType(context.TODO(), clientConfig)
+		jobType, err := platformidentification2.GetJobType(ctx, clientConfig)
 		if err == nil && (jobType.Platform == "ovirt" || jobType.Platform == "metal") {
 			failTest = false
 		}
@@ -22,6 +22,10 @@ func (w *operatorStateChecker) StartCollection(ctx context.Context, adminRESTCon
 	return nil
 }
 
+func (w *operatorStateChecker) PrepareForReplay(ctx context.Context) error {
+	return nil
+}
+
 func (w *operatorStateChecker) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
 	return nil, nil, nil
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/openshift/origin/pkg/monitortestframework"
 
 	"github.com/openshift/origin/pkg/monitortestlibrary/disruptionlibrary"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
 
 	routev1 "github.com/openshift/api/route/v1"
 	routeclient "github.com/openshift/client-go/route/clientset/versioned"
@@ -29,6 +30,11 @@ import (
 const (
 	newConnectionTestName    = "[sig-imageregistry] disruption/image-registry connection/new should be available throughout the test"
 	reusedConnectionTestName = "[sig-imageregistry] disruption/image-registry connection/reused should be available throughout the test"
+
+	imageRegistryNamespace = "openshift-image-registry"
+
+	// routeDeletionTimeout is how long Cleanup waits for the route to be deleted.
+	routeDeletionTimeout = 20 * time.Minute
 )
 
 type availability struct {
@@ -51,10 +57,31 @@ func NewRecordAvailabilityOnly() monitortestframework.MonitorTest {
 	}
 }
 
+// PhaseWaits reports that Cleanup waits for the route to be deleted.
+func (w *availability) PhaseWaits() map[monitortestframework.MonitorTestPhase]time.Duration {
+	return map[monitortestframework.MonitorTestPhase]time.Duration{
+		monitortestframework.PhaseCleanup: routeDeletionTimeout,
+	}
+}
+
+func (w *availability) PhaseDeadlines() monitortestframework.PhaseDeadlines {
+	return monitortestframework.PhaseDeadlines{
+		monitortestframework.PhaseCleanup: {Timeout: routeDeletionTimeout + 5*time.Minute},
+	}
+}
+
+// disruptionLocator identifies the samples of the image registry route.  The route itself is generated, so its
+// samples are located under a fixed name instead.
+func disruptionLocator(connectionType monitorapi.BackendConnectionType) monitorapi.Locator {
+	historicalBackendDisruptionDataName := fmt.Sprintf("image-registry-%s-connections", connectionType)
+	return monitorapi.NewLocator().LocateRouteForDisruptionCheck(historicalBackendDisruptionDataName, backenddisruption.OpenshiftTestsSource,
+		imageRegistryNamespace, fmt.Sprintf("test-disruption-%s", connectionType), connectionType)
+}
+
 func (w *availability) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
 	var err error
 
-	namespace := "openshift-image-registry"
+	namespace := imageRegistryNamespace
 	imageRegistryDeploymentName := "image-registry"
 
 	w.kubeClient, err = kubernetes.NewForConfig(adminRESTConfig)
@@ -91,17 +118,15 @@ func (w *availability) StartCollection(ctx context.Context, adminRESTConfig *res
 	}
 
 	baseURL := fmt.Sprintf("https://%s", w.imageRegistryRoute.Status.Ingress[0].Host)
-	historicalBackendDisruptionDataForNewConnectionsName := "image-registry-new-connections"
-	historicalBackendDisruptionDataForReusedConnectionsName := "image-registry-reused-connections"
 	path := "/healthz"
 	newConnectionDisruptionSampler := backenddisruption.NewSimpleBackendWithLocator(
-		monitorapi.NewLocator().LocateRouteForDisruptionCheck(historicalBackendDisruptionDataForNewConnectionsName, backenddisruption.OpenshiftTestsSource, namespace, "test-disruption-new", monitorapi.NewConnectionType),
+		disruptionLocator(monitorapi.NewConnectionType),
 		baseURL,
 		path,
 		monitorapi.NewConnectionType)
 
 	reusedConnectionDisruptionSampler := backenddisruption.NewSimpleBackendWithLocator(
-		monitorapi.NewLocator().LocateRouteForDisruptionCheck(historicalBackendDisruptionDataForReusedConnectionsName, backenddisruption.OpenshiftTestsSource, namespace, "test-disruption-reused", monitorapi.ReusedConnectionType),
+		disruptionLocator(monitorapi.ReusedConnectionType),
 		baseURL,
 		path,
 		monitorapi.ReusedConnectionType)
@@ -117,6 +142,20 @@ func (w *availability) StartCollection(ctx context.Context, adminRESTConfig *res
 	return nil
 }
 
+// PrepareForReplay evaluates the disruption of the image registry route.  If the replayed run did not sample it,
+// because the image registry was not present or only had a single replica, it is skipped.
+func (w *availability) PrepareForReplay(ctx context.Context) error {
+	if err := platformidentification.RequireClusterDataOverride(ctx); err != nil {
+		return err
+	}
+	w.disruptionChecker = disruptionlibrary.NewReplayedAvailabilityInvariant(
+		newConnectionTestName, reusedConnectionTestName,
+		disruptionLocator(monitorapi.NewConnectionType),
+		disruptionLocator(monitorapi.ReusedConnectionType),
+	)
+	return nil
+}
+
 func (w *availability) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
 	if w.notSupportedReason != nil {
 		return nil, nil, w.notSupportedReason
@@ -174,7 +213,7 @@ func (w *availability) Cleanup(ctx context.Context) error {
 		}
 
 		startTime := time.Now()
-		err = wait.PollUntilContextTimeout(ctx, 15*time.Second, 20*time.Minute, true, w.routeDeleted)
+		err = wait.PollUntilContextTimeout(ctx, 15*time.Second, routeDeletionTimeout, true, w.routeDeleted)
 		if err != nil {
 			return err
 		}
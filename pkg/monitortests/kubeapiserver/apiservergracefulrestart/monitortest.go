@@ -31,6 +31,10 @@ func (w *apiserverGracefulShutdownAnalyzer) StartCollection(ctx context.Context,
 	return nil
 }
 
+func (w *apiserverGracefulShutdownAnalyzer) PrepareForReplay(ctx context.Context) error {
+	return nil
+}
+
 func (w *apiserverGracefulShutdownAnalyzer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
 	return nil, nil, nil
 }
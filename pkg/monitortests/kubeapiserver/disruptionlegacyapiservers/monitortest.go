@@ -14,8 +14,10 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
+	"github.com/openshift/origin/pkg/monitor/backenddisruption"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	"github.com/openshift/origin/pkg/monitortestlibrary/disruptionlibrary"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
 )
 
@@ -221,6 +223,23 @@ func (w *availability) StartCollection(ctx context.Context, adminRESTConfig *res
 	return nil
 }
 
+// PrepareForReplay evaluates the disruption of every backend StartCollection samples.  Backends the replayed run did
+// not sample, because the openshift-apiserver or openshift-oauth-apiserver namespace was not present, are skipped.
+func (w *availability) PrepareForReplay(ctx context.Context) error {
+	if err := platformidentification.RequireClusterDataOverride(ctx); err != nil {
+		return err
+	}
+	for _, disruptionBackendName := range []string{"kube-api", "cache-kube-api", "openshift-api", "cache-openshift-api", "oauth-api", "cache-oauth-api"} {
+		newConnectionTestName, reusedConnectionTestName := testNames("sig-api-machinery", disruptionBackendName)
+		w.disruptionCheckers = append(w.disruptionCheckers, disruptionlibrary.NewReplayedAvailabilityInvariant(
+			newConnectionTestName, reusedConnectionTestName,
+			backenddisruption.APIServerBackendLocator(disruptionBackendName, monitorapi.NewConnectionType),
+			backenddisruption.APIServerBackendLocator(disruptionBackendName, monitorapi.ReusedConnectionType),
+		))
+	}
+	return nil
+}
+
 func (w *availability) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
 	if w.notSupportedReason != nil {
 		return nil, nil, w.notSupportedReason
@@ -19,6 +19,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/openshift/origin/pkg/monitortestlibrary/disruptionlibrary"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
 
 	routeclient "github.com/openshift/client-go/route/clientset/versioned"
 	"github.com/openshift/origin/pkg/monitor/backenddisruption"
@@ -57,13 +58,11 @@ func (w *availability) StartCollection(ctx context.Context, adminRESTConfig *res
 	case err != nil:
 		return err
 	default:
-		newConnectionTestName := "[sig-network-edge] ns/openshift-authentication route/oauth-openshift disruption/ingress-to-oauth-server connection/new should be available throughout the test"
-		reusedConnectionTestName := "[sig-network-edge] ns/openshift-authentication route/oauth-openshift disruption/ingress-to-oauth-server connection/reused should be available throughout the test"
 		newConnectionDisruptionSampler := createOAuthRouteAvailableWithNewConnections(adminRESTConfig)
 		reusedConnectionDisruptionSampler := createOAuthRouteAvailableWithConnectionReuse(adminRESTConfig)
 
 		disruptionChecker := disruptionlibrary.NewAvailabilityInvariant(
-			newConnectionTestName, reusedConnectionTestName,
+			oauthNewConnectionTestName, oauthReusedConnectionTestName,
 			newConnectionDisruptionSampler, reusedConnectionDisruptionSampler,
 		)
 		w.disruptionCheckers = append(w.disruptionCheckers, disruptionChecker)
@@ -87,13 +86,11 @@ func (w *availability) StartCollection(ctx context.Context, adminRESTConfig *res
 		// If the cluster does not know about the Console capability, it likely predates 4.12 and we can assume
 		// it has it by default. This is to catch possible future scenarios where we upgrade 4.11 no cap to 4.12 no cap.
 		if hasCapability(clusterVersion, "Console") {
-			newConnectionTestName := "[sig-network-edge] ns/openshift-console route/console disruption/ingress-to-console connection/new should be available throughout the test"
-			reusedConnectionTestName := "[sig-network-edge] ns/openshift-console route/console disruption/ingress-to-console connection/reused should be available throughout the test"
 			newConnectionDisruptionSampler := CreateConsoleRouteAvailableWithNewConnections(adminRESTConfig)
 			reusedConnectionDisruptionSampler := createConsoleRouteAvailableWithConnectionReuse(adminRESTConfig)
 
 			disruptionChecker := disruptionlibrary.NewAvailabilityInvariant(
-				newConnectionTestName, reusedConnectionTestName,
+				consoleNewConnectionTestName, consoleReusedConnectionTestName,
 				newConnectionDisruptionSampler, reusedConnectionDisruptionSampler,
 			)
 			w.disruptionCheckers = append(w.disruptionCheckers, disruptionChecker)
@@ -110,6 +107,27 @@ func (w *availability) StartCollection(ctx context.Context, adminRESTConfig *res
 	return nil
 }
 
+// PrepareForReplay evaluates the disruption of the oauth and console routes.  Routes the replayed run did not sample,
+// because they were not present, are skipped.
+func (w *availability) PrepareForReplay(ctx context.Context) error {
+	if err := platformidentification.RequireClusterDataOverride(ctx); err != nil {
+		return err
+	}
+	w.disruptionCheckers = append(w.disruptionCheckers,
+		disruptionlibrary.NewReplayedAvailabilityInvariant(
+			oauthNewConnectionTestName, oauthReusedConnectionTestName,
+			backenddisruption.RouteBackendLocator(oauthRouteNamespace, oauthRouteName, "ingress-to-oauth-server", monitorapi.NewConnectionType),
+			backenddisruption.RouteBackendLocator(oauthRouteNamespace, oauthRouteName, "ingress-to-oauth-server", monitorapi.ReusedConnectionType),
+		),
+		disruptionlibrary.NewReplayedAvailabilityInvariant(
+			consoleNewConnectionTestName, consoleReusedConnectionTestName,
+			backenddisruption.RouteBackendLocator("openshift-console", "console", "ingress-to-console", monitorapi.NewConnectionType),
+			backenddisruption.RouteBackendLocator("openshift-console", "console", "ingress-to-console", monitorapi.ReusedConnectionType),
+		),
+	)
+	return nil
+}
+
 func (w *availability) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
 	intervals := monitorapi.Intervals{}
 	junits := []*junitapi.JUnitTestCase{}
@@ -171,6 +189,11 @@ func (w *availability) Cleanup(ctx context.Context) error {
 const (
 	oauthRouteNamespace = "openshift-authentication"
 	oauthRouteName      = "oauth-openshift"
+
+	oauthNewConnectionTestName      = "[sig-network-edge] ns/openshift-authentication route/oauth-openshift disruption/ingress-to-oauth-server connection/new should be available throughout the test"
+	oauthReusedConnectionTestName   = "[sig-network-edge] ns/openshift-authentication route/oauth-openshift disruption/ingress-to-oauth-server connection/reused should be available throughout the test"
+	consoleNewConnectionTestName    = "[sig-network-edge] ns/openshift-console route/console disruption/ingress-to-console connection/new should be available throughout the test"
+	consoleReusedConnectionTestName = "[sig-network-edge] ns/openshift-console route/console disruption/ingress-to-console connection/reused should be available throughout the test"
 )
 
 func hasCapability(clusterVersion *configv1.ClusterVersion, desiredCapability string) bool {
@@ -52,6 +52,9 @@ var (
 	hostNetworkTargetService                 *corev1.Service
 )
 
+// namespaceDeletionTimeout is how long Cleanup waits for the namespace to be deleted.
+const namespaceDeletionTimeout = 20 * time.Minute
+
 func yamlOrDie(name string) []byte {
 	ret, err := yamls.ReadFile(name)
 	if err != nil {
@@ -90,6 +93,19 @@ func NewPodNetworkAvalibilityInvariant(info monitortestframework.MonitorTestInit
 	}
 }
 
+// PhaseWaits reports that Cleanup waits for the namespace to be deleted.
+func (pna *podNetworkAvalibility) PhaseWaits() map[monitortestframework.MonitorTestPhase]time.Duration {
+	return map[monitortestframework.MonitorTestPhase]time.Duration{
+		monitortestframework.PhaseCleanup: namespaceDeletionTimeout,
+	}
+}
+
+func (pna *podNetworkAvalibility) PhaseDeadlines() monitortestframework.PhaseDeadlines {
+	return monitortestframework.PhaseDeadlines{
+		monitortestframework.PhaseCleanup: {Timeout: namespaceDeletionTimeout + 5*time.Minute},
+	}
+}
+
 func updateDeploymentENVs(deployment *appsv1.Deployment, deploymentID, serviceClusterIP string) *appsv1.Deployment {
 	for i, env := range deployment.Spec.Template.Spec.Containers[0].Env {
 		if env.Name == "DEPLOYMENT_ID" {
@@ -366,7 +382,7 @@ func (pna *podNetworkAvalibility) Cleanup(ctx context.Context) error {
 		}
 
 		startTime := time.Now()
-		err := wait.PollUntilContextTimeout(ctx, 15*time.Second, 20*time.Minute, true, pna.namespaceDeleted)
+		err := wait.PollUntilContextTimeout(ctx, 15*time.Second, namespaceDeletionTimeout, true, pna.namespaceDeleted)
 		if err != nil {
 			return err
 		}
@@ -30,6 +30,7 @@ import (
 	"github.com/openshift/origin/pkg/monitor/backenddisruption"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	"github.com/openshift/origin/pkg/monitortestlibrary/disruptionlibrary"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
 	exutil "github.com/openshift/origin/test/extended/util"
 	"github.com/openshift/origin/test/extended/util/image"
@@ -47,6 +48,18 @@ var (
 const (
 	newConnectionTestName    = "[sig-network-edge] disruption/service-load-balancer-with-pdb connection/new should be available throughout the test"
 	reusedConnectionTestName = "[sig-network-edge] disruption/service-load-balancer-with-pdb connection/reused should be available throughout the test"
+
+	newConnectionBackendName    = "service-load-balancer-with-pdb-new-connections"
+	reusedConnectionBackendName = "service-load-balancer-with-pdb-reused-connections"
+
+	// loadBalancerCreateTimeout is the longest service.GetServiceLoadBalancerCreationTimeout, for large clusters.
+	loadBalancerCreateTimeout = time.Hour
+	// jigTimeout is how long the jig waits for the pods of the ReplicationController and the PDB to be ready.
+	jigTimeout = 6 * time.Minute
+	// reachableTimeout is how long the load balancer may take to serve requests reliably.
+	reachableTimeout = 10 * time.Minute
+	// namespaceDeletionTimeout is how long Cleanup waits for the namespace to be deleted.
+	namespaceDeletionTimeout = 20 * time.Minute
 )
 
 func init() {
@@ -72,6 +85,22 @@ func NewRecordAvailabilityOnly() monitortestframework.MonitorTest {
 	}
 }
 
+// PhaseWaits reports that StartCollection waits for the load balancer, the pods behind it and then for the load
+// balancer to serve requests, and Cleanup for the namespace to be deleted.
+func (w *availability) PhaseWaits() map[monitortestframework.MonitorTestPhase]time.Duration {
+	return map[monitortestframework.MonitorTestPhase]time.Duration{
+		monitortestframework.PhaseStartCollection: loadBalancerCreateTimeout + jigTimeout + reachableTimeout,
+		monitortestframework.PhaseCleanup:         namespaceDeletionTimeout,
+	}
+}
+
+func (w *availability) PhaseDeadlines() monitortestframework.PhaseDeadlines {
+	return monitortestframework.PhaseDeadlines{
+		monitortestframework.PhaseStartCollection: {Timeout: loadBalancerCreateTimeout + jigTimeout + reachableTimeout + 5*time.Minute},
+		monitortestframework.PhaseCleanup:         {Timeout: namespaceDeletionTimeout + 5*time.Minute},
+	}
+}
+
 func (w *availability) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
 	var err error
 
@@ -205,7 +234,7 @@ func (w *availability) StartCollection(ctx context.Context, adminRESTConfig *res
 
 	// Hit it once before considering ourselves ready
 	fmt.Fprintf(os.Stderr, "hitting pods through the service's LoadBalancer\n")
-	timeout := 10 * time.Minute
+	timeout := reachableTimeout
 	// require thirty seconds of passing requests to continue (in case the SLB becomes available and then degrades)
 	// TODO this seems weird to @deads2k, why is status not trustworthy
 	baseURL := fmt.Sprintf("http://%s", net.JoinHostPort(tcpIngressIP, strconv.Itoa(svcPort)))
@@ -217,13 +246,13 @@ func (w *availability) StartCollection(ctx context.Context, adminRESTConfig *res
 
 	newConnectionDisruptionSampler := backenddisruption.NewSimpleBackendFromOpenshiftTests(
 		baseURL,
-		"service-load-balancer-with-pdb-new-connections",
+		newConnectionBackendName,
 		path,
 		monitorapi.NewConnectionType).
 		WithExpectedBody("hello")
 	reusedConnectionDisruptionSampler := backenddisruption.NewSimpleBackendFromOpenshiftTests(
 		baseURL,
-		"service-load-balancer-with-pdb-reused-connections",
+		reusedConnectionBackendName,
 		path,
 		monitorapi.ReusedConnectionType).
 		WithExpectedBody("hello")
@@ -239,6 +268,20 @@ func (w *availability) StartCollection(ctx context.Context, adminRESTConfig *res
 	return nil
 }
 
+// PrepareForReplay evaluates the disruption of the load balancer.  If the replayed run did not sample it, because the
+// platform does not support load balancers, it is skipped.
+func (w *availability) PrepareForReplay(ctx context.Context) error {
+	if err := platformidentification.RequireClusterDataOverride(ctx); err != nil {
+		return err
+	}
+	w.disruptionChecker = disruptionlibrary.NewReplayedAvailabilityInvariant(
+		newConnectionTestName, reusedConnectionTestName,
+		monitorapi.NewLocator().LocateDisruptionCheck(newConnectionBackendName, backenddisruption.OpenshiftTestsSource, monitorapi.NewConnectionType),
+		monitorapi.NewLocator().LocateDisruptionCheck(reusedConnectionBackendName, backenddisruption.OpenshiftTestsSource, monitorapi.ReusedConnectionType),
+	)
+	return nil
+}
+
 func (w *availability) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
 	if w.notSupportedReason != nil {
 		return nil, nil, w.notSupportedReason
@@ -299,7 +342,7 @@ func (w *availability) Cleanup(ctx context.Context) error {
 
 		startTime := time.Now()
 		log.Info("waiting for namespace deletion to complete")
-		err := wait.PollUntilContextTimeout(ctx, 15*time.Second, 20*time.Minute, true, w.namespaceDeleted)
+		err := wait.PollUntilContextTimeout(ctx, 15*time.Second, namespaceDeletionTimeout, true, w.namespaceDeleted)
 		if err != nil {
 			log.WithError(err).Error("error waiting for namespace to delete")
 			return err
@@ -22,6 +22,10 @@ func (w *nodeStateAnalyzer) StartCollection(ctx context.Context, adminRESTConfig
 	return nil
 }
 
+func (w *nodeStateAnalyzer) PrepareForReplay(ctx context.Context) error {
+	return nil
+}
+
 func (w *nodeStateAnalyzer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
 	return nil, nil, nil
 }
@@ -30,6 +30,11 @@ func (w *podWatcher) StartCollection(ctx context.Context, adminRESTConfig *rest.
 	return nil
 }
 
+// the watched resources were recorded by the run being replayed.
+func (w *podWatcher) PrepareForReplay(ctx context.Context) error {
+	return nil
+}
+
 func (w *podWatcher) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
 	// because we are sharing a recorder that we're streaming into, we don't need to have a separate data collection step.
 	return nil, nil, nil
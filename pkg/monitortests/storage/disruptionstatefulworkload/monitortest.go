@@ -0,0 +1,327 @@
+package disruptionstatefulworkload
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	routev1 "github.com/openshift/api/route/v1"
+	routeclient "github.com/openshift/client-go/route/clientset/versioned"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/backenddisruption"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortests/network/disruptionpodnetwork"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	exutil "github.com/openshift/origin/test/extended/util"
+)
+
+var (
+	//go:embed *.yaml
+	yamls embed.FS
+
+	namespace   *corev1.Namespace
+	statefulSet *appsv1.StatefulSet
+	service     *corev1.Service
+	pdb         *policyv1.PodDisruptionBudget
+	route       *routev1.Route
+)
+
+const (
+	dataIntegrityTestName = "[sig-storage] stateful workload should not lose acknowledged writes throughout the test"
+
+	sampleInterval = time.Second
+	requestTimeout = 10 * time.Second
+
+	// reachableTimeout is how long the volume of the workload may take to be provisioned and attached, and its
+	// route to be admitted.
+	reachableTimeout = 10 * time.Minute
+	// readBackTimeout is how long CollectData waits for the workload to recover to read the records back.
+	readBackTimeout = 5 * time.Minute
+	// namespaceDeletionTimeout is how long Cleanup waits for the namespace, with its volume, to be deleted.
+	namespaceDeletionTimeout = 20 * time.Minute
+)
+
+func yamlOrDie(name string) []byte {
+	ret, err := yamls.ReadFile(name)
+	if err != nil {
+		panic(err)
+	}
+
+	return ret
+}
+
+func readStatefulSetV1OrDie(objBytes []byte) *appsv1.StatefulSet {
+	requiredObj, err := runtime.Decode(scheme.Codecs.UniversalDecoder(appsv1.SchemeGroupVersion), objBytes)
+	if err != nil {
+		panic(err)
+	}
+	return requiredObj.(*appsv1.StatefulSet)
+}
+
+func init() {
+	namespace = resourceread.ReadNamespaceV1OrDie(yamlOrDie("namespace.yaml"))
+	statefulSet = readStatefulSetV1OrDie(yamlOrDie("statefulset.yaml"))
+	service = resourceread.ReadServiceV1OrDie(yamlOrDie("service.yaml"))
+	pdb = resourceread.ReadPodDisruptionBudgetV1OrDie(yamlOrDie("pdb.yaml"))
+	route = resourceread.ReadRouteV1OrDie(yamlOrDie("route.yaml"))
+}
+
+// statefulWorkloadAvailability deploys a StatefulSet that stores records on a persistent volume, with a PDB, and
+// continuously writes records through its Service and reads them back, across the node drains and reboots of an
+// upgrade.  It records when the workload did not accept writes, and fails if a write it acknowledged was lost.
+// The test does not run in the cluster, it reaches the Service through a route, so the disruption of the default
+// ingress controller shows up as well.
+type statefulWorkloadAvailability struct {
+	payloadImagePullSpec string
+	notSupportedReason   error
+	namespaceName        string
+	kubeClient           kubernetes.Interface
+	routeClient          routeclient.Interface
+
+	writer     *recordWriter
+	stopWriter context.CancelFunc
+	writerDone chan struct{}
+}
+
+func NewStatefulWorkloadAvailabilityInvariant(info monitortestframework.MonitorTestInitializationInfo) monitortestframework.MonitorTest {
+	return &statefulWorkloadAvailability{
+		payloadImagePullSpec: info.UpgradeTargetPayloadImagePullSpec,
+	}
+}
+
+// PhaseWaits reports that StartCollection waits for the workload to become reachable, CollectData for it to recover
+// to read the records back, and Cleanup for the namespace to be deleted.
+func (w *statefulWorkloadAvailability) PhaseWaits() map[monitortestframework.MonitorTestPhase]time.Duration {
+	return map[monitortestframework.MonitorTestPhase]time.Duration{
+		monitortestframework.PhaseStartCollection: reachableTimeout,
+		monitortestframework.PhaseCollectData:     readBackTimeout,
+		monitortestframework.PhaseCleanup:         namespaceDeletionTimeout,
+	}
+}
+
+// PhaseDeadlines gives StartCollection the time to wait for the workload to become reachable, on top of the time it
+// takes to create it, and Cleanup the time to wait for the namespace to be deleted.
+func (w *statefulWorkloadAvailability) PhaseDeadlines() monitortestframework.PhaseDeadlines {
+	return monitortestframework.PhaseDeadlines{
+		monitortestframework.PhaseStartCollection: {Timeout: reachableTimeout + 5*time.Minute},
+		monitortestframework.PhaseCleanup:         {Timeout: namespaceDeletionTimeout + 5*time.Minute},
+	}
+}
+
+func (w *statefulWorkloadAvailability) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	var err error
+	w.kubeClient, err = kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	w.routeClient, err = routeclient.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	isMicroShift, err := exutil.IsMicroShiftCluster(w.kubeClient)
+	if err != nil {
+		return fmt.Errorf("unable to determine if cluster is MicroShift: %v", err)
+	}
+	if isMicroShift {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "platform MicroShift not supported"}
+		return w.notSupportedReason
+	}
+	hasDefault, err := w.hasDefaultStorageClass(ctx)
+	if err != nil {
+		return err
+	}
+	if !hasDefault {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "no default storage class to provision the volume of the workload"}
+		return w.notSupportedReason
+	}
+
+	openshiftTestsImagePullSpec, err := disruptionpodnetwork.GetOpenshiftTestsImagePullSpec(ctx, adminRESTConfig, w.payloadImagePullSpec)
+	if err != nil {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: fmt.Sprintf("unable to determine openshift-tests image: %v", err)}
+		return w.notSupportedReason
+	}
+
+	actualNamespace, err := w.kubeClient.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	w.namespaceName = actualNamespace.Name
+
+	workload := statefulSet.DeepCopy()
+	workload.Spec.Template.Spec.Containers[0].Image = openshiftTestsImagePullSpec
+	if _, err := w.kubeClient.AppsV1().StatefulSets(w.namespaceName).Create(ctx, workload, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	if _, err := w.kubeClient.CoreV1().Services(w.namespaceName).Create(ctx, service, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	if _, err := w.kubeClient.PolicyV1().PodDisruptionBudgets(w.namespaceName).Create(ctx, pdb, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	if _, err := w.routeClient.RouteV1().Routes(w.namespaceName).Create(ctx, route, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+
+	// the volume has to be provisioned and attached, and the route admitted, first.
+	var client recordClient
+	hostGetter := backenddisruption.NewRouteHostGetter(adminRESTConfig, w.namespaceName, route.Name)
+	err = wait.PollUntilContextTimeout(ctx, 5*time.Second, reachableTimeout, true, func(ctx context.Context) (bool, error) {
+		if client == nil {
+			host, err := hostGetter.GetHost()
+			if err != nil || len(host) == 0 {
+				logrus.WithError(err).Debug("the route of the stateful workload is not admitted yet")
+				return false, nil
+			}
+			client = newRouteRecordClient(host, requestTimeout)
+		}
+		return reachable(client)(ctx)
+	})
+	if err != nil {
+		return fmt.Errorf("stateful workload never became reachable: %w", err)
+	}
+
+	w.writer = newRecordWriter(client)
+	writerCtx, cancel := context.WithCancel(context.Background())
+	w.stopWriter = cancel
+	w.writerDone = make(chan struct{})
+	go func() {
+		defer close(w.writerDone)
+		w.writer.Run(writerCtx, sampleInterval)
+	}()
+
+	return nil
+}
+
+func (w *statefulWorkloadAvailability) hasDefaultStorageClass(ctx context.Context) (bool, error) {
+	storageClasses, err := w.kubeClient.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return false, err
+	}
+	for _, storageClass := range storageClasses.Items {
+		if storageClass.Annotations["storageclass.kubernetes.io/is-default-class"] == "true" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func reachable(client recordClient) wait.ConditionWithContextFunc {
+	return func(ctx context.Context) (bool, error) {
+		if _, err := client.List(ctx); err != nil {
+			logrus.WithError(err).Debug("stateful workload is not reachable yet")
+			return false, nil
+		}
+		return true, nil
+	}
+}
+
+func (w *statefulWorkloadAvailability) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, nil, w.notSupportedReason
+	}
+	// we failed and indicated it during setup.
+	if w.writer == nil {
+		return nil, nil, nil
+	}
+
+	w.stopWriter()
+	<-w.writerDone
+	stoppedAt := time.Now()
+
+	// the workload may still be recovering from the last reboot, give it some time before checking the integrity.
+	var records map[uint64]string
+	listErr := wait.PollUntilContextTimeout(ctx, 5*time.Second, readBackTimeout, true, func(ctx context.Context) (bool, error) {
+		var err error
+		records, err = w.writer.client.List(ctx)
+		return err == nil, nil
+	})
+
+	junit := &junitapi.JUnitTestCase{Name: dataIntegrityTestName}
+	if listErr != nil {
+		junit.FailureOutput = &junitapi.FailureOutput{
+			Output: fmt.Sprintf("unable to read the records of the stateful workload back to check their integrity: %v", listErr),
+		}
+		return w.writer.Intervals(w.namespaceName, stoppedAt), []*junitapi.JUnitTestCase{junit}, nil
+	}
+
+	acknowledged := w.writer.Verify(records, time.Now())
+	lost := w.writer.Lost()
+	junit.SystemOut = fmt.Sprintf("%d acknowledged records, %d records read back, %d lost", acknowledged, len(records), len(lost))
+	if len(lost) > 0 {
+		messages := []string{}
+		for _, record := range lost {
+			messages = append(messages, fmt.Sprintf("record %d, noticed at %s: %s", record.id, record.at.UTC().Format(time.RFC3339), record.reason))
+		}
+		junit.FailureOutput = &junitapi.FailureOutput{
+			Output: fmt.Sprintf("the stateful workload lost %d of %d acknowledged records:\n%s", len(lost), acknowledged, strings.Join(messages, "\n")),
+		}
+	}
+	return w.writer.Intervals(w.namespaceName, stoppedAt), []*junitapi.JUnitTestCase{junit}, nil
+}
+
+func (w *statefulWorkloadAvailability) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, w.notSupportedReason
+}
+
+func (w *statefulWorkloadAvailability) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return nil, w.notSupportedReason
+}
+
+func (w *statefulWorkloadAvailability) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return w.notSupportedReason
+}
+
+func (w *statefulWorkloadAvailability) namespaceDeleted(ctx context.Context) (bool, error) {
+	_, err := w.kubeClient.CoreV1().Namespaces().Get(ctx, w.namespaceName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+
+	if err != nil {
+		logrus.Errorf("Error checking for deleted namespace: %s, %s", w.namespaceName, err.Error())
+		return false, err
+	}
+
+	return false, nil
+}
+
+func (w *statefulWorkloadAvailability) Cleanup(ctx context.Context) error {
+	if w.stopWriter != nil {
+		w.stopWriter()
+	}
+	if len(w.namespaceName) > 0 && w.kubeClient != nil {
+		log := logrus.WithField("monitorTest", "stateful-workload-availability").WithField("namespace", w.namespaceName)
+		log.Info("deleting namespace")
+		if err := w.kubeClient.CoreV1().Namespaces().Delete(ctx, w.namespaceName, metav1.DeleteOptions{}); err != nil {
+			log.WithError(err).Error("error during namespace deletion")
+			return err
+		}
+
+		startTime := time.Now()
+		log.Info("waiting for namespace deletion to complete")
+		err := wait.PollUntilContextTimeout(ctx, 15*time.Second, namespaceDeletionTimeout, true, w.namespaceDeleted)
+		if err != nil {
+			log.WithError(err).Error("error waiting for namespace to delete")
+			return err
+		}
+		log.Infof("namespace deleted in %.2f seconds", time.Now().Sub(startTime).Seconds())
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package disruptionstatefulworkload
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/recordstore"
+)
+
+// recordClient writes and reads back the records of the stateful workload.
+type recordClient interface {
+	Write(ctx context.Context, id uint64, value string) error
+	// Read returns the value of the record, and whether the workload has it.
+	Read(ctx context.Context, id uint64) (string, bool, error)
+	List(ctx context.Context) (map[uint64]string, error)
+}
+
+// routeRecordClient reaches the workload through a route, the test does not
+// run in the cluster, and going through the service proxy of the kube-apiserver
+// would count the disruption of the kube-apiserver as the one of the workload.
+type routeRecordClient struct {
+	// baseURL is the scheme and host of the route
+	baseURL string
+	client  *http.Client
+}
+
+func newRouteRecordClient(baseURL string, timeout time.Duration) *routeRecordClient {
+	return &routeRecordClient{
+		baseURL: baseURL,
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				// like the other route samplers, the default ingress certificate is not trusted.
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			},
+		},
+	}
+}
+
+func (c *routeRecordClient) do(ctx context.Context, method, path, body string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%s %s failed with %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(content)))
+	}
+	return content, nil
+}
+
+func (c *routeRecordClient) Write(ctx context.Context, id uint64, value string) error {
+	_, err := c.do(ctx, http.MethodPut, fmt.Sprintf("%s/%d", recordstore.RecordsPath, id), value)
+	return err
+}
+
+func (c *routeRecordClient) Read(ctx context.Context, id uint64) (string, bool, error) {
+	content, err := c.do(ctx, http.MethodGet, fmt.Sprintf("%s/%d", recordstore.RecordsPath, id), "")
+	if err != nil {
+		return "", false, err
+	}
+	record := recordstore.Record{}
+	if err := json.Unmarshal(content, &record); err != nil {
+		return "", false, fmt.Errorf("invalid record %d: %w", id, err)
+	}
+	return record.Value, record.Found, nil
+}
+
+func (c *routeRecordClient) List(ctx context.Context) (map[uint64]string, error) {
+	content, err := c.do(ctx, http.MethodGet, recordstore.RecordsPath, "")
+	if err != nil {
+		return nil, err
+	}
+	records := map[uint64]string{}
+	if err := json.Unmarshal(content, &records); err != nil {
+		return nil, fmt.Errorf("invalid records: %w", err)
+	}
+	return records, nil
+}
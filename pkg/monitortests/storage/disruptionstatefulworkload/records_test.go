@@ -0,0 +1,46 @@
+package disruptionstatefulworkload
+
+import (
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/recordstore"
+)
+
+func TestRouteRecordClient(t *testing.T) {
+	store, err := recordstore.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer store.Close()
+	// the default ingress certificate is self-signed in most clusters, like the one of the test server
+	server := httptest.NewTLSServer(recordstore.NewHandler(store, &atomic.Bool{}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := newRouteRecordClient(server.URL, 10*time.Second)
+	if err := client.Write(ctx, 1, "one"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.Write(ctx, 2, ""); err == nil {
+		t.Errorf("expected an error for a write the workload rejected")
+	}
+
+	if value, found, err := client.Read(ctx, 1); err != nil || !found || value != "one" {
+		t.Errorf("expected record 1, but got: %q %v %v", value, found, err)
+	}
+	if _, found, err := client.Read(ctx, 2); err != nil || found {
+		t.Errorf("expected record 2 not to be found, but got: %v %v", found, err)
+	}
+	records, err := client.List(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := map[uint64]string{1: "one"}; !reflect.DeepEqual(want, records) {
+		t.Errorf("expected records: %v, but got: %v", want, records)
+	}
+}
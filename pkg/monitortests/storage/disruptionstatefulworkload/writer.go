@@ -0,0 +1,210 @@
+package disruptionstatefulworkload
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+const (
+	// backendName is the name the write availability of the workload is tracked under, like any other backend.
+	backendName = "stateful-workload-writes"
+)
+
+// sample is the outcome of writing a record and reading it back, err is nil if both succeeded.
+type sample struct {
+	at  time.Time
+	err error
+}
+
+// lostRecord is an acknowledged record that could not be read back, or not with the value that was written.
+type lostRecord struct {
+	id     uint64
+	at     time.Time
+	reason string
+}
+
+// recordWriter continuously writes new records to the workload, and reads back the record it just wrote and,
+// in turn, one of the records it wrote earlier.
+type recordWriter struct {
+	client recordClient
+
+	lock     sync.Mutex
+	nextID   uint64
+	samples  []sample
+	written  map[uint64]string
+	ids      []uint64
+	cursor   int
+	lost     []lostRecord
+	lostByID map[uint64]bool
+}
+
+func newRecordWriter(client recordClient) *recordWriter {
+	return &recordWriter{
+		client:   client,
+		nextID:   1,
+		written:  map[uint64]string{},
+		lostByID: map[uint64]bool{},
+	}
+}
+
+// Run samples the workload every interval until the context is done.
+func (w *recordWriter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		w.sample(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *recordWriter) sample(ctx context.Context, now time.Time) {
+	w.lock.Lock()
+	id := w.nextID
+	w.nextID++
+	w.lock.Unlock()
+
+	value := fmt.Sprintf("record-%d-%d", id, now.UnixNano())
+	if err := w.client.Write(ctx, id, value); err != nil {
+		if ctx.Err() == nil {
+			w.record(sample{at: now, err: fmt.Errorf("write failed: %w", err)})
+		}
+		return
+	}
+	w.acknowledged(id, value)
+
+	got, found, err := w.client.Read(ctx, id)
+	if err != nil {
+		if ctx.Err() == nil {
+			w.record(sample{at: now, err: fmt.Errorf("read back failed: %w", err)})
+		}
+		return
+	}
+	w.check(id, got, found, now)
+	w.record(sample{at: now})
+
+	// a record that was lost earlier, when the workload moved to another node for instance, would otherwise only
+	// be noticed at the end.
+	if earlier, ok := w.nextToVerify(); ok {
+		if got, found, err := w.client.Read(ctx, earlier); err == nil {
+			w.check(earlier, got, found, now)
+		}
+	}
+}
+
+func (w *recordWriter) record(s sample) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.samples = append(w.samples, s)
+}
+
+func (w *recordWriter) acknowledged(id uint64, value string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.written[id] = value
+	w.ids = append(w.ids, id)
+}
+
+// nextToVerify cycles through the acknowledged records, oldest first.
+func (w *recordWriter) nextToVerify() (uint64, bool) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if len(w.ids) == 0 {
+		return 0, false
+	}
+	if w.cursor >= len(w.ids) {
+		w.cursor = 0
+	}
+	id := w.ids[w.cursor]
+	w.cursor++
+	return id, true
+}
+
+// check compares what was read back with what was acknowledged.
+func (w *recordWriter) check(id uint64, got string, found bool, at time.Time) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	want, ok := w.written[id]
+	switch {
+	case !ok || w.lostByID[id]:
+		return
+	case !found:
+		w.lostByID[id] = true
+		w.lost = append(w.lost, lostRecord{id: id, at: at, reason: "the record is missing"})
+	case got != want:
+		w.lostByID[id] = true
+		w.lost = append(w.lost, lostRecord{id: id, at: at, reason: fmt.Sprintf("expected value %q, but got %q", want, got)})
+	}
+}
+
+// Verify compares every record the workload has with every acknowledged record, it returns the number of
+// acknowledged records.
+func (w *recordWriter) Verify(records map[uint64]string, at time.Time) int {
+	w.lock.Lock()
+	ids := append([]uint64{}, w.ids...)
+	w.lock.Unlock()
+
+	for _, id := range ids {
+		got, found := records[id]
+		w.check(id, got, found, at)
+	}
+	return len(ids)
+}
+
+// Lost returns the acknowledged records that were lost, by record id.
+func (w *recordWriter) Lost() []lostRecord {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	ret := append([]lostRecord{}, w.lost...)
+	sort.Slice(ret, func(i, j int) bool { return ret[i].id < ret[j].id })
+	return ret
+}
+
+// Intervals returns the write-unavailable intervals, consecutive failed samples with the same error are one interval,
+// and one data-loss interval per lost record.
+func (w *recordWriter) Intervals(namespace string, end time.Time) monitorapi.Intervals {
+	w.lock.Lock()
+	samples := append([]sample{}, w.samples...)
+	w.lock.Unlock()
+
+	locator := monitorapi.NewLocator().DisruptionRequiredOnly(backendName, fmt.Sprintf("%s-%s", backendName, namespace))
+	ret := monitorapi.Intervals{}
+	for i := 0; i < len(samples); {
+		if samples[i].err == nil {
+			i++
+			continue
+		}
+		from := samples[i]
+		j := i + 1
+		for j < len(samples) && samples[j].err != nil && samples[j].err.Error() == from.err.Error() {
+			j++
+		}
+		to := end
+		if j < len(samples) {
+			to = samples[j].at
+		}
+		ret = append(ret, monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).
+			Locator(locator).
+			Message(monitorapi.NewMessage().Reason(monitorapi.DisruptionBeganEventReason).
+				HumanMessage(fmt.Sprintf("stateful workload stopped accepting writes: %v", from.err))).
+			Build(from.at, to))
+		i = j
+	}
+
+	for _, lost := range w.Lost() {
+		ret = append(ret, monitorapi.NewInterval(monitorapi.SourceStatefulWorkload, monitorapi.Error).
+			Locator(locator).
+			Message(monitorapi.NewMessage().Reason(monitorapi.DataLossReason).
+				HumanMessage(fmt.Sprintf("acknowledged record %d was lost: %s", lost.id, lost.reason))).
+			Build(lost.at, lost.at.Add(time.Second)))
+	}
+	return ret
+}
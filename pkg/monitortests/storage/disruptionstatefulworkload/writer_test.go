@@ -0,0 +1,122 @@
+package disruptionstatefulworkload
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// fakeRecordClient stores the records in memory, failing is the error the next requests fail with, and the
+// records in forget are dropped once written, as if they were written to a volume that was then lost.
+type fakeRecordClient struct {
+	records map[uint64]string
+	failing error
+	forget  map[uint64]bool
+}
+
+func (c *fakeRecordClient) Write(_ context.Context, id uint64, value string) error {
+	if c.failing != nil {
+		return c.failing
+	}
+	if !c.forget[id] {
+		c.records[id] = value
+	}
+	return nil
+}
+
+func (c *fakeRecordClient) Read(_ context.Context, id uint64) (string, bool, error) {
+	if c.failing != nil {
+		return "", false, c.failing
+	}
+	value, ok := c.records[id]
+	return value, ok, nil
+}
+
+func (c *fakeRecordClient) List(_ context.Context) (map[uint64]string, error) {
+	if c.failing != nil {
+		return nil, c.failing
+	}
+	return c.records, nil
+}
+
+func TestRecordWriter(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time {
+		return start.Add(time.Duration(seconds) * time.Second)
+	}
+	noEndpoints := fmt.Errorf("no endpoints available for service \"stateful-workload\"")
+	ctx := context.Background()
+
+	client := &fakeRecordClient{records: map[uint64]string{}, forget: map[uint64]bool{}}
+	writer := newRecordWriter(client)
+	writer.sample(ctx, at(0))
+	writer.sample(ctx, at(1))
+	// the pod is drained
+	client.failing = noEndpoints
+	writer.sample(ctx, at(2))
+	writer.sample(ctx, at(3))
+	client.failing = nil
+	writer.sample(ctx, at(4))
+	// the pod comes back without the first record
+	delete(client.records, 1)
+	writer.sample(ctx, at(5))
+	writer.sample(ctx, at(6))
+
+	if want, got := 5, writer.Verify(client.records, at(10)); want != got {
+		t.Errorf("expected %d acknowledged records, but got: %d", want, got)
+	}
+	lost := writer.Lost()
+	if want := []lostRecord{{id: 1, at: at(10), reason: "the record is missing"}}; !reflect.DeepEqual(want, lost) {
+		t.Errorf("expected lost records: %+v, but got: %+v", want, lost)
+	}
+
+	intervals := writer.Intervals("e2e-test", at(7))
+	if len(intervals) != 2 {
+		t.Fatalf("expected 2 intervals, but got: %v", intervals)
+	}
+	unavailable, dataLoss := intervals[0], intervals[1]
+	if unavailable.Source != monitorapi.SourceDisruption || !unavailable.From.Equal(at(2)) || !unavailable.To.Equal(at(4)) {
+		t.Errorf("expected the workload to be unavailable from %s to %s, but got: %v", at(2), at(4), unavailable)
+	}
+	if want, got := backendName, monitorapi.BackendDisruptionNameFromLocator(unavailable.StructuredLocator); want != got {
+		t.Errorf("expected backend: %s, but got: %s", want, got)
+	}
+	if dataLoss.Source != monitorapi.SourceStatefulWorkload || dataLoss.StructuredMessage.Reason != monitorapi.DataLossReason || !dataLoss.From.Equal(at(10)) {
+		t.Errorf("expected the loss of record 1 at %s, but got: %v", at(10), dataLoss)
+	}
+}
+
+func TestRecordWriterVerify(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	// the second write is acknowledged, but never makes it to the volume
+	client := &fakeRecordClient{records: map[uint64]string{}, forget: map[uint64]bool{2: true}}
+	writer := newRecordWriter(client)
+	for i := 0; i < 3; i++ {
+		writer.sample(ctx, start.Add(time.Duration(i)*time.Second))
+	}
+	// the read back noticed it right away, verifying again does not report it twice
+	writer.Verify(client.records, start.Add(time.Minute))
+	if lost := writer.Lost(); len(lost) != 1 || lost[0].id != 2 {
+		t.Errorf("expected record 2 to be lost once, but got: %+v", lost)
+	}
+
+	// a record the workload has, but with another value
+	client.records[3] = "corrupted"
+	writer.Verify(client.records, start.Add(time.Minute))
+	if lost := writer.Lost(); len(lost) != 2 || lost[1].id != 3 {
+		t.Errorf("expected record 3 to be lost, but got: %+v", lost)
+	}
+
+	// a workload that stays up does not lose anything
+	writer = newRecordWriter(&fakeRecordClient{records: map[uint64]string{}})
+	writer.sample(ctx, start)
+	if intervals := writer.Intervals("e2e-test", start.Add(time.Second)); len(intervals) != 0 {
+		t.Errorf("expected no intervals, but got: %v", intervals)
+	}
+}
@@ -27,6 +27,10 @@ func (w *additionalEventsCollector) StartCollection(ctx context.Context, adminRE
 	return nil
 }
 
+func (w *additionalEventsCollector) PrepareForReplay(ctx context.Context) error {
+	return nil
+}
+
 func (w *additionalEventsCollector) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
 	var err error
 	additionIntervals := monitorapi.Intervals{}
@@ -0,0 +1,185 @@
+package disruptioncauseanalyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+type CauseType string
+
+const (
+	CauseAPIServerGracefulShutdown CauseType = "APIServerGracefulShutdown"
+	CauseNodeReboot                CauseType = "NodeReboot"
+	CauseNodeNotReady              CauseType = "NodeNotReady"
+	CauseEtcdLeaderChange          CauseType = "EtcdLeaderChange"
+	CauseOperatorUnavailable       CauseType = "OperatorUnavailable"
+	CauseOperatorDegraded          CauseType = "OperatorDegraded"
+	CauseKubeEvent                 CauseType = "KubeEvent"
+)
+
+const (
+	// nearbyWindow is how long before a disruption a candidate cause that ended may still explain it.
+	nearbyWindow = time.Minute
+	// maxProbableCauses is the number of probable causes a disruption interval is annotated with.
+	maxProbableCauses = 3
+)
+
+// causeWeights reflect how likely each type of cause is to take a backend down, a graceful shutdown of an apiserver
+// directly drops connections while a warning event is at best circumstantial.
+var causeWeights = map[CauseType]float64{
+	CauseAPIServerGracefulShutdown: 1.0,
+	CauseNodeReboot:                0.9,
+	CauseNodeNotReady:              0.8,
+	CauseEtcdLeaderChange:          0.7,
+	CauseOperatorUnavailable:       0.5,
+	CauseOperatorDegraded:          0.4,
+	CauseKubeEvent:                 0.3,
+}
+
+// candidateCause is an interval that may explain a disruption.
+type candidateCause struct {
+	causeType CauseType
+	// subject is what the cause happened to, a node or an operator for instance, it may be empty.
+	subject  string
+	from, to time.Time
+}
+
+func (c candidateCause) String() string {
+	if len(c.subject) == 0 {
+		return string(c.causeType)
+	}
+	return fmt.Sprintf("%s:%s", c.causeType, c.subject)
+}
+
+// rankedCause is a candidate cause with how likely it is to explain a given disruption.
+type rankedCause struct {
+	cause string
+	score float64
+}
+
+// candidateCauses returns the intervals that may explain a disruption.
+func candidateCauses(intervals monitorapi.Intervals, end time.Time) []candidateCause {
+	ret := []candidateCause{}
+	for _, interval := range intervals {
+		candidate := candidateCause{from: interval.From, to: interval.To}
+		// intervals that were never closed last until the end of the run.
+		if candidate.to.IsZero() {
+			candidate.to = end
+		}
+		locator := interval.StructuredLocator.Keys
+		annotations := interval.StructuredMessage.Annotations
+
+		switch {
+		case interval.Source == monitorapi.APIServerGracefulShutdown, interval.Source == monitorapi.SourceAPIServerShutdown:
+			candidate.causeType = CauseAPIServerGracefulShutdown
+			candidate.subject = locator[monitorapi.LocatorNodeKey]
+			if len(candidate.subject) == 0 {
+				candidate.subject = locator[monitorapi.LocatorServerKey]
+			}
+
+		case interval.Source == monitorapi.SourceNodeState && interval.StructuredMessage.Reason == monitorapi.NodeNotReadyReason:
+			candidate.causeType = CauseNodeNotReady
+			candidate.subject = locator[monitorapi.LocatorNodeKey]
+
+		case interval.Source == monitorapi.SourceNodeState && annotations[monitorapi.AnnotationPhase] == "Reboot":
+			candidate.causeType = CauseNodeReboot
+			candidate.subject = locator[monitorapi.LocatorNodeKey]
+
+		case interval.Source == monitorapi.SourceEtcdLeadership:
+			// an interval lasts as long as a leader, the leader changed to this member when it began.
+			candidate.causeType = CauseEtcdLeaderChange
+			candidate.subject = locator[monitorapi.LocatorEtcdMemberKey]
+			candidate.to = candidate.from
+
+		case interval.Source == monitorapi.SourceOperatorState && interval.Level == monitorapi.Error:
+			candidate.causeType = CauseOperatorDegraded
+			if annotations[monitorapi.AnnotationCondition] == "Available" {
+				candidate.causeType = CauseOperatorUnavailable
+			}
+			candidate.subject = locator[monitorapi.LocatorClusterOperatorKey]
+
+		case interval.Source == monitorapi.SourceKubeEvent && interval.Level >= monitorapi.Warning:
+			candidate.causeType = CauseKubeEvent
+			candidate.subject = string(interval.StructuredMessage.Reason)
+
+		default:
+			continue
+		}
+		ret = append(ret, candidate)
+	}
+	return ret
+}
+
+// proximity is how close a candidate cause is to a disruption: 1 when it began before the disruption and was still
+// going on when the disruption began, less when it began during the disruption, and less still, down to zero at
+// nearbyWindow, when it ended before the disruption.  A cause that began after the disruption ended cannot explain it.
+func proximity(candidate candidateCause, disruption monitorapi.Interval) float64 {
+	switch {
+	case candidate.from.After(disruption.To):
+		return 0
+	case !candidate.from.After(disruption.From) && !candidate.to.Before(disruption.From):
+		return 1
+	case candidate.from.After(disruption.From):
+		return 0.75
+	}
+	gap := disruption.From.Sub(candidate.to)
+	if gap >= nearbyWindow {
+		return 0
+	}
+	return 0.5 * (1 - float64(gap)/float64(nearbyWindow))
+}
+
+// rankCauses returns the probable causes of a disruption, most likely first.
+func rankCauses(candidates []candidateCause, disruption monitorapi.Interval) []rankedCause {
+	scores := map[string]float64{}
+	for _, candidate := range candidates {
+		score := causeWeights[candidate.causeType] * proximity(candidate, disruption)
+		if score <= 0 {
+			continue
+		}
+		// the same node rebooting twice around a disruption is still one cause.
+		if key := candidate.String(); score > scores[key] {
+			scores[key] = score
+		}
+	}
+
+	ret := []rankedCause{}
+	for cause, score := range scores {
+		ret = append(ret, rankedCause{cause: cause, score: score})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].score != ret[j].score {
+			return ret[i].score > ret[j].score
+		}
+		return ret[i].cause < ret[j].cause
+	})
+	if len(ret) > maxProbableCauses {
+		ret = ret[:maxProbableCauses]
+	}
+	return ret
+}
+
+// annotateProbableCauses returns the disruption interval with its probable causes, or unchanged when none were found.
+func annotateProbableCauses(candidates []candidateCause, disruption monitorapi.Interval) monitorapi.Interval {
+	ranked := rankCauses(candidates, disruption)
+	if len(ranked) == 0 {
+		return disruption
+	}
+	causes := []string{}
+	for _, cause := range ranked {
+		causes = append(causes, cause.cause)
+	}
+
+	message := monitorapi.NewMessage().
+		WithAnnotations(disruption.StructuredMessage.Annotations).
+		WithAnnotation(monitorapi.AnnotationProbableCause, strings.Join(causes, ",")).
+		HumanMessage(disruption.StructuredMessage.HumanMessage).
+		Build()
+	disruption.StructuredMessage = message
+	disruption.Message = message.OldMessage()
+	return disruption
+}
@@ -0,0 +1,134 @@
+package disruptioncauseanalyzer
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func disruptionInterval(backendName string, from, to time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).
+		Locator(monitorapi.NewLocator().Disruption(backendName, "", "", "", "", monitorapi.NewConnectionType)).
+		Message(monitorapi.NewMessage().Reason(monitorapi.DisruptionBeganEventReason).HumanMessage("stopped responding")).
+		Build(from, to)
+}
+
+func nodeInterval(nodeName string, message *monitorapi.MessageBuilder, from, to time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceNodeState, monitorapi.Info).
+		Locator(monitorapi.NewLocator().NodeFromName(nodeName)).
+		Message(message).
+		Build(from, to)
+}
+
+func TestAnnotateIntervals(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time {
+		return start.Add(time.Duration(seconds) * time.Second)
+	}
+
+	shutdown := monitorapi.NewInterval(monitorapi.APIServerGracefulShutdown, monitorapi.Info).
+		Locator(monitorapi.NewLocator().LocateServer("kube-apiserver", "master-1", "openshift-kube-apiserver", "kube-apiserver-master-1", true)).
+		Message(monitorapi.NewMessage().Reason(monitorapi.GracefulAPIServerShutdown)).
+		Build(at(100), at(170))
+	reboot := nodeInterval("master-2", monitorapi.NewMessage().Reason(monitorapi.NodeUpdateReason).WithAnnotation(monitorapi.AnnotationPhase, "Reboot"), at(300), at(400))
+	notReady := nodeInterval("master-2", monitorapi.NewMessage().Reason(monitorapi.NodeNotReadyReason), at(310), at(390))
+	leaderChange := monitorapi.NewInterval(monitorapi.SourceEtcdLeadership, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().EtcdMemberFromNames("master-0", "abc")).
+		Message(monitorapi.NewMessage().WithAnnotation(monitorapi.AnnotationEtcdLeader, "abc")).
+		Build(at(280), at(1000))
+
+	tests := []struct {
+		name       string
+		disruption monitorapi.Interval
+		causes     []string
+	}{
+		{
+			name:       "during a graceful shutdown",
+			disruption: disruptionInterval("kube-api-new-connections", at(160), at(165)),
+			causes:     []string{"APIServerGracefulShutdown:master-1"},
+		},
+		{
+			name:       "during a reboot, shortly after a leader change",
+			disruption: disruptionInterval("kube-api-new-connections", at(320), at(330)),
+			causes:     []string{"NodeReboot:master-2", "NodeNotReady:master-2", "EtcdLeaderChange:abc"},
+		},
+		{
+			name:       "nothing around",
+			disruption: disruptionInterval("kube-api-new-connections", at(600), at(605)),
+		},
+		{
+			name:       "causes after the disruption are ignored",
+			disruption: disruptionInterval("kube-api-new-connections", at(90), at(95)),
+		},
+	}
+
+	intervals := monitorapi.Intervals{shutdown, reboot, notReady, leaderChange}
+	for _, test := range tests {
+		intervals = append(intervals, test.disruption)
+	}
+	annotate, err := NewDisruptionCauseAnalyzer().(*disruptionCauseAnalyzer).AnnotateIntervals(context.Background(), intervals)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			annotated := annotate(test.disruption)
+			if got := monitorapi.ProbableCauses(annotated); !reflect.DeepEqual(test.causes, got) {
+				t.Errorf("expected probable causes %v, but got: %v", test.causes, got)
+			}
+			if len(test.causes) > 0 && annotated.Message == test.disruption.Message {
+				t.Errorf("expected the probable causes in the message, but got: %s", annotated.Message)
+			}
+			if want, got := test.disruption.StructuredMessage.Reason, annotated.StructuredMessage.Reason; want != got {
+				t.Errorf("expected reason %s to be kept, but got: %s", want, got)
+			}
+		})
+	}
+
+	// the causes are not interesting on their own
+	if annotated := annotate(reboot); !reflect.DeepEqual(reboot, annotated) {
+		t.Errorf("expected the reboot interval to be unchanged, but got: %v", annotated)
+	}
+}
+
+func TestComputeBackendCauses(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	annotated := func(backendName string, cause string, from, to time.Duration) monitorapi.Interval {
+		interval := disruptionInterval(backendName, start.Add(from), start.Add(to))
+		if len(cause) > 0 {
+			interval.StructuredMessage.Annotations[monitorapi.AnnotationProbableCause] = cause
+		}
+		return interval
+	}
+	intervals := monitorapi.Intervals{
+		annotated("kube-api-new-connections", "NodeReboot:master-2,EtcdLeaderChange:abc", 0, 5*time.Second),
+		annotated("kube-api-new-connections", "NodeReboot:master-0", 10*time.Second, 12*time.Second),
+		annotated("kube-api-new-connections", "NodeReboot:master-2", 20*time.Second, 24*time.Second),
+		annotated("kube-api-new-connections", "", 30*time.Second, 31*time.Second),
+		annotated("ingress-to-console-new-connections", "", 30*time.Second, 31*time.Second),
+	}
+
+	backendCauses := computeBackendCauses(intervals).BackendCauses
+	if len(backendCauses) != 2 {
+		t.Fatalf("expected the causes of 2 backends, but got: %v", backendCauses)
+	}
+	kubeAPI := backendCauses["kube-api-new-connections"]
+	if want, got := 12*time.Second, kubeAPI.Disruption.Duration; want != got {
+		t.Errorf("expected disruption: %s, but got: %s", want, got)
+	}
+	expected := []BackendCause{
+		{ProbableCause: "NodeReboot:master-2", Intervals: 2},
+		{ProbableCause: "NodeReboot:master-0", Intervals: 1},
+		{ProbableCause: "", Intervals: 1},
+	}
+	expected[0].Disruption.Duration = 9 * time.Second
+	expected[1].Disruption.Duration = 2 * time.Second
+	expected[2].Disruption.Duration = time.Second
+	if !reflect.DeepEqual(expected, kubeAPI.Causes) {
+		t.Errorf("expected causes %v, but got: %v", expected, kubeAPI.Causes)
+	}
+}
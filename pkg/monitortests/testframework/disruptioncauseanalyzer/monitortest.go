@@ -0,0 +1,127 @@
+package disruptioncauseanalyzer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+// disruptionCauseAnalyzer correlates the disruption of every backend with the apiserver shutdowns, node reboots,
+// etcd leader changes, operator outages and warning events around it, and annotates each disruption interval
+// with its probable causes, most likely first.
+type disruptionCauseAnalyzer struct {
+}
+
+func NewDisruptionCauseAnalyzer() monitortestframework.MonitorTest {
+	return &disruptionCauseAnalyzer{}
+}
+
+var _ monitortestframework.IntervalAnnotator = &disruptionCauseAnalyzer{}
+
+func (*disruptionCauseAnalyzer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (*disruptionCauseAnalyzer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	return nil, nil, nil
+}
+
+func (*disruptionCauseAnalyzer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, nil
+}
+
+// AnnotateIntervals runs once the node, etcd and operator state intervals have been constructed.
+func (*disruptionCauseAnalyzer) AnnotateIntervals(ctx context.Context, intervals monitorapi.Intervals) (monitortestframework.IntervalAnnotationFunc, error) {
+	end := time.Time{}
+	for _, interval := range intervals {
+		if interval.To.After(end) {
+			end = interval.To
+		}
+	}
+	candidates := candidateCauses(intervals, end)
+
+	return func(interval monitorapi.Interval) monitorapi.Interval {
+		if !monitorapi.IsDisruptionEvent(interval) || !monitorapi.IsErrorEvent(interval) {
+			return interval
+		}
+		return annotateProbableCauses(candidates, interval)
+	}, nil
+}
+
+func (*disruptionCauseAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return nil, nil
+}
+
+func (*disruptionCauseAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	backendCauses := computeBackendCauses(finalIntervals)
+	return writeBackendCauses(filepath.Join(storageDir, fmt.Sprintf("backend-disruption-causes%s.json", timeSuffix)), backendCauses)
+}
+
+func (*disruptionCauseAnalyzer) Cleanup(ctx context.Context) error {
+	return nil
+}
+
+type BackendCauseList struct {
+	// BackendCauses is keyed by name to make the consumption easier
+	BackendCauses map[string]*BackendCauses
+}
+
+type BackendCauses struct {
+	BackendName string
+	// Disruption is the disruption of the backend, it is the sum of the disruption of its causes.
+	Disruption metav1.Duration
+	// Causes are sorted by decreasing disruption.
+	Causes []BackendCause
+}
+
+type BackendCause struct {
+	// ProbableCause is the most likely cause, it is empty for the disruption no probable cause was found for.
+	ProbableCause string
+	Disruption    metav1.Duration
+	Intervals     int
+}
+
+func computeBackendCauses(finalIntervals monitorapi.Intervals) *BackendCauseList {
+	ret := &BackendCauseList{BackendCauses: map[string]*BackendCauses{}}
+
+	byBackend := map[string]monitorapi.Intervals{}
+	disruptionIntervals := finalIntervals.Filter(monitorapi.And(monitorapi.IsDisruptionEvent, monitorapi.IsErrorEvent))
+	for _, interval := range disruptionIntervals {
+		backendName := monitorapi.BackendDisruptionNameFromLocator(interval.StructuredLocator)
+		if len(backendName) == 0 {
+			continue
+		}
+		byBackend[backendName] = append(byBackend[backendName], interval)
+	}
+
+	for backendName, intervals := range byBackend {
+		backendCauses := &BackendCauses{BackendName: backendName}
+		for _, cause := range monitorapi.DisruptionByProbableCause(intervals) {
+			backendCauses.Disruption.Duration += cause.Duration
+			backendCauses.Causes = append(backendCauses.Causes, BackendCause{
+				ProbableCause: cause.Cause,
+				Disruption:    metav1.Duration{Duration: cause.Duration},
+				Intervals:     cause.Intervals,
+			})
+		}
+		ret.BackendCauses[backendName] = backendCauses
+	}
+	return ret
+}
+
+func writeBackendCauses(filename string, backendCauses *BackendCauseList) error {
+	jsonContent, err := json.MarshalIndent(backendCauses, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, jsonContent, 0644)
+}
@@ -0,0 +1,121 @@
+package disruptiondiagnosticsserializer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/disruption/backend/diagnostics"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/rest"
+)
+
+// disruptionDiagnosticsSerializer writes the client side network diagnostics the disruption samplers capture when a
+// backend becomes unavailable, and attach to the disruption interval, in a readable form.
+type disruptionDiagnosticsSerializer struct {
+}
+
+func NewDisruptionDiagnosticsSerializer() monitortestframework.MonitorTest {
+	return &disruptionDiagnosticsSerializer{}
+}
+
+func (*disruptionDiagnosticsSerializer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (*disruptionDiagnosticsSerializer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	return nil, nil, nil
+}
+
+func (*disruptionDiagnosticsSerializer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, nil
+}
+
+func (*disruptionDiagnosticsSerializer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return nil, nil
+}
+
+func (*disruptionDiagnosticsSerializer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	backendDiagnostics := computeBackendDiagnostics(finalIntervals)
+	return writeBackendDiagnostics(filepath.Join(storageDir, fmt.Sprintf("backend-disruption-diagnostics%s.json", timeSuffix)), backendDiagnostics)
+}
+
+func (*disruptionDiagnosticsSerializer) Cleanup(ctx context.Context) error {
+	return nil
+}
+
+type BackendDiagnosticsList struct {
+	// BackendDiagnostics is keyed by name to make the consumption easier
+	BackendDiagnostics map[string]*BackendDiagnostics
+}
+
+type BackendDiagnostics struct {
+	BackendName string
+	// ErrorClasses counts the diagnosed disruptions by the class of their error.
+	ErrorClasses map[backend.ErrorClass]int
+	// Disruptions are sorted by time.
+	Disruptions []DisruptionDiagnostics
+}
+
+type DisruptionDiagnostics struct {
+	From        time.Time
+	To          time.Time
+	Locator     string
+	Message     string
+	Diagnostics *diagnostics.Bundle
+}
+
+func computeBackendDiagnostics(finalIntervals monitorapi.Intervals) *BackendDiagnosticsList {
+	ret := &BackendDiagnosticsList{BackendDiagnostics: map[string]*BackendDiagnostics{}}
+
+	disruptionIntervals := finalIntervals.Filter(monitorapi.And(monitorapi.IsDisruptionEvent, monitorapi.IsErrorEvent))
+	for _, interval := range disruptionIntervals {
+		encoded, ok := interval.StructuredMessage.Annotations[monitorapi.AnnotationDiagnostics]
+		if !ok {
+			continue
+		}
+		backendName := monitorapi.BackendDisruptionNameFromLocator(interval.StructuredLocator)
+		bundle, err := diagnostics.Decode(encoded)
+		if err != nil {
+			logrus.WithError(err).Warnf("ignoring the diagnostics of a disruption of %s", backendName)
+			continue
+		}
+
+		existing, ok := ret.BackendDiagnostics[backendName]
+		if !ok {
+			existing = &BackendDiagnostics{BackendName: backendName, ErrorClasses: map[backend.ErrorClass]int{}}
+			ret.BackendDiagnostics[backendName] = existing
+		}
+		existing.ErrorClasses[bundle.ErrorClass]++
+		existing.Disruptions = append(existing.Disruptions, DisruptionDiagnostics{
+			From:        interval.From,
+			To:          interval.To,
+			Locator:     interval.StructuredLocator.OldLocator(),
+			Message:     interval.StructuredMessage.HumanMessage,
+			Diagnostics: bundle,
+		})
+	}
+
+	for _, backendDiagnostics := range ret.BackendDiagnostics {
+		sort.SliceStable(backendDiagnostics.Disruptions, func(i, j int) bool {
+			return backendDiagnostics.Disruptions[i].From.Before(backendDiagnostics.Disruptions[j].From)
+		})
+	}
+	return ret
+}
+
+func writeBackendDiagnostics(filename string, backendDiagnostics *BackendDiagnosticsList) error {
+	jsonContent, err := json.MarshalIndent(backendDiagnostics, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, jsonContent, 0644)
+}
@@ -0,0 +1,67 @@
+package disruptiondiagnosticsserializer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/disruption/backend/diagnostics"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func disruptionInterval(t *testing.T, backendName string, bundle *diagnostics.Bundle, from, to time.Time) monitorapi.Interval {
+	message := monitorapi.NewMessage().Reason(monitorapi.DisruptionBeganEventReason).HumanMessage("stopped responding")
+	if bundle != nil {
+		encoded, err := diagnostics.Encode(bundle)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		message = message.WithAnnotation(monitorapi.AnnotationDiagnostics, encoded)
+	}
+	return monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).
+		Locator(monitorapi.NewLocator().Disruption(backendName, "", "", "", "", monitorapi.NewConnectionType)).
+		Message(message).
+		Build(from, to)
+}
+
+func TestComputeBackendDiagnostics(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	reset := &diagnostics.Bundle{BackendName: "kube-api-new-connections", SampleID: 10, Error: "connection reset by peer", ErrorClass: backend.ErrorClassConnectionReset,
+		Connection: &diagnostics.Connection{DialedAddr: "10.0.0.1:6443"}}
+	refused := &diagnostics.Bundle{BackendName: "kube-api-new-connections", SampleID: 2, Error: "connection refused", ErrorClass: backend.ErrorClassConnectionRefused,
+		DNS: &diagnostics.DNS{Host: "api.ci.example.com", Answers: []string{"10.0.0.1"}}}
+
+	invalid := disruptionInterval(t, "kube-api-new-connections", nil, start.Add(time.Hour), start.Add(time.Hour+time.Second))
+	invalid.StructuredMessage.Annotations[monitorapi.AnnotationDiagnostics] = "not a bundle"
+	intervals := monitorapi.Intervals{
+		disruptionInterval(t, "kube-api-new-connections", reset, start.Add(time.Minute), start.Add(time.Minute+2*time.Second)),
+		disruptionInterval(t, "kube-api-new-connections", refused, start, start.Add(time.Second)),
+		// rate limited, or captured before the diagnostics existed
+		disruptionInterval(t, "kube-api-new-connections", nil, start.Add(5*time.Second), start.Add(6*time.Second)),
+		disruptionInterval(t, "ingress-to-console-new-connections", nil, start, start.Add(time.Second)),
+		invalid,
+	}
+
+	backendDiagnostics := computeBackendDiagnostics(intervals).BackendDiagnostics
+	if len(backendDiagnostics) != 1 {
+		t.Fatalf("expected the diagnostics of 1 backend, but got: %v", backendDiagnostics)
+	}
+	kubeAPI := backendDiagnostics["kube-api-new-connections"]
+	if kubeAPI == nil {
+		t.Fatalf("expected the diagnostics of kube-api-new-connections")
+	}
+	expectedClasses := map[backend.ErrorClass]int{backend.ErrorClassConnectionReset: 1, backend.ErrorClassConnectionRefused: 1}
+	if !reflect.DeepEqual(expectedClasses, kubeAPI.ErrorClasses) {
+		t.Errorf("expected error classes: %v, but got: %v", expectedClasses, kubeAPI.ErrorClasses)
+	}
+	if len(kubeAPI.Disruptions) != 2 {
+		t.Fatalf("expected 2 diagnosed disruptions, but got: %v", kubeAPI.Disruptions)
+	}
+	if !reflect.DeepEqual(refused, kubeAPI.Disruptions[0].Diagnostics) || !reflect.DeepEqual(reset, kubeAPI.Disruptions[1].Diagnostics) {
+		t.Errorf("expected the diagnostics in time order, but got: %+v, %+v", kubeAPI.Disruptions[0].Diagnostics, kubeAPI.Disruptions[1].Diagnostics)
+	}
+	if want, got := start, kubeAPI.Disruptions[0].From; !want.Equal(got) {
+		t.Errorf("expected from: %s, but got: %s", want, got)
+	}
+}
@@ -0,0 +1,256 @@
+package disruptionlatencyserializer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/disruption/backend/latency"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/allowedbackendlatency"
+	"github.com/openshift/origin/pkg/monitortestlibrary/historicaldata"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+// disruptionLatencySerializer writes the round trip latency histograms the disruption samplers record in their
+// summary intervals, and compares the observed P99 latency of each backend with historical data.
+type disruptionLatencySerializer struct {
+	adminRESTConfig *rest.Config
+}
+
+func NewDisruptionLatencySerializer() monitortestframework.MonitorTest {
+	return &disruptionLatencySerializer{}
+}
+
+func (w *disruptionLatencySerializer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	w.adminRESTConfig = adminRESTConfig
+	return nil
+}
+
+func (w *disruptionLatencySerializer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	return nil, nil, nil
+}
+
+func (*disruptionLatencySerializer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, nil
+}
+
+func (w *disruptionLatencySerializer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	// the latency of the backends is still written by WriteContentToStorage, that is what the historical data is
+	// built from, but the junits are only created once there is historical data to compare against.
+	if !allowedbackendlatency.HasHistoricalData() {
+		return nil, nil
+	}
+	backendLatency := computeLatencyData(finalIntervals)
+	if len(backendLatency.BackendLatencies) == 0 {
+		return nil, nil
+	}
+
+	jobType, err := platformidentification.GetJobType(ctx, w.adminRESTConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for name := range backendLatency.BackendLatencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ret := []*junitapi.JUnitTestCase{}
+	for _, name := range names {
+		historicalPercentiles, details, err := allowedbackendlatency.GetAllowedLatencyPercentiles(name, *jobType)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get allowed latency for %s: %w", name, err)
+		}
+		ret = append(ret, createLatencyJunit(backendLatency.BackendLatencies[name], historicalPercentiles, details, jobType))
+	}
+	return ret, nil
+}
+
+func (*disruptionLatencySerializer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	backendLatency := computeLatencyData(finalIntervals)
+	return writeLatencyData(filepath.Join(storageDir, fmt.Sprintf("backend-latency%s.json", timeSuffix)), backendLatency)
+}
+
+func (*disruptionLatencySerializer) Cleanup(ctx context.Context) error {
+	return nil
+}
+
+type BackendLatencyList struct {
+	// BackendLatencies is keyed by name to make the consumption easier
+	BackendLatencies map[string]*BackendLatency
+}
+
+type BackendLatency struct {
+	// Name ensure self-identification, it includes the connection type
+	Name string
+	// BackendName is the name of backend.  It is the same across all connection types.
+	BackendName      string
+	ConnectionType   string
+	LoadBalancerType string
+	Protocol         string
+	TargetAPI        string
+
+	// Samples is the number of successful samples the latencies are calculated from.
+	Samples int64
+	P50     metav1.Duration
+	P95     metav1.Duration
+	P99     metav1.Duration
+	// Histogram holds the number of samples in each bucket, the last bucket has an UpperBound of +Inf.
+	Histogram []HistogramBucket
+
+	// DegradedDuration is how long the latency of the backend was over its threshold.
+	DegradedDuration metav1.Duration
+	DegradedMessages []string
+
+	histogram *latency.Histogram
+}
+
+type HistogramBucket struct {
+	UpperBound string
+	Count      int64
+}
+
+func writeLatencyData(filename string, backendLatency *BackendLatencyList) error {
+	jsonContent, err := json.MarshalIndent(backendLatency, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, jsonContent, 0644)
+}
+
+func isLatencySummary(eventInterval monitorapi.Interval) bool {
+	return eventInterval.StructuredMessage.Reason == monitorapi.DisruptionLatencySummaryEventReason
+}
+
+func isLatencyDegraded(eventInterval monitorapi.Interval) bool {
+	return eventInterval.StructuredMessage.Reason == monitorapi.DisruptionLatencyDegradedEventReason
+}
+
+func computeLatencyData(eventIntervals monitorapi.Intervals) *BackendLatencyList {
+	ret := &BackendLatencyList{
+		BackendLatencies: map[string]*BackendLatency{},
+	}
+
+	latencyIntervals := eventIntervals.Filter(monitorapi.IsDisruptionLatencyEvent)
+	for _, eventInterval := range latencyIntervals.Filter(isLatencySummary) {
+		backendName := monitorapi.BackendDisruptionNameFromLocator(eventInterval.StructuredLocator)
+		histogram, err := latency.ParseHistogram(eventInterval.StructuredMessage.Annotations[monitorapi.AnnotationHistogram])
+		if err != nil {
+			logrus.WithError(err).Warnf("ignoring the latency summary of %s", backendName)
+			continue
+		}
+
+		existing, ok := ret.BackendLatencies[backendName]
+		if !ok {
+			keys := eventInterval.StructuredLocator.Keys
+			ret.BackendLatencies[backendName] = &BackendLatency{
+				Name:             backendName,
+				BackendName:      backendName,
+				ConnectionType:   strings.Title(keys[monitorapi.LocatorConnectionKey]),
+				LoadBalancerType: keys[monitorapi.LocatorLoadBalancerKey],
+				Protocol:         keys[monitorapi.LocatorProtocolKey],
+				TargetAPI:        keys[monitorapi.LocatorTargetKey],
+				histogram:        histogram,
+			}
+			continue
+		}
+		// a sampler that was restarted records a summary for every run.
+		if err := existing.histogram.Add(histogram); err != nil {
+			logrus.WithError(err).Warnf("ignoring a latency summary of %s", backendName)
+		}
+	}
+
+	for backendName, backendLatency := range ret.BackendLatencies {
+		histogram := backendLatency.histogram
+		backendLatency.Samples = histogram.Count()
+		backendLatency.P50 = metav1.Duration{Duration: histogram.Quantile(0.50)}
+		backendLatency.P95 = metav1.Duration{Duration: histogram.Quantile(0.95)}
+		backendLatency.P99 = metav1.Duration{Duration: histogram.Quantile(0.99)}
+		for i, upper := range histogram.Buckets {
+			backendLatency.Histogram = append(backendLatency.Histogram, HistogramBucket{UpperBound: upper.String(), Count: histogram.Counts[i]})
+		}
+		backendLatency.Histogram = append(backendLatency.Histogram, HistogramBucket{UpperBound: "+Inf", Count: histogram.Counts[len(histogram.Buckets)]})
+
+		degraded := latencyIntervals.Filter(
+			monitorapi.And(
+				monitorapi.IsForDisruptionBackend(backendName),
+				isLatencyDegraded,
+			),
+		)
+		backendLatency.DegradedDuration = metav1.Duration{Duration: degraded.Duration(1 * time.Second).Round(time.Second)}
+		backendLatency.DegradedMessages = degraded.Strings()
+	}
+
+	return ret
+}
+
+func createLatencyJunit(
+	backendLatency *BackendLatency,
+	historicalPercentiles *historicaldata.StatisticalDuration,
+	latencyDetails string,
+	jobType *platformidentification.JobType) *junitapi.JUnitTestCase {
+
+	testName := fmt.Sprintf("[sig-trt] disruption/%s should not have a P99 round trip latency worse than historically", backendLatency.BackendName)
+
+	if jobType.Platform == "" {
+		return &junitapi.JUnitTestCase{
+			Name: testName,
+			SkipMessage: &junitapi.SkipMessage{
+				Message: "Unknown platform, skipping latency testing",
+			},
+		}
+	}
+	if historicalPercentiles == nil {
+		return &junitapi.JUnitTestCase{
+			Name: testName,
+			SkipMessage: &junitapi.SkipMessage{
+				Message: fmt.Sprintf("No historical data to calculate allowed latency %s", latencyDetails),
+			},
+		}
+	}
+
+	observed := backendLatency.P99.Duration
+	rankDetails := fmt.Sprintf("observed P99 latency %s over %d samples is at %s of historical data for similar jobs (%s)",
+		observed.Round(time.Millisecond), backendLatency.Samples,
+		historicaldata.FormatPercentileRank(historicalPercentiles.PercentileRank(observed)),
+		historicalPercentiles.PercentilesString())
+
+	// like disruption, the P99 from historical data fluctuates, allow 20% or 250ms of grace, whichever is larger,
+	// so only really severe regressions fail.
+	allowed := historicalPercentiles.P99 + 250*time.Millisecond
+	if withPercent := time.Duration(float64(historicalPercentiles.P99) * 1.2); withPercent > allowed {
+		allowed = withPercent
+	}
+
+	if observed <= allowed {
+		return &junitapi.JUnitTestCase{
+			Name:      testName,
+			SystemOut: rankDetails,
+		}
+	}
+
+	failureMessage := fmt.Sprintf("%s had a P99 round trip latency of %s (maxAllowed=%s):\n%s\n\nlatency was over the threshold for %s:\n%s",
+		backendLatency.BackendName, observed.Round(time.Millisecond), allowed.Round(time.Millisecond),
+		rankDetails,
+		backendLatency.DegradedDuration.Duration,
+		strings.Join(backendLatency.DegradedMessages, "\n"))
+	return &junitapi.JUnitTestCase{
+		Name: testName,
+		FailureOutput: &junitapi.FailureOutput{
+			Output: failureMessage,
+		},
+		SystemOut: failureMessage,
+	}
+}
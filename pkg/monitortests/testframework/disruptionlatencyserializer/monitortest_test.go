@@ -0,0 +1,122 @@
+package disruptionlatencyserializer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/historicaldata"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+)
+
+func latencyInterval(reason monitorapi.IntervalReason, level monitorapi.IntervalLevel, annotations map[monitorapi.AnnotationKey]string, from, to time.Time) monitorapi.Interval {
+	message := monitorapi.NewMessage().Reason(reason).HumanMessage("latency")
+	for k, v := range annotations {
+		message = message.WithAnnotation(k, v)
+	}
+	return monitorapi.NewInterval(monitorapi.SourceDisruptionLatency, level).
+		Locator(monitorapi.NewLocator().Disruption("kube-api-new-connections", "", "", "", "", monitorapi.NewConnectionType)).
+		Message(message).
+		Build(from, to)
+}
+
+func TestComputeLatencyData(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	summary := func(histogram string) monitorapi.Interval {
+		return latencyInterval(monitorapi.DisruptionLatencySummaryEventReason, monitorapi.Info,
+			map[monitorapi.AnnotationKey]string{monitorapi.AnnotationHistogram: histogram}, start, start.Add(time.Hour))
+	}
+	intervals := monitorapi.Intervals{
+		summary("100ms:90,1s:5,+Inf:5"),
+		// a restarted sampler records another summary
+		summary("100ms:90,1s:10,+Inf:0"),
+		// not a valid histogram, ignored
+		summary("100ms:90"),
+		latencyInterval(monitorapi.DisruptionLatencyDegradedEventReason, monitorapi.Warning, nil, start, start.Add(30*time.Second)),
+	}
+
+	backendLatency := computeLatencyData(intervals).BackendLatencies["kube-api-new-connections"]
+	if backendLatency == nil {
+		t.Fatalf("expected the latency of kube-api-new-connections")
+	}
+	if want, got := int64(200), backendLatency.Samples; want != got {
+		t.Errorf("expected samples: %d, but got: %d", want, got)
+	}
+	if want, got := "New", backendLatency.ConnectionType; want != got {
+		t.Errorf("expected connection type: %s, but got: %s", want, got)
+	}
+	if got := backendLatency.P50.Duration; got <= 0 || got > 100*time.Millisecond {
+		t.Errorf("expected P50 in the first bucket, but got: %s", got)
+	}
+	if want, got := time.Second, backendLatency.P99.Duration; want != got {
+		t.Errorf("expected P99: %s, but got: %s", want, got)
+	}
+	if len(backendLatency.Histogram) != 3 || backendLatency.Histogram[2].UpperBound != "+Inf" || backendLatency.Histogram[2].Count != 5 {
+		t.Errorf("unexpected histogram: %v", backendLatency.Histogram)
+	}
+	if want, got := 30*time.Second, backendLatency.DegradedDuration.Duration; want != got {
+		t.Errorf("expected degraded duration: %s, but got: %s", want, got)
+	}
+}
+
+func TestCreateLatencyJunit(t *testing.T) {
+	historical := &historicaldata.StatisticalDuration{
+		P50: 100 * time.Millisecond,
+		P75: 200 * time.Millisecond,
+		P95: 500 * time.Millisecond,
+		P99: time.Second,
+	}
+	jobType := &platformidentification.JobType{Platform: "aws"}
+
+	tests := []struct {
+		name       string
+		observed   time.Duration
+		historical *historicaldata.StatisticalDuration
+		jobType    *platformidentification.JobType
+		skipped    bool
+		failed     bool
+	}{
+		{
+			name:       "unknown platform",
+			historical: historical,
+			jobType:    &platformidentification.JobType{},
+			skipped:    true,
+		},
+		{
+			name:    "no historical data",
+			jobType: jobType,
+			skipped: true,
+		},
+		{
+			name:       "within the grace",
+			observed:   1200 * time.Millisecond,
+			historical: historical,
+			jobType:    jobType,
+		},
+		{
+			name:       "over the grace",
+			observed:   1300 * time.Millisecond,
+			historical: historical,
+			jobType:    jobType,
+			failed:     true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			backendLatency := &BackendLatency{BackendName: "kube-api-new-connections"}
+			backendLatency.P99.Duration = test.observed
+
+			junit := createLatencyJunit(backendLatency, test.historical, "", test.jobType)
+			if !strings.Contains(junit.Name, "disruption/kube-api-new-connections") {
+				t.Errorf("unexpected test name: %s", junit.Name)
+			}
+			if want, got := test.skipped, junit.SkipMessage != nil; want != got {
+				t.Errorf("expected skipped: %t, but got: %t", want, got)
+			}
+			if want, got := test.failed, junit.FailureOutput != nil; want != got {
+				t.Errorf("expected failed: %t, but got: %t", want, got)
+			}
+		})
+	}
+}
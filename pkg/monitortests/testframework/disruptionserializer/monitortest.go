@@ -27,6 +27,10 @@ func (w *disruptionSummarySerializer) StartCollection(ctx context.Context, admin
 	return nil
 }
 
+func (w *disruptionSummarySerializer) PrepareForReplay(ctx context.Context) error {
+	return nil
+}
+
 func (w *disruptionSummarySerializer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
 	return nil, nil, nil
 }
@@ -62,8 +66,11 @@ type BackendDisruption struct {
 	// ConnectionType is New or Reused
 	ConnectionType string
 
-	DisruptedDuration  metav1.Duration
-	DisruptionMessages []string
+	DisruptedDuration metav1.Duration
+	// DisruptedMilliseconds is the disruption with a sub-second resolution, DisruptedDuration
+	// is rounded to the second, and counts at least one second for every disruption interval.
+	DisruptedMilliseconds int64
+	DisruptionMessages    []string
 
 	// New disruption test framework is introducing these fields, for
 	// previous version of the test, these fields will default:
@@ -73,6 +80,20 @@ type BackendDisruption struct {
 	LoadBalancerType string
 	Protocol         string
 	TargetAPI        string
+
+	// ServerInstances breaks the disruption down per server instance that
+	// served the failed requests, it is only set for the samplers that
+	// identify the server instance of every request.
+	ServerInstances []ServerInstanceDisruption `json:",omitempty"`
+}
+
+type ServerInstanceDisruption struct {
+	// ServerInstance is empty for the disruption of the requests that
+	// never made it to a server.
+	ServerInstance    string
+	Node              string `json:",omitempty"`
+	DisruptedDuration metav1.Duration
+	Intervals         int
 }
 
 func writeDisruptionData(filename string, disruption *BackendDisruptionList) error {
@@ -121,8 +142,36 @@ func computeDisruptionData(eventIntervals monitorapi.Intervals) *BackendDisrupti
 			// part closely resembles the api being tested.
 			TargetAPI: "",
 		}
+		bs.DisruptedMilliseconds = monitorapi.BackendDisruptionMilliseconds(backendDisruptionName, allDisruptionEventsIntervals)
+		bs.ServerInstances = computeServerInstanceDisruption(backendDisruptionName, allDisruptionEventsIntervals)
 		ret.BackendDisruptions[backendDisruptionName] = bs
 	}
 
 	return ret
 }
+
+func computeServerInstanceDisruption(backendDisruptionName string, eventIntervals monitorapi.Intervals) []ServerInstanceDisruption {
+	disruptionIntervals := eventIntervals.Filter(
+		monitorapi.And(
+			monitorapi.IsErrorEvent,
+			monitorapi.IsEventForBackendDisruptionName(backendDisruptionName),
+		),
+	)
+	identified := disruptionIntervals.Filter(func(eventInterval monitorapi.Interval) bool {
+		return len(eventInterval.StructuredLocator.Keys[monitorapi.LocatorServerInstanceKey]) > 0
+	})
+	if len(identified) == 0 {
+		return nil
+	}
+
+	ret := []ServerInstanceDisruption{}
+	for _, instance := range monitorapi.DisruptionByServerInstance(disruptionIntervals) {
+		ret = append(ret, ServerInstanceDisruption{
+			ServerInstance:    instance.ServerInstance,
+			Node:              instance.Node,
+			DisruptedDuration: metav1.Duration{Duration: instance.Duration},
+			Intervals:         instance.Intervals,
+		})
+	}
+	return ret
+}
@@ -436,6 +436,28 @@ func TestComputeDisruptionData(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "disruption per server instance",
+			intervals: []monitorapi.Interval{
+				instanceDisruption("kube-api-new-connections", "apiserver-a", "master-0", 30*time.Minute, 25*time.Minute),
+				instanceDisruption("kube-api-new-connections", "apiserver-b", "master-1", 20*time.Minute, 19*time.Minute),
+				instanceDisruption("kube-api-new-connections", "apiserver-a", "master-0", 10*time.Minute, 9*time.Minute),
+				instanceDisruption("kube-api-new-connections", "", "", 5*time.Minute, 3*time.Minute),
+			},
+			expected: map[string]BackendDisruption{
+				"kube-api-new-connections": {
+					Name:              "kube-api-new-connections",
+					BackendName:       "kube-api-new-connections",
+					ConnectionType:    "New",
+					DisruptedDuration: metav1.Duration{Duration: 9 * time.Minute},
+					ServerInstances: []ServerInstanceDisruption{
+						{ServerInstance: "apiserver-a", Node: "master-0", DisruptedDuration: metav1.Duration{Duration: 6 * time.Minute}, Intervals: 2},
+						{ServerInstance: "", DisruptedDuration: metav1.Duration{Duration: 2 * time.Minute}, Intervals: 1},
+						{ServerInstance: "apiserver-b", Node: "master-1", DisruptedDuration: metav1.Duration{Duration: time.Minute}, Intervals: 1},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -449,8 +471,46 @@ func TestComputeDisruptionData(t *testing.T) {
 				assert.Equal(t, expectedDisruption.BackendName, ad.BackendName)
 				assert.Equal(t, expectedDisruption.ConnectionType, ad.ConnectionType)
 				assert.Equal(t, expectedDisruption.DisruptedDuration, ad.DisruptedDuration)
+				assert.Equal(t, expectedDisruption.ServerInstances, ad.ServerInstances)
 				// NOTE: not checking the actual disruption messages, embedded timestamps make it cumbersome
 			}
 		})
 	}
 }
+
+func TestComputeDisruptionDataMilliseconds(t *testing.T) {
+	// a blip measured by an adaptive sampler, and a longer disruption
+	intervals := monitorapi.Intervals{
+		instanceDisruption("kube-api-new-connections", "", "", 10*time.Minute, 10*time.Minute-250*time.Millisecond),
+		instanceDisruption("kube-api-new-connections", "", "", 5*time.Minute, 5*time.Minute-1500*time.Millisecond),
+	}
+	disruption := computeDisruptionData(intervals).BackendDisruptions["kube-api-new-connections"]
+	if !assert.NotNil(t, disruption) {
+		return
+	}
+	assert.Equal(t, metav1.Duration{Duration: 3 * time.Second}, disruption.DisruptedDuration)
+	assert.Equal(t, int64(1750), disruption.DisruptedMilliseconds)
+
+	// the same intervals, recorded by an adaptive sampler, are not padded to a second
+	for i := range intervals {
+		intervals[i].StructuredMessage.Annotations = map[monitorapi.AnnotationKey]string{monitorapi.AnnotationAdaptiveSampling: "true"}
+	}
+	disruption = computeDisruptionData(intervals).BackendDisruptions["kube-api-new-connections"]
+	if !assert.NotNil(t, disruption) {
+		return
+	}
+	assert.Equal(t, metav1.Duration{Duration: 2 * time.Second}, disruption.DisruptedDuration)
+	assert.Equal(t, int64(1750), disruption.DisruptedMilliseconds)
+}
+
+func instanceDisruption(backendName, serverInstance, node string, fromAgo, toAgo time.Duration) monitorapi.Interval {
+	now := time.Now()
+	locator := monitorapi.NewLocator().Disruption(backendName, "kube-api", "", "", "", monitorapi.NewConnectionType)
+	if len(serverInstance) > 0 {
+		locator.Keys[monitorapi.LocatorServerInstanceKey] = serverInstance
+		locator.Keys[monitorapi.LocatorNodeKey] = node
+	}
+	return monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).Locator(locator).
+		Message(monitorapi.NewMessage().Reason(monitorapi.DisruptionBeganEventReason).HumanMessage("stopped responding")).
+		Build(now.Add(-fromAgo), now.Add(-toAgo))
+}
@@ -22,6 +22,10 @@ func (w *e2eTestAnalyzer) StartCollection(ctx context.Context, adminRESTConfig *
 	return nil
 }
 
+func (w *e2eTestAnalyzer) PrepareForReplay(ctx context.Context) error {
+	return nil
+}
+
 func (w *e2eTestAnalyzer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
 	return nil, nil, nil
 }
@@ -26,6 +26,10 @@ func (w *intervalSerializer) StartCollection(ctx context.Context, adminRESTConfi
 	return nil
 }
 
+func (w *intervalSerializer) PrepareForReplay(ctx context.Context) error {
+	return nil
+}
+
 func (w *intervalSerializer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
 	return nil, nil, nil
 }
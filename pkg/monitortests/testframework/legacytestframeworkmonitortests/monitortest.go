@@ -21,10 +21,15 @@ type legacyMonitorTests struct {
 	duration                   time.Duration
 	recordedResources          monitorapi.ResourcesMap
 	clusterStabilityDuringTest *monitortestframework.ClusterStabilityDuringTest
+	// pathologicalEventAllowances are the additional matchers loaded from allowance files.
+	pathologicalEventAllowances []pathologicaleventlibrary.EventMatcher
 }
 
-func NewLegacyTests(info monitortestframework.MonitorTestInitializationInfo) monitortestframework.MonitorTest {
-	return &legacyMonitorTests{clusterStabilityDuringTest: &info.ClusterStabilityDuringTest}
+func NewLegacyTests(info monitortestframework.MonitorTestInitializationInfo, pathologicalEventAllowances []pathologicaleventlibrary.EventMatcher) monitortestframework.MonitorTest {
+	return &legacyMonitorTests{
+		clusterStabilityDuringTest:  &info.ClusterStabilityDuringTest,
+		pathologicalEventAllowances: pathologicalEventAllowances,
+	}
 }
 
 func (w *legacyMonitorTests) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
@@ -32,6 +37,12 @@ func (w *legacyMonitorTests) StartCollection(ctx context.Context, adminRESTConfi
 	return nil
 }
 
+// PrepareForReplay evaluates alerts and pathological events from the intervals alone.  Without a cluster, the feature
+// set is assumed to be the default and etcd revision changes are not allowed for.
+func (w *legacyMonitorTests) PrepareForReplay(ctx context.Context) error {
+	return platformidentification.RequireClusterDataOverride(ctx)
+}
+
 func (w *legacyMonitorTests) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
 	w.duration = end.Sub(beginning)
 	return nil, nil, nil
@@ -43,7 +54,7 @@ func (w *legacyMonitorTests) ConstructComputedIntervals(ctx context.Context, sta
 }
 
 func (w *legacyMonitorTests) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	jobType, err := platformidentification.GetJobType(context.TODO(), w.adminRESTConfig)
+	jobType, err := platformidentification.GetJobType(ctx, w.adminRESTConfig)
 	if err != nil {
 		// JobType will be nil here, but we want test cases to all fail if this is the case, so we rely on them to nil check
 		logrus.WithError(err).Warn("ERROR: unable to determine job type for alert testing, jobType will be nil")
@@ -53,11 +64,11 @@ func (w *legacyMonitorTests) EvaluateTestsFromConstructedIntervals(ctx context.C
 
 	isUpgrade := platformidentification.DidUpgradeHappenDuringCollection(finalIntervals, time.Time{}, time.Time{})
 	if isUpgrade {
-		junits = append(junits, pathologicaleventlibrary.TestDuplicatedEventForUpgrade(finalIntervals, w.adminRESTConfig)...)
+		junits = append(junits, pathologicaleventlibrary.TestDuplicatedEventForUpgrade(ctx, finalIntervals, w.adminRESTConfig, w.pathologicalEventAllowances...)...)
 		junits = append(junits, testAlerts(finalIntervals, alerts.AllowedAlertsDuringUpgrade, jobType, w.clusterStabilityDuringTest,
 			w.adminRESTConfig, w.duration, w.recordedResources)...)
 	} else {
-		junits = append(junits, pathologicaleventlibrary.TestDuplicatedEventForStableSystem(finalIntervals, w.adminRESTConfig)...)
+		junits = append(junits, pathologicaleventlibrary.TestDuplicatedEventForStableSystem(ctx, finalIntervals, w.adminRESTConfig, w.pathologicalEventAllowances...)...)
 		junits = append(junits, testAlerts(finalIntervals, alerts.AllowedAlertsDuringConformance, jobType, w.clusterStabilityDuringTest,
 			w.adminRESTConfig, w.duration, w.recordedResources)...)
 	}
@@ -28,6 +28,10 @@ func (w *pathologicalEventAnalyzer) StartCollection(ctx context.Context, adminRE
 	return nil
 }
 
+func (w *pathologicalEventAnalyzer) PrepareForReplay(ctx context.Context) error {
+	return nil
+}
+
 func (w *pathologicalEventAnalyzer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
 	return nil, nil, nil
 }
@@ -25,6 +25,10 @@ func (w *timelineSerializer) StartCollection(ctx context.Context, adminRESTConfi
 	return nil
 }
 
+func (w *timelineSerializer) PrepareForReplay(ctx context.Context) error {
+	return nil
+}
+
 func (w *timelineSerializer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
 	return nil, nil, nil
 }
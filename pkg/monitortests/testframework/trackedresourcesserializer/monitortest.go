@@ -27,6 +27,10 @@ func (w *trackedResourcesSerializer) StartCollection(ctx context.Context, adminR
 	return nil
 }
 
+func (w *trackedResourcesSerializer) PrepareForReplay(ctx context.Context) error {
+	return nil
+}
+
 func (w *trackedResourcesSerializer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
 	return nil, nil, nil
 }
@@ -26,7 +26,7 @@ import (
 
 var reMatchFirstQuote = regexp.MustCompile(`"([^"]+)"( in (\d+(\.\d+)?(s|ms)$))?`)
 
-func startEventMonitoring(ctx context.Context, m monitorapi.RecorderWriter, adminRESTConfig *rest.Config, client kubernetes.Interface) {
+func startEventMonitoring(ctx context.Context, m monitorapi.RecorderWriter, adminRESTConfig *rest.Config, client kubernetes.Interface, allowances []pathologicaleventlibrary.EventMatcher) {
 
 	// filter out events written "now" but with significantly older start times (events
 	// created in test jobs are the most common)
@@ -36,7 +36,7 @@ func startEventMonitoring(ctx context.Context, m monitorapi.RecorderWriter, admi
 	// we've already recorded.
 	processedEventUIDs := map[types.UID]string{}
 
-	_, topology, err := pathologicaleventlibrary.GetClusterInfraInfo(adminRESTConfig)
+	_, topology, err := pathologicaleventlibrary.GetClusterInfraInfo(ctx, adminRESTConfig)
 	if err != nil {
 		logrus.WithError(err).Error("could not fetch cluster infra info")
 	}
@@ -64,7 +64,7 @@ func startEventMonitoring(ctx context.Context, m monitorapi.RecorderWriter, admi
 				return nil
 			}
 			if processedEventUIDs[event.UID] != event.ResourceVersion {
-				recordAddOrUpdateEvent(ctx, m, topology, client, significantlyBeforeNow, event)
+				recordAddOrUpdateEvent(ctx, m, topology, client, significantlyBeforeNow, event, allowances)
 				processedEventUIDs[event.UID] = event.ResourceVersion
 			}
 			return nil
@@ -75,7 +75,7 @@ func startEventMonitoring(ctx context.Context, m monitorapi.RecorderWriter, admi
 				return nil
 			}
 			if processedEventUIDs[event.UID] != event.ResourceVersion {
-				recordAddOrUpdateEvent(ctx, m, topology, client, significantlyBeforeNow, event)
+				recordAddOrUpdateEvent(ctx, m, topology, client, significantlyBeforeNow, event, allowances)
 				processedEventUIDs[event.UID] = event.ResourceVersion
 			}
 			return nil
@@ -91,7 +91,8 @@ func recordAddOrUpdateEvent(
 	topology v1.TopologyMode,
 	client kubernetes.Interface,
 	significantlyBeforeNow time.Time,
-	obj *corev1.Event) {
+	obj *corev1.Event,
+	allowances []pathologicaleventlibrary.EventMatcher) {
 
 	recorder.RecordResource("events", obj)
 
@@ -182,7 +183,7 @@ func recordAddOrUpdateEvent(
 	// times it occurred. We include upgrade allowances here. (the upgrade set contains both)
 	// We do not pass a Kubeconfig or list of final intervals (as final intervals obviously do not exist), so a small subset of more matchers will not be active,
 	// and will not get flagged as "interesting" as a result.
-	registry := pathologicaleventlibrary.NewUpgradePathologicalEventMatchers(nil, nil)
+	registry := pathologicaleventlibrary.NewUpgradePathologicalEventMatchers(nil, nil, allowances...)
 
 	intervalBuilder := monitorapi.NewInterval(monitorapi.SourceKubeEvent, level)
 
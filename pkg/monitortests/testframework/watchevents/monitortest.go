@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/pathologicaleventlibrary"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
@@ -13,10 +14,12 @@ import (
 )
 
 type eventWatcher struct {
+	// pathologicalEventAllowances are the additional matchers loaded from allowance files.
+	pathologicalEventAllowances []pathologicaleventlibrary.EventMatcher
 }
 
-func NewEventWatcher() monitortestframework.MonitorTest {
-	return &eventWatcher{}
+func NewEventWatcher(pathologicalEventAllowances []pathologicaleventlibrary.EventMatcher) monitortestframework.MonitorTest {
+	return &eventWatcher{pathologicalEventAllowances: pathologicalEventAllowances}
 }
 
 func (w *eventWatcher) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
@@ -25,11 +28,16 @@ func (w *eventWatcher) StartCollection(ctx context.Context, adminRESTConfig *res
 		return err
 	}
 
-	startEventMonitoring(ctx, recorder, adminRESTConfig, kubeClient)
+	startEventMonitoring(ctx, recorder, adminRESTConfig, kubeClient, w.pathologicalEventAllowances)
 
 	return nil
 }
 
+// the watched resources were recorded by the run being replayed.
+func (w *eventWatcher) PrepareForReplay(ctx context.Context) error {
+	return nil
+}
+
 func (w *eventWatcher) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
 	// because we are sharing a recorder that we're streaming into, we don't need to have a separate data collection step.
 	return nil, nil, nil
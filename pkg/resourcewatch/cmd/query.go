@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/resourcewatch/query"
+	"github.com/openshift/origin/pkg/resourcewatch/storage"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+type QueryFlags struct {
+	From            string
+	Namespace       string
+	Since           string
+	Until           string
+	UpdatedMoreThan int
+	IntervalsFile   string
+
+	genericclioptions.IOStreams
+}
+
+func NewQueryFlags(streams genericclioptions.IOStreams) *QueryFlags {
+	return &QueryFlags{
+		UpdatedMoreThan: -1,
+		IOStreams:       streams,
+	}
+}
+
+func newQueryCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	f := NewQueryFlags(streams)
+
+	cmd := &cobra.Command{
+		Use:   "query [RESOURCE[/NAME]]",
+		Short: "Query the resource changes recorded by run-resourcewatch",
+		Long: templates.LongDesc(`
+		Query the resource changes recorded by run-resourcewatch.
+
+		--from is the git repository, or the directory holding the resource-changes.jsonl.gz or
+		resource-changes.db file, written by run-resourcewatch.  Every change to the matching resources is
+		printed along with which fields it changed and who changed them, according to managedFields.
+		With --updated-more-than, the resources that were modified more than that many times are listed
+		instead.  With --intervals-file, the changes are also written as intervals that can be rendered
+		over the e2e timeline.
+		`),
+		Example: templates.Examples(`
+		# show all changes to the kube-apiserver clusteroperator during the upgrade
+		openshift-tests resourcewatch query --from ./resourcewatch clusteroperators/kube-apiserver --since 2024-01-01T10:00:00Z --until 2024-01-01T11:00:00Z
+
+		# list the pods that were updated more than 20 times
+		openshift-tests resourcewatch query --from ./resourcewatch pods --updated-more-than 20
+		`),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o, err := f.ToOptions(args)
+			if err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (f *QueryFlags) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&f.From, "from", f.From, "The storage written by run-resourcewatch: a git repository or a directory holding resource-changes.jsonl.gz or resource-changes.db.")
+	flags.StringVarP(&f.Namespace, "namespace", "n", f.Namespace, "Only show changes to resources in this namespace.")
+	flags.StringVar(&f.Since, "since", f.Since, "Only show changes observed at or after this RFC3339 time.")
+	flags.StringVar(&f.Until, "until", f.Until, "Only show changes observed at or before this RFC3339 time.")
+	flags.IntVar(&f.UpdatedMoreThan, "updated-more-than", f.UpdatedMoreThan, "List the resources modified more than this many times instead of showing changes.")
+	flags.StringVar(&f.IntervalsFile, "intervals-file", f.IntervalsFile, "Also write the changes as intervals to this file.")
+}
+
+func (f *QueryFlags) ToOptions(args []string) (*QueryOptions, error) {
+	if len(f.From) == 0 {
+		return nil, fmt.Errorf("missing --from")
+	}
+
+	filter := query.Filter{}
+	if len(args) > 0 {
+		var err error
+		if filter, err = query.ParseResourceArg(args[0]); err != nil {
+			return nil, err
+		}
+	}
+	filter.Namespace = f.Namespace
+	for _, timeFlag := range []struct {
+		name   string
+		value  string
+		target *time.Time
+	}{
+		{name: "--since", value: f.Since, target: &filter.Since},
+		{name: "--until", value: f.Until, target: &filter.Until},
+	} {
+		if len(timeFlag.value) == 0 {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, timeFlag.value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be an RFC3339 time: %w", timeFlag.name, err)
+		}
+		*timeFlag.target = parsed
+	}
+
+	if f.UpdatedMoreThan >= 0 && len(f.IntervalsFile) > 0 {
+		return nil, fmt.Errorf("--intervals-file cannot be used with --updated-more-than")
+	}
+
+	return &QueryOptions{
+		From:            f.From,
+		Filter:          filter,
+		UpdatedMoreThan: f.UpdatedMoreThan,
+		IntervalsFile:   f.IntervalsFile,
+		IOStreams:       f.IOStreams,
+	}, nil
+}
+
+type QueryOptions struct {
+	From   string
+	Filter query.Filter
+	// UpdatedMoreThan lists frequently updated resources instead of changes when it is not negative.
+	UpdatedMoreThan int
+	IntervalsFile   string
+
+	genericclioptions.IOStreams
+}
+
+func (o *QueryOptions) Run() error {
+	reader, err := storage.NewChangeReader(o.From)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	if o.UpdatedMoreThan >= 0 {
+		counts, err := query.FrequentlyUpdated(reader, o.Filter, o.UpdatedMoreThan)
+		if err != nil {
+			return err
+		}
+		for _, count := range counts {
+			fmt.Fprintf(o.Out, "%5d %s between %s and %s\n",
+				count.Updates, resourceDisplayName(count.Resource.GroupResource().String(), count.Namespace, count.Name),
+				count.First.Format(time.RFC3339), count.Last.Format(time.RFC3339))
+		}
+		return nil
+	}
+
+	changes, err := query.Changes(reader, o.Filter)
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		printChange(o.Out, change)
+	}
+
+	if len(o.IntervalsFile) > 0 {
+		if err := monitorserialization.EventsToFile(o.IntervalsFile, query.ToIntervals(changes)); err != nil {
+			return fmt.Errorf("unable to write intervals: %w", err)
+		}
+		fmt.Fprintf(o.ErrOut, "Wrote %d intervals to %s\n", len(changes), o.IntervalsFile)
+	}
+	return nil
+}
+
+// uninterestingFields change with every update and are left out of the printed diff.
+var uninterestingFields = fieldpath.NewSet(
+	fieldpath.MakePathOrDie("metadata", "managedFields"),
+	fieldpath.MakePathOrDie("metadata", "resourceVersion"),
+	fieldpath.MakePathOrDie("metadata", "generation"),
+)
+
+func printChange(out io.Writer, change *query.ResourceChangeDiff) {
+	fmt.Fprintf(out, "%s %s %s resourceVersion=%s",
+		change.ObservedTime.Format(time.RFC3339), change.Operation,
+		resourceDisplayName(change.Resource.GroupResource().String(), change.Namespace, change.Name), change.ResourceVersion)
+	if len(change.ModifyingUser) > 0 {
+		fmt.Fprintf(out, " by %s", change.ModifyingUser)
+	}
+	fmt.Fprintln(out)
+	if change.Diff == nil {
+		return
+	}
+
+	if comparison := change.Diff.Comparison.ExcludeFields(uninterestingFields); !comparison.IsSame() {
+		fmt.Fprint(out, indent(comparison.String(), "    "))
+	}
+	users := []string{}
+	for user := range change.Diff.FieldsByUser {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+	for _, user := range users {
+		fmt.Fprintf(out, "    - Fields owned by %s:\n", user)
+		fmt.Fprintln(out, indent(change.Diff.FieldsByUser[user].String(), "    "))
+	}
+}
+
+func resourceDisplayName(resource, namespace, name string) string {
+	if len(namespace) == 0 {
+		return fmt.Sprintf("%s/%s", resource, name)
+	}
+	return fmt.Sprintf("%s/%s -n %s", resource, name, namespace)
+}
+
+func indent(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i := range lines {
+		if len(lines[i]) > 0 {
+			lines[i] = prefix + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
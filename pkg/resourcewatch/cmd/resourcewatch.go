@@ -1,12 +1,18 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/openshift/origin/pkg/resourcewatch/operator"
+	"github.com/openshift/origin/pkg/resourcewatch/storage"
 	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/kubectl/pkg/util/templates"
 )
 
 func NewRunResourceWatchCommand() *cobra.Command {
+	storageType := string(storage.StorageTypeGit)
+
 	cmd := &cobra.Command{
 		Use:   "run-resourcewatch",
 		Short: "Run watch for resource changes and commit each to a git repository",
@@ -16,6 +22,11 @@ func NewRunResourceWatchCommand() *cobra.Command {
 			see precisely how a resource changed over time.
 			By default /repository will be used, specify REPOSITORY_PATH env var to
 			override.
+			On busy clusters the git repository can fall behind.  --storage=log appends
+			every change to a compressed resource-changes.jsonl.gz file instead, and
+			--storage=indexed stores every change in a resource-changes.db file indexed by
+			resource and resourceVersion.  Both can be turned into a git repository afterwards
+			with "openshift-tests resourcewatch convert-to-git".
 			Sample invocation against an external cluster:
 			  $ REPOSITORY_PATH="/tmp/resource-watch-repo" openshift-tests run-resourcewatch --kubeconfig /path/to/kubeconfig --namespace default
 		`),
@@ -23,11 +34,68 @@ func NewRunResourceWatchCommand() *cobra.Command {
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return operator.RunResourceWatch()
+			return operator.RunResourceWatch(storage.StorageType(storageType))
 		},
 	}
 	var dummy string
 	cmd.Flags().StringVar(&dummy, "kubeconfig", "", "This option is not used any more. It will be removed in later releases")
 	cmd.Flags().StringVar(&dummy, "namespace", "", "This option is not used any more. It will be removed in later releases")
+	cmd.Flags().StringVar(&storageType, "storage", storageType, fmt.Sprintf("How to store resource changes, one of %v", storage.AllStorageTypes))
+	return cmd
+}
+
+func NewResourceWatchCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "resourcewatch",
+		Short:         "Commands for working with the resource changes recorded by run-resourcewatch",
+		SilenceErrors: true,
+	}
+
+	cmd.AddCommand(
+		newConvertToGitCommand(),
+		newQueryCommand(streams),
+	)
+	return cmd
+}
+
+func newConvertToGitCommand() *cobra.Command {
+	var from, repositoryPath string
+
+	cmd := &cobra.Command{
+		Use:   "convert-to-git",
+		Short: "Convert resource changes recorded with --storage=log or --storage=indexed to a git repository",
+		Long: templates.LongDesc(`
+			Convert resource changes recorded with --storage=log or --storage=indexed to a git repository.
+
+			The repository has the same layout run-resourcewatch --storage=git produces, with one commit per
+			change dated at the time the change was observed, so "git log -p" works as usual.
+		`),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(from) == 0 {
+				return fmt.Errorf("missing --from")
+			}
+			if len(repositoryPath) == 0 {
+				return fmt.Errorf("missing --repository")
+			}
+
+			reader, err := storage.NewChangeReader(from)
+			if err != nil {
+				return err
+			}
+			defer reader.Close()
+
+			commits, err := storage.ConvertToGit(reader, repositoryPath)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Committed %d changes to %s\n", commits, repositoryPath)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&from, "from", from, "The storage directory written by run-resourcewatch, or the resource-changes.jsonl.gz or resource-changes.db file in it.")
+	cmd.Flags().StringVar(&repositoryPath, "repository", repositoryPath, "The directory to create or add to the git repository in.")
 	return cmd
 }
@@ -19,7 +19,7 @@ import (
 
 // this doesn't appear to handle restarts cleanly.  To do so it would need to compare the resource version that it is applying
 // to the resource version present and it would need to handle unobserved deletions properly.  both are possible, neither is easy.
-func RunResourceWatch() error {
+func RunResourceWatch(storageType storage.StorageType) error {
 	ctx, cancelFn := context.WithCancel(context.Background())
 	defer cancelFn()
 	abortCh := make(chan os.Signal, 2)
@@ -55,11 +55,16 @@ func RunResourceWatch() error {
 		repositoryPath = repositoryPathEnv
 	}
 
-	gitStorage, err := storage.NewGitStorage(repositoryPath)
+	resourceStorage, err := storage.NewResourceStorage(storageType, repositoryPath)
 	if err != nil {
-		klog.Errorf("Failed to create git storage with error %v", err)
+		klog.Errorf("Failed to create %s storage with error %v", storageType, err)
 		return err
 	}
+	defer func() {
+		if err := resourceStorage.Close(); err != nil {
+			klog.Errorf("Failed to close %s storage with error %v", storageType, err)
+		}
+	}()
 
 	dynamicInformer := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 0)
 
@@ -117,7 +122,7 @@ func RunResourceWatch() error {
 
 	configmonitor.WireResourceInformersToGitRepo(
 		dynamicInformer,
-		gitStorage,
+		resourceStorage,
 		resourcesToWatch,
 	)
 
@@ -127,6 +132,9 @@ func RunResourceWatch() error {
 
 	<-ctx.Done()
 
+	// the handlers must be done recording changes before the storage is closed.
+	dynamicInformer.Shutdown()
+
 	return nil
 }
 
@@ -0,0 +1,230 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/resourcewatch/storage"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+)
+
+// Filter selects the changes a query is about.  Empty fields match everything.
+type Filter struct {
+	// Resource is the plural resource name, optionally qualified by group: clusteroperators or
+	// clusteroperators.config.openshift.io.
+	Resource  string
+	Namespace string
+	Name      string
+	// Since and Until bound the time the change was observed, both inclusive.
+	Since time.Time
+	Until time.Time
+}
+
+// ParseResourceArg parses resource/name or resource into a Filter.
+func ParseResourceArg(arg string) (Filter, error) {
+	parts := strings.Split(arg, "/")
+	switch {
+	case len(parts) == 1 && len(parts[0]) > 0:
+		return Filter{Resource: parts[0]}, nil
+	case len(parts) == 2 && len(parts[0]) > 0 && len(parts[1]) > 0:
+		return Filter{Resource: parts[0], Name: parts[1]}, nil
+	default:
+		return Filter{}, fmt.Errorf("expected resource or resource/name, got %q", arg)
+	}
+}
+
+func (f Filter) matchesResource(change *storage.ResourceChange) bool {
+	if len(f.Resource) > 0 && f.Resource != change.Resource.Resource && f.Resource != qualifiedResource(change.Resource) {
+		return false
+	}
+	if len(f.Namespace) > 0 && f.Namespace != change.Namespace {
+		return false
+	}
+	if len(f.Name) > 0 && f.Name != change.Name {
+		return false
+	}
+	return true
+}
+
+func (f Filter) matchesTime(change *storage.ResourceChange) bool {
+	if !f.Since.IsZero() && change.ObservedTime.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && change.ObservedTime.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+func qualifiedResource(gvr schema.GroupVersionResource) string {
+	return gvr.GroupResource().String()
+}
+
+func resourceKey(change *storage.ResourceChange) string {
+	return fmt.Sprintf("%s/%s/%s", qualifiedResource(change.Resource), change.Namespace, change.Name)
+}
+
+// ResourceChangeDiff is a recorded change and how it differs from the previous version of the resource.
+type ResourceChangeDiff struct {
+	*storage.ResourceChange
+	// Diff is nil for additions and deletions, and for modifications of a resource whose previous version was
+	// not recorded.
+	Diff *storage.FieldDiff
+}
+
+// Changes returns the changes matching the filter in the order they were observed, with the fields each of
+// them changed.
+func Changes(reader storage.ChangeReader, filter Filter) ([]*ResourceChangeDiff, error) {
+	// the previous version may be from before Since, so every version of a matching resource is tracked.
+	previousVersions := map[string]*storage.ResourceChange{}
+	changes := []*ResourceChangeDiff{}
+	err := reader.Changes(func(change *storage.ResourceChange) error {
+		if !filter.matchesResource(change) {
+			return nil
+		}
+		key := resourceKey(change)
+		previous := previousVersions[key]
+		previousVersions[key] = change
+		if !filter.matchesTime(change) {
+			return nil
+		}
+
+		changeDiff := &ResourceChangeDiff{ResourceChange: change}
+		if change.Operation == storage.OperationModified && previous != nil {
+			diff, err := diffChanges(previous, change)
+			if err != nil {
+				klog.Warningf("Unable to diff %s at resourceVersion %s: %v", key, change.ResourceVersion, err)
+			}
+			changeDiff.Diff = diff
+		}
+		changes = append(changes, changeDiff)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func diffChanges(previous, current *storage.ResourceChange) (*storage.FieldDiff, error) {
+	oldObj, err := previous.Unstructured()
+	if err != nil {
+		return nil, err
+	}
+	obj, err := current.Unstructured()
+	if err != nil {
+		return nil, err
+	}
+	return storage.DiffObjects(oldObj, obj)
+}
+
+// UpdateCount is the number of times a resource was updated.
+type UpdateCount struct {
+	Resource  schema.GroupVersionResource
+	Namespace string
+	Name      string
+	Updates   int
+	First     time.Time
+	Last      time.Time
+}
+
+// FrequentlyUpdated returns the resources matching the filter that were modified more than minUpdates times, most
+// updated first.
+func FrequentlyUpdated(reader storage.ChangeReader, filter Filter, minUpdates int) ([]*UpdateCount, error) {
+	counts := map[string]*UpdateCount{}
+	err := reader.Changes(func(change *storage.ResourceChange) error {
+		if change.Operation != storage.OperationModified || !filter.matchesResource(change) || !filter.matchesTime(change) {
+			return nil
+		}
+		key := resourceKey(change)
+		count, ok := counts[key]
+		if !ok {
+			count = &UpdateCount{
+				Resource:  change.Resource,
+				Namespace: change.Namespace,
+				Name:      change.Name,
+				First:     change.ObservedTime,
+			}
+			counts[key] = count
+		}
+		count.Updates++
+		count.Last = change.ObservedTime
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []*UpdateCount{}
+	for _, count := range counts {
+		if count.Updates > minUpdates {
+			ret = append(ret, count)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Updates != ret[j].Updates {
+			return ret[i].Updates > ret[j].Updates
+		}
+		return fmt.Sprintf("%s/%s/%s", qualifiedResource(ret[i].Resource), ret[i].Namespace, ret[i].Name) <
+			fmt.Sprintf("%s/%s/%s", qualifiedResource(ret[j].Resource), ret[j].Namespace, ret[j].Name)
+	})
+	return ret, nil
+}
+
+// ToIntervals converts the changes to intervals that can be rendered over the e2e timeline.
+func ToIntervals(changes []*ResourceChangeDiff) monitorapi.Intervals {
+	intervals := monitorapi.Intervals{}
+	for _, change := range changes {
+		reason := monitorapi.ResourceModified
+		switch change.Operation {
+		case storage.OperationAdded:
+			reason = monitorapi.ResourceAdded
+		case storage.OperationDeleted:
+			reason = monitorapi.ResourceDeleted
+		}
+
+		message := monitorapi.NewMessage().
+			Reason(reason).
+			WithAnnotation(monitorapi.AnnotationResourceVersion, change.ResourceVersion)
+		if len(change.ModifyingUser) > 0 {
+			message = message.WithAnnotation(monitorapi.AnnotationUser, change.ModifyingUser)
+		}
+		if change.Diff != nil {
+			message = message.HumanMessagef("%s %s", strings.ToLower(string(change.Operation)), changedFieldsSummary(change.Diff))
+		} else {
+			message = message.HumanMessage(strings.ToLower(string(change.Operation)))
+		}
+
+		intervals = append(intervals,
+			monitorapi.NewInterval(monitorapi.SourceResourceWatch, monitorapi.Info).
+				Locator(monitorapi.NewLocator().Resource(qualifiedResource(change.Resource), change.Namespace, change.Name)).
+				Message(message).
+				Display().
+				Build(change.ObservedTime, change.ObservedTime),
+		)
+	}
+	return intervals
+}
+
+// changedFieldsSummary lists the changed fields owned by each user on a single line.
+func changedFieldsSummary(diff *storage.FieldDiff) string {
+	users := []string{}
+	for user := range diff.FieldsByUser {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+
+	summary := []string{}
+	for _, user := range users {
+		fields := strings.Split(diff.FieldsByUser[user].String(), "\n")
+		summary = append(summary, fmt.Sprintf("%s by %s", strings.Join(fields, ", "), user))
+	}
+	if len(summary) == 0 {
+		return "fields owned by no one"
+	}
+	return strings.Join(summary, "; ")
+}
@@ -0,0 +1,180 @@
+package query
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/resourcewatch/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	clusterOperatorsResource = schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: "clusteroperators"}
+	podsResource             = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	startTime                = time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+)
+
+type fakeChangeReader []*storage.ResourceChange
+
+func (r fakeChangeReader) Changes(fn func(change *storage.ResourceChange) error) error {
+	for _, change := range r {
+		if err := fn(change); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r fakeChangeReader) Close() error {
+	return nil
+}
+
+func clusterOperatorChange(minute int, operation storage.Operation, name, resourceVersion, message string) *storage.ResourceChange {
+	obj := map[string]interface{}{
+		"apiVersion": "config.openshift.io/v1",
+		"kind":       "ClusterOperator",
+		"metadata": map[string]interface{}{
+			"name":            name,
+			"resourceVersion": resourceVersion,
+			"managedFields": []interface{}{
+				map[string]interface{}{
+					"manager":    "cluster-kube-apiserver-operator",
+					"operation":  "Update",
+					"apiVersion": "config.openshift.io/v1",
+					"fieldsType": "FieldsV1",
+					"fieldsV1":   map[string]interface{}{"f:status": map[string]interface{}{"f:conditions": map[string]interface{}{}}},
+				},
+			},
+		},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Available", "status": "True", "message": message},
+			},
+		},
+	}
+	objBytes, err := json.Marshal(obj)
+	if err != nil {
+		panic(err)
+	}
+	return &storage.ResourceChange{
+		ObservedTime:    startTime.Add(time.Duration(minute) * time.Minute),
+		Operation:       operation,
+		Resource:        clusterOperatorsResource,
+		Name:            name,
+		ResourceVersion: resourceVersion,
+		ModifyingUser:   "cluster-kube-apiserver-operator",
+		Object:          objBytes,
+	}
+}
+
+func podChange(minute int, operation storage.Operation, namespace, name, resourceVersion string) *storage.ResourceChange {
+	return &storage.ResourceChange{
+		ObservedTime:    startTime.Add(time.Duration(minute) * time.Minute),
+		Operation:       operation,
+		Resource:        podsResource,
+		Namespace:       namespace,
+		Name:            name,
+		ResourceVersion: resourceVersion,
+		Object:          json.RawMessage(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"` + name + `","namespace":"` + namespace + `"}}`),
+	}
+}
+
+var recordedChanges = fakeChangeReader{
+	clusterOperatorChange(0, storage.OperationAdded, "kube-apiserver", "100", "first"),
+	clusterOperatorChange(1, storage.OperationAdded, "etcd", "110", "etcd"),
+	podChange(2, storage.OperationAdded, "openshift-etcd", "etcd-0", "120"),
+	clusterOperatorChange(3, storage.OperationModified, "kube-apiserver", "200", "second"),
+	podChange(4, storage.OperationModified, "openshift-etcd", "etcd-0", "210"),
+	clusterOperatorChange(5, storage.OperationModified, "kube-apiserver", "300", "third"),
+	podChange(6, storage.OperationModified, "openshift-etcd", "etcd-0", "310"),
+	podChange(7, storage.OperationModified, "openshift-etcd", "etcd-0", "320"),
+	clusterOperatorChange(8, storage.OperationDeleted, "kube-apiserver", "300", "third"),
+}
+
+func TestParseResourceArg(t *testing.T) {
+	filter, err := ParseResourceArg("clusteroperators/kube-apiserver")
+	require.NoError(t, err)
+	assert.Equal(t, Filter{Resource: "clusteroperators", Name: "kube-apiserver"}, filter)
+
+	filter, err = ParseResourceArg("pods")
+	require.NoError(t, err)
+	assert.Equal(t, Filter{Resource: "pods"}, filter)
+
+	for _, invalid := range []string{"", "pods/", "/etcd-0", "pods/etcd-0/status"} {
+		_, err := ParseResourceArg(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestChanges(t *testing.T) {
+	changes, err := Changes(recordedChanges, Filter{
+		Resource: "clusteroperators.config.openshift.io",
+		Name:     "kube-apiserver",
+		Since:    startTime.Add(5 * time.Minute),
+		Until:    startTime.Add(8 * time.Minute),
+	})
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+
+	// the previous version was observed before --since, but is still used for the diff.
+	assert.Equal(t, "300", changes[0].ResourceVersion)
+	require.NotNil(t, changes[0].Diff)
+	assert.Equal(t, []string{"cluster-kube-apiserver-operator"}, mapKeys(changes[0].Diff.FieldsByUser))
+	assert.Equal(t, ".status.conditions", changes[0].Diff.FieldsByUser["cluster-kube-apiserver-operator"].String())
+
+	assert.Equal(t, storage.OperationDeleted, changes[1].Operation)
+	assert.Nil(t, changes[1].Diff)
+
+	changes, err = Changes(recordedChanges, Filter{Namespace: "openshift-etcd"})
+	require.NoError(t, err)
+	assert.Len(t, changes, 4)
+}
+
+func TestFrequentlyUpdated(t *testing.T) {
+	counts, err := FrequentlyUpdated(recordedChanges, Filter{}, 1)
+	require.NoError(t, err)
+	require.Len(t, counts, 2)
+	assert.Equal(t, "etcd-0", counts[0].Name)
+	assert.Equal(t, 3, counts[0].Updates)
+	assert.Equal(t, startTime.Add(4*time.Minute), counts[0].First)
+	assert.Equal(t, startTime.Add(7*time.Minute), counts[0].Last)
+	assert.Equal(t, "kube-apiserver", counts[1].Name)
+	assert.Equal(t, 2, counts[1].Updates)
+
+	counts, err = FrequentlyUpdated(recordedChanges, Filter{Resource: "pods"}, 3)
+	require.NoError(t, err)
+	assert.Empty(t, counts)
+}
+
+func TestToIntervals(t *testing.T) {
+	changes, err := Changes(recordedChanges, Filter{Resource: "clusteroperators", Name: "kube-apiserver"})
+	require.NoError(t, err)
+
+	intervals := ToIntervals(changes)
+	require.Len(t, intervals, 4)
+	for i, interval := range intervals {
+		assert.Equal(t, monitorapi.SourceResourceWatch, interval.Source)
+		assert.Equal(t, changes[i].ObservedTime, interval.From)
+		assert.Equal(t, interval.From, interval.To)
+		assert.Equal(t, "clusteroperators.config.openshift.io", interval.StructuredLocator.Keys[monitorapi.LocatorResourceKey])
+		assert.Equal(t, "kube-apiserver", interval.StructuredLocator.Keys[monitorapi.LocatorNameKey])
+	}
+	assert.Equal(t, monitorapi.ResourceAdded, intervals[0].StructuredMessage.Reason)
+	assert.Equal(t, monitorapi.ResourceModified, intervals[1].StructuredMessage.Reason)
+	assert.Equal(t, "200", intervals[1].StructuredMessage.Annotations[monitorapi.AnnotationResourceVersion])
+	assert.Equal(t, "cluster-kube-apiserver-operator", intervals[1].StructuredMessage.Annotations[monitorapi.AnnotationUser])
+	assert.Equal(t, "modified .status.conditions by cluster-kube-apiserver-operator", intervals[1].StructuredMessage.HumanMessage)
+	assert.Equal(t, monitorapi.ResourceDeleted, intervals[3].StructuredMessage.Reason)
+}
+
+func mapKeys[V any](m map[string]V) []string {
+	keys := []string{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -10,6 +10,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/managedfields"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
 	"sigs.k8s.io/structured-merge-diff/v4/typed"
 )
 
@@ -34,7 +35,21 @@ func modifiedFields(oldRuntimeObject, newRuntimeObject *unstructured.Unstructure
 }
 
 func whichUsersOwnModifiedFields(obj *unstructured.Unstructured, comparison typed.Comparison) ([]string, error) {
+	fieldsByUser, err := modifiedFieldsByUser(obj, comparison)
+	if err != nil {
+		return nil, err
+	}
+
 	users := sets.NewString()
+	for user := range fieldsByUser {
+		users.Insert(user)
+	}
+	return users.List(), nil
+}
+
+// modifiedFieldsByUser returns the fields in the comparison that are owned by each user, according to managedFields.
+func modifiedFieldsByUser(obj *unstructured.Unstructured, comparison typed.Comparison) (map[string]*fieldpath.Set, error) {
+	fieldsByUser := map[string]*fieldpath.Set{}
 
 	managers, err := managedfields.DecodeManagedFields(obj.GetManagedFields())
 	if err != nil {
@@ -45,17 +60,43 @@ func whichUsersOwnModifiedFields(obj *unstructured.Unstructured, comparison type
 		setByThisManager := managerSet.Set().Intersection(comparison.Modified.Union(comparison.Added).Union(comparison.Removed))
 		if !setByThisManager.Empty() {
 			// sometimes I'm seeing the entire manager json listed.  My guess is for subresources its tracked as a key.
+			user := manager
 			currManagerAsJSON := &metav1.ManagedFieldsEntry{}
-			if err := json.Unmarshal([]byte(manager), currManagerAsJSON); err != nil {
-				users.Insert(manager)
-			} else {
-				users.Insert(currManagerAsJSON.Manager)
+			if err := json.Unmarshal([]byte(manager), currManagerAsJSON); err == nil {
+				user = currManagerAsJSON.Manager
 			}
+			if existing, ok := fieldsByUser[user]; ok {
+				setByThisManager = existing.Union(setByThisManager)
+			}
+			fieldsByUser[user] = setByThisManager
 			continue
 		}
 	}
 
-	return users.List(), nil
+	return fieldsByUser, nil
+}
+
+// FieldDiff is the difference between two versions of a resource.
+type FieldDiff struct {
+	Comparison *typed.Comparison
+	// FieldsByUser is which of the changed fields each user owns, according to managedFields.
+	FieldsByUser map[string]*fieldpath.Set
+}
+
+// DiffObjects compares two versions of a resource and uses managedFields to find out who changed what.
+func DiffObjects(oldObj, obj *unstructured.Unstructured) (*FieldDiff, error) {
+	comparison, err := modifiedFields(oldObj, obj)
+	if err != nil {
+		return nil, err
+	}
+	fieldsByUser, err := modifiedFieldsByUser(obj, *comparison)
+	if err != nil {
+		return nil, err
+	}
+	return &FieldDiff{
+		Comparison:   comparison,
+		FieldsByUser: fieldsByUser,
+	}, nil
 }
 
 func objectGVKNN(obj runtime.Object) string {
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+// ConvertToGit replays the recorded changes into a git repository at repositoryPath, laid out exactly as GitStorage
+// would have written it, with one commit per change dated at the time the change was observed.
+func ConvertToGit(reader ChangeReader, repositoryPath string) (int, error) {
+	gitStorage, err := NewGitStorage(repositoryPath)
+	if err != nil {
+		return 0, err
+	}
+
+	commits := 0
+	err = reader.Changes(func(change *ResourceChange) error {
+		filePath := resourceFilename(change.Resource, change.Namespace, change.Name)
+		ocCommand := resourceOCCommand(change.Resource, change.Namespace, change.Name)
+
+		if change.Operation == OperationDeleted {
+			if _, err := os.Stat(filepath.Join(repositoryPath, filePath)); os.IsNotExist(err) {
+				klog.Warningf("Skipping deletion of %s, it was never added", filePath)
+				return nil
+			}
+			if err := gitStorage.commitRemove(filePath, "unknown", ocCommand, change.ObservedTime); err != nil {
+				return fmt.Errorf("unable to commit deletion of %s: %w", filePath, err)
+			}
+			commits++
+			return nil
+		}
+
+		content, err := yaml.JSONToYAML(change.Object)
+		if err != nil {
+			return fmt.Errorf("unable to decode %s at resourceVersion %s: %w", filePath, change.ResourceVersion, err)
+		}
+		// a restarted watch lists everything again, which would leave nothing to commit.
+		if existing, err := os.ReadFile(filepath.Join(repositoryPath, filePath)); err == nil && bytes.Equal(existing, content) {
+			return nil
+		}
+		operation, err := gitStorage.write(filePath, content)
+		if err != nil {
+			return err
+		}
+		modifyingUser := change.ModifyingUser
+		if len(modifyingUser) == 0 {
+			modifyingUser = "unknown"
+		}
+		switch operation {
+		case gitOpAdded:
+			err = gitStorage.commitAdd(filePath, modifyingUser, ocCommand, change.ObservedTime)
+		case gitOpModified:
+			err = gitStorage.commitModify(filePath, modifyingUser, ocCommand, change.ObservedTime)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to commit %s at resourceVersion %s: %w", filePath, change.ResourceVersion, err)
+		}
+		commits++
+		return nil
+	})
+	return commits, err
+}
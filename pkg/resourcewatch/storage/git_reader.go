@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/utils/merkletrie"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// gitChangeReader reads the changes committed by GitStorage, one change per commit.
+type gitChangeReader struct {
+	repo *git.Repository
+}
+
+func newGitChangeReader(path string) (*gitChangeReader, error) {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open git repository %s: %w", path, err)
+	}
+	return &gitChangeReader{repo: repo}, nil
+}
+
+func (r *gitChangeReader) Changes(fn func(change *ResourceChange) error) error {
+	// the history is linear, and commits made within the same second can't be ordered by time.
+	commitIter, err := r.repo.Log(&git.LogOptions{})
+	if err == plumbing.ErrReferenceNotFound {
+		// nothing has been committed yet.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	commits := []*object.Commit{}
+	if err := commitIter.ForEach(func(commit *object.Commit) error {
+		commits = append(commits, commit)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// the log is newest first.
+	for i := len(commits) - 1; i >= 0; i-- {
+		changes, err := changesInCommit(commits[i])
+		if err != nil {
+			return fmt.Errorf("unable to read commit %s: %w", commits[i].Hash, err)
+		}
+		for _, change := range changes {
+			if err := fn(change); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *gitChangeReader) Close() error {
+	return nil
+}
+
+func changesInCommit(commit *object.Commit) ([]*ResourceChange, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	var parentTree *object.Tree
+	if commit.NumParents() > 0 {
+		parent, err := commit.Parent(0)
+		if err != nil {
+			return nil, err
+		}
+		if parentTree, err = parent.Tree(); err != nil {
+			return nil, err
+		}
+	}
+	treeChanges, err := object.DiffTree(parentTree, tree)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []*ResourceChange{}
+	for _, treeChange := range treeChanges {
+		action, err := treeChange.Action()
+		if err != nil {
+			return nil, err
+		}
+		from, to, err := treeChange.Files()
+		if err != nil {
+			return nil, err
+		}
+
+		change := &ResourceChange{
+			ObservedTime:  commit.Author.When.UTC(),
+			ModifyingUser: commit.Author.Name,
+		}
+		file, filename := to, treeChange.To.Name
+		switch action {
+		case merkletrie.Insert:
+			change.Operation = OperationAdded
+		case merkletrie.Modify:
+			change.Operation = OperationModified
+		case merkletrie.Delete:
+			change.Operation = OperationDeleted
+			change.ModifyingUser = ""
+			file, filename = from, treeChange.From.Name
+		}
+
+		gvr, namespace, name, ok := parseResourceFilename(filename)
+		if !ok {
+			continue
+		}
+		content, err := file.Contents()
+		if err != nil {
+			return nil, err
+		}
+		if change.Object, err = yaml.YAMLToJSON([]byte(content)); err != nil {
+			return nil, fmt.Errorf("unable to decode %s: %w", filename, err)
+		}
+		obj, err := change.Unstructured()
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode %s: %w", filename, err)
+		}
+		if gv, err := schema.ParseGroupVersion(obj.GetAPIVersion()); err == nil {
+			gvr.Version = gv.Version
+		}
+		change.Resource = gvr
+		change.Namespace = namespace
+		change.Name = name
+		change.ResourceVersion = obj.GetResourceVersion()
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// parseResourceFilename is the reverse of resourceFilename.  The version is not part of the filename.
+func parseResourceFilename(filename string) (gvr schema.GroupVersionResource, namespace, name string, ok bool) {
+	parts := strings.Split(filepath.ToSlash(filename), "/")
+	switch {
+	case len(parts) == 4 && parts[0] == "cluster-scoped-resources":
+		gvr.Group, gvr.Resource, name = parts[1], parts[2], parts[3]
+	case len(parts) == 5 && parts[0] == "namespaces":
+		namespace, gvr.Group, gvr.Resource, name = parts[1], parts[2], parts[3], parts[4]
+	default:
+		return gvr, "", "", false
+	}
+	if gvr.Group == "core" {
+		gvr.Group = ""
+	}
+	if !strings.HasSuffix(name, ".yaml") {
+		return gvr, "", "", false
+	}
+	return gvr, namespace, strings.TrimSuffix(name, ".yaml"), true
+}
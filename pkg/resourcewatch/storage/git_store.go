@@ -61,6 +61,8 @@ type GitStorage struct {
 	path string
 
 	currentlyRecording workingSet
+	// inProgress tracks the commits that are still running, so Close can wait for them.
+	inProgress sync.WaitGroup
 
 	// Writing to Git repository must be synced otherwise Git will freak out
 	sync.Mutex
@@ -123,24 +125,13 @@ func (s *GitStorage) handle(gvr schema.GroupVersionResource, oldObj, obj *unstru
 		klog.Warningf("Decoding %q failed: %v", filePath, err)
 		return
 	}
-	resourceName := ""
-	if len(gvr.Group) == 0 {
-		resourceName = gvr.Resource
-	} else {
-		resourceName = gvr.Resource + "." + gvr.Group
-	}
-	ocCommand := ""
-	if len(obj.GetNamespace()) == 0 {
-		ocCommand = fmt.Sprintf("%s/%s", resourceName, obj.GetName())
-	} else {
-		ocCommand = fmt.Sprintf("%s/%s -n %s", resourceName, obj.GetName(), obj.GetNamespace())
-	}
+	ocCommand := resourceOCCommand(gvr, obj.GetNamespace(), obj.GetName())
 
 	if delete {
 		klog.Infof("Calling commitRemove for %s", filePath)
 		// ignore error, we've already reported and we're not doing anything else.
 		pollErr := wait.PollImmediate(1*time.Second, 15*time.Second, func() (bool, error) {
-			if err := s.commitRemove(filePath, "unknown", ocCommand); err != nil {
+			if err := s.commitRemove(filePath, "unknown", ocCommand, time.Now()); err != nil {
 				klog.Error(err)
 				return false, nil
 			}
@@ -172,13 +163,13 @@ func (s *GitStorage) handle(gvr schema.GroupVersionResource, oldObj, obj *unstru
 		switch {
 		case operation == gitOpAdded:
 			klog.Infof("Calling commitAdd for %s", filePath)
-			if err := s.commitAdd(filePath, modifyingUser, ocCommand); err != nil {
+			if err := s.commitAdd(filePath, modifyingUser, ocCommand, time.Now()); err != nil {
 				klog.Error(err)
 				return false, nil
 			}
 		case operation == gitOpModified:
 			klog.Infof("Calling commitModify for %s", filePath)
-			if err := s.commitModify(filePath, modifyingUser, ocCommand); err != nil {
+			if err := s.commitModify(filePath, modifyingUser, ocCommand, time.Now()); err != nil {
 				klog.Error(err)
 				return false, nil
 			}
@@ -205,9 +196,11 @@ func (s *GitStorage) OnAdd(gvr schema.GroupVersionResource, obj interface{}) {
 		return
 	}
 	s.currentlyRecording.reserve(key)
+	s.inProgress.Add(1)
 
 	// start new go func to allow parallel processing where possible and to avoid blocking all progress on retries.
 	go func() {
+		defer s.inProgress.Done()
 		defer s.currentlyRecording.release(key)
 		s.handle(gvr, nil, objUnstructured, false)
 	}()
@@ -224,9 +217,11 @@ func (s *GitStorage) OnUpdate(gvr schema.GroupVersionResource, oldObj, obj inter
 		return
 	}
 	s.currentlyRecording.reserve(key)
+	s.inProgress.Add(1)
 
 	// start new go func to allow parallel processing where possible and to avoid blocking all progress on retries.
 	go func() {
+		defer s.inProgress.Done()
 		defer s.currentlyRecording.release(key)
 		s.handle(gvr, oldObjUnstructured, objUnstructured, false)
 	}()
@@ -254,14 +249,31 @@ func (s *GitStorage) OnDelete(gvr schema.GroupVersionResource, obj interface{})
 		return
 	}
 	s.currentlyRecording.reserve(key)
+	s.inProgress.Add(1)
 
 	// start new go func to allow parallel processing where possible and to avoid blocking all progress on retries.
 	go func() {
+		defer s.inProgress.Done()
 		defer s.currentlyRecording.release(key)
 		s.handle(gvr, nil, objUnstructured, true)
 	}()
 }
 
+func (s *GitStorage) Close() error {
+	// wait for the commits that are still in progress.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.inProgress.Wait()
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-time.After(30 * time.Second):
+		return fmt.Errorf("timed out waiting for commits in progress to %s", s.path)
+	}
+}
+
 // guessAtModifyingUsers tries to figure out who modified the resource
 func guessAtModifyingUsers(oldObj, obj *unstructured.Unstructured) (string, error) {
 	if oldObj == nil {
@@ -306,6 +318,20 @@ func decodeUnstructuredObject(gvr schema.GroupVersionResource, objUnstructured *
 	return filename, objectYAML, err
 }
 
+// resourceOCCommand is how the resource would be referred to by oc, used in commit messages.
+func resourceOCCommand(gvr schema.GroupVersionResource, namespace, name string) string {
+	resourceName := ""
+	if len(gvr.Group) == 0 {
+		resourceName = gvr.Resource
+	} else {
+		resourceName = gvr.Resource + "." + gvr.Group
+	}
+	if len(namespace) == 0 {
+		return fmt.Sprintf("%s/%s", resourceName, name)
+	}
+	return fmt.Sprintf("%s/%s -n %s", resourceName, name, namespace)
+}
+
 // resourceFilename extracts the filename out from the group version kind
 func resourceFilename(gvr schema.GroupVersionResource, namespace, name string) string {
 	groupStr := ""
@@ -322,13 +348,26 @@ func resourceFilename(gvr schema.GroupVersionResource, namespace, name string) s
 	return filepath.Join("namespaces", namespace, groupStr, gvr.Resource, name+".yaml")
 }
 
-func (s *GitStorage) commitAdd(path, author, ocCommand string) error {
+// gitCommand runs command in the repository, dating the commit it makes at when.
+func (s *GitStorage) gitCommand(command string, when time.Time) *exec.Cmd {
+	osCommand := exec.Command("bash", "-e", "-c", command)
+	osCommand.Dir = s.path
+	gitDate := when.Format(time.RFC3339)
+	osCommand.Env = append(os.Environ(),
+		"GIT_AUTHOR_DATE="+gitDate,
+		"GIT_COMMITTER_DATE="+gitDate,
+		"GIT_COMMITTER_NAME=ci-monitor",
+		"GIT_COMMITTER_EMAIL=ci-monitor@openshift.io",
+	)
+	return osCommand
+}
+
+func (s *GitStorage) commitAdd(path, author, ocCommand string, when time.Time) error {
 	authorString := fmt.Sprintf("%s <ci-monitor@openshift.io>", author)
 	commitMessage := fmt.Sprintf("added %s", ocCommand)
 	command := fmt.Sprintf(`git add %q && git commit --author=%q -m %q`, path, authorString, commitMessage)
 
-	osCommand := exec.Command("bash", "-e", "-c", command)
-	osCommand.Dir = s.path
+	osCommand := s.gitCommand(command, when)
 	output, err := osCommand.CombinedOutput()
 	if err != nil {
 		klog.Errorf("Ran %v\n%v\n\n", command, string(output))
@@ -346,13 +385,12 @@ func (s *GitStorage) commitAdd(path, author, ocCommand string) error {
 	return nil
 }
 
-func (s *GitStorage) commitModify(path, author, ocCommand string) error {
+func (s *GitStorage) commitModify(path, author, ocCommand string, when time.Time) error {
 	authorString := fmt.Sprintf("%s <ci-monitor@openshift.io>", author)
 	commitMessage := fmt.Sprintf("modifed %s", ocCommand)
 	command := fmt.Sprintf(`git add %q && git commit --author=%q -m %q`, path, authorString, commitMessage)
 
-	osCommand := exec.Command("bash", "-e", "-c", command)
-	osCommand.Dir = s.path
+	osCommand := s.gitCommand(command, when)
 	output, err := osCommand.CombinedOutput()
 	if err != nil {
 		klog.Errorf("Ran %v\n%v\n\n", command, string(output))
@@ -380,13 +418,12 @@ func (s *GitStorage) commitModify(path, author, ocCommand string) error {
 	return nil
 }
 
-func (s *GitStorage) commitRemove(path, author, ocCommand string) error {
+func (s *GitStorage) commitRemove(path, author, ocCommand string, when time.Time) error {
 	authorString := fmt.Sprintf("%s <ci-monitor@openshift.io>", author)
 	commitMessage := fmt.Sprintf("removed %s", ocCommand)
 	command := fmt.Sprintf(`rm %q && git rm %q && git commit --author=%q -m %q`, path, path, authorString, commitMessage)
 
-	osCommand := exec.Command("bash", "-e", "-c", command)
-	osCommand.Dir = s.path
+	osCommand := s.gitCommand(command, when)
 	output, err := osCommand.CombinedOutput()
 	if err != nil {
 		klog.Errorf("Ran %v\n%v\n\n", command, string(output))
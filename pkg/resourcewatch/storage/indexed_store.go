@@ -0,0 +1,273 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/klog/v2"
+)
+
+const indexedStorageFilename = "resource-changes.db"
+
+const (
+	// indexedQueueLength is how many changes may wait to be written before recording blocks.
+	indexedQueueLength = 1000
+	// indexedMaxBatch is the most changes written in a single transaction.  Every transaction is synced to disk,
+	// so changes that arrive while one is written are written together in the next.
+	indexedMaxBatch = 500
+)
+
+var (
+	// changesBucket holds every change keyed by the order it was observed in.
+	changesBucket = []byte("changes")
+	// resourceVersionsBucket indexes changesBucket by group/version/resource/namespace/name/resourceVersion, with a
+	// /deleted suffix for deletions.
+	resourceVersionsBucket = []byte("resourceVersions")
+)
+
+// IndexedStorage stores every change in a single file database, indexed so that the history of a single resource
+// can be read without scanning every change.
+type IndexedStorage struct {
+	db *bolt.DB
+
+	// queue holds the changes waiting for writeQueued, which closes writerDone once queue is closed and drained.
+	queue      chan indexedChange
+	writerDone chan struct{}
+
+	// lock is held to send to queue, and to close it, so that changes recorded once closed are dropped.
+	lock   sync.RWMutex
+	closed bool
+}
+
+// indexedChange is an encoded change waiting to be written.
+type indexedChange struct {
+	resourceVersionKey []byte
+	value              []byte
+	description        string
+}
+
+// NewIndexedStorage returns the resource event handler capable of storing changes observed on resources into
+// resource-changes.db in the directory at path.  An existing database is added to.
+func NewIndexedStorage(path string) (*IndexedStorage, error) {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, err
+	}
+	return openIndexedStorage(filepath.Join(path, indexedStorageFilename), false)
+}
+
+func openIndexedStorage(filename string, readOnly bool) (*IndexedStorage, error) {
+	db, err := bolt.Open(filename, 0644, &bolt.Options{Timeout: 10 * time.Second, ReadOnly: readOnly})
+	if err != nil {
+		return nil, fmt.Errorf("unable to open %s: %w", filename, err)
+	}
+	if readOnly {
+		return &IndexedStorage{db: db}, nil
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{changesBucket, resourceVersionsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("unable to initialize %s: %w", filename, err)
+	}
+	storage := &IndexedStorage{
+		db:         db,
+		queue:      make(chan indexedChange, indexedQueueLength),
+		writerDone: make(chan struct{}),
+	}
+	go storage.writeQueued()
+	return storage, nil
+}
+
+// resourceKeyPrefix is the prefix shared by every resourceVersion of a resource in resourceVersionsBucket.
+func resourceKeyPrefix(gvr schema.GroupVersionResource, namespace, name string) []byte {
+	// none of these may contain a "/", except for an empty group.
+	return []byte(strings.Join([]string{gvr.Group, gvr.Version, gvr.Resource, namespace, name, ""}, "/"))
+}
+
+func (s *IndexedStorage) record(operation Operation, gvr schema.GroupVersionResource, oldObj, obj *unstructured.Unstructured) {
+	change, err := newResourceChange(operation, gvr, oldObj, obj)
+	if err != nil {
+		klog.Warningf("Encoding %s %s/%s failed: %v", gvr.String(), obj.GetNamespace(), obj.GetName(), err)
+		return
+	}
+	value, err := json.Marshal(change)
+	if err != nil {
+		klog.Warningf("Encoding %s %s/%s failed: %v", gvr.String(), obj.GetNamespace(), obj.GetName(), err)
+		return
+	}
+
+	resourceVersionKey := append(resourceKeyPrefix(gvr, change.Namespace, change.Name), []byte(change.ResourceVersion)...)
+	if operation == OperationDeleted {
+		// the tombstone of a deletion we missed carries the last resourceVersion we saw, keep both.
+		resourceVersionKey = append(resourceVersionKey, []byte("/deleted")...)
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if s.closed {
+		klog.Warningf("Dropping %s %s/%s observed after the storage was closed", gvr.String(), obj.GetNamespace(), obj.GetName())
+		return
+	}
+	s.queue <- indexedChange{
+		resourceVersionKey: resourceVersionKey,
+		value:              value,
+		description:        fmt.Sprintf("%s %s/%s", gvr.String(), obj.GetNamespace(), obj.GetName()),
+	}
+}
+
+// writeQueued writes the queued changes until the queue is closed, everything that is queued by the time a
+// transaction starts is written in it, up to indexedMaxBatch changes.
+func (s *IndexedStorage) writeQueued() {
+	defer close(s.writerDone)
+	for first := range s.queue {
+		batch := []indexedChange{first}
+	fillBatch:
+		for len(batch) < indexedMaxBatch {
+			select {
+			case change, ok := <-s.queue:
+				if !ok {
+					break fillBatch
+				}
+				batch = append(batch, change)
+			default:
+				break fillBatch
+			}
+		}
+		s.write(batch)
+	}
+}
+
+func (s *IndexedStorage) write(batch []indexedChange) {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		resourceVersions := tx.Bucket(resourceVersionsBucket)
+		changes := tx.Bucket(changesBucket)
+		for _, change := range batch {
+			// a restarted watch lists everything again, there is no need to record the same resourceVersion twice.
+			if resourceVersions.Get(change.resourceVersionKey) != nil {
+				continue
+			}
+
+			sequence, err := changes.NextSequence()
+			if err != nil {
+				return err
+			}
+			changeKey := make([]byte, 8)
+			binary.BigEndian.PutUint64(changeKey, sequence)
+			if err := changes.Put(changeKey, change.value); err != nil {
+				return fmt.Errorf("storing %s failed: %w", change.description, err)
+			}
+			if err := resourceVersions.Put(change.resourceVersionKey, changeKey); err != nil {
+				return fmt.Errorf("storing %s failed: %w", change.description, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		klog.Errorf("Storing %d changes failed: %v", len(batch), err)
+	}
+}
+
+func (s *IndexedStorage) OnAdd(gvr schema.GroupVersionResource, obj interface{}) {
+	s.record(OperationAdded, gvr, nil, obj.(*unstructured.Unstructured))
+}
+
+func (s *IndexedStorage) OnUpdate(gvr schema.GroupVersionResource, oldObj, obj interface{}) {
+	s.record(OperationModified, gvr, oldObj.(*unstructured.Unstructured), obj.(*unstructured.Unstructured))
+}
+
+func (s *IndexedStorage) OnDelete(gvr schema.GroupVersionResource, obj interface{}) {
+	objUnstructured, err := unstructuredFromInformer(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	s.record(OperationDeleted, gvr, nil, objUnstructured)
+}
+
+// Close writes the changes that are still queued before closing the database.  Changes recorded after Close are
+// dropped.
+func (s *IndexedStorage) Close() error {
+	if s.queue != nil {
+		s.lock.Lock()
+		if !s.closed {
+			s.closed = true
+			close(s.queue)
+		}
+		s.lock.Unlock()
+		<-s.writerDone
+	}
+	return s.db.Close()
+}
+
+func (s *IndexedStorage) Changes(fn func(change *ResourceChange) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		changes := tx.Bucket(changesBucket)
+		if changes == nil {
+			return nil
+		}
+		return changes.ForEach(func(_, value []byte) error {
+			change := &ResourceChange{}
+			if err := json.Unmarshal(value, change); err != nil {
+				return err
+			}
+			return fn(change)
+		})
+	})
+}
+
+// ResourceHistory returns the changes of a single resource in the order they were observed.
+func (s *IndexedStorage) ResourceHistory(gvr schema.GroupVersionResource, namespace, name string) ([]*ResourceChange, error) {
+	history := []*ResourceChange{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		resourceVersions := tx.Bucket(resourceVersionsBucket)
+		changes := tx.Bucket(changesBucket)
+		if resourceVersions == nil || changes == nil {
+			return nil
+		}
+
+		// resourceVersions are opaque strings, so the index is only used to find the changes.  Their order comes from
+		// the order they were observed in.
+		changeKeys := [][]byte{}
+		prefix := resourceKeyPrefix(gvr, namespace, name)
+		cursor := resourceVersions.Cursor()
+		for key, changeKey := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, changeKey = cursor.Next() {
+			changeKeys = append(changeKeys, changeKey)
+		}
+		// big endian sequence numbers sort the same as bytes.
+		sort.Slice(changeKeys, func(i, j int) bool {
+			return bytes.Compare(changeKeys[i], changeKeys[j]) < 0
+		})
+
+		for _, changeKey := range changeKeys {
+			change := &ResourceChange{}
+			if err := json.Unmarshal(changes.Get(changeKey), change); err != nil {
+				return err
+			}
+			history = append(history, change)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
@@ -0,0 +1,194 @@
+package storage
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/klog/v2"
+)
+
+const changeLogFilename = "resource-changes.jsonl.gz"
+
+// logFlushInterval is how often buffered changes are flushed, so that everything observed up to then can be read
+// if we are killed.  Flushing every change would compress each one on its own.
+var logFlushInterval = time.Second
+
+// LogStorage appends every change as a line of JSON to a gzip compressed file.  Unlike GitStorage there is no
+// external process involved, so changes are recorded in the order they are observed without any throttling.
+type LogStorage struct {
+	file *os.File
+	gzip *gzip.Writer
+
+	lock sync.Mutex
+	// dirty is set when changes were written since the last flush.
+	dirty bool
+	// closed is set by Close, changes recorded afterwards are dropped.
+	closed bool
+
+	stopFlushing chan struct{}
+	flushingDone chan struct{}
+}
+
+// NewLogStorage returns the resource event handler capable of storing changes observed on resources into
+// resource-changes.jsonl.gz in the directory at path.  An existing log is appended to.
+func NewLogStorage(path string) (*LogStorage, error) {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, err
+	}
+	// gzip allows multiple members to be concatenated, so a restarted process can append to the same file.
+	file, err := os.OpenFile(filepath.Join(path, changeLogFilename), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	storage := &LogStorage{
+		file:         file,
+		gzip:         gzip.NewWriter(file),
+		stopFlushing: make(chan struct{}),
+		flushingDone: make(chan struct{}),
+	}
+	go storage.flushPeriodically()
+	return storage, nil
+}
+
+func (s *LogStorage) flushPeriodically() {
+	defer close(s.flushingDone)
+	ticker := time.NewTicker(logFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stopFlushing:
+			return
+		case <-ticker.C:
+			s.flush()
+		}
+	}
+}
+
+func (s *LogStorage) flush() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if !s.dirty {
+		return
+	}
+	if err := s.gzip.Flush(); err != nil {
+		klog.Errorf("Flushing %s failed: %v", s.file.Name(), err)
+		return
+	}
+	s.dirty = false
+}
+
+func (s *LogStorage) record(operation Operation, gvr schema.GroupVersionResource, oldObj, obj *unstructured.Unstructured) {
+	change, err := newResourceChange(operation, gvr, oldObj, obj)
+	if err != nil {
+		klog.Warningf("Encoding %s %s/%s failed: %v", gvr.String(), obj.GetNamespace(), obj.GetName(), err)
+		return
+	}
+	line, err := json.Marshal(change)
+	if err != nil {
+		klog.Warningf("Encoding %s %s/%s failed: %v", gvr.String(), obj.GetNamespace(), obj.GetName(), err)
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed {
+		klog.Warningf("Dropping %s %s/%s observed after the storage was closed", gvr.String(), obj.GetNamespace(), obj.GetName())
+		return
+	}
+	if _, err := s.gzip.Write(append(line, '\n')); err != nil {
+		klog.Errorf("Writing %s %s/%s failed: %v", gvr.String(), obj.GetNamespace(), obj.GetName(), err)
+		return
+	}
+	s.dirty = true
+}
+
+func (s *LogStorage) OnAdd(gvr schema.GroupVersionResource, obj interface{}) {
+	s.record(OperationAdded, gvr, nil, obj.(*unstructured.Unstructured))
+}
+
+func (s *LogStorage) OnUpdate(gvr schema.GroupVersionResource, oldObj, obj interface{}) {
+	s.record(OperationModified, gvr, oldObj.(*unstructured.Unstructured), obj.(*unstructured.Unstructured))
+}
+
+func (s *LogStorage) OnDelete(gvr schema.GroupVersionResource, obj interface{}) {
+	objUnstructured, err := unstructuredFromInformer(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	s.record(OperationDeleted, gvr, nil, objUnstructured)
+}
+
+// Close flushes the changes before closing the file.  Changes recorded after Close are dropped.
+func (s *LogStorage) Close() error {
+	close(s.stopFlushing)
+	<-s.flushingDone
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.closed = true
+	if err := s.gzip.Close(); err != nil {
+		return err
+	}
+	return s.file.Close()
+}
+
+type changeLogReader struct {
+	filename string
+}
+
+func (r *changeLogReader) Changes(fn func(change *ResourceChange) error) error {
+	file, err := os.Open(r.filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("unable to read %s: %w", r.filename, err)
+	}
+	defer gzipReader.Close()
+
+	reader := bufio.NewReader(gzipReader)
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			change := &ResourceChange{}
+			if jsonErr := json.Unmarshal(line, change); jsonErr != nil {
+				// the last line is cut short if we were killed mid-write, everything before it is still good.
+				if err == io.EOF || err == io.ErrUnexpectedEOF {
+					klog.Warningf("Ignoring truncated last line %d of %s: %v", lineNumber, r.filename, jsonErr)
+					return nil
+				}
+				return fmt.Errorf("unable to parse line %d of %s: %w", lineNumber, r.filename, jsonErr)
+			}
+			if fnErr := fn(change); fnErr != nil {
+				return fnErr
+			}
+		}
+		switch {
+		case err == io.EOF:
+			return nil
+		case err == io.ErrUnexpectedEOF:
+			klog.Warningf("%s was not closed cleanly, read %d lines", r.filename, lineNumber)
+			return nil
+		case err != nil:
+			return fmt.Errorf("unable to read %s: %w", r.filename, err)
+		}
+	}
+}
+
+func (r *changeLogReader) Close() error {
+	return nil
+}
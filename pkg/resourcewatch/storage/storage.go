@@ -0,0 +1,167 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// ResourceStorage records the changes observed by the resource informers.
+type ResourceStorage interface {
+	OnAdd(gvr schema.GroupVersionResource, obj interface{})
+	OnUpdate(gvr schema.GroupVersionResource, oldObj, obj interface{})
+	OnDelete(gvr schema.GroupVersionResource, obj interface{})
+
+	// Close flushes anything buffered.  No changes may be recorded after Close.
+	Close() error
+}
+
+// StorageType selects a ResourceStorage implementation.
+type StorageType string
+
+const (
+	// StorageTypeGit commits every change to a git repository.
+	StorageTypeGit StorageType = "git"
+	// StorageTypeLog appends every change to a gzip compressed JSON lines file.
+	StorageTypeLog StorageType = "log"
+	// StorageTypeIndexed stores every change in a database indexed by resource and resourceVersion.
+	StorageTypeIndexed StorageType = "indexed"
+)
+
+var AllStorageTypes = []StorageType{StorageTypeGit, StorageTypeLog, StorageTypeIndexed}
+
+// NewResourceStorage creates the storage of the given type in the directory at path.
+func NewResourceStorage(storageType StorageType, path string) (ResourceStorage, error) {
+	switch storageType {
+	case StorageTypeGit:
+		return NewGitStorage(path)
+	case StorageTypeLog:
+		return NewLogStorage(path)
+	case StorageTypeIndexed:
+		return NewIndexedStorage(path)
+	default:
+		return nil, fmt.Errorf("unknown storage type %q, expected one of %v", storageType, AllStorageTypes)
+	}
+}
+
+type Operation string
+
+const (
+	OperationAdded    Operation = "Added"
+	OperationModified Operation = "Modified"
+	OperationDeleted  Operation = "Deleted"
+)
+
+// ResourceChange is a single observed change to a resource.
+type ResourceChange struct {
+	ObservedTime    time.Time                   `json:"observedTime"`
+	Operation       Operation                   `json:"operation"`
+	Resource        schema.GroupVersionResource `json:"resource"`
+	Namespace       string                      `json:"namespace,omitempty"`
+	Name            string                      `json:"name"`
+	ResourceVersion string                      `json:"resourceVersion"`
+	// ModifyingUser is our best guess at who made the change, see guessAtModifyingUsers.
+	ModifyingUser string `json:"modifyingUser,omitempty"`
+	// Object is the full content of the resource after the change, or the last known content if it was deleted.
+	Object json.RawMessage `json:"object"`
+}
+
+// Unstructured decodes the object of the change.
+func (c *ResourceChange) Unstructured() (*unstructured.Unstructured, error) {
+	obj, _, err := unstructured.UnstructuredJSONScheme.Decode(c.Object, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	objUnstructured, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("%s %s is a list, not an object", c.Resource.String(), c.Name)
+	}
+	return objUnstructured, nil
+}
+
+// newResourceChange describes the change from oldObj to obj.  oldObj is nil for additions and deletions.
+func newResourceChange(operation Operation, gvr schema.GroupVersionResource, oldObj, obj *unstructured.Unstructured) (*ResourceChange, error) {
+	objectBytes, err := runtime.Encode(unstructured.UnstructuredJSONScheme, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	change := &ResourceChange{
+		ObservedTime:    time.Now().UTC(),
+		Operation:       operation,
+		Resource:        gvr,
+		Namespace:       obj.GetNamespace(),
+		Name:            obj.GetName(),
+		ResourceVersion: obj.GetResourceVersion(),
+		Object:          objectBytes,
+	}
+	if operation != OperationDeleted {
+		modifyingUser, err := guessAtModifyingUsers(oldObj, obj)
+		if err != nil {
+			klog.Warningf("Guessing users failed for %s %s/%s: %v", gvr.String(), change.Namespace, change.Name, err)
+			modifyingUser = err.Error()
+		}
+		change.ModifyingUser = modifyingUser
+	}
+	return change, nil
+}
+
+// unstructuredFromInformer returns the object passed to an informer handler, unwrapping tombstones of deletions.
+func unstructuredFromInformer(obj interface{}) (*unstructured.Unstructured, error) {
+	if objUnstructured, ok := obj.(*unstructured.Unstructured); ok {
+		return objUnstructured, nil
+	}
+	tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+	if !ok {
+		return nil, fmt.Errorf("couldn't get object from tombstone %#v", obj)
+	}
+	objUnstructured, ok := tombstone.Obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("tombstone contained object that is not unstructured %#v", obj)
+	}
+	return objUnstructured, nil
+}
+
+// ChangeReader reads back the changes recorded by a ResourceStorage.
+type ChangeReader interface {
+	// Changes calls fn for every recorded change in the order they were observed, stopping at the first error.
+	Changes(fn func(change *ResourceChange) error) error
+	Close() error
+}
+
+// NewChangeReader opens the git, log or indexed storage at path, which may be the storage directory or the file in it.
+func NewChangeReader(path string) (ChangeReader, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		switch filepath.Base(path) {
+		case changeLogFilename:
+			return &changeLogReader{filename: path}, nil
+		case indexedStorageFilename:
+			return openIndexedStorage(path, true)
+		default:
+			return nil, fmt.Errorf("%s is not a %s or %s file", path, changeLogFilename, indexedStorageFilename)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
+		return newGitChangeReader(path)
+	}
+	if _, err := os.Stat(filepath.Join(path, changeLogFilename)); err == nil {
+		return &changeLogReader{filename: filepath.Join(path, changeLogFilename)}, nil
+	}
+	if _, err := os.Stat(filepath.Join(path, indexedStorageFilename)); err == nil {
+		return openIndexedStorage(filepath.Join(path, indexedStorageFilename), true)
+	}
+	return nil, fmt.Errorf("no git repository, %s or %s found in %s", changeLogFilename, indexedStorageFilename, path)
+}
@@ -0,0 +1,177 @@
+package storage
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+var clusterOperatorsResource = schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: "clusteroperators"}
+
+func clusterOperator(name, resourceVersion, message string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "config.openshift.io/v1",
+		"kind":       "ClusterOperator",
+		"metadata": map[string]interface{}{
+			"name":            name,
+			"resourceVersion": resourceVersion,
+		},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Available", "status": "True", "message": message},
+			},
+		},
+	}}
+}
+
+// recordChanges adds, updates, re-adds as a restarted watch would, and deletes through a tombstone.
+func recordChanges(resourceStorage ResourceStorage) {
+	v1 := clusterOperator("kube-apiserver", "100", "first")
+	v2 := clusterOperator("kube-apiserver", "200", "second")
+	resourceStorage.OnAdd(clusterOperatorsResource, v1)
+	resourceStorage.OnAdd(clusterOperatorsResource, clusterOperator("etcd", "150", "etcd"))
+	resourceStorage.OnUpdate(clusterOperatorsResource, v1, v2)
+	resourceStorage.OnAdd(clusterOperatorsResource, v2)
+	resourceStorage.OnDelete(clusterOperatorsResource, cache.DeletedFinalStateUnknown{Key: "kube-apiserver", Obj: v2})
+}
+
+type recordedChange struct {
+	Operation       Operation
+	Name            string
+	ResourceVersion string
+}
+
+func readChanges(t *testing.T, path string) []recordedChange {
+	reader, err := NewChangeReader(path)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	changes := []recordedChange{}
+	require.NoError(t, reader.Changes(func(change *ResourceChange) error {
+		changes = append(changes, recordedChange{change.Operation, change.Name, change.ResourceVersion})
+		assert.Equal(t, clusterOperatorsResource, change.Resource)
+		obj, err := change.Unstructured()
+		require.NoError(t, err)
+		assert.Equal(t, change.Name, obj.GetName())
+		return nil
+	}))
+	return changes
+}
+
+func TestLogStorage(t *testing.T) {
+	storageDir := t.TempDir()
+	logStorage, err := NewLogStorage(storageDir)
+	require.NoError(t, err)
+	recordChanges(logStorage)
+	require.NoError(t, logStorage.Close())
+
+	// a restarted process appends to the same log.
+	logStorage, err = NewLogStorage(storageDir)
+	require.NoError(t, err)
+	logStorage.OnAdd(clusterOperatorsResource, clusterOperator("kube-apiserver", "300", "recreated"))
+	require.NoError(t, logStorage.Close())
+
+	assert.Equal(t, []recordedChange{
+		{OperationAdded, "kube-apiserver", "100"},
+		{OperationAdded, "etcd", "150"},
+		{OperationModified, "kube-apiserver", "200"},
+		{OperationAdded, "kube-apiserver", "200"},
+		{OperationDeleted, "kube-apiserver", "200"},
+		{OperationAdded, "kube-apiserver", "300"},
+	}, readChanges(t, storageDir))
+}
+
+func TestIndexedStorage(t *testing.T) {
+	storageDir := t.TempDir()
+	indexedStorage, err := NewIndexedStorage(storageDir)
+	require.NoError(t, err)
+	recordChanges(indexedStorage)
+	require.NoError(t, indexedStorage.Close())
+
+	// the duplicate of resourceVersion 200 is only recorded once.
+	assert.Equal(t, []recordedChange{
+		{OperationAdded, "kube-apiserver", "100"},
+		{OperationAdded, "etcd", "150"},
+		{OperationModified, "kube-apiserver", "200"},
+		{OperationDeleted, "kube-apiserver", "200"},
+	}, readChanges(t, filepath.Join(storageDir, indexedStorageFilename)))
+
+	indexedStorage, err = openIndexedStorage(filepath.Join(storageDir, indexedStorageFilename), true)
+	require.NoError(t, err)
+	defer indexedStorage.Close()
+	history, err := indexedStorage.ResourceHistory(clusterOperatorsResource, "", "kube-apiserver")
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Equal(t, OperationAdded, history[0].Operation)
+	assert.Equal(t, OperationModified, history[1].Operation)
+	assert.Equal(t, OperationDeleted, history[2].Operation)
+}
+
+func TestRecordAfterClose(t *testing.T) {
+	storageDir := t.TempDir()
+	logStorage, err := NewLogStorage(storageDir)
+	require.NoError(t, err)
+	indexedStorage, err := NewIndexedStorage(storageDir)
+	require.NoError(t, err)
+
+	for _, resourceStorage := range []ResourceStorage{logStorage, indexedStorage} {
+		resourceStorage.OnAdd(clusterOperatorsResource, clusterOperator("kube-apiserver", "100", "first"))
+		require.NoError(t, resourceStorage.Close())
+		// a handler still running when the storage is closed must not panic, its change is dropped.
+		resourceStorage.OnAdd(clusterOperatorsResource, clusterOperator("kube-apiserver", "200", "second"))
+	}
+
+	expected := []recordedChange{{OperationAdded, "kube-apiserver", "100"}}
+	assert.Equal(t, expected, readChanges(t, storageDir))
+	assert.Equal(t, expected, readChanges(t, filepath.Join(storageDir, indexedStorageFilename)))
+}
+
+func TestConvertToGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is required")
+	}
+	storageDir := t.TempDir()
+	logStorage, err := NewLogStorage(storageDir)
+	require.NoError(t, err)
+	recordChanges(logStorage)
+	require.NoError(t, logStorage.Close())
+
+	reader, err := NewChangeReader(storageDir)
+	require.NoError(t, err)
+	repositoryPath := t.TempDir()
+	commits, err := ConvertToGit(reader, repositoryPath)
+	require.NoError(t, err)
+	assert.Equal(t, 4, commits)
+
+	gitLog := exec.Command("git", "log", "--format=%s", "--reverse")
+	gitLog.Dir = repositoryPath
+	output, err := gitLog.CombinedOutput()
+	require.NoError(t, err, string(output))
+	assert.Equal(t, []string{
+		"added clusteroperators.config.openshift.io/kube-apiserver",
+		"added clusteroperators.config.openshift.io/etcd",
+		"modifed clusteroperators.config.openshift.io/kube-apiserver",
+		"removed clusteroperators.config.openshift.io/kube-apiserver",
+	}, strings.Split(strings.TrimSpace(string(output)), "\n"))
+
+	etcdYAML, err := os.ReadFile(filepath.Join(repositoryPath, "cluster-scoped-resources", "config.openshift.io", "clusteroperators", "etcd.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(etcdYAML), "resourceVersion: \"150\"")
+	assert.NoFileExists(t, filepath.Join(repositoryPath, "cluster-scoped-resources", "config.openshift.io", "clusteroperators", "kube-apiserver.yaml"))
+
+	// the repository can be read back like the other storage types.
+	assert.Equal(t, []recordedChange{
+		{OperationAdded, "kube-apiserver", "100"},
+		{OperationAdded, "etcd", "150"},
+		{OperationModified, "kube-apiserver", "200"},
+		{OperationDeleted, "kube-apiserver", "200"},
+	}, readChanges(t, repositoryPath))
+}
@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"net/http"
 	"os"
 	"path/filepath"
 	"sort"
@@ -29,14 +28,43 @@ const (
 type Options struct {
 	JUnitDir string
 	SippyURL string
+
+	// Backend is either sippy or local.
+	Backend string
+	// HistoryDir holds the results of prior runs for the local backend.
+	HistoryDir string
 }
 
+const (
+	BackendSippy = "sippy"
+	BackendLocal = "local"
+)
+
 const testFailureSummaryFilePrefix = "test-failures-summary"
 const sippyURL = "https://sippy.dptools.openshift.org/sippy-ng/"
 
-// Run performs the test risk analysis by reading the output files from the test run, submitting them to sippy,
-// and writing out the analysis result as a new artifact.
+func (opt *Options) riskAnalyzer() (RiskAnalyzer, error) {
+	switch opt.Backend {
+	case "", BackendSippy:
+		return NewSippyRiskAnalyzer(opt.SippyURL), nil
+	case BackendLocal:
+		if len(opt.HistoryDir) == 0 {
+			return nil, fmt.Errorf("the %s risk analysis backend requires a history directory", BackendLocal)
+		}
+		return NewLocalRiskAnalyzer(opt.HistoryDir), nil
+	default:
+		return nil, fmt.Errorf("unknown risk analysis backend %q, expected %s or %s", opt.Backend, BackendSippy, BackendLocal)
+	}
+}
+
+// Run performs the test risk analysis by reading the output files from the test run, submitting them to the
+// risk analyzer, and writing out the analysis result as a new artifact.
 func (opt *Options) Run() error {
+	analyzer, err := opt.riskAnalyzer()
+	if err != nil {
+		return err
+	}
+
 	logrus.Infof("Scanning for %s files in: %s", testFailureSummaryFilePrefix, opt.JUnitDir)
 
 	resultFiles, err := filepath.Glob(fmt.Sprintf("%s/%s*.json", opt.JUnitDir, testFailureSummaryFilePrefix))
@@ -86,50 +114,10 @@ func (opt *Options) Run() error {
 		finalProwJobRun.TestCount += pjr.TestCount
 	}
 
-	inputBytes, err := json.Marshal(finalProwJobRun)
-	if err != nil {
-		logrus.WithError(err).Error("Error marshalling results")
-		return nil
-	}
-
-	req, err := http.NewRequest("GET", opt.SippyURL, bytes.NewBuffer(inputBytes))
+	logrus.Infof("Requesting risk analysis from the %s backend", analyzer.Name())
+	riskAnalysisBytes, err := analyzer.Analyze(context.Background(), finalProwJobRun)
 	if err != nil {
-		logrus.WithError(err).Error("Error creating GET request during risk analysis")
-		return nil
-	}
-	req.Header.Set("Content-Type", "application/json")
-	client := &http.Client{}
-
-	var resp *http.Response
-	clientDoSuccess := false
-	for i := 1; i <= maxRetries; i++ {
-		ctx, cancelFn := context.WithTimeout(req.Context(), 20*time.Second)
-		defer cancelFn()
-		startTime := time.Now()
-		logrus.Infof("Requesting risk analysis (attempt %d/%d) from: %s", i, maxRetries, sippyURL)
-		resp, err = client.Do(req.WithContext(ctx))
-		endTime := time.Now()
-		duration := endTime.Sub(startTime)
-		logrus.Infof("Call to sippy finished after: %s", duration)
-		if err == nil {
-			clientDoSuccess = true
-			break
-		}
-		logrus.WithError(err).Warn("error requesting risk analysis from sippy, sleeping 30s")
-
-		// cancel the context we just used.
-		cancelFn()
-		time.Sleep(time.Duration(i*30) * time.Second)
-	}
-	if !clientDoSuccess {
-		logrus.WithError(err).Error("Unable to obtain risk analysis from sippy after retries")
-		return nil
-	}
-	defer resp.Body.Close()
-
-	riskAnalysisBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		logrus.WithError(err).Error("Error reading risk analysis request body from sippy")
+		logrus.WithError(err).Error("Unable to obtain risk analysis")
 		return nil
 	}
 	logrus.Info("response Body:", string(riskAnalysisBytes))
@@ -171,12 +159,15 @@ func (opt *Options) Run() error {
 type disruptionBackendAnalysis struct {
 	BackendName        string
 	ObservedDisruption int
-	P50                float64
-	P75                float64
-	P95                float64
-	P99                float64
-	JobRuns            int64
-	RiskColor          string // red, yellow or green
+	// P50 and P75 are nil when the historical data does not have them.
+	P50     *float64
+	P75     *float64
+	P95     float64
+	P99     float64
+	JobRuns int64
+	// PercentileRank is the estimated percentile of historical job runs the observed disruption falls at.
+	PercentileRank float64
+	RiskColor      string // red, yellow or green
 }
 
 type disruptionAnalysis struct {
@@ -223,11 +214,16 @@ func runDisruptionAnalysis(opt *Options, jobType platformidentification.JobType)
 			logrus.WithField("details", details).Warn("no historical data found for job run: ")
 			continue
 		}
-		analysis.Backends[i].P50 = percentiles.P50.Seconds()
-		analysis.Backends[i].P75 = percentiles.P75.Seconds()
+		if !percentiles.LowerPercentilesUnknown {
+			p50, p75 := percentiles.P50.Seconds(), percentiles.P75.Seconds()
+			analysis.Backends[i].P50 = &p50
+			analysis.Backends[i].P75 = &p75
+		}
 		analysis.Backends[i].P95 = percentiles.P95.Seconds()
 		analysis.Backends[i].P99 = percentiles.P99.Seconds()
 		analysis.Backends[i].JobRuns = percentiles.JobRuns
+		analysis.Backends[i].PercentileRank = percentiles.PercentileRank(
+			time.Duration(analysis.Backends[i].ObservedDisruption) * time.Second)
 
 		analysis.Backends[i].RiskColor = "lightgreen"
 		if float64(analysis.Backends[i].ObservedDisruption) > analysis.Backends[i].P95 {
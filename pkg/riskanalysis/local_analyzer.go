@@ -0,0 +1,293 @@
+package riskanalysis
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	// minimumHistoricalRuns is the number of runs of a test needed before its pass rate is trusted.
+	minimumHistoricalRuns = 7
+	// highRiskPassRate and mediumRiskPassRate are the historical pass rates above which a failure is unusual.
+	highRiskPassRate   = 0.98
+	mediumRiskPassRate = 0.80
+	// highRiskFailureCount is the number of failed tests above which the job run itself is considered unusual.
+	highRiskFailureCount = 20
+
+	testPassRatesFilePrefix = "test-pass-rates"
+)
+
+// TestPassRate is the historical record of a single test.  A list of them is the snapshot format read from
+// test-pass-rates*.json files.
+type TestPassRate struct {
+	Name   string
+	Runs   int
+	Passes int
+}
+
+type localRiskAnalyzer struct {
+	historyDir string
+}
+
+// NewLocalRiskAnalyzer creates a RiskAnalyzer that computes historical pass rates from the test-failures-summary,
+// junit, and test-pass-rates files of prior runs found in historyDir.  No network access is required.
+func NewLocalRiskAnalyzer(historyDir string) RiskAnalyzer {
+	return &localRiskAnalyzer{historyDir: historyDir}
+}
+
+func (l *localRiskAnalyzer) Name() string {
+	return "local"
+}
+
+func (l *localRiskAnalyzer) Analyze(ctx context.Context, jobRun *ProwJobRun) ([]byte, error) {
+	history := newTestHistory()
+	if err := history.read(l.historyDir); err != nil {
+		return nil, err
+	}
+	logrus.Infof("Loaded history of %d tests and %d job runs without junit", len(history.passRates), len(history.summaryRuns))
+
+	return json.MarshalIndent(analyzeJobRun(jobRun, history), "", "    ")
+}
+
+func analyzeJobRun(jobRun *ProwJobRun, history *testHistory) *ProwJobRunRiskAnalysis {
+	analysis := &ProwJobRunRiskAnalysis{
+		ProwJobName:  jobRun.ProwJob.Name,
+		ProwJobRunID: jobRun.ID,
+		Release:      jobRun.ClusterData.JobType.Release,
+		// history is whatever the caller provided, we have no way to compare against anything else.
+		CompareRelease: jobRun.ClusterData.JobType.Release,
+		Tests:          []ProwJobRunTestRiskAnalysis{},
+		OverallRisk:    FailureRisk{Level: RiskLevelNone, Reasons: []string{}},
+		OpenBugs:       []Bug{},
+	}
+
+	failedTests := failedTestNames(jobRun)
+	for _, name := range failedTests {
+		risk := testFailureRisk(name, history.passRate(name))
+		analysis.Tests = append(analysis.Tests, ProwJobRunTestRiskAnalysis{
+			Name:     name,
+			Risk:     risk,
+			OpenBugs: []Bug{},
+		})
+		if risk.Level.Level > analysis.OverallRisk.Level.Level {
+			analysis.OverallRisk.Level = risk.Level
+		}
+	}
+
+	if len(failedTests) > highRiskFailureCount {
+		analysis.OverallRisk.Level = RiskLevelHigh
+		analysis.OverallRisk.Reasons = append(analysis.OverallRisk.Reasons,
+			fmt.Sprintf("%d tests failed in this run: High", len(failedTests)))
+	}
+	for _, test := range analysis.Tests {
+		if test.Risk.Level == analysis.OverallRisk.Level && analysis.OverallRisk.Level.Level > RiskLevelLow.Level {
+			analysis.OverallRisk.Reasons = append(analysis.OverallRisk.Reasons,
+				fmt.Sprintf("Maximum failed test risk: %s", test.Risk.Level.Name))
+			break
+		}
+	}
+
+	return analysis
+}
+
+// failedTestNames returns the sorted, unique names of the tests that failed (not flaked) in the job run.
+func failedTestNames(jobRun *ProwJobRun) []string {
+	names := sets.NewString()
+	for _, test := range jobRun.Tests {
+		if test.Status != getSippyStatusCode(&passFail{Failed: true}) {
+			continue
+		}
+		names.Insert(test.Test.Name)
+	}
+	return names.List()
+}
+
+func testFailureRisk(name string, passRate TestPassRate) FailureRisk {
+	if passRate.Runs < minimumHistoricalRuns {
+		return FailureRisk{
+			Level:   RiskLevelUnknown,
+			Reasons: []string{fmt.Sprintf("Only %d historical runs of this test were found, at least %d are required", passRate.Runs, minimumHistoricalRuns)},
+		}
+	}
+
+	percentage := float64(passRate.Passes) / float64(passRate.Runs)
+	reason := fmt.Sprintf("This test has passed %.2f%% of %d runs in the provided history.", percentage*100, passRate.Runs)
+	switch {
+	case percentage >= highRiskPassRate:
+		return FailureRisk{Level: RiskLevelHigh, Reasons: []string{reason}}
+	case percentage >= mediumRiskPassRate:
+		return FailureRisk{Level: RiskLevelMedium, Reasons: []string{reason}}
+	default:
+		return FailureRisk{Level: RiskLevelLow, Reasons: []string{reason}}
+	}
+}
+
+// testHistory tallies the pass rate of tests across prior job runs.
+type testHistory struct {
+	passRates map[string]TestPassRate
+
+	// summaryRuns are the job runs only known from test-failures-summary files.  Those only list failures, so
+	// they are counted as a run of the tests that failed in them and nothing else.
+	summaryRuns     sets.String
+	summaryFailures map[string]sets.String
+}
+
+func newTestHistory() *testHistory {
+	return &testHistory{
+		passRates:       map[string]TestPassRate{},
+		summaryRuns:     sets.NewString(),
+		summaryFailures: map[string]sets.String{},
+	}
+}
+
+func (h *testHistory) passRate(name string) TestPassRate {
+	passRate := h.passRates[name]
+	passRate.Name = name
+	passRate.Runs += h.summaryFailures[name].Len()
+	return passRate
+}
+
+// read walks historyDir for the results of prior job runs.
+//   - junit xml files count a test as passed if it passed at least once, a flake is a pass.
+//   - test-failures-summary*.json files are only used for job runs without junit in the same directory, since the
+//     junit has the same failures and also the passes.  Summaries for the same job run ID, as written before and
+//     after an upgrade, are counted as a single run.
+//   - test-pass-rates*.json files are snapshots that are added as is.
+func (h *testHistory) read(historyDir string) error {
+	junitDirs := sets.NewString()
+	// the failed tests of each job run summary, by directory and job run.
+	summaries := map[string]map[string][]string{}
+	err := filepath.WalkDir(historyDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		base := d.Name()
+		switch {
+		case strings.HasPrefix(base, testFailureSummaryFilePrefix) && strings.HasSuffix(base, ".json"):
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			jobRun := &ProwJobRun{}
+			if err := json.Unmarshal(data, jobRun); err != nil {
+				logrus.WithError(err).Warnf("Skipping unreadable %s", path)
+				return nil
+			}
+			runKey := path
+			if jobRun.ID != 0 {
+				runKey = fmt.Sprintf("%s/%d", jobRun.ProwJob.Name, jobRun.ID)
+			}
+			dir := filepath.Dir(path)
+			if _, ok := summaries[dir]; !ok {
+				summaries[dir] = map[string][]string{}
+			}
+			summaries[dir][runKey] = append(summaries[dir][runKey], failedTestNames(jobRun)...)
+
+		case strings.HasSuffix(base, ".xml"):
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			suite := &junitapi.JUnitTestSuite{}
+			if err := xml.Unmarshal(data, suite); err != nil {
+				logrus.WithError(err).Debugf("Skipping %s, not a junit test suite", path)
+				return nil
+			}
+			junitDirs.Insert(filepath.Dir(path))
+			for name, result := range junitResults(suite) {
+				curr := h.passRates[name]
+				curr.Name = name
+				curr.Runs++
+				if result.Passed {
+					curr.Passes++
+				}
+				h.passRates[name] = curr
+			}
+
+		case strings.HasPrefix(base, testPassRatesFilePrefix) && strings.HasSuffix(base, ".json"):
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if err := h.addTestPassRates(data); err != nil {
+				logrus.WithError(err).Warnf("Skipping unreadable %s", path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("unable to read history from %s: %w", historyDir, err)
+	}
+
+	for dir, jobRuns := range summaries {
+		if junitDirs.Has(dir) {
+			continue
+		}
+		for runKey, failedTests := range jobRuns {
+			h.summaryRuns.Insert(runKey)
+			for _, name := range failedTests {
+				if _, ok := h.summaryFailures[name]; !ok {
+					h.summaryFailures[name] = sets.NewString()
+				}
+				h.summaryFailures[name].Insert(runKey)
+			}
+		}
+	}
+	return nil
+}
+
+func junitResults(suite *junitapi.JUnitTestSuite) map[string]*passFail {
+	results := map[string]*passFail{}
+	for _, testCase := range suite.TestCases {
+		if testCase.SkipMessage != nil {
+			continue
+		}
+		if _, ok := results[testCase.Name]; !ok {
+			results[testCase.Name] = &passFail{}
+		}
+		if testCase.FailureOutput != nil {
+			results[testCase.Name].Failed = true
+		} else {
+			results[testCase.Name].Passed = true
+		}
+	}
+	for _, child := range suite.Children {
+		for name, result := range junitResults(child) {
+			if _, ok := results[name]; !ok {
+				results[name] = &passFail{}
+			}
+			results[name].Passed = results[name].Passed || result.Passed
+			results[name].Failed = results[name].Failed || result.Failed
+		}
+	}
+	return results
+}
+
+func (h *testHistory) addTestPassRates(data []byte) error {
+	snapshot := []TestPassRate{}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+	for _, rate := range snapshot {
+		curr := h.passRates[rate.Name]
+		curr.Name = rate.Name
+		curr.Runs += rate.Runs
+		curr.Passes += rate.Passes
+		h.passRates[rate.Name] = curr
+	}
+	return nil
+}
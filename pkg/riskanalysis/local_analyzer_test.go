@@ -0,0 +1,134 @@
+package riskanalysis
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+func writeJSON(t *testing.T, filename string, obj interface{}) {
+	data, err := json.Marshal(obj)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+	require.NoError(t, os.WriteFile(filename, data, 0644))
+}
+
+func failedTest(name string) ProwJobRunTest {
+	return ProwJobRunTest{Test: Test{Name: name}, Status: 12}
+}
+
+func TestLocalRiskAnalyzer(t *testing.T) {
+	historyDir := t.TempDir()
+
+	writeJUnit := func(filename string, testCases ...*junitapi.JUnitTestCase) {
+		junitBytes, err := xml.Marshal(&junitapi.JUnitTestSuite{TestCases: testCases})
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+		require.NoError(t, os.WriteFile(filename, junitBytes, 0644))
+	}
+	failed := &junitapi.FailureOutput{Output: "failed"}
+
+	// 10 prior job runs with junit, and the pre and post upgrade summaries that must not be counted again.
+	// "always passes" never failed, "sometimes fails" failed in 1, "often fails" failed in 5.
+	for i := 1; i <= 10; i++ {
+		pre := &ProwJobRun{ID: i, ProwJob: ProwJob{Name: "periodic-upgrade"}}
+		post := &ProwJobRun{ID: i, ProwJob: ProwJob{Name: "periodic-upgrade"}}
+		sometimesFails := &junitapi.JUnitTestCase{Name: "sometimes fails"}
+		oftenFails := &junitapi.JUnitTestCase{Name: "often fails"}
+		if i == 1 {
+			pre.Tests = append(pre.Tests, failedTest("sometimes fails"))
+			sometimesFails.FailureOutput = failed
+		}
+		if i <= 5 {
+			post.Tests = append(post.Tests, failedTest("often fails"))
+			oftenFails.FailureOutput = failed
+		}
+		runDir := filepath.Join(historyDir, fmt.Sprint(i))
+		writeJSON(t, filepath.Join(runDir, "test-failures-summary_20240101-000000.json"), pre)
+		writeJSON(t, filepath.Join(runDir, "test-failures-summary_monitor_20240101-000000.json"), post)
+		writeJUnit(filepath.Join(runDir, "junit_e2e_20240101-000000.xml"),
+			&junitapi.JUnitTestCase{Name: "always passes"}, sometimesFails, oftenFails)
+	}
+
+	// a run only known from its summaries counts as a run of the tests that failed in it, and nothing else.
+	for _, summary := range []string{"test-failures-summary_20240101-000000.json", "test-failures-summary_monitor_20240101-000000.json"} {
+		writeJSON(t, filepath.Join(historyDir, "summary-only", summary), &ProwJobRun{
+			ID:      11,
+			ProwJob: ProwJob{Name: "periodic-upgrade"},
+			Tests:   []ProwJobRunTest{failedTest("sometimes fails")},
+		})
+	}
+
+	// a junit from a run without a summary, "only in junit" flaked which counts as a pass, but a single run is
+	// not enough history.
+	writeJUnit(filepath.Join(historyDir, "junit_e2e_20240101-000000.xml"),
+		&junitapi.JUnitTestCase{Name: "only in junit", FailureOutput: failed},
+		&junitapi.JUnitTestCase{Name: "only in junit"},
+		&junitapi.JUnitTestCase{Name: "skipped", SkipMessage: &junitapi.SkipMessage{Message: "skipped"}},
+	)
+
+	history := newTestHistory()
+	require.NoError(t, history.read(historyDir))
+	assert.Equal(t, TestPassRate{Name: "always passes", Runs: 10, Passes: 10}, history.passRate("always passes"))
+	assert.Equal(t, TestPassRate{Name: "sometimes fails", Runs: 11, Passes: 9}, history.passRate("sometimes fails"))
+	assert.Equal(t, TestPassRate{Name: "often fails", Runs: 10, Passes: 5}, history.passRate("often fails"))
+	assert.Equal(t, TestPassRate{Name: "never run"}, history.passRate("never run"))
+
+	jobRun := &ProwJobRun{
+		ID:      42,
+		ProwJob: ProwJob{Name: "periodic-upgrade"},
+		Tests: []ProwJobRunTest{
+			failedTest("always passes"),
+			failedTest("sometimes fails"),
+			failedTest("often fails"),
+			failedTest("only in junit"),
+			{Test: Test{Name: "flaked"}, Status: 13},
+		},
+	}
+
+	analysisBytes, err := NewLocalRiskAnalyzer(historyDir).Analyze(context.Background(), jobRun)
+	require.NoError(t, err)
+	analysis := &ProwJobRunRiskAnalysis{}
+	require.NoError(t, json.Unmarshal(analysisBytes, analysis))
+
+	risks := map[string]RiskLevel{}
+	for _, test := range analysis.Tests {
+		risks[test.Name] = test.Risk.Level
+	}
+	assert.Equal(t, map[string]RiskLevel{
+		"always passes":   RiskLevelHigh,
+		"sometimes fails": RiskLevelMedium,
+		"often fails":     RiskLevelLow,
+		"only in junit":   RiskLevelUnknown,
+	}, risks)
+	assert.Equal(t, RiskLevelHigh, analysis.OverallRisk.Level)
+	assert.Equal(t, "periodic-upgrade", analysis.ProwJobName)
+	assert.Equal(t, 42, analysis.ProwJobRunID)
+}
+
+func TestLocalRiskAnalyzerWithoutHistory(t *testing.T) {
+	jobRun := &ProwJobRun{
+		Tests: []ProwJobRunTest{failedTest("unknown")},
+	}
+
+	analysisBytes, err := NewLocalRiskAnalyzer(t.TempDir()).Analyze(context.Background(), jobRun)
+	require.NoError(t, err)
+	analysis := &ProwJobRunRiskAnalysis{}
+	require.NoError(t, json.Unmarshal(analysisBytes, analysis))
+
+	require.Len(t, analysis.Tests, 1)
+	assert.Equal(t, RiskLevelUnknown, analysis.Tests[0].Risk.Level)
+	assert.Equal(t, RiskLevelUnknown, analysis.OverallRisk.Level)
+
+	_, err = (&Options{Backend: BackendLocal}).riskAnalyzer()
+	assert.Error(t, err, "the local backend requires a history directory")
+}
@@ -0,0 +1,82 @@
+package riskanalysis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RiskAnalyzer determines how severe or unusual the test failures in a job run are.
+type RiskAnalyzer interface {
+	// Name is used for logging.
+	Name() string
+
+	// Analyze returns the risk analysis of the job run, serialized in the format returned by sippy so it can
+	// be written as risk-analysis.json and rendered by test-risk-analysis.html.
+	Analyze(ctx context.Context, jobRun *ProwJobRun) ([]byte, error)
+}
+
+type sippyRiskAnalyzer struct {
+	sippyURL string
+}
+
+// NewSippyRiskAnalyzer creates a RiskAnalyzer that submits the job run to the sippy risk analysis API.
+func NewSippyRiskAnalyzer(sippyURL string) RiskAnalyzer {
+	return &sippyRiskAnalyzer{sippyURL: sippyURL}
+}
+
+func (s *sippyRiskAnalyzer) Name() string {
+	return "sippy"
+}
+
+func (s *sippyRiskAnalyzer) Analyze(ctx context.Context, jobRun *ProwJobRun) ([]byte, error) {
+	inputBytes, err := json.Marshal(jobRun)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling results: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", s.sippyURL, bytes.NewBuffer(inputBytes))
+	if err != nil {
+		return nil, fmt.Errorf("error creating GET request during risk analysis: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := &http.Client{}
+
+	var resp *http.Response
+	clientDoSuccess := false
+	for i := 1; i <= maxRetries; i++ {
+		ctx, cancelFn := context.WithTimeout(req.Context(), 20*time.Second)
+		defer cancelFn()
+		startTime := time.Now()
+		logrus.Infof("Requesting risk analysis (attempt %d/%d) from: %s", i, maxRetries, s.sippyURL)
+		resp, err = client.Do(req.WithContext(ctx))
+		endTime := time.Now()
+		duration := endTime.Sub(startTime)
+		logrus.Infof("Call to sippy finished after: %s", duration)
+		if err == nil {
+			clientDoSuccess = true
+			break
+		}
+		logrus.WithError(err).Warn("error requesting risk analysis from sippy, sleeping 30s")
+
+		// cancel the context we just used.
+		cancelFn()
+		time.Sleep(time.Duration(i*30) * time.Second)
+	}
+	if !clientDoSuccess {
+		return nil, fmt.Errorf("unable to obtain risk analysis from sippy after retries: %w", err)
+	}
+	defer resp.Body.Close()
+
+	riskAnalysisBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading risk analysis request body from sippy: %w", err)
+	}
+	return riskAnalysisBytes, nil
+}
@@ -34,3 +34,47 @@ type ProwJobRunTest struct {
 	Suite  Suite
 	Status int // would like to use smallint here, but gorm auto-migrate breaks trying to change the type every start
 }
+
+// The risk analysis types are subsets of the sippy API of the same name, the local analyzer produces them in the
+// same format sippy returns so both can be rendered by test-risk-analysis.html.
+
+type ProwJobRunRiskAnalysis struct {
+	ProwJobName    string
+	ProwJobRunID   int
+	Release        string
+	CompareRelease string
+	Tests          []ProwJobRunTestRiskAnalysis
+	OverallRisk    FailureRisk
+	OpenBugs       []Bug
+}
+
+type ProwJobRunTestRiskAnalysis struct {
+	Name     string
+	Risk     FailureRisk
+	OpenBugs []Bug
+}
+
+type FailureRisk struct {
+	Level   RiskLevel
+	Reasons []string
+}
+
+type RiskLevel struct {
+	Name string
+	// Level is used to sort and color the results, higher is riskier.
+	Level int
+}
+
+type Bug struct {
+	Key     string `json:"key"`
+	Summary string `json:"summary"`
+	URL     string `json:"url"`
+}
+
+var (
+	RiskLevelNone    = RiskLevel{Name: "None", Level: 0}
+	RiskLevelLow     = RiskLevel{Name: "Low", Level: 1}
+	RiskLevelUnknown = RiskLevel{Name: "Unknown", Level: 3}
+	RiskLevelMedium  = RiskLevel{Name: "Medium", Level: 5}
+	RiskLevelHigh    = RiskLevel{Name: "High", Level: 10}
+)
@@ -18,7 +18,25 @@ import (
 // This is intended to be later submitted to sippy for a risk analysis of how unusual the
 // test failures were, but that final step is handled elsewhere.
 func WriteJobRunTestFailureSummary(artifactDir, timeSuffix string, finalSuiteResults *junitapi.JUnitTestSuite, wasMasterNodeUpdated, outputFileSubStr string) error {
+	// If we can't parse this, we submit without it, it is not required.
+	jobRunID, _ := strconv.Atoi(os.Getenv("BUILD_ID"))
+
+	restConfig, err := clusterinfo.GetMonitorRESTConfig()
+	if err != nil {
+		return err
+	}
+	jr := ProwJobRun{
+		ID:          jobRunID,
+		ProwJob:     ProwJob{Name: os.Getenv("JOB_NAME")},
+		ClusterData: clusterinfo.CollectClusterData(restConfig, wasMasterNodeUpdated),
+	}
+	return WriteProwJobRunTestFailureSummary(artifactDir, timeSuffix, jr, finalSuiteResults, outputFileSubStr)
+}
 
+// WriteProwJobRunTestFailureSummary fills in the tests of the job run from the suite results and writes the
+// summary, without reaching the cluster.  It is used when the cluster data is already known, like when merging
+// the results of the shards of a run.
+func WriteProwJobRunTestFailureSummary(artifactDir, timeSuffix string, jr ProwJobRun, finalSuiteResults *junitapi.JUnitTestSuite, outputFileSubStr string) error {
 	tests := map[string]*passFail{}
 
 	for _, testCase := range finalSuiteResults.TestCases {
@@ -36,20 +54,8 @@ func WriteJobRunTestFailureSummary(artifactDir, timeSuffix string, finalSuiteRes
 		}
 	}
 
-	// If we can't parse this, we submit without it, it is not required.
-	jobRunID, _ := strconv.Atoi(os.Getenv("BUILD_ID"))
-
-	restConfig, err := clusterinfo.GetMonitorRESTConfig()
-	if err != nil {
-		return err
-	}
-	jr := ProwJobRun{
-		ID:          jobRunID,
-		ProwJob:     ProwJob{Name: os.Getenv("JOB_NAME")},
-		ClusterData: clusterinfo.CollectClusterData(restConfig, wasMasterNodeUpdated),
-		Tests:       []ProwJobRunTest{},
-		TestCount:   len(tests),
-	}
+	jr.Tests = []ProwJobRunTest{}
+	jr.TestCount = len(tests)
 
 	for k, v := range tests {
 		if !v.Failed {
@@ -0,0 +1,217 @@
+package ginkgo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// checkpointDir is the directory in --junit-dir holding what is needed to resume an interrupted run.
+	checkpointDir = "checkpoint"
+	// checkpointRunFile describes the interrupted run.
+	checkpointRunFile = "run.json"
+	// checkpointJournalFile has one line per completed test, in the order they completed.
+	checkpointJournalFile = "tests.jsonl"
+	// checkpointIntervalsDir holds the interval segments of the run, unless --interval-storage-dir is set.
+	checkpointIntervalsDir = "intervals"
+)
+
+// checkpointRun is what a resumed run must share with the interrupted one.
+type checkpointRun struct {
+	Suite     string    `json:"suite"`
+	StartTime time.Time `json:"startTime"`
+}
+
+// checkpointTest is the result of a completed test.
+type checkpointTest struct {
+	Name     string        `json:"name"`
+	Start    time.Time     `json:"start"`
+	End      time.Time     `json:"end"`
+	Duration time.Duration `json:"duration"`
+	Output   []byte        `json:"output,omitempty"`
+
+	Flake    bool `json:"flake,omitempty"`
+	Failed   bool `json:"failed,omitempty"`
+	Skipped  bool `json:"skipped,omitempty"`
+	Success  bool `json:"success,omitempty"`
+	TimedOut bool `json:"timedOut,omitempty"`
+}
+
+func newCheckpointTest(test *testCase) checkpointTest {
+	return checkpointTest{
+		Name:     test.name,
+		Start:    test.start,
+		End:      test.end,
+		Duration: test.duration,
+		Output:   test.testOutputBytes,
+		Flake:    test.flake,
+		Failed:   test.failed,
+		Skipped:  test.skipped,
+		Success:  test.success,
+		TimedOut: test.timedOut,
+	}
+}
+
+func (t checkpointTest) toTestCase() *testCase {
+	return &testCase{
+		name:            t.Name,
+		start:           t.Start,
+		end:             t.End,
+		duration:        t.Duration,
+		testOutputBytes: t.Output,
+		flake:           t.Flake,
+		failed:          t.Failed,
+		skipped:         t.Skipped,
+		success:         t.Success,
+		timedOut:        t.TimedOut,
+	}
+}
+
+// checkpointJournal appends the result of each test to the journal as soon as it completes, so that a run that
+// is killed can be resumed.  A nil journal records nothing.
+type checkpointJournal struct {
+	lock sync.Mutex
+	file *os.File
+}
+
+// newCheckpointJournal starts the journal of a new run in dir, discarding the checkpoint of any earlier run, or
+// continues the journal of the resumed run.
+func newCheckpointJournal(dir string, run checkpointRun, resume bool) (*checkpointJournal, error) {
+	if !resume {
+		if err := os.RemoveAll(dir); err != nil {
+			return nil, fmt.Errorf("unable to remove the checkpoint of an earlier run: %w", err)
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("unable to create checkpoint directory: %w", err)
+	}
+	journalFile := filepath.Join(dir, checkpointJournalFile)
+	if resume {
+		// drop the partial line left behind by the interrupted run before appending to it.
+		content, err := os.ReadFile(journalFile)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if len(content) > 0 && content[len(content)-1] != '\n' {
+			if err := os.Truncate(journalFile, int64(bytes.LastIndexByte(content, '\n')+1)); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		runJSON, err := json.Marshal(run)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(filepath.Join(dir, checkpointRunFile), runJSON, 0644); err != nil {
+			return nil, err
+		}
+	}
+	file, err := os.OpenFile(journalFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &checkpointJournal{file: file}, nil
+}
+
+// Record appends the result of the test to the journal.
+func (j *checkpointJournal) Record(test *testCase) {
+	if j == nil {
+		return
+	}
+	testJSON, err := json.Marshal(newCheckpointTest(test))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error encoding checkpoint for %q: %v\n", test.name, err)
+		return
+	}
+
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if j.file == nil {
+		return
+	}
+	// a single write per test, so a kill leaves at most one partial line behind.
+	if _, err := j.file.Write(append(testJSON, '\n')); err != nil {
+		fmt.Fprintf(os.Stderr, "error writing checkpoint for %q: %v\n", test.name, err)
+		return
+	}
+	if err := j.file.Sync(); err != nil {
+		fmt.Fprintf(os.Stderr, "error syncing checkpoint for %q: %v\n", test.name, err)
+	}
+}
+
+func (j *checkpointJournal) Close() error {
+	if j == nil {
+		return nil
+	}
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+// loadCheckpoint reads the run and the completed tests from the checkpoint in dir.  When a test is in the journal
+// more than once, because it failed and was run again by a resumed run, the last result is returned.
+func loadCheckpoint(dir string) (*checkpointRun, map[string]*testCase, error) {
+	runJSON, err := os.ReadFile(filepath.Join(dir, checkpointRunFile))
+	if err != nil {
+		return nil, nil, fmt.Errorf("no run to resume: %w", err)
+	}
+	run := &checkpointRun{}
+	if err := json.Unmarshal(runJSON, run); err != nil {
+		return nil, nil, fmt.Errorf("unable to read %s: %w", checkpointRunFile, err)
+	}
+
+	journal, err := os.Open(filepath.Join(dir, checkpointJournalFile))
+	if os.IsNotExist(err) {
+		return run, map[string]*testCase{}, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	defer journal.Close()
+
+	completed := map[string]*testCase{}
+	reader := bufio.NewReader(journal)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			// no trailing newline means the write was interrupted
+			break
+		}
+		line = strings.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		test := checkpointTest{}
+		if err := json.Unmarshal([]byte(line), &test); err != nil {
+			return nil, nil, fmt.Errorf("unable to read %s: %w", checkpointJournalFile, err)
+		}
+		completed[test.Name] = test.toTestCase()
+	}
+	return run, completed, nil
+}
+
+// resumeTests splits the tests between the tests that still have to run, the ones that did not complete or
+// failed, and the results of the tests that completed.
+func resumeTests(tests []*testCase, completed map[string]*testCase) (remaining, restored []*testCase) {
+	for _, test := range tests {
+		previous, ok := completed[test.name]
+		if !ok || previous.failed {
+			remaining = append(remaining, test)
+			continue
+		}
+		restored = append(restored, previous)
+	}
+	return remaining, restored
+}
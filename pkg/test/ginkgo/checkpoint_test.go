@@ -0,0 +1,90 @@
+package ginkgo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_checkpointJournal(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), checkpointDir)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	run := checkpointRun{Suite: "openshift/conformance/parallel", StartTime: start}
+
+	journal, err := newCheckpointJournal(dir, run, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	journal.Record(&testCase{name: "passes", start: start, end: start.Add(time.Minute), duration: time.Minute, success: true, testOutputBytes: []byte("ok")})
+	journal.Record(&testCase{name: "fails", start: start, end: start.Add(time.Second), duration: time.Second, failed: true})
+	journal.Record(&testCase{name: "skips", skipped: true})
+	if err := journal.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// the run was killed while writing the result of a test
+	journalFile, err := os.OpenFile(filepath.Join(dir, checkpointJournalFile), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := journalFile.WriteString(`{"name":"torn","succ`); err != nil {
+		t.Fatal(err)
+	}
+	journalFile.Close()
+
+	loadedRun, completed, err := loadCheckpoint(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(run, *loadedRun) {
+		t.Errorf("expected run %+v, got %+v", run, *loadedRun)
+	}
+	if len(completed) != 3 {
+		t.Fatalf("expected 3 completed tests, got %v", completed)
+	}
+	if passes := completed["passes"]; !passes.success || passes.duration != time.Minute || string(passes.testOutputBytes) != "ok" || !passes.end.Equal(start.Add(time.Minute)) {
+		t.Errorf("unexpected result: %+v", passes)
+	}
+
+	tests := []*testCase{{name: "passes"}, {name: "fails"}, {name: "skips"}, {name: "never ran"}}
+	remaining, restored := resumeTests(tests, completed)
+	if expected := []string{"fails", "never ran"}; !reflect.DeepEqual(expected, testNames(remaining)) {
+		t.Errorf("expected to run %v, got %v", expected, testNames(remaining))
+	}
+	if expected := []string{"passes", "skips"}; !reflect.DeepEqual(expected, testNames(restored)) {
+		t.Errorf("expected to restore %v, got %v", expected, testNames(restored))
+	}
+
+	// the resumed run appends to the journal, the last result of a test wins
+	journal, err = newCheckpointJournal(dir, checkpointRun{Suite: run.Suite, StartTime: time.Now()}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	journal.Record(&testCase{name: "fails", success: true})
+	journal.Close()
+	loadedRun, completed, err = loadCheckpoint(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loadedRun.StartTime.Equal(start) {
+		t.Errorf("expected the start time of the interrupted run, got %s", loadedRun.StartTime)
+	}
+	if fails := completed["fails"]; fails.failed || !fails.success {
+		t.Errorf("expected the result of the resumed run, got %+v", fails)
+	}
+
+	// a new run discards the checkpoint
+	journal, err = newCheckpointJournal(dir, run, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	journal.Close()
+	if _, completed, err = loadCheckpoint(dir); err != nil || len(completed) != 0 {
+		t.Errorf("expected no completed tests, got %v: %v", completed, err)
+	}
+
+	if _, _, err := loadCheckpoint(filepath.Join(t.TempDir(), checkpointDir)); err == nil {
+		t.Errorf("expected an error without a run to resume")
+	}
+}
@@ -15,13 +15,12 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/sirupsen/logrus"
-
 	"github.com/onsi/ginkgo/v2"
 	"github.com/openshift/origin/pkg/clioptions/clusterinfo"
 	"github.com/openshift/origin/pkg/defaultmonitortests"
 	"github.com/openshift/origin/pkg/disruption/backend/sampler"
 	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/intervalstream"
 	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
 	"github.com/openshift/origin/pkg/monitortestframework"
 	"github.com/openshift/origin/pkg/riskanalysis"
@@ -71,6 +70,37 @@ type GinkgoRunSuiteOptions struct {
 
 	ExactMonitorTests   []string
 	DisableMonitorTests []string
+
+	// IntervalStorageDir, if set, streams intervals to a segmented log on disk instead of keeping them in memory.
+	IntervalStorageDir string
+
+	// IntervalStreamAddress, if set, serves recorded intervals as they happen for `openshift-tests monitor tail`.
+	IntervalStreamAddress string
+
+	// PathologicalEventAllowanceFiles are YAML or JSON files of additional pathological event matchers.
+	PathologicalEventAllowanceFiles []string
+
+	// TestDurationHistoryFiles are JUnit reports of previous runs, or JSON files of test durations, used to run the
+	// longest tests first.  Without them, tests run in random order.
+	TestDurationHistoryFiles []string
+
+	// Checkpoint records the completed tests in the --junit-dir, so an interrupted run can be resumed.  Unless
+	// IntervalStorageDir is set, the intervals are streamed next to the checkpoint.
+	Checkpoint bool
+	// ResumeDir is the --junit-dir of an interrupted run to resume.  The tests that completed are not run again.
+	ResumeDir string
+
+	// ShardIndex and ShardCount split the suite across several runs, each one runs the tests of its shard.
+	ShardIndex int
+	ShardCount int
+
+	// RetryMaxAttempts, RetryOn and QuarantineAttempts, if set, override the RetryPolicy of the suite.
+	RetryMaxAttempts   int
+	RetryOn            []string
+	QuarantineAttempts int
+	// QuarantinedTestsFiles list the names of tests known to be unreliable, in addition to the quarantined tests of
+	// the suite.
+	QuarantinedTestsFiles []string
 }
 
 func NewGinkgoRunSuiteOptions(streams genericclioptions.IOStreams) *GinkgoRunSuiteOptions {
@@ -95,6 +125,18 @@ func (o *GinkgoRunSuiteOptions) BindFlags(flags *pflag.FlagSet) {
 	flags.StringSliceVar(&o.ExactMonitorTests, "monitor", o.ExactMonitorTests,
 		fmt.Sprintf("list of exactly which monitors to enable. All others will be disabled.  Current monitors are: [%s]", strings.Join(monitorNames, ", ")))
 	flags.StringSliceVar(&o.DisableMonitorTests, "disable-monitor", o.DisableMonitorTests, "list of monitors to disable.  Defaults for others will be honored.")
+	flags.StringVar(&o.IntervalStorageDir, "interval-storage-dir", o.IntervalStorageDir, "If set, monitor intervals are streamed to a segmented log in this directory instead of being held in memory. Recommended for long running and --count=-1 runs.")
+	flags.StringVar(&o.IntervalStreamAddress, "interval-stream-address", o.IntervalStreamAddress, "If set, serve a live stream of monitor intervals on this local address (i.e. 127.0.0.1:9911) for use by the monitor tail command.")
+	flags.StringSliceVar(&o.PathologicalEventAllowanceFiles, "pathological-event-allowances", o.PathologicalEventAllowanceFiles, "YAML or JSON files of additional pathological event matchers allowing known repeated events.")
+	flags.BoolVar(&o.Checkpoint, "checkpoint", o.Checkpoint, "Record the completed tests in --junit-dir, so the run can be continued with --resume if it is interrupted. Unless --interval-storage-dir is set, monitor intervals are streamed to a segmented log in --junit-dir.")
+	flags.StringVar(&o.ResumeDir, "resume", o.ResumeDir, "The --junit-dir of an interrupted run of the same suite, started with --checkpoint, to resume. Only the tests that did not complete or failed are run, and the results are merged with the completed ones. The same --interval-storage-dir must be passed, if any. Intervals that were still open when the run was interrupted are restored from the interval storage, and stay open until the end of the resumed run.")
+	flags.IntVar(&o.ShardIndex, "shard-index", o.ShardIndex, "The shard of the suite to run, from 0 to --shard-count minus one.")
+	flags.IntVar(&o.ShardCount, "shard-count", o.ShardCount, "Split the suite in this many shards, to run them from several processes or against several identical clusters, and combine the results with merge-results. Tests are assigned by a stable hash of their name, or balanced by duration when --test-duration-history is set, in which case every shard must use the same history.")
+	flags.IntVar(&o.RetryMaxAttempts, "retry-max-attempts", o.RetryMaxAttempts, "The number of times a failing test is run, including the first attempt, to detect flakes. Defaults to the suite's retry policy, or 2.")
+	flags.StringSliceVar(&o.RetryOn, "retry-on", o.RetryOn, fmt.Sprintf("The failures that are retried, any of %s and %s. Defaults to the suite's retry policy, or every failure.", FailureClassTimeout, FailureClassAssertion))
+	flags.StringSliceVar(&o.QuarantinedTestsFiles, "quarantined-tests", o.QuarantinedTestsFiles, "Files of the names of tests known to be unreliable, one per line. A failing quarantined test is retried even when too many tests failed to retry the others, and does not count against the flakes the suite allows.")
+	flags.IntVar(&o.QuarantineAttempts, "quarantine-attempts", o.QuarantineAttempts, "The number of times a failing quarantined test is run, including the first attempt. Defaults to the suite's retry policy, or the --retry-max-attempts.")
+	flags.StringSliceVar(&o.TestDurationHistoryFiles, "test-duration-history", o.TestDurationHistoryFiles, "JUnit reports of previous runs (i.e. junit_e2e_*.xml), or JSON files mapping test names to their duration in seconds. If set, the longest tests of each bucket are run first. Missing files are ignored.")
 }
 
 func (o *GinkgoRunSuiteOptions) Validate() error {
@@ -103,6 +145,27 @@ func (o *GinkgoRunSuiteOptions) Validate() error {
 	default:
 		return fmt.Errorf("unknown --cluster-stability, %q, expected Stable or Disruptive", o.ClusterStabilityDuringTest)
 	}
+	if o.Checkpoint && len(o.JUnitDir) == 0 {
+		return fmt.Errorf("--checkpoint requires --junit-dir")
+	}
+	if o.RetryMaxAttempts < 0 || o.QuarantineAttempts < 0 {
+		return fmt.Errorf("--retry-max-attempts and --quarantine-attempts must not be negative")
+	}
+	for _, class := range o.RetryOn {
+		switch FailureClass(class) {
+		case FailureClassTimeout, FailureClassAssertion:
+		default:
+			return fmt.Errorf("unknown --retry-on, %q, expected %s or %s", class, FailureClassTimeout, FailureClassAssertion)
+		}
+	}
+	switch {
+	case o.ShardCount < 0:
+		return fmt.Errorf("--shard-count must not be negative")
+	case o.ShardCount == 0 && o.ShardIndex != 0:
+		return fmt.Errorf("--shard-index requires --shard-count")
+	case o.ShardCount > 0 && (o.ShardIndex < 0 || o.ShardIndex >= o.ShardCount):
+		return fmt.Errorf("--shard-index must be between 0 and %d", o.ShardCount-1)
+	}
 	return nil
 }
 
@@ -127,6 +190,10 @@ func max(a, b int) int {
 func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, monitorTestInfo monitortestframework.MonitorTestInitializationInfo, upgrade bool) error {
 	ctx := context.Background()
 
+	if err := o.Validate(); err != nil {
+		return err
+	}
+
 	tests, err := testsForSuite()
 	if err != nil {
 		return fmt.Errorf("failed reading origin test suites: %w", err)
@@ -202,7 +269,38 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 		count = suite.Count
 	}
 
+	scheduler, err := o.durationScheduler(r)
+	if err != nil {
+		return err
+	}
+	if o.ShardCount > 1 {
+		tests = shardTests(tests, o.ShardIndex, o.ShardCount, scheduler)
+		fmt.Fprintf(o.Out, "found %d tests in shard %d of %d\n", len(tests), o.ShardIndex, o.ShardCount)
+	}
+
 	start := time.Now()
+	var restored []*testCase
+	if len(o.ResumeDir) > 0 {
+		if len(o.JUnitDir) > 0 && filepath.Clean(o.JUnitDir) != filepath.Clean(o.ResumeDir) {
+			return fmt.Errorf("--junit-dir must be the --resume directory when resuming a run")
+		}
+		if count > 1 || count == -1 {
+			return fmt.Errorf("--resume is not supported with --count=%d", count)
+		}
+		o.JUnitDir = o.ResumeDir
+
+		run, completed, err := loadCheckpoint(filepath.Join(o.ResumeDir, checkpointDir))
+		if err != nil {
+			return fmt.Errorf("could not --resume: %w", err)
+		}
+		if run.Suite != suite.Name {
+			return fmt.Errorf("could not --resume: the interrupted run is of suite %q, not %q", run.Suite, suite.Name)
+		}
+		start = run.StartTime
+		o.StartTime = run.StartTime
+		tests, restored = resumeTests(tests, completed)
+		fmt.Fprintf(o.Out, "Resuming the run started at %s, %d tests completed, %d tests to run\n", start.UTC().Format(time.RFC3339), len(restored), len(tests))
+	}
 	if o.StartTime.IsZero() {
 		o.StartTime = start
 	}
@@ -215,10 +313,15 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 		timeout = 15 * time.Minute
 	}
 
+	retryPolicy, err := o.retryPolicy(suite.RetryPolicy)
+	if err != nil {
+		return err
+	}
+
 	testRunnerContext := newCommandContext(o.AsEnv(), timeout)
 
 	if o.PrintCommands {
-		newParallelTestQueue(testRunnerContext).OutputCommands(ctx, tests, o.Out)
+		newParallelTestQueue(testRunnerContext, nil).OutputCommands(ctx, tests, o.Out)
 		return nil
 	}
 	if o.DryRun {
@@ -250,6 +353,19 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 		}
 	}
 
+	// when checkpointing, the results of the tests and the intervals are written as the run progresses, so it can be resumed.
+	var checkpoint *checkpointJournal
+	if len(o.JUnitDir) > 0 && (o.Checkpoint || len(o.ResumeDir) > 0) {
+		checkpoint, err = newCheckpointJournal(filepath.Join(o.JUnitDir, checkpointDir), checkpointRun{Suite: suite.Name, StartTime: start}, len(o.ResumeDir) > 0)
+		if err != nil {
+			return fmt.Errorf("could not create checkpoint: %w", err)
+		}
+		defer checkpoint.Close()
+		if len(o.IntervalStorageDir) == 0 {
+			o.IntervalStorageDir = filepath.Join(o.JUnitDir, checkpointDir, checkpointIntervalsDir)
+		}
+	}
+
 	parallelism := o.Parallelism
 	if parallelism == 0 {
 		parallelism = suite.Parallelism
@@ -277,18 +393,40 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	}()
 	signal.Notify(abortCh, syscall.SIGINT, syscall.SIGTERM)
 
+	monitorTestInfo.PathologicalEventAllowanceFiles = o.PathologicalEventAllowanceFiles
 	monitorTests, err := defaultmonitortests.NewMonitorTestsFor(monitorTestInfo)
 	if err != nil {
-		logrus.Errorf("Error getting monitor tests: %v", err)
+		return fmt.Errorf("could not create monitor tests: %w", err)
 	}
 
 	monitorEventRecorder := monitor.NewRecorder()
+	if len(o.IntervalStorageDir) > 0 {
+		monitorEventRecorder, err = monitor.NewSegmentedRecorder(o.IntervalStorageDir, monitor.DefaultIntervalsPerSegment)
+		if err != nil {
+			return fmt.Errorf("could not create --interval-storage-dir recorder: %w", err)
+		}
+	}
+	if len(o.IntervalStreamAddress) > 0 {
+		monitorEventRecorder, err = intervalstream.StreamIntervals(ctx, o.IntervalStreamAddress, monitorEventRecorder, o.Out)
+		if err != nil {
+			return err
+		}
+	}
 	m := monitor.NewMonitor(
 		monitorEventRecorder,
 		restConfig,
 		o.JUnitDir,
 		monitorTests,
 	)
+	if len(o.ResumeDir) > 0 {
+		m = monitor.NewResumedMonitor(
+			monitorEventRecorder,
+			restConfig,
+			o.JUnitDir,
+			monitorTests,
+			start,
+		)
+	}
 	if err := m.Start(ctx); err != nil {
 		return err
 	}
@@ -346,6 +484,10 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	}
 	expectedTestCount += len(openshiftTests) + len(kubeTests) + len(storageTests) + len(mustGatherTests)
 
+	for _, bucket := range [][]*testCase{early, kubeTests, storageTests, openshiftTests, mustGatherTests, late} {
+		scheduler.Order(bucket)
+	}
+
 	abortFn := neverAbort
 	testCtx := ctx
 	if o.FailFast {
@@ -355,8 +497,8 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	tests = nil
 
 	// run our Early tests
-	q := newParallelTestQueue(testRunnerContext)
-	q.Execute(testCtx, early, parallelism, testOutputConfig, abortFn)
+	q := newParallelTestQueue(testRunnerContext, checkpoint)
+	scheduler.Execute(testCtx, q, "early", early, parallelism, testOutputConfig, abortFn)
 	tests = append(tests, early...)
 
 	// TODO: will move to the monitor
@@ -366,21 +508,21 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	// we loop indefinitely.
 	for i := 0; (i < 1 || count == -1) && testCtx.Err() == nil; i++ {
 		kubeTestsCopy := copyTests(kubeTests)
-		q.Execute(testCtx, kubeTestsCopy, parallelism, testOutputConfig, abortFn)
+		scheduler.Execute(testCtx, q, "kube", kubeTestsCopy, parallelism, testOutputConfig, abortFn)
 		tests = append(tests, kubeTestsCopy...)
 
 		// I thought about randomizing the order of the kube, storage, and openshift tests, but storage dominates our e2e runs, so it doesn't help much.
 		storageTestsCopy := copyTests(storageTests)
-		q.Execute(testCtx, storageTestsCopy, max(1, parallelism/2), testOutputConfig, abortFn) // storage tests only run at half the parallelism, so we can avoid cloud provider quota problems.
+		scheduler.Execute(testCtx, q, "storage", storageTestsCopy, max(1, parallelism/2), testOutputConfig, abortFn) // storage tests only run at half the parallelism, so we can avoid cloud provider quota problems.
 		tests = append(tests, storageTestsCopy...)
 
 		openshiftTestsCopy := copyTests(openshiftTests)
-		q.Execute(testCtx, openshiftTestsCopy, parallelism, testOutputConfig, abortFn)
+		scheduler.Execute(testCtx, q, "openshift", openshiftTestsCopy, parallelism, testOutputConfig, abortFn)
 		tests = append(tests, openshiftTestsCopy...)
 
 		// run the must-gather tests after parallel tests to reduce resource contention
 		mustGatherTestsCopy := copyTests(mustGatherTests)
-		q.Execute(testCtx, mustGatherTestsCopy, parallelism, testOutputConfig, abortFn)
+		scheduler.Execute(testCtx, q, "must-gather", mustGatherTestsCopy, parallelism, testOutputConfig, abortFn)
 		tests = append(tests, mustGatherTestsCopy...)
 	}
 
@@ -388,9 +530,12 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	pc.SetEvents([]string{postUpgradeEvent})
 
 	// run Late test suits after everything else
-	q.Execute(testCtx, late, parallelism, testOutputConfig, abortFn)
+	scheduler.Execute(testCtx, q, "late", late, parallelism, testOutputConfig, abortFn)
 	tests = append(tests, late...)
 
+	// the tests that completed before the run was interrupted
+	tests = append(tests, restored...)
+
 	// TODO: will move to the monitor
 	if len(o.JUnitDir) > 0 {
 		pc.ComputePodTransitions()
@@ -420,46 +565,48 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	pass, fail, skip, failing := summarizeTests(tests)
 
 	// attempt to retry failures to do flake detection
-	if fail > 0 && fail <= suite.MaximumAllowedFlakes {
-		var retries []*testCase
-
-		// Make a copy of the all failing tests (subject to the max allowed flakes) so we can have
-		// a list of tests to retry.
-		for _, test := range failing {
-			retry := test.Retry()
-			retries = append(retries, retry)
-			if len(retries) > suite.MaximumAllowedFlakes {
-				break
-			}
+	withinFlakeBudget := retryPolicy.withinFlakeBudget(failing, suite.MaximumAllowedFlakes)
+	// quarantined tests may flake even when the suite allows no flakes
+	onlyQuarantinedFailures := retryPolicy.onlyQuarantined(failing)
+	var retries, repeatFailures []*testCase
+	for _, test := range failing {
+		if retryPolicy.shouldRetry(test, withinFlakeBudget) {
+			retries = append(retries, test.Retry())
+		} else {
+			repeatFailures = append(repeatFailures, test)
 		}
-
-		fmt.Fprintf(o.Out, "Retry count: %d\n", len(retries))
-
-		// Run the tests in the retries list.
-		q := newParallelTestQueue(testRunnerContext)
-		q.Execute(testCtx, retries, parallelism, testOutputConfig, abortFn)
-
+	}
+	if len(retries) > 0 {
 		var flaky, skipped []string
-		var repeatFailures []*testCase
-		for _, test := range retries {
-			if test.success {
-				flaky = append(flaky, test.name)
-			} else if test.skipped {
-				skipped = append(skipped, test.name)
-			} else {
-				repeatFailures = append(repeatFailures, test)
+		for len(retries) > 0 {
+			fmt.Fprintf(o.Out, "Retry count: %d\n", len(retries))
+
+			// Run the tests in the retries list.
+			q := newParallelTestQueue(testRunnerContext, nil)
+			q.Execute(testCtx, retries, parallelism, testOutputConfig, abortFn)
+
+			// Every attempt is added to the list of all tests, to keep the history of the retries.
+			var next []*testCase
+			for _, test := range retries {
+				tests = append(tests, test)
+				switch {
+				case test.success:
+					flaky = append(flaky, test.name)
+				case test.skipped:
+					skipped = append(skipped, test.name)
+				case retryPolicy.shouldRetry(test, withinFlakeBudget):
+					next = append(next, test.Retry())
+				default:
+					if test.flake {
+						// A retry that flaked does not make the test pass, the failure is authoritative.
+						fmt.Fprintf(o.Out, "Retry returned a flake, counting it as a failure for test: %s\n", test.name)
+					}
+					repeatFailures = append(repeatFailures, test)
+				}
 			}
+			retries = next
 		}
 
-		// Add the list of retries into the list of all tests.
-		for _, retry := range retries {
-			if retry.flake {
-				// Retry tests that flaked are omitted so that the original test is counted as a failure.
-				fmt.Fprintf(o.Out, "Ignoring retry that returned a flake, original failure is authoritative for test: %s\n", retry.name)
-				continue
-			}
-			tests = append(tests, retry)
-		}
 		if len(flaky) > 0 {
 			failing = repeatFailures
 			sort.Strings(flaky)
@@ -567,6 +714,12 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 
 	if len(o.JUnitDir) > 0 {
 		finalSuiteResults := generateJUnitTestSuiteResults(junitSuiteName, duration, tests, syntheticTestResults...)
+		if o.ShardCount > 1 {
+			finalSuiteResults.Properties = append(finalSuiteResults.Properties, &junitapi.TestSuiteProperty{
+				Name:  junitapi.ShardProperty,
+				Value: fmt.Sprintf("%d/%d", o.ShardIndex, o.ShardCount),
+			})
+		}
 		if err := writeJUnitReport(finalSuiteResults, "junit_e2e", timeSuffix, o.JUnitDir, o.ErrOut); err != nil {
 			fmt.Fprintf(o.Out, "error: Unable to write e2e JUnit xml results: %v", err)
 		}
@@ -574,10 +727,20 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 		if err := riskanalysis.WriteJobRunTestFailureSummary(o.JUnitDir, timeSuffix, finalSuiteResults, wasMasterNodeUpdated, ""); err != nil {
 			fmt.Fprintf(o.Out, "error: Unable to write e2e job run failures summary: %v", err)
 		}
+
+		// the run completed, there is nothing left to resume
+		if checkpoint != nil && ctx.Err() == nil {
+			if err := checkpoint.Close(); err != nil {
+				fmt.Fprintf(o.ErrOut, "error: Unable to close checkpoint: %v\n", err)
+			}
+			if err := os.RemoveAll(filepath.Join(o.JUnitDir, checkpointDir)); err != nil {
+				fmt.Fprintf(o.ErrOut, "error: Unable to remove checkpoint: %v\n", err)
+			}
+		}
 	}
 
 	if fail > 0 {
-		if len(failing) > 0 || suite.MaximumAllowedFlakes == 0 {
+		if len(failing) > 0 || (suite.MaximumAllowedFlakes == 0 && !onlyQuarantinedFailures) {
 			return fmt.Errorf("%d fail, %d pass, %d skip (%s)", fail, pass, skip, duration)
 		}
 		fmt.Fprintf(o.Out, "%d flakes detected, suite allows passing with only flakes\n\n", fail)
@@ -594,6 +757,55 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	return ctx.Err()
 }
 
+// retryPolicy returns the retry policy of the suite, with the retry and quarantine flags applied.
+func (o *GinkgoRunSuiteOptions) retryPolicy(policy RetryPolicy) (RetryPolicy, error) {
+	if o.RetryMaxAttempts > 0 {
+		policy.MaxAttempts = o.RetryMaxAttempts
+	}
+	if len(o.RetryOn) > 0 {
+		policy.RetryOn = nil
+		for _, class := range o.RetryOn {
+			policy.RetryOn = append(policy.RetryOn, FailureClass(class))
+		}
+	}
+	if o.QuarantineAttempts > 0 {
+		policy.QuarantineAttempts = o.QuarantineAttempts
+	}
+	if len(o.QuarantinedTestsFiles) > 0 {
+		quarantined, err := loadQuarantinedTests(o.QuarantinedTestsFiles...)
+		if err != nil {
+			return RetryPolicy{}, fmt.Errorf("could not load --quarantined-tests: %w", err)
+		}
+		policy.QuarantinedTests = append(append([]string{}, policy.QuarantinedTests...), quarantined...)
+	}
+	return policy, nil
+}
+
+// durationScheduler returns nil, keeping the random order, unless a test duration history file exists.
+func (o *GinkgoRunSuiteOptions) durationScheduler(r *rand.Rand) (*durationScheduler, error) {
+	var paths []string
+	for _, path := range o.TestDurationHistoryFiles {
+		if _, err := os.Stat(path); err != nil {
+			if !os.IsNotExist(err) {
+				return nil, fmt.Errorf("could not access --test-duration-history: %w", err)
+			}
+			fmt.Fprintf(o.Out, "Ignoring missing test duration history %s\n", path)
+			continue
+		}
+		paths = append(paths, path)
+	}
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	history, err := loadTestDurationHistory(paths...)
+	if err != nil {
+		return nil, fmt.Errorf("could not load --test-duration-history: %w", err)
+	}
+	fmt.Fprintf(o.Out, "Scheduling the longest tests first, using the duration of %d tests from previous runs\n", len(history))
+	return newDurationScheduler(history, r, o.Out), nil
+}
+
 func (o *GinkgoRunSuiteOptions) filterOutRebaseTests(restConfig *rest.Config, tests []*testCase) ([]*testCase, error) {
 	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
 	if err != nil {
@@ -0,0 +1,196 @@
+package ginkgo
+
+import (
+	"container/heap"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math/bits"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// testDurationHistory is how long each test took in previous runs, by test name.
+type testDurationHistory map[string]time.Duration
+
+// loadTestDurationHistory reads the duration of the tests from JUnit reports of previous runs, like
+// junit_e2e_*.xml, or from JSON files mapping the name of the tests to their duration in seconds.
+// When a test is in several files, its average duration is used.  Skipped tests and tests without a
+// duration are ignored.
+func loadTestDurationHistory(paths ...string) (testDurationHistory, error) {
+	totals := map[string]float64{}
+	counts := map[string]int{}
+	for _, path := range paths {
+		durations, err := readTestDurations(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read test durations from %s: %w", path, err)
+		}
+		for name, seconds := range durations {
+			if seconds <= 0 {
+				continue
+			}
+			totals[name] += seconds
+			counts[name]++
+		}
+	}
+
+	history := testDurationHistory{}
+	for name, total := range totals {
+		history[name] = time.Duration(total / float64(counts[name]) * float64(time.Second))
+	}
+	return history, nil
+}
+
+func readTestDurations(path string) (map[string]float64, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	durations := map[string]float64{}
+	if !strings.EqualFold(filepath.Ext(path), ".xml") {
+		if err := json.Unmarshal(content, &durations); err != nil {
+			return nil, err
+		}
+		return durations, nil
+	}
+
+	// openshift-tests writes a single suite, other tools wrap the suites.
+	suites := &junitapi.JUnitTestSuites{}
+	suite := &junitapi.JUnitTestSuite{}
+	if err := xml.Unmarshal(content, suite); err == nil {
+		suites.Suites = append(suites.Suites, suite)
+	} else if err := xml.Unmarshal(content, suites); err != nil {
+		return nil, err
+	}
+	var addSuite func(suite *junitapi.JUnitTestSuite)
+	addSuite = func(suite *junitapi.JUnitTestSuite) {
+		for _, testCase := range suite.TestCases {
+			if testCase.SkipMessage != nil {
+				continue
+			}
+			durations[testCase.Name] = testCase.Duration
+		}
+		for _, child := range suite.Children {
+			addSuite(child)
+		}
+	}
+	for _, suite := range suites.Suites {
+		addSuite(suite)
+	}
+	return durations, nil
+}
+
+// durationScheduler orders the tests of each bucket longest first, so that the long tests do not land at the end
+// of a bucket and stretch it while the other workers are idle.  Tests with a similar duration are kept in random
+// order to avoid intra-tests dependencies.
+type durationScheduler struct {
+	history testDurationHistory
+	// defaultDuration is the predicted duration of tests without history, the median of the known durations.
+	defaultDuration time.Duration
+	rand            *rand.Rand
+	out             io.Writer
+}
+
+func newDurationScheduler(history testDurationHistory, r *rand.Rand, out io.Writer) *durationScheduler {
+	durations := make([]time.Duration, 0, len(history))
+	for _, duration := range history {
+		durations = append(durations, duration)
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	var defaultDuration time.Duration
+	if len(durations) > 0 {
+		defaultDuration = durations[len(durations)/2]
+	}
+
+	return &durationScheduler{
+		history:         history,
+		defaultDuration: defaultDuration,
+		rand:            r,
+		out:             out,
+	}
+}
+
+func (s *durationScheduler) predictedDuration(test *testCase) time.Duration {
+	if duration, ok := s.history[test.name]; ok {
+		return duration
+	}
+	return s.defaultDuration
+}
+
+// durationBand groups durations within a factor of two of each other.
+func durationBand(duration time.Duration) int {
+	return bits.Len64(uint64(duration / time.Second))
+}
+
+// Order sorts the tests longest processing time first, by duration band, shuffling the tests within a band.
+// A nil scheduler leaves the order untouched.
+func (s *durationScheduler) Order(tests []*testCase) {
+	if s == nil {
+		return
+	}
+	s.rand.Shuffle(len(tests), func(i, j int) { tests[i], tests[j] = tests[j], tests[i] })
+	sort.SliceStable(tests, func(i, j int) bool {
+		return durationBand(s.predictedDuration(tests[i])) > durationBand(s.predictedDuration(tests[j]))
+	})
+}
+
+// PredictMakespan is how long the bucket is expected to take, running the parallel tests in order on the first
+// available worker, then the serial tests one at a time.
+func (s *durationScheduler) PredictMakespan(tests []*testCase, parallelism int) time.Duration {
+	serial, parallel := splitTests(tests, isSerialTest)
+
+	workers := make(workerFinishTimes, max(1, parallelism))
+	for _, test := range parallel {
+		workers[0] += s.predictedDuration(test)
+		heap.Fix(&workers, 0)
+	}
+	var makespan time.Duration
+	for _, finish := range workers {
+		if finish > makespan {
+			makespan = finish
+		}
+	}
+	for _, test := range serial {
+		makespan += s.predictedDuration(test)
+	}
+	return makespan
+}
+
+// Execute runs the bucket on the queue and reports its predicted and actual makespan.  A nil scheduler just
+// runs the bucket.
+func (s *durationScheduler) Execute(ctx context.Context, q *parallelByFileTestQueue, bucket string, tests []*testCase, parallelism int, testOutput testOutputConfig, maybeAbortOnFailureFn testAbortFunc) {
+	if s == nil {
+		q.Execute(ctx, tests, parallelism, testOutput, maybeAbortOnFailureFn)
+		return
+	}
+
+	predicted := s.PredictMakespan(tests, parallelism)
+	start := time.Now()
+	q.Execute(ctx, tests, parallelism, testOutput, maybeAbortOnFailureFn)
+	actual := time.Since(start)
+	fmt.Fprintf(s.out, "Bucket %s ran %d tests at parallelism %d in %s, predicted %s\n", bucket, len(tests), parallelism, actual.Round(time.Second), predicted.Round(time.Second))
+}
+
+// workerFinishTimes is a min-heap of the time each worker becomes available.
+type workerFinishTimes []time.Duration
+
+func (w workerFinishTimes) Len() int            { return len(w) }
+func (w workerFinishTimes) Less(i, j int) bool  { return w[i] < w[j] }
+func (w workerFinishTimes) Swap(i, j int)       { w[i], w[j] = w[j], w[i] }
+func (w *workerFinishTimes) Push(x interface{}) { *w = append(*w, x.(time.Duration)) }
+func (w *workerFinishTimes) Pop() interface{} {
+	old := *w
+	n := len(old)
+	x := old[n-1]
+	*w = old[:n-1]
+	return x
+}
@@ -0,0 +1,104 @@
+package ginkgo
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_loadTestDurationHistory(t *testing.T) {
+	dir := t.TempDir()
+	junit := filepath.Join(dir, "junit_e2e_20240101-000000.xml")
+	if err := os.WriteFile(junit, []byte(`<testsuite name="openshift-tests" tests="3" skipped="1" failures="0" time="1200">
+    <testcase name="test a" time="600"></testcase>
+    <testcase name="test b" time="30"></testcase>
+    <testcase name="test c" time="0">
+        <skipped message="skipped"></skipped>
+    </testcase>
+</testsuite>`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	wrapped := filepath.Join(dir, "junit_other.xml")
+	if err := os.WriteFile(wrapped, []byte(`<testsuites>
+    <testsuite name="other" tests="1" skipped="0" failures="0" time="400">
+        <testcase name="test a" time="400"></testcase>
+    </testsuite>
+</testsuites>`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	durations := filepath.Join(dir, "durations.json")
+	if err := os.WriteFile(durations, []byte(`{"test d": 90.5}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := loadTestDurationHistory(junit, wrapped, durations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := testDurationHistory{
+		"test a": 500 * time.Second,
+		"test b": 30 * time.Second,
+		"test d": 90500 * time.Millisecond,
+	}
+	if !reflect.DeepEqual(expected, history) {
+		t.Errorf("expected %v, got %v", expected, history)
+	}
+
+	if _, err := loadTestDurationHistory(filepath.Join(dir, "missing.xml")); err == nil {
+		t.Errorf("expected an error for a missing file")
+	}
+}
+
+func Test_durationSchedulerOrder(t *testing.T) {
+	history := testDurationHistory{
+		"long":    20 * time.Minute,
+		"medium":  3 * time.Minute,
+		"short 1": 10 * time.Second,
+		"short 2": 11 * time.Second,
+		"short 3": 12 * time.Second,
+	}
+	scheduler := newDurationScheduler(history, rand.New(rand.NewSource(1)), nil)
+
+	tests := []*testCase{{name: "short 1"}, {name: "unknown"}, {name: "short 2"}, {name: "long"}, {name: "short 3"}, {name: "medium"}}
+	scheduler.Order(tests)
+	names := testNames(tests)
+	// tests without history are predicted to take the median duration
+	if expected := []string{"long", "medium"}; !reflect.DeepEqual(expected, names[:2]) {
+		t.Errorf("expected the longest tests first, got %v", names)
+	}
+	for _, name := range names[2:] {
+		if durationBand(scheduler.predictedDuration(&testCase{name: name})) != durationBand(10*time.Second) {
+			t.Errorf("expected the short tests last, got %v", names)
+		}
+	}
+
+	var unscheduled *durationScheduler
+	tests = []*testCase{{name: "short 1"}, {name: "long"}}
+	unscheduled.Order(tests)
+	if expected := []string{"short 1", "long"}; !reflect.DeepEqual(expected, testNames(tests)) {
+		t.Errorf("expected the order to be kept without history, got %v", testNames(tests))
+	}
+}
+
+func Test_durationSchedulerPredictMakespan(t *testing.T) {
+	history := testDurationHistory{
+		"a":          10 * time.Minute,
+		"b":          6 * time.Minute,
+		"c":          5 * time.Minute,
+		"d":          4 * time.Minute,
+		"e [Serial]": 2 * time.Minute,
+	}
+	scheduler := newDurationScheduler(history, rand.New(rand.NewSource(1)), nil)
+	tests := []*testCase{{name: "a"}, {name: "b"}, {name: "c"}, {name: "d"}, {name: "e [Serial]"}}
+
+	// a then d on one worker, b then c on the other, then the serial test
+	if expected, actual := 16*time.Minute, scheduler.PredictMakespan(tests, 2); expected != actual {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+	if expected, actual := 27*time.Minute, scheduler.PredictMakespan(tests, 1); expected != actual {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+}
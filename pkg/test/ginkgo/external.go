@@ -55,10 +55,16 @@ func externalTestsForSuite(ctx context.Context) ([]*testCase, error) {
 			return nil, err
 		}
 		for _, test := range serializedTests {
+			name := test.Name + test.Labels
+			group, err := exclusionGroup(name)
+			if err != nil {
+				return nil, err
+			}
 			tests = append(tests, &testCase{
-				name:       test.Name + test.Labels,
-				rawName:    test.Name,
-				binaryName: testBinary,
+				name:          name,
+				rawName:       test.Name,
+				binaryName:    testBinary,
+				testExclusion: group,
 			})
 		}
 	}
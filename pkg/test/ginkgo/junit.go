@@ -31,7 +31,18 @@ func generateJUnitTestSuiteResults(
 			},
 		},
 	}
+	// the attempts of retried tests are numbered, so the history of the retries can be told apart
+	retried := map[*testCase]bool{}
 	for _, test := range tests {
+		for previous := test.previous; previous != nil; previous = previous.previous {
+			retried[previous] = true
+		}
+	}
+	for _, test := range tests {
+		var attempt int
+		if retried[test] || test.previous != nil {
+			attempt = test.attempt()
+		}
 		switch {
 		case test.skipped:
 			s.NumTests++
@@ -40,6 +51,7 @@ func generateJUnitTestSuiteResults(
 				Name:      test.name,
 				SystemOut: string(test.testOutputBytes),
 				Duration:  test.duration.Seconds(),
+				Attempt:   attempt,
 				SkipMessage: &junitapi.SkipMessage{
 					Message: lastLinesUntil(string(test.testOutputBytes), 100, "skip ["),
 				},
@@ -51,6 +63,7 @@ func generateJUnitTestSuiteResults(
 				Name:      test.name,
 				SystemOut: string(test.testOutputBytes),
 				Duration:  test.duration.Seconds(),
+				Attempt:   attempt,
 				FailureOutput: &junitapi.FailureOutput{
 					Output: lastLinesUntil(string(test.testOutputBytes), 100, "fail ["),
 				},
@@ -62,23 +75,35 @@ func generateJUnitTestSuiteResults(
 				Name:      test.name,
 				SystemOut: string(test.testOutputBytes),
 				Duration:  test.duration.Seconds(),
+				Attempt:   attempt,
 				FailureOutput: &junitapi.FailureOutput{
 					Output: lastLinesUntil(string(test.testOutputBytes), 100, "flake:"),
 				},
 			})
 
+			// a retry that flaked does not make up for the failure of the previous attempt
+			if test.previous != nil {
+				continue
+			}
 			// also add the successful junit result:
 			s.NumTests++
 			s.TestCases = append(s.TestCases, &junitapi.JUnitTestCase{
 				Name:     test.name,
 				Duration: test.duration.Seconds(),
+				Attempt:  attempt,
 			})
 		case test.success:
 			s.NumTests++
-			s.TestCases = append(s.TestCases, &junitapi.JUnitTestCase{
+			testCase := &junitapi.JUnitTestCase{
 				Name:     test.name,
 				Duration: test.duration.Seconds(),
-			})
+				Attempt:  attempt,
+			}
+			// keep the output of the attempt that passed after failing
+			if test.previous != nil {
+				testCase.SystemOut = string(test.testOutputBytes)
+			}
+			s.TestCases = append(s.TestCases, testCase)
 		}
 	}
 	for _, result := range syntheticTestResults {
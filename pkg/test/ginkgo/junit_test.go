@@ -1,6 +1,10 @@
 package ginkgo
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+	"time"
+)
 
 func Test_lastLines(t *testing.T) {
 	tests := []struct {
@@ -33,3 +37,42 @@ func Test_lastLines(t *testing.T) {
 		})
 	}
 }
+
+func Test_generateJUnitTestSuiteResults_retries(t *testing.T) {
+	first := &testCase{name: "failed twice, passed third", failed: true, duration: time.Second, testOutputBytes: []byte("fail [first]")}
+	second := first.Retry()
+	second.failed, second.duration, second.testOutputBytes = true, 2*time.Second, []byte("fail [second]")
+	third := second.Retry()
+	third.success, third.duration, third.testOutputBytes = true, 3*time.Second, []byte("passed")
+	flaked := (&testCase{name: "failed once, flaked", failed: true}).Retry()
+	flaked.flake, flaked.testOutputBytes = true, []byte("flake: retry")
+	passed := &testCase{name: "passed", success: true, testOutputBytes: []byte("passed")}
+
+	suite := generateJUnitTestSuiteResults("suite", time.Minute, []*testCase{first, flaked.previous, passed, second, flaked, third})
+
+	type result struct {
+		name     string
+		attempt  int
+		failed   bool
+		duration float64
+		output   string
+	}
+	var got []result
+	for _, testCase := range suite.TestCases {
+		got = append(got, result{name: testCase.Name, attempt: testCase.Attempt, failed: testCase.FailureOutput != nil, duration: testCase.Duration, output: testCase.SystemOut})
+	}
+	expected := []result{
+		{name: "failed twice, passed third", attempt: 1, failed: true, duration: 1, output: "fail [first]"},
+		{name: "failed once, flaked", attempt: 1, failed: true},
+		{name: "passed"},
+		{name: "failed twice, passed third", attempt: 2, failed: true, duration: 2, output: "fail [second]"},
+		{name: "failed once, flaked", attempt: 2, failed: true, output: "flake: retry"},
+		{name: "failed twice, passed third", attempt: 3, duration: 3, output: "passed"},
+	}
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected\n%v\ngot\n%v", expected, got)
+	}
+	if suite.NumTests != 6 || suite.NumFailed != 4 {
+		t.Errorf("unexpected counts, %d tests, %d failed", suite.NumTests, suite.NumFailed)
+	}
+}
@@ -45,6 +45,9 @@ type JUnitTestSuite struct {
 	Children []*JUnitTestSuite `xml:"testsuite"`
 }
 
+// ShardProperty is the property of the test suite of a sharded run identifying the shard, as <index>/<count>.
+const ShardProperty = "Shard"
+
 // TestSuiteProperty contains a mapping of a property name to a value
 type TestSuiteProperty struct {
 	XMLName xml.Name `xml:"property"`
@@ -53,6 +56,21 @@ type TestSuiteProperty struct {
 	Value string `xml:"value,attr"`
 }
 
+// UnmarshalXML reads the property whatever the name of its element.  Properties are written as <properties>
+// elements, which does not match the name of XMLName.
+func (p *TestSuiteProperty) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	property := struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:"value,attr"`
+	}{}
+	if err := d.DecodeElement(&property, &start); err != nil {
+		return err
+	}
+	p.Name = property.Name
+	p.Value = property.Value
+	return nil
+}
+
 // JUnitTestCase represents a jUnit test case
 type JUnitTestCase struct {
 	XMLName xml.Name `xml:"testcase"`
@@ -66,6 +84,9 @@ type JUnitTestCase struct {
 	// Duration is the time taken in seconds to run the test
 	Duration float64 `xml:"time,attr"`
 
+	// Attempt is the attempt of a retried test this result is for, starting at 1.  Unset if the test was not retried.
+	Attempt int `xml:"attempt,attr,omitempty"`
+
 	// SkipMessage holds the reason why the test was skipped
 	SkipMessage *SkipMessage `xml:"skipped"`
 
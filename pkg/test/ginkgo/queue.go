@@ -10,17 +10,22 @@ import (
 
 // parallelByFileTestQueue runs tests in parallel unless they have
 // the `[Serial]` tag on their name or if another test with the
-// testExclusion field is currently running. Serial tests are
-// defered until all other tests are completed.
+// same testExclusion field is currently running. Serial tests are
+// defered until all other tests are completed. A test whose exclusion
+// group is busy is handed to the worker running the group, the other
+// workers keep running the remaining tests.
 type parallelByFileTestQueue struct {
 	commandContext *commandContext
+	// checkpoint records the result of each test as it completes, if set.
+	checkpoint *checkpointJournal
 }
 
 type TestFunc func(ctx context.Context, test *testCase)
 
-func newParallelTestQueue(commandContext *commandContext) *parallelByFileTestQueue {
+func newParallelTestQueue(commandContext *commandContext, checkpoint *checkpointJournal) *parallelByFileTestQueue {
 	return &parallelByFileTestQueue{
 		commandContext: commandContext,
+		checkpoint:     checkpoint,
 	}
 }
 
@@ -65,8 +70,56 @@ func queueAllTests(remainingParallelTests chan *testCase, tests []*testCase) {
 	close(remainingParallelTests)
 }
 
+// exclusionGroups tracks the exclusion groups that have a test running, and the tests of those groups
+// waiting for it to complete.
+type exclusionGroups struct {
+	lock    sync.Mutex
+	running map[string]bool
+	waiting map[string][]*testCase
+}
+
+func newExclusionGroups() *exclusionGroups {
+	return &exclusionGroups{
+		running: map[string]bool{},
+		waiting: map[string][]*testCase{},
+	}
+}
+
+// claim returns true if the test can run now.  Otherwise another test of its group is running, and the
+// test is queued behind it.
+func (g *exclusionGroups) claim(test *testCase) bool {
+	if len(test.testExclusion) == 0 {
+		return true
+	}
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if g.running[test.testExclusion] {
+		g.waiting[test.testExclusion] = append(g.waiting[test.testExclusion], test)
+		return false
+	}
+	g.running[test.testExclusion] = true
+	return true
+}
+
+// release is called once the test completed, it returns the next test of the group to run, which the
+// caller now holds the group for, or nil if none is waiting.
+func (g *exclusionGroups) release(test *testCase) *testCase {
+	if len(test.testExclusion) == 0 {
+		return nil
+	}
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if waiting := g.waiting[test.testExclusion]; len(waiting) > 0 {
+		g.waiting[test.testExclusion] = waiting[1:]
+		return waiting[0]
+	}
+	delete(g.running, test.testExclusion)
+	return nil
+}
+
 // runTestsUntilChannelEmpty reads from the channel to consume tests, run them, and return when the channel is closed.
-func runTestsUntilChannelEmpty(ctx context.Context, remainingParallelTests chan *testCase, testSuiteRunner testSuiteRunner) {
+// Tests of an exclusion group that is busy are left to the worker running that group.
+func runTestsUntilChannelEmpty(ctx context.Context, remainingParallelTests chan *testCase, groups *exclusionGroups, testSuiteRunner testSuiteRunner) {
 	for {
 		select {
 		// if the context is finished, simply return
@@ -77,11 +130,16 @@ func runTestsUntilChannelEmpty(ctx context.Context, remainingParallelTests chan
 			if !ok { // channel closed, then we're done
 				return
 			}
-			// if the context is finished, simply return
-			if ctx.Err() != nil {
-				return
+			if !groups.claim(test) {
+				continue
+			}
+			for ; test != nil; test = groups.release(test) {
+				// if the context is finished, simply return
+				if ctx.Err() != nil {
+					return
+				}
+				testSuiteRunner.RunOneTest(ctx, test)
 			}
-			testSuiteRunner.RunOneTest(ctx, test)
 		}
 	}
 }
@@ -94,6 +152,7 @@ func (q *parallelByFileTestQueue) Execute(ctx context.Context, tests []*testCase
 		testOutput:            testOutput,
 		testSuiteProgress:     testSuiteProgress,
 		maybeAbortOnFailureFn: maybeAbortOnFailureFn,
+		checkpoint:            q.checkpoint,
 	}
 
 	execute(ctx, testSuiteRunner, tests, parallelism)
@@ -109,13 +168,14 @@ func execute(ctx context.Context, testSuiteRunner testSuiteRunner, tests []*test
 
 	remainingParallelTests := make(chan *testCase, 100)
 	go queueAllTests(remainingParallelTests, parallel)
+	groups := newExclusionGroups()
 
 	var wg sync.WaitGroup
 	for i := 0; i < parallelism; i++ {
 		wg.Add(1)
 		go func(ctx context.Context) {
 			defer wg.Done()
-			runTestsUntilChannelEmpty(ctx, remainingParallelTests, testSuiteRunner)
+			runTestsUntilChannelEmpty(ctx, remainingParallelTests, groups, testSuiteRunner)
 		}(ctx)
 	}
 	wg.Wait()
@@ -2,6 +2,7 @@ package ginkgo
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
 	"strings"
 	"sync"
@@ -64,3 +65,89 @@ func Test_execute(t *testing.T) {
 		t.Errorf("expected %v, got %v", len(tests), len(testsCompleted))
 	}
 }
+
+// exclusionTrackingSuiteRunner records whether two tests of the same exclusion group ran at the same time,
+// and whether tests ran alongside a running group.
+type exclusionTrackingSuiteRunner struct {
+	lock             sync.Mutex
+	running          map[string]int
+	runningUngrouped int
+	overlaps         []string
+	ranAlongside     bool
+	testsRun         int
+}
+
+func (r *exclusionTrackingSuiteRunner) RunOneTest(ctx context.Context, test *testCase) {
+	r.lock.Lock()
+	if len(test.testExclusion) > 0 {
+		if r.running[test.testExclusion] > 0 {
+			r.overlaps = append(r.overlaps, test.name)
+		}
+		r.running[test.testExclusion]++
+		if r.runningUngrouped > 0 {
+			r.ranAlongside = true
+		}
+	} else {
+		r.runningUngrouped++
+	}
+	r.lock.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if len(test.testExclusion) > 0 {
+		r.running[test.testExclusion]--
+	} else {
+		r.runningUngrouped--
+	}
+	r.testsRun++
+}
+
+func Test_executeExclusionGroups(t *testing.T) {
+	var tests []*testCase
+	for i := 0; i < 20; i++ {
+		for _, group := range []string{"ingress-config", "", "image-config", ""} {
+			tests = append(tests, &testCase{
+				name:          fmt.Sprintf("test %d in group %q", i, group),
+				testExclusion: group,
+			})
+		}
+	}
+	testSuiteRunner := &exclusionTrackingSuiteRunner{running: map[string]int{}}
+	execute(context.TODO(), testSuiteRunner, tests, 10)
+
+	if testSuiteRunner.testsRun != len(tests) {
+		t.Errorf("expected %d tests to run, got %d", len(tests), testSuiteRunner.testsRun)
+	}
+	if len(testSuiteRunner.overlaps) > 0 {
+		t.Errorf("expected no tests of the same exclusion group to run at the same time, got %v", testSuiteRunner.overlaps)
+	}
+	if !testSuiteRunner.ranAlongside {
+		t.Errorf("expected the other tests to keep running while an exclusion group is running")
+	}
+}
+
+func Test_exclusionGroup(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "[sig-network] should work [Suite:openshift/conformance/parallel]"},
+		{name: "[sig-network-edge] should change the ingress config [Exclusive:ingress-config] [Suite:openshift/conformance/parallel]", want: "ingress-config"},
+		{name: "[sig-network-edge] should change the ingress config [Exclusive:ingress-config] [Exclusive:ingress-config]", want: "ingress-config"},
+		{name: "[sig-network-edge] should change the ingress config [Exclusive:ingress-config] [Exclusive:image-config]", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := exclusionGroup(test.name)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != test.want {
+				t.Errorf("expected group %q, got %q", test.want, got)
+			}
+		})
+	}
+}
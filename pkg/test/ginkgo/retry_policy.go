@@ -0,0 +1,136 @@
+package ginkgo
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// FailureClass is the kind of failure of a test attempt, used to decide whether the test is retried.
+type FailureClass string
+
+const (
+	// FailureClassTimeout is a test that was killed because it ran longer than its timeout.
+	FailureClassTimeout FailureClass = "Timeout"
+	// FailureClassAssertion is a test that ran to completion and failed, or reported a flake.
+	FailureClassAssertion FailureClass = "Assertion"
+)
+
+// RetryPolicy controls how the failing tests of a suite are retried to detect flakes.  The zero value runs every
+// failing test once more, as long as no more tests failed than the MaximumAllowedFlakes of the suite.
+type RetryPolicy struct {
+	// MaxAttempts is the number of times a failing test is run, including the first attempt.  A test is not run
+	// again once it passes.  Defaults to 2.
+	MaxAttempts int
+	// RetryOn are the failures that are retried.  If empty, every failure is retried.
+	RetryOn []FailureClass
+
+	// QuarantinedTests are the names of tests known to be unreliable.  When they fail, they are run up to
+	// QuarantineAttempts times whatever the failure, even when too many tests failed to retry the others.
+	QuarantinedTests []string
+	// QuarantineAttempts is the number of times a failing quarantined test is run, including the first attempt.
+	// Defaults to MaxAttempts.
+	QuarantineAttempts int
+}
+
+func (p RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts <= 0 {
+		return 2
+	}
+	return p.MaxAttempts
+}
+
+func (p RetryPolicy) quarantineAttempts() int {
+	if p.QuarantineAttempts <= 0 {
+		return p.maxAttempts()
+	}
+	return p.QuarantineAttempts
+}
+
+func (p RetryPolicy) isQuarantined(test *testCase) bool {
+	for _, name := range p.QuarantinedTests {
+		if name == test.name {
+			return true
+		}
+	}
+	return false
+}
+
+// onlyQuarantined returns true if every one of the tests is quarantined.
+func (p RetryPolicy) onlyQuarantined(tests []*testCase) bool {
+	for _, test := range tests {
+		if !p.isQuarantined(test) {
+			return false
+		}
+	}
+	return len(tests) > 0
+}
+
+// withinFlakeBudget returns true if no more tests failed than the suite allows to flake.  Quarantined tests are
+// expected to fail, they do not count against the budget.
+func (p RetryPolicy) withinFlakeBudget(failing []*testCase, maximumAllowedFlakes int) bool {
+	fail := 0
+	for _, test := range failing {
+		if !p.isQuarantined(test) {
+			fail++
+		}
+	}
+	return fail <= maximumAllowedFlakes
+}
+
+// shouldRetry returns true if the failed attempt of the test should be followed by another one.  Tests that are
+// not quarantined are only retried when the suite is within its flake budget.
+func (p RetryPolicy) shouldRetry(test *testCase, withinFlakeBudget bool) bool {
+	if p.isQuarantined(test) {
+		return test.attempt() < p.quarantineAttempts()
+	}
+	if !withinFlakeBudget || test.attempt() >= p.maxAttempts() {
+		return false
+	}
+	if len(p.RetryOn) == 0 {
+		return true
+	}
+	class := failureClass(test)
+	for _, retryOn := range p.RetryOn {
+		if retryOn == class {
+			return true
+		}
+	}
+	return false
+}
+
+// loadQuarantinedTests reads the names of the quarantined tests from files with one test name per line.  Empty
+// lines and lines starting with # are ignored.
+func loadQuarantinedTests(paths ...string) ([]string, error) {
+	var names []string
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read quarantined tests from %s: %w", path, err)
+		}
+		for _, line := range strings.Split(string(content), "\n") {
+			name := strings.TrimSpace(line)
+			if len(name) == 0 || strings.HasPrefix(name, "#") {
+				continue
+			}
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+func failureClass(test *testCase) FailureClass {
+	if test.timedOut {
+		return FailureClassTimeout
+	}
+	return FailureClassAssertion
+}
+
+// attempt returns the attempt of the test this is, starting at 1.
+func (t *testCase) attempt() int {
+	attempt := 1
+	for previous := t.previous; previous != nil; previous = previous.previous {
+		attempt++
+	}
+	return attempt
+}
@@ -0,0 +1,82 @@
+package ginkgo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRetryPolicy_shouldRetry(t *testing.T) {
+	failed := &testCase{name: "fails", failed: true}
+	timedOut := &testCase{name: "times out", failed: true, timedOut: true}
+	quarantined := &testCase{name: "quarantined", failed: true}
+	retried := func(test *testCase, attempts int) *testCase {
+		for i := 1; i < attempts; i++ {
+			test = test.Retry()
+			test.failed = true
+		}
+		return test
+	}
+
+	tests := []struct {
+		name              string
+		policy            RetryPolicy
+		test              *testCase
+		withinFlakeBudget bool
+		want              bool
+	}{
+		{name: "default retries once", test: failed, withinFlakeBudget: true, want: true},
+		{name: "default does not retry twice", test: retried(failed, 2), withinFlakeBudget: true, want: false},
+		{name: "not retried over the flake budget", test: failed, want: false},
+		{name: "retried up to the max attempts", policy: RetryPolicy{MaxAttempts: 3}, test: retried(failed, 2), withinFlakeBudget: true, want: true},
+		{name: "not retried after the max attempts", policy: RetryPolicy{MaxAttempts: 3}, test: retried(failed, 3), withinFlakeBudget: true, want: false},
+		{name: "timeout retried", policy: RetryPolicy{RetryOn: []FailureClass{FailureClassTimeout}}, test: timedOut, withinFlakeBudget: true, want: true},
+		{name: "assertion not retried", policy: RetryPolicy{RetryOn: []FailureClass{FailureClassTimeout}}, test: failed, withinFlakeBudget: true, want: false},
+		{name: "quarantined retried over the flake budget", policy: RetryPolicy{QuarantinedTests: []string{"quarantined"}, QuarantineAttempts: 3, RetryOn: []FailureClass{FailureClassTimeout}}, test: retried(quarantined, 2), want: true},
+		{name: "quarantined not retried after its attempts", policy: RetryPolicy{QuarantinedTests: []string{"quarantined"}, QuarantineAttempts: 3}, test: retried(quarantined, 3), withinFlakeBudget: true, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.shouldRetry(tt.test, tt.withinFlakeBudget); got != tt.want {
+				t.Errorf("shouldRetry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	policy := RetryPolicy{QuarantinedTests: []string{"quarantined"}}
+	if !policy.onlyQuarantined([]*testCase{quarantined, retried(quarantined, 2)}) {
+		t.Errorf("expected only quarantined failures")
+	}
+	if policy.onlyQuarantined([]*testCase{quarantined, failed}) || policy.onlyQuarantined(nil) {
+		t.Errorf("expected failures that are not quarantined")
+	}
+}
+
+func TestRetryPolicy_withinFlakeBudget(t *testing.T) {
+	policy := RetryPolicy{QuarantinedTests: []string{"quarantined"}}
+	failing := []*testCase{{name: "fails", failed: true}, {name: "quarantined", failed: true}, {name: "quarantined", failed: true}}
+	if !policy.withinFlakeBudget(failing, 1) {
+		t.Errorf("expected the quarantined failures not to count against the flake budget")
+	}
+	if policy.withinFlakeBudget(failing, 0) {
+		t.Errorf("expected the failure that is not quarantined to exceed the flake budget")
+	}
+}
+
+func TestLoadQuarantinedTests(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quarantined.txt")
+	if err := os.WriteFile(path, []byte("# known to flake\n[sig-network] test a\n\n  [sig-node] test b  \n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	names, err := loadQuarantinedTests(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"[sig-network] test a", "[sig-node] test b"}; !reflect.DeepEqual(expected, names) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+	if _, err := loadQuarantinedTests(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Errorf("expected an error for a missing file")
+	}
+}
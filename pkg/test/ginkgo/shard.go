@@ -0,0 +1,54 @@
+package ginkgo
+
+import (
+	"hash/fnv"
+	"sort"
+	"time"
+)
+
+// shardTests returns the tests of shard index out of count, in their current order.  Every shard must compute the
+// assignment from the same filtered list of tests.  Without a scheduler, a test is assigned by a stable hash of
+// its name.  With a scheduler, the tests are balanced across the shards by their predicted duration, longest
+// first, so every shard must use the same test duration history.
+func shardTests(tests []*testCase, index, count int, scheduler *durationScheduler) []*testCase {
+	if count <= 1 {
+		return tests
+	}
+
+	assigned := map[*testCase]int{}
+	if scheduler == nil {
+		for _, test := range tests {
+			hash := fnv.New32a()
+			hash.Write([]byte(test.name))
+			assigned[test] = int(hash.Sum32() % uint32(count))
+		}
+	} else {
+		byDuration := append([]*testCase{}, tests...)
+		sort.SliceStable(byDuration, func(i, j int) bool {
+			iDuration, jDuration := scheduler.predictedDuration(byDuration[i]), scheduler.predictedDuration(byDuration[j])
+			if iDuration != jDuration {
+				return iDuration > jDuration
+			}
+			return byDuration[i].name < byDuration[j].name
+		})
+		loads := make([]time.Duration, count)
+		for _, test := range byDuration {
+			shard := 0
+			for i := range loads {
+				if loads[i] < loads[shard] {
+					shard = i
+				}
+			}
+			loads[shard] += scheduler.predictedDuration(test)
+			assigned[test] = shard
+		}
+	}
+
+	shard := []*testCase{}
+	for _, test := range tests {
+		if assigned[test] == index {
+			shard = append(shard, test)
+		}
+	}
+	return shard
+}
@@ -0,0 +1,71 @@
+package ginkgo
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func Test_shardTests(t *testing.T) {
+	var tests []*testCase
+	for i := 0; i < 100; i++ {
+		tests = append(tests, &testCase{name: fmt.Sprintf("test %d", i)})
+	}
+	shuffled := append([]*testCase{}, tests...)
+	rand.New(rand.NewSource(1)).Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+	history := testDurationHistory{}
+	for i, test := range tests {
+		history[test.name] = time.Duration(i+1) * time.Second
+	}
+	scheduler := newDurationScheduler(history, rand.New(rand.NewSource(1)), nil)
+
+	for name, scheduler := range map[string]*durationScheduler{"hash": nil, "duration": scheduler} {
+		t.Run(name, func(t *testing.T) {
+			seen := map[string]int{}
+			var loads []time.Duration
+			for index := 0; index < 3; index++ {
+				shard := shardTests(tests, index, 3, scheduler)
+				// the assignment does not depend on the order of the tests
+				if expected, actual := testNames(shard), testNames(shardTests(shuffled, index, 3, scheduler)); !reflect.DeepEqual(sortedNames(expected), sortedNames(actual)) {
+					t.Errorf("expected shard %d to be the same in any order, got %v and %v", index, expected, actual)
+				}
+				var load time.Duration
+				for _, test := range shard {
+					seen[test.name]++
+					load += history[test.name]
+				}
+				loads = append(loads, load)
+			}
+			if len(seen) != len(tests) {
+				t.Errorf("expected every test in a shard, got %d of %d", len(seen), len(tests))
+			}
+			for name, count := range seen {
+				if count != 1 {
+					t.Errorf("expected %q in exactly one shard, got %d", name, count)
+				}
+			}
+			if scheduler == nil {
+				return
+			}
+			for _, load := range loads {
+				if load-loads[0] > 100*time.Second || loads[0]-load > 100*time.Second {
+					t.Errorf("expected the shards to be balanced by duration, got %v", loads)
+				}
+			}
+		})
+	}
+
+	if shard := shardTests(tests, 0, 1, nil); len(shard) != len(tests) {
+		t.Errorf("expected a single shard to have every test, got %d", len(shard))
+	}
+}
+
+func sortedNames(names []string) []string {
+	sorted := append([]string{}, names...)
+	sort.Strings(sorted)
+	return sorted
+}
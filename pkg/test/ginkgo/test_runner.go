@@ -29,6 +29,7 @@ type testSuiteRunnerImpl struct {
 	testOutput            testOutputConfig
 	testSuiteProgress     *testSuiteProgress
 	maybeAbortOnFailureFn testAbortFunc
+	checkpoint            *checkpointJournal
 }
 
 // RunOneTest runs a test, mutates the testCase with result, and reports the result
@@ -57,6 +58,10 @@ func (r *testSuiteRunnerImpl) RunOneTest(ctx context.Context, test *testCase) {
 
 	testRunResult.testRunResult = r.commandContext.RunTestInNewProcess(ctx, test)
 	mutateTestCaseWithResults(test, testRunResult)
+	// a test interrupted by the end of the run did not complete, a resumed run runs it again.
+	if ctx.Err() == nil {
+		r.checkpoint.Record(test)
+	}
 }
 
 func mutateTestCaseWithResults(test *testCase, testRunResult *testRunResultHandle) {
@@ -1,6 +1,7 @@
 package ginkgo
 
 import (
+	"fmt"
 	"regexp"
 	"time"
 
@@ -45,7 +46,24 @@ func testsForSuite() ([]*testCase, error) {
 	return tests, nil
 }
 
-var re = regexp.MustCompile(`.*\[Timeout:(.[^\]]*)\]`)
+var (
+	re          = regexp.MustCompile(`.*\[Timeout:(.[^\]]*)\]`)
+	exclusiveRe = regexp.MustCompile(`\[Exclusive:([^\]]+)\]`)
+)
+
+// exclusionGroup returns the name of the exclusion group the test declares with an [Exclusive:<group>]
+// tag, either in its name or through the annotation rules, or an empty string.  Tests in the same group
+// never run at the same time, a test can only belong to one group.
+func exclusionGroup(name string) (string, error) {
+	group := ""
+	for _, match := range exclusiveRe.FindAllStringSubmatch(name, -1) {
+		if len(group) > 0 && group != match[1] {
+			return "", fmt.Errorf("test %q belongs to more than one exclusion group: %s and %s", name, group, match[1])
+		}
+		group = match[1]
+	}
+	return group, nil
+}
 
 func newTestCaseFromGinkgoSpec(spec types.TestSpec) (*testCase, error) {
 	name := spec.Text()
@@ -63,6 +81,12 @@ func newTestCaseFromGinkgoSpec(spec types.TestSpec) (*testCase, error) {
 		tc.testTimeout = testTimeOut
 	}
 
+	group, err := exclusionGroup(name)
+	if err != nil {
+		return nil, err
+	}
+	tc.testExclusion = group
+
 	return tc, nil
 }
 
@@ -78,7 +102,8 @@ type testCase struct {
 	spec       types.TestSpec
 	locations  []types.CodeLocation
 
-	// identifies which tests can be run in parallel (ginkgo runs suites linearly)
+	// identifies which tests can be run in parallel (ginkgo runs suites linearly),
+	// tests with the same testExclusion never run at the same time
 	testExclusion string
 	// specific timeout for the current test. When set, it overrides the current
 	// suite timeout
@@ -134,6 +159,9 @@ type TestSuite struct {
 	Parallelism int
 	// The number of flakes that may occur before this test is marked as a failure.
 	MaximumAllowedFlakes int
+	// RetryPolicy controls how failing tests are retried to detect flakes.  The --retry-* and --quarantine* flags
+	// override it.
+	RetryPolicy RetryPolicy
 
 	ClusterStabilityDuringTest ClusterStabilityDuringTest
 
@@ -52706,7 +52706,7 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
     }
 
     function isEndpointConnectivity(eventInterval) {
-        if (!eventInterval.message.includes("reason/DisruptionBegan") && !eventInterval.message.includes("reason/DisruptionSamplerOutageBegan")){
+        if (!eventInterval.message.includes("reason/DisruptionBegan") && !eventInterval.message.includes("reason/DisruptionSamplerOutageBegan") && !eventInterval.message.includes("reason/DisruptionLatencyDegraded")){
             return false
         }
         if (eventInterval.locator.includes("disruption/")) {
@@ -52847,6 +52847,10 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
         if (ciClusterDisruption != -1) {
             return [item.locator, "", "CIClusterDisruption"]
         }
+        // the backend was available, but slower than its latency threshold
+        if (item.message.includes("reason/DisruptionLatencyDegraded")) {
+            return [item.locator, "", "LatencyDegraded"]
+        }
         return [item.locator, "", "Disruption"]
     }
 
@@ -53041,7 +53045,7 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
                 'Update', 'Drain', 'Reboot', 'OperatingSystemUpdate', 'NodeNotReady', // nodes
                 'Passed', 'Skipped', 'Flaked', 'Failed',  // tests
                 'PodCreated', 'PodScheduled', 'PodTerminating','ContainerWait', 'ContainerStart', 'ContainerNotReady', 'ContainerReady', 'ContainerReadinessFailed', 'ContainerReadinessErrored',  'StartupProbeFailed', // pods
-                'CIClusterDisruption', 'Disruption', // disruption
+                'CIClusterDisruption', 'Disruption', 'LatencyDegraded', // disruption
                 'Degraded', 'Upgradeable', 'False', 'Unknown',
                 'PodLogInfo', 'PodLogWarning', 'PodLogError',
                 'EtcdOther', 'EtcdLeaderFound', 'EtcdLeaderLost', 'EtcdLeaderElected', 'EtcdLeaderMissing'])
@@ -53052,7 +53056,7 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
                 '#1e7bd9', '#4294e6', '#6aaef2', '#96cbff', '#fada5e', // nodes
                 '#3cb043', '#ceba76', '#ffa500', '#d0312d', // tests
                 '#96cbff', '#1e7bd9', '#ffa500', '#ca8dfd', '#9300ff', '#fada5e','#3cb043', '#d0312d', '#d0312d', '#c90076', // pods
-                '#96cbff', '#d0312d', // disruption
+                '#96cbff', '#d0312d', '#ffa500', // disruption
                 '#b65049', '#32b8b6', '#ffffff', '#bbbbbb',
                 '#96cbff', '#fada5e', '#d0312d',
                 '#d3d3de', '#03fc62', '#fc0303', '#fada5e', '#8c5efa']); // EtcdLeadership
@@ -53523,7 +53527,7 @@ var _e2echartNonSpyglassE2eChartTemplateHtml = []byte(`<html lang="en">
     }
 
     function isEndpointConnectivity(eventInterval) {
-        if (!eventInterval.message.includes("reason/DisruptionBegan") && !eventInterval.message.includes("reason/DisruptionSamplerOutageBegan")){
+        if (!eventInterval.message.includes("reason/DisruptionBegan") && !eventInterval.message.includes("reason/DisruptionSamplerOutageBegan") && !eventInterval.message.includes("reason/DisruptionLatencyDegraded")){
             return false
         }
         if (eventInterval.locator.includes("disruption/")) {
@@ -53670,6 +53674,10 @@ var _e2echartNonSpyglassE2eChartTemplateHtml = []byte(`<html lang="en">
         if (ciClusterDisruption != -1) {
             return [item.locator, "", "CIClusterDisruption"]
         }
+        // the backend was available, but slower than its latency threshold
+        if (item.message.includes("reason/DisruptionLatencyDegraded")) {
+            return [item.locator, "", "LatencyDegraded"]
+        }
         return [item.locator, "", "Disruption"]
     }
 
@@ -53944,7 +53952,7 @@ var _e2echartNonSpyglassE2eChartTemplateHtml = []byte(`<html lang="en">
                 'Update', 'Drain', 'Reboot', 'OperatingSystemUpdate', 'NodeNotReady', // nodes
                 'Passed', 'Skipped', 'Flaked', 'Failed',  // tests
                 'PodCreated', 'PodScheduled', 'PodTerminating','ContainerWait', 'ContainerStart', 'ContainerNotReady', 'ContainerReady', 'ContainerReadinessFailed', 'ContainerReadinessErrored',  'StartupProbeFailed', // pods
-                'CIClusterDisruption', 'Disruption', // disruption
+                'CIClusterDisruption', 'Disruption', 'LatencyDegraded', // disruption
                 'Degraded', 'Upgradeable', 'False', 'Unknown',
                 'PodLogInfo', 'PodLogWarning', 'PodLogError'])
             .range([
@@ -53954,7 +53962,7 @@ var _e2echartNonSpyglassE2eChartTemplateHtml = []byte(`<html lang="en">
                 '#1e7bd9', '#4294e6', '#6aaef2', '#96cbff', '#fada5e', // nodes
                 '#3cb043', '#ceba76', '#ffa500', '#d0312d', // tests
                 '#96cbff', '#1e7bd9', '#ffa500', '#ca8dfd', '#9300ff', '#fada5e','#3cb043', '#d0312d', '#d0312d', '#c90076', // pods
-                '#96cbff', '#d0312d', // disruption
+                '#96cbff', '#d0312d', '#ffa500', // disruption
                 '#b65049', '#32b8b6', '#ffffff', '#bbbbbb',
                 '#96cbff', '#fada5e', '#d0312d']);
         myChart.
@@ -54129,8 +54137,9 @@ alarming.<p/>
             row$.css("background-color", disruptionResult.Backends[i].RiskColor);
             row$.append(addSimpleCell(disruptionResult.Backends[i].BackendName))
             row$.append(addSimpleCell(disruptionResult.Backends[i].ObservedDisruption))
-            row$.append(addSimpleCell(disruptionResult.Backends[i].P50.toFixed(2)))
-            row$.append(addSimpleCell(disruptionResult.Backends[i].P75.toFixed(2)))
+            row$.append(addSimpleCell(formatPercentileRank(disruptionResult.Backends[i].PercentileRank)))
+            row$.append(addSimpleCell(formatOptionalSeconds(disruptionResult.Backends[i].P50)))
+            row$.append(addSimpleCell(formatOptionalSeconds(disruptionResult.Backends[i].P75)))
             row$.append(addSimpleCell(disruptionResult.Backends[i].P95.toFixed(2)))
             row$.append(addSimpleCell(disruptionResult.Backends[i].P99.toFixed(2)))
             row$.append(addSimpleCell(disruptionResult.Backends[i].JobRuns))
@@ -54141,6 +54150,7 @@ alarming.<p/>
         var headerTr$ = $('<tr/>');
         headerTr$.append($('<th/>').html("Backend Name"));
         headerTr$.append($('<th/>').html("Observed Disruption"));
+        headerTr$.append($('<th/>').html("Percentile Rank"));
         headerTr$.append($('<th/>').html("P50"));
         headerTr$.append($('<th/>').html("P75"));
         headerTr$.append($('<th/>').html("P95"));
@@ -54150,6 +54160,22 @@ alarming.<p/>
         $(selector).append(headerTr$);
     }
 
+    // formatOptionalSeconds renders percentiles missing from older historical data as unknown
+    function formatOptionalSeconds(seconds) {
+        if (seconds == null) {
+            return "unknown"
+        }
+        return seconds.toFixed(2)
+    }
+
+    // formatPercentileRank matches historicaldata.FormatPercentileRank
+    function formatPercentileRank(rank) {
+        if (rank >= 100) {
+            return ">P99"
+        }
+        return "P" + rank.toFixed(1)
+    }
+
     function addSimpleCell(v) {
         td$ = $('<td/>')
         td$.append(v)
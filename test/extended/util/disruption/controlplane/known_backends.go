@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/disruption/backend/latency"
 
 	disruptionci "github.com/openshift/origin/pkg/disruption/ci"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
@@ -35,6 +36,15 @@ func StartAPIMonitoringUsingNewBackend(ctx context.Context, recorder monitorapi.
 	return nil
 }
 
+// apiServerLatency marks an apiserver as degraded when the P95 of the
+// round trip latency of the requests over the last minute goes over 2s,
+// healthy apiservers answer these GET requests in milliseconds.
+var apiServerLatency = latency.Config{
+	Percentile: 95,
+	Window:     time.Minute,
+	Threshold:  2 * time.Second,
+}
+
 func startKubeAPIMonitoringWithNewConnectionsHTTP2(ctx context.Context, recorder monitorapi.Recorder, factory disruptionci.Factory, lb backend.LoadBalancerType) error {
 	backendSampler, err := createKubeAPIMonitoringWithNewConnectionsHTTP2(factory, lb)
 	if err != nil {
@@ -95,6 +105,7 @@ func createKubeAPIMonitoringWithNewConnectionsHTTP2(factory disruptionci.Factory
 		Timeout:                      15 * time.Second,
 		SampleInterval:               time.Second,
 		EnableShutdownResponseHeader: true,
+		Latency:                      apiServerLatency,
 	})
 }
 
@@ -110,6 +121,7 @@ func createKubeAPIMonitoringWithConnectionReuseHTTP2(factory disruptionci.Factor
 		Timeout:                      15 * time.Second,
 		SampleInterval:               time.Second,
 		EnableShutdownResponseHeader: true,
+		Latency:                      apiServerLatency,
 	})
 }
 
@@ -125,6 +137,7 @@ func createKubeAPIMonitoringWithNewConnectionsHTTP1(factory disruptionci.Factory
 		Timeout:                      15 * time.Second,
 		SampleInterval:               time.Second,
 		EnableShutdownResponseHeader: true,
+		Latency:                      apiServerLatency,
 	})
 }
 
@@ -140,6 +153,7 @@ func createKubeAPIMonitoringWithConnectionReuseHTTP1(factory disruptionci.Factor
 		Timeout:                      15 * time.Second,
 		SampleInterval:               time.Second,
 		EnableShutdownResponseHeader: true,
+		Latency:                      apiServerLatency,
 	})
 }
 
@@ -155,6 +169,7 @@ func createOpenShiftAPIMonitoringWithNewConnectionsHTTP2(factory disruptionci.Fa
 		Timeout:                      15 * time.Second,
 		SampleInterval:               time.Second,
 		EnableShutdownResponseHeader: true,
+		Latency:                      apiServerLatency,
 	})
 }
 
@@ -170,5 +185,6 @@ func createOpenShiftAPIMonitoringWithConnectionReuseHTTP2(factory disruptionci.F
 		Timeout:                      15 * time.Second,
 		SampleInterval:               time.Second,
 		EnableShutdownResponseHeader: true,
+		Latency:                      apiServerLatency,
 	})
 }